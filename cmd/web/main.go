@@ -1,20 +1,45 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"os"
 	"path"
 	"time"
+	_ "time/tzdata"
 
 	"github.com/Jashanveer-Singh/todo-go/internal/adpaters/apis/http"
 	"github.com/Jashanveer-Singh/todo-go/internal/adpaters/bcrypt"
+	"github.com/Jashanveer-Singh/todo-go/internal/adpaters/idgen"
 	"github.com/Jashanveer-Singh/todo-go/internal/adpaters/jwttoken"
 	"github.com/Jashanveer-Singh/todo-go/internal/adpaters/repository/file"
+	"github.com/Jashanveer-Singh/todo-go/internal/adpaters/repository/sqlite"
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/internal/ports"
+	"github.com/Jashanveer-Singh/todo-go/internal/search"
 	"github.com/Jashanveer-Singh/todo-go/internal/services"
 )
 
 func main() {
+	storage := flag.String("storage", "file", "storage backend to use: file or sqlite")
+	node := flag.Int64("node", 0, "id of this server instance (0-15), keeps generated ids unique")
+	trashRetention := flag.Duration(
+		"trash-retention", 30*24*time.Hour, "how long deleted tasks stay in the trash, 0 keeps them",
+	)
+	admin := flag.String("admin", "", "username of a user to make an admin on start")
+	jwtKeys := flag.String(
+		"jwt-keys", "", "directory of the PEM private keys tokens are signed with (default data/keys)",
+	)
+	jwtAlg := flag.String(
+		"jwt-alg", jwttoken.AlgES256, "algorithm of generated signing keys: RS256, ES256 or EdDSA",
+	)
+	jwtRotation := flag.Duration(
+		"jwt-rotation", 30*24*time.Hour, "how often a new signing key is generated, 0 keeps the newest key",
+	)
+	flag.Parse()
+
 	cwd, err := os.Getwd()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Can't get the current working directory\n")
@@ -22,24 +47,215 @@ func main() {
 	}
 	dirPath := path.Join(cwd, "data")
 
-	tasksFile := path.Join(dirPath, "tasks.json")
-	usersFile := path.Join(dirPath, "users.json")
+	idGenerator := idgen.NewSnowflakeGenerator(*node)
+
+	var taskRepo ports.TaskRepo
+	var labelRepo ports.LabelRepo
+	var projectRepo ports.ProjectRepo
+	var workflowRepo ports.WorkflowRepo
+	var userRepo ports.UserRepo
+	var refreshTokenRepo ports.RefreshTokenRepo
+	var revocationRepo ports.RevocationRepo
+	var auditRepo ports.AuditRepo
+	var accessTokenRepo ports.AccessTokenRepo
+
+	switch *storage {
+	case "file":
+		tasksFile := path.Join(dirPath, "tasks.json")
+		labelsFile := path.Join(dirPath, "labels.json")
+		projectsFile := path.Join(dirPath, "projects.json")
+		workflowsFile := path.Join(dirPath, "workflows.json")
+		usersFile := path.Join(dirPath, "users.json")
+		refreshTokensFile := path.Join(dirPath, "refresh_tokens.json")
+		revocationsFile := path.Join(dirPath, "revocations.json")
+		auditFile := path.Join(dirPath, "audit.json")
+		accessTokensFile := path.Join(dirPath, "access_tokens.json")
+
+		fileTaskRepo := file.NewTaskRepo(tasksFile, idGenerator)
+		taskRepo = fileTaskRepo
+		labelRepo = file.NewLabelRepo(labelsFile, fileTaskRepo, idGenerator)
+		projectRepo = file.NewProjectRepo(projectsFile, fileTaskRepo, idGenerator)
+		workflowRepo = file.NewWorkflowRepo(workflowsFile, fileTaskRepo)
+		userRepo = file.NewUserRepo(usersFile, idGenerator)
+		refreshTokenRepo = file.NewRefreshTokenRepo(refreshTokensFile)
+		revocationRepo = file.NewRevocationRepo(revocationsFile)
+		auditRepo = file.NewAuditRepo(auditFile, idGenerator)
+		accessTokenRepo = file.NewAccessTokenRepo(accessTokensFile, idGenerator)
+	case "sqlite":
+		db, err := sqlite.NewDB(path.Join(dirPath, "todo.db"))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Can't open the sqlite database\n%s\n", err.Error())
+			os.Exit(1)
+		}
+		defer db.Close()
+
+		taskRepo = sqlite.NewTaskRepo(db, idGenerator)
+		labelRepo = sqlite.NewLabelRepo(db, idGenerator)
+		projectRepo = sqlite.NewProjectRepo(db, idGenerator)
+		workflowRepo = sqlite.NewWorkflowRepo(db)
+		userRepo = sqlite.NewUserRepo(db, idGenerator)
+		refreshTokenRepo = sqlite.NewRefreshTokenRepo(db)
+		revocationRepo = sqlite.NewRevocationRepo(db)
+		auditRepo = sqlite.NewAuditRepo(db, idGenerator)
+		accessTokenRepo = sqlite.NewAccessTokenRepo(db, idGenerator)
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown storage backend: %s\n", *storage)
+		os.Exit(1)
+	}
 
-	taskRepo := file.NewTaskRepo(tasksFile)
-	userRepo := file.NewUserRepo(usersFile)
+	if *admin != "" {
+		appErr := grantAdmin(userRepo, *admin)
+		if appErr != nil {
+			fmt.Fprintf(os.Stderr, "Can't make %s an admin\n%s\n", *admin, appErr.Message)
+			os.Exit(1)
+		}
+	}
+
+	accessTTL := 15 * time.Minute
+	keysDir := *jwtKeys
+	if keysDir == "" {
+		keysDir = path.Join(dirPath, "keys")
+	}
+	keyDir := jwttoken.NewKeyDir(keysDir, *jwtAlg, *jwtRotation)
+	keySet, err := keyDir.Load(accessTTL, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Can't load the token signing keys\n%s\n", err.Error())
+		os.Exit(1)
+	}
+	go maintainKeys(keyDir, keySet)
 	jwtTokenProvider := jwttoken.NewJWTTokenProvider(
-		"my secret key",
+		keySet,
 		"issuer",
 		"audience",
-		time.Hour*24,
+		accessTTL,
 	)
 	bcryptPasswordHasher := bcrypt.NewBcryptPasswordHasher(10)
 
-	authService := services.NewAuthService(userRepo, jwtTokenProvider, bcryptPasswordHasher)
-	taskService := services.NewTaskService(taskRepo)
+	authService := services.NewAuthService(
+		userRepo,
+		refreshTokenRepo,
+		revocationRepo,
+		jwtTokenProvider,
+		bcryptPasswordHasher,
+		accessTTL,
+		30*24*time.Hour,
+	)
+	go pruneRevocations(authService)
+	taskService := services.NewTaskService(
+		taskRepo,
+		labelRepo,
+		projectRepo,
+		workflowRepo,
+		search.NewIndex(),
+	)
+	appErr := taskService.RebuildIndex()
+	if appErr != nil {
+		fmt.Fprintf(os.Stderr, "Can't build the search index\n%s\n", appErr.Message)
+		os.Exit(1)
+	}
+	if *trashRetention > 0 {
+		go purgeTrash(taskService, *trashRetention)
+	}
+	labelService := services.NewLabelService(labelRepo)
+	projectService := services.NewProjectService(projectRepo)
+	workflowService := services.NewWorkflowService(workflowRepo)
 	userService := services.NewUserService(userRepo, bcryptPasswordHasher)
-	apiServer := http.NewHttpServer(taskService, userService, authService, jwtTokenProvider)
+	adminService := services.NewAdminService(
+		userRepo,
+		auditRepo,
+		accessTokenRepo,
+		bcryptPasswordHasher,
+		authService,
+		taskService,
+	)
+	accessTokenService := services.NewAccessTokenService(accessTokenRepo, userRepo)
+	apiServer := http.NewHttpServer(
+		taskService,
+		labelService,
+		projectService,
+		workflowService,
+		userService,
+		authService,
+		adminService,
+		accessTokenService,
+		jwtTokenProvider,
+		revocationRepo,
+	)
 
 	log.Println("Starting Server at port:8080")
 	apiServer.ListenAndServe(":8080")
 }
+
+// maintainKeys rotates the signing key when it is due and deletes retired
+// keys once they verify no token anymore. It checks at least once an hour, so
+// a failed rotation is retried.
+func maintainKeys(keyDir jwttoken.KeyDir, keySet *jwttoken.KeySet) {
+	for {
+		wait := time.Hour
+		next := keyDir.NextMaintenance(keySet)
+		if !next.IsZero() {
+			wait = min(max(time.Until(next), 0), wait)
+		}
+		time.Sleep(wait)
+
+		rotated, err := keyDir.Maintain(keySet, time.Now())
+		if err != nil {
+			log.Printf("Can't maintain the token signing keys: %s", err.Error())
+		}
+		if rotated {
+			key, _ := keySet.SigningKey()
+			log.Printf("Rotated the token signing key to %s", key.ID)
+		}
+	}
+}
+
+// grantAdmin gives the user with username the admin role, so a fresh install
+// has someone to manage accounts.
+func grantAdmin(userRepo ports.UserRepo, username string) *errr.AppError {
+	user, appErr := userRepo.GetUserByUsername(username)
+	if appErr != nil {
+		return appErr
+	}
+	if user.Role == models.RoleAdmin {
+		return nil
+	}
+
+	user.Role = models.RoleAdmin
+	return userRepo.UpdateUser(user)
+}
+
+// purgeTrash empties the trash of the tasks deleted longer than retention
+// ago, at least once an hour.
+func purgeTrash(taskService ports.TaskService, retention time.Duration) {
+	ticker := time.NewTicker(min(retention, time.Hour))
+	defer ticker.Stop()
+
+	for ; ; <-ticker.C {
+		purged, appErr := taskService.PurgeTrash(retention)
+		if appErr != nil {
+			log.Printf("Can't purge the trash: %s", appErr.Message)
+			continue
+		}
+		if purged > 0 {
+			log.Printf("Purged %d tasks from the trash", purged)
+		}
+	}
+}
+
+// pruneRevocations drops the revocations of tokens that have expired, once an
+// hour.
+func pruneRevocations(authService ports.AuthService) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for ; ; <-ticker.C {
+		pruned, appErr := authService.PruneRevocations()
+		if appErr != nil {
+			log.Printf("Can't prune token revocations: %s", appErr.Message)
+			continue
+		}
+		if pruned > 0 {
+			log.Printf("Pruned %d token revocations", pruned)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/internal/ports"
+)
+
+type accessTokenHandler struct {
+	accessTokenService ports.AccessTokenService
+}
+
+func newAccessTokenHandler(accessTokenService ports.AccessTokenService) *accessTokenHandler {
+	return &accessTokenHandler{
+		accessTokenService: accessTokenService,
+	}
+}
+
+func (ah accessTokenHandler) CreateAccessTokenHandler(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value("claims").(models.Claims)
+
+	var tokenReq models.AccessTokenRequestDto
+	err := json.NewDecoder(r.Body).Decode(&tokenReq)
+	if err != nil {
+		writeError(w, r, invalidBody())
+		return
+	}
+
+	token, appErr := ah.accessTokenService.CreateAccessToken(tokenReq, claims)
+	if appErr != nil {
+		writeError(w, r, appErr)
+		return
+	}
+
+	tokenjson, _ := json.Marshal(token)
+
+	// the response is the only time the token can be read.
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	w.Write(tokenjson)
+}
+
+func (ah accessTokenHandler) GetAccessTokensHandler(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value("claims").(models.Claims)
+
+	tokens, appErr := ah.accessTokenService.GetAccessTokens(claims)
+	if appErr != nil {
+		writeError(w, r, appErr)
+		return
+	}
+
+	tokensjson, _ := json.Marshal(tokens)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(tokensjson)
+}
+
+func (ah accessTokenHandler) DeleteAccessTokenHandler(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value("claims").(models.Claims)
+
+	appErr := ah.accessTokenService.DeleteAccessToken(r.PathValue("id"), claims)
+	if appErr != nil {
+		writeError(w, r, appErr)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	w.Write([]byte(""))
+}
@@ -0,0 +1,216 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/test/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+func Test_accessTokenHandler(t *testing.T) {
+	claims := models.Claims{ID: 4321}
+
+	tests := []struct {
+		name         string
+		method       string
+		url          string
+		requestBody  string
+		setupMAS     func(mas *mocks.MockAccessTokenService)
+		wantStatus   int
+		responseBody string
+	}{
+		{
+			name:        "create token",
+			method:      http.MethodPost,
+			url:         "/users/me/tokens",
+			requestBody: `{"name": "ci", "scopes": ["tasks:read"]}`,
+			setupMAS: func(mas *mocks.MockAccessTokenService) {
+				mas.EXPECT().CreateAccessToken(models.AccessTokenRequestDto{
+					Name:   "ci",
+					Scopes: []string{models.ScopeTasksRead},
+				}, claims).Return(models.AccessTokenResponseDto{
+					ID:        "9",
+					Name:      "ci",
+					Scopes:    []string{models.ScopeTasksRead},
+					CreatedAt: "2025-02-01T12:00:00Z",
+					Token:     "todo_pat_secret",
+				}, nil)
+			},
+			wantStatus: http.StatusCreated,
+			responseBody: `{"id":"9","name":"ci","scopes":["tasks:read"],` +
+				`"created_at":"2025-02-01T12:00:00Z","token":"todo_pat_secret"}`,
+		},
+		{
+			name:         "create token with an invalid body",
+			method:       http.MethodPost,
+			url:          "/users/me/tokens",
+			requestBody:  `ci`,
+			setupMAS:     func(mas *mocks.MockAccessTokenService) {},
+			wantStatus:   http.StatusBadRequest,
+			responseBody: problemBody(http.StatusBadRequest, "invalid_body", "Invalid Body"),
+		},
+		{
+			name:   "list tokens",
+			method: http.MethodGet,
+			url:    "/users/me/tokens",
+			setupMAS: func(mas *mocks.MockAccessTokenService) {
+				mas.EXPECT().GetAccessTokens(claims).Return([]models.AccessTokenResponseDto{{
+					ID:         "9",
+					Name:       "ci",
+					Scopes:     []string{models.ScopeTasksRead},
+					CreatedAt:  "2025-02-01T12:00:00Z",
+					LastUsedAt: "2025-02-02T12:00:00Z",
+				}}, nil)
+			},
+			wantStatus: http.StatusOK,
+			responseBody: `[{"id":"9","name":"ci","scopes":["tasks:read"],` +
+				`"created_at":"2025-02-01T12:00:00Z","last_used_at":"2025-02-02T12:00:00Z"}]`,
+		},
+		{
+			name:   "delete token",
+			method: http.MethodDelete,
+			url:    "/users/me/tokens/9",
+			setupMAS: func(mas *mocks.MockAccessTokenService) {
+				mas.EXPECT().DeleteAccessToken("9", claims).Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:   "delete unknown token",
+			method: http.MethodDelete,
+			url:    "/users/me/tokens/10",
+			setupMAS: func(mas *mocks.MockAccessTokenService) {
+				mas.EXPECT().DeleteAccessToken("10", claims).
+					Return(errr.NewNotFoundError("Access token not found"))
+			},
+			wantStatus:   http.StatusNotFound,
+			responseBody: problemBody(http.StatusNotFound, "not_found", "Access token not found"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.requestBody))
+			req.Header.Set("Authorization", "Bearer token")
+			rr := httptest.NewRecorder()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockAccessTokenService := mocks.NewMockAccessTokenService(ctrl)
+			tt.setupMAS(mockAccessTokenService)
+			mockTokenProvider := mocks.NewMockTokenProvider(ctrl)
+			mockTokenProvider.EXPECT().ValidateToken("token").Return(claims, nil)
+
+			router := newRouter(
+				newTaskHandler(nil), newLabelHandler(nil), newProjectHandler(nil),
+				newWorkflowHandler(nil), NewUserHandler(nil), NewAuthHandler(nil),
+				newAdminHandler(nil), newAccessTokenHandler(mockAccessTokenService), newKeyHandler(nil),
+				NewAuthMiddleware(mockTokenProvider, noRevocations(t), mockAccessTokenService),
+			)
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("wanted status code %d, got %d.", tt.wantStatus, rr.Code)
+			}
+			if rr.Body.String() != tt.responseBody {
+				t.Errorf("wanted response body: %s, got %s.", tt.responseBody, rr.Body)
+			}
+		})
+	}
+}
+
+func TestAuthMiddleware_accessTokens(t *testing.T) {
+	readOnly := models.Claims{ID: 4321, Scopes: []string{models.ScopeTasksRead}}
+
+	tests := []struct {
+		name         string
+		method       string
+		url          string
+		setupMAS     func(mas *mocks.MockAccessTokenService)
+		setupMTS     func(mts *mocks.MockTaskService)
+		wantStatus   int
+		responseBody string
+	}{
+		{
+			name:   "token with the scope of the route",
+			method: http.MethodGet,
+			url:    "/tasks",
+			setupMAS: func(mas *mocks.MockAccessTokenService) {
+				mas.EXPECT().Authenticate("todo_pat_secret").Return(readOnly, nil)
+			},
+			setupMTS: func(mts *mocks.MockTaskService) {
+				mts.EXPECT().GetTasks(readOnly, models.TaskFilterDto{}).
+					Return(models.TaskPageDto{Tasks: []models.TaskResponseDto{}}, nil)
+			},
+			wantStatus:   http.StatusOK,
+			responseBody: `{"tasks":[]}`,
+		},
+		{
+			name:   "token without the scope of the route",
+			method: http.MethodPost,
+			url:    "/tasks",
+			setupMAS: func(mas *mocks.MockAccessTokenService) {
+				mas.EXPECT().Authenticate("todo_pat_secret").Return(readOnly, nil)
+			},
+			wantStatus: http.StatusForbidden,
+			responseBody: problemBody(
+				http.StatusForbidden, "insufficient_scope", "This requires the tasks:write scope",
+			),
+		},
+		{
+			name:       "token on a route without scopes",
+			method:     http.MethodGet,
+			url:        "/users/me/tokens",
+			wantStatus: http.StatusForbidden,
+			responseBody: problemBody(
+				http.StatusForbidden, "insufficient_scope", "Personal access tokens can't be used here",
+			),
+		},
+		{
+			name:   "expired token",
+			method: http.MethodGet,
+			url:    "/tasks",
+			setupMAS: func(mas *mocks.MockAccessTokenService) {
+				mas.EXPECT().Authenticate("todo_pat_secret").
+					Return(models.Claims{}, errr.NewUnauthenticatedError("Access token has expired"))
+			},
+			wantStatus:   http.StatusUnauthorized,
+			responseBody: problemBody(http.StatusUnauthorized, "invalid_token", "Access token has expired"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.url, strings.NewReader(""))
+			req.Header.Set("Authorization", "Bearer todo_pat_secret")
+			rr := httptest.NewRecorder()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockAccessTokenService := mocks.NewMockAccessTokenService(ctrl)
+			if tt.setupMAS != nil {
+				tt.setupMAS(mockAccessTokenService)
+			}
+			mockTaskService := mocks.NewMockTaskService(ctrl)
+			if tt.setupMTS != nil {
+				tt.setupMTS(mockTaskService)
+			}
+
+			router := newRouter(
+				newTaskHandler(mockTaskService), newLabelHandler(nil), newProjectHandler(nil),
+				newWorkflowHandler(nil), NewUserHandler(nil), NewAuthHandler(nil),
+				newAdminHandler(nil), newAccessTokenHandler(mockAccessTokenService), newKeyHandler(nil),
+				NewAuthMiddleware(nil, nil, mockAccessTokenService),
+			)
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("wanted status code %d, got %d.", tt.wantStatus, rr.Code)
+			}
+			if rr.Body.String() != tt.responseBody {
+				t.Errorf("wanted response body: %s, got %s.", tt.responseBody, rr.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/internal/ports"
+)
+
+type adminHandler struct {
+	adminService ports.AdminService
+}
+
+func newAdminHandler(adminService ports.AdminService) *adminHandler {
+	return &adminHandler{
+		adminService: adminService,
+	}
+}
+
+// auditDenials records the attempts at action of users without the admin
+// role, which next turns away before they reach the admin service.
+func (ah adminHandler) auditDenials(action string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := r.Context().Value("claims").(models.Claims)
+		if ok && claims.Role != models.RoleAdmin {
+			ah.adminService.AuditDenied(action, r.PathValue("id"), claims)
+		}
+
+		next.ServeHTTP(w, r)
+	}
+}
+
+func (ah adminHandler) GetUsersHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(models.Claims)
+	if !ok {
+		writeError(w, r, errr.NewUnexpectedError("Unexpected error in authentication"))
+		return
+	}
+
+	users, appErr := ah.adminService.GetUsers(claims)
+	if appErr != nil {
+		writeError(w, r, appErr)
+		return
+	}
+
+	usersjson, _ := json.Marshal(users)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(usersjson)
+}
+
+func (ah adminHandler) DisableUserHandler(w http.ResponseWriter, r *http.Request) {
+	ah.setUserDisabled(w, r, true)
+}
+
+func (ah adminHandler) EnableUserHandler(w http.ResponseWriter, r *http.Request) {
+	ah.setUserDisabled(w, r, false)
+}
+
+func (ah adminHandler) setUserDisabled(w http.ResponseWriter, r *http.Request, disabled bool) {
+	claims, ok := r.Context().Value("claims").(models.Claims)
+	if !ok {
+		writeError(w, r, errr.NewUnexpectedError("Unexpected error in authentication"))
+		return
+	}
+
+	appErr := ah.adminService.SetUserDisabled(r.PathValue("id"), disabled, claims)
+	if appErr != nil {
+		writeError(w, r, appErr)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	w.Write([]byte(""))
+}
+
+func (ah adminHandler) ResetPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(models.Claims)
+	if !ok {
+		writeError(w, r, errr.NewUnexpectedError("Unexpected error in authentication"))
+		return
+	}
+	reset := models.PasswordResetDto{}
+
+	err := json.NewDecoder(r.Body).Decode(&reset)
+	if err != nil {
+		writeError(w, r, invalidBody())
+		return
+	}
+
+	appErr := ah.adminService.ResetPassword(r.PathValue("id"), reset, claims)
+	if appErr != nil {
+		writeError(w, r, appErr)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	w.Write([]byte(""))
+}
+
+func (ah adminHandler) GetUserTasksHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(models.Claims)
+	if !ok {
+		writeError(w, r, errr.NewUnexpectedError("Unexpected error in authentication"))
+		return
+	}
+	filter := taskFilter(r.URL.Query())
+	filter.Project = r.URL.Query().Get("project")
+
+	page, appErr := ah.adminService.GetUserTasks(r.PathValue("id"), filter, claims)
+	if appErr != nil {
+		writeError(w, r, appErr)
+		return
+	}
+
+	pagejson, _ := json.Marshal(page)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(pagejson)
+}
+
+func (ah adminHandler) GetAuditLogHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(models.Claims)
+	if !ok {
+		writeError(w, r, errr.NewUnexpectedError("Unexpected error in authentication"))
+		return
+	}
+
+	entries, appErr := ah.adminService.GetAuditLog(claims)
+	if appErr != nil {
+		writeError(w, r, appErr)
+		return
+	}
+
+	entriesjson, _ := json.Marshal(entries)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(entriesjson)
+}
@@ -0,0 +1,187 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/test/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+func Test_adminHandler(t *testing.T) {
+	admin := models.Claims{ID: 1, Role: models.RoleAdmin}
+
+	tests := []struct {
+		name         string
+		method       string
+		url          string
+		requestBody  string
+		claims       models.Claims
+		setupMAS     func(mas *mocks.MockAdminService)
+		wantStatus   int
+		responseBody string
+	}{
+		{
+			name:   "users can't use the admin api",
+			method: http.MethodGet,
+			url:    "/admin/users",
+			claims: models.Claims{ID: 77, Role: models.RoleUser},
+			setupMAS: func(mas *mocks.MockAdminService) {
+				mas.EXPECT().AuditDenied(models.AuditListUsers, "", models.Claims{ID: 77, Role: models.RoleUser})
+			},
+			wantStatus: http.StatusForbidden,
+			responseBody: problemBody(
+				http.StatusForbidden, "insufficient_role", "This requires the admin role",
+			),
+		},
+		{
+			name:   "denied attempts on a user are audited",
+			method: http.MethodPost,
+			url:    "/admin/users/1/password",
+			claims: models.Claims{ID: 77, Role: models.RoleUser},
+			setupMAS: func(mas *mocks.MockAdminService) {
+				mas.EXPECT().AuditDenied(models.AuditResetPassword, "1", models.Claims{ID: 77, Role: models.RoleUser})
+			},
+			wantStatus: http.StatusForbidden,
+			responseBody: problemBody(
+				http.StatusForbidden, "insufficient_role", "This requires the admin role",
+			),
+		},
+		{
+			name:   "list users",
+			method: http.MethodGet,
+			url:    "/admin/users",
+			claims: admin,
+			setupMAS: func(mas *mocks.MockAdminService) {
+				mas.EXPECT().GetUsers(admin).Return([]models.UserResponseDto{
+					{ID: "77", Username: "jass", Role: models.RoleUser, Disabled: true},
+				}, nil)
+			},
+			wantStatus:   http.StatusOK,
+			responseBody: `[{"id":"77","username":"jass","role":"user","disabled":true}]`,
+		},
+		{
+			name:   "disable user",
+			method: http.MethodPost,
+			url:    "/admin/users/77/disable",
+			claims: admin,
+			setupMAS: func(mas *mocks.MockAdminService) {
+				mas.EXPECT().SetUserDisabled("77", true, admin).Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:   "enable unknown user",
+			method: http.MethodPost,
+			url:    "/admin/users/78/enable",
+			claims: admin,
+			setupMAS: func(mas *mocks.MockAdminService) {
+				mas.EXPECT().SetUserDisabled("78", false, admin).Return(errr.NewNotFoundError("User not Found"))
+			},
+			wantStatus:   http.StatusNotFound,
+			responseBody: problemBody(http.StatusNotFound, "not_found", "User not Found"),
+		},
+		{
+			name:        "reset password",
+			method:      http.MethodPost,
+			url:         "/admin/users/77/password",
+			requestBody: `{"password": "new password"}`,
+			claims:      admin,
+			setupMAS: func(mas *mocks.MockAdminService) {
+				mas.EXPECT().ResetPassword("77", models.PasswordResetDto{Password: "new password"}, admin).
+					Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:         "reset password with an invalid body",
+			method:       http.MethodPost,
+			url:          "/admin/users/77/password",
+			requestBody:  `password`,
+			claims:       admin,
+			setupMAS:     func(mas *mocks.MockAdminService) {},
+			wantStatus:   http.StatusBadRequest,
+			responseBody: problemBody(http.StatusBadRequest, "invalid_body", "Invalid Body"),
+		},
+		{
+			name:   "view a user's tasks",
+			method: http.MethodGet,
+			url:    "/admin/users/77/tasks?status=Done",
+			claims: admin,
+			setupMAS: func(mas *mocks.MockAdminService) {
+				mas.EXPECT().GetUserTasks("77", models.TaskFilterDto{Status: "Done"}, admin).
+					Return(models.TaskPageDto{Tasks: []models.TaskResponseDto{}}, nil)
+			},
+			wantStatus:   http.StatusOK,
+			responseBody: `{"tasks":[]}`,
+		},
+		{
+			name:   "audit log",
+			method: http.MethodGet,
+			url:    "/admin/audit",
+			claims: admin,
+			setupMAS: func(mas *mocks.MockAdminService) {
+				mas.EXPECT().GetAuditLog(admin).Return([]models.AuditEntryDto{
+					{ID: "9", ActorID: "1", Action: "list_users", Outcome: "succeeded", At: "2025-02-01T12:00:00Z"},
+				}, nil)
+			},
+			wantStatus: http.StatusOK,
+			responseBody: `[{"id":"9","actor_id":"1","action":"list_users","outcome":"succeeded",` +
+				`"at":"2025-02-01T12:00:00Z"}]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.requestBody))
+			req.Header.Set("Authorization", "Bearer token")
+			rr := httptest.NewRecorder()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockAdminService := mocks.NewMockAdminService(ctrl)
+			tt.setupMAS(mockAdminService)
+			mockTokenProvider := mocks.NewMockTokenProvider(ctrl)
+			mockTokenProvider.EXPECT().ValidateToken("token").Return(tt.claims, nil)
+
+			router := newRouter(
+				newTaskHandler(nil), newLabelHandler(nil), newProjectHandler(nil),
+				newWorkflowHandler(nil), NewUserHandler(nil), NewAuthHandler(nil),
+				newAdminHandler(mockAdminService), newAccessTokenHandler(nil), newKeyHandler(nil),
+				NewAuthMiddleware(mockTokenProvider, noRevocations(t), nil),
+			)
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("wanted status code %d, got %d.", tt.wantStatus, rr.Code)
+			}
+			if rr.Body.String() != tt.responseBody {
+				t.Errorf("wanted response body: %s, got %s.", tt.responseBody, rr.Body)
+			}
+		})
+	}
+}
+
+func Test_adminHandler_without_claims(t *testing.T) {
+	ah := newAdminHandler(nil)
+	handlers := map[string]http.HandlerFunc{
+		"GetUsersHandler":      ah.GetUsersHandler,
+		"DisableUserHandler":   ah.DisableUserHandler,
+		"EnableUserHandler":    ah.EnableUserHandler,
+		"ResetPasswordHandler": ah.ResetPasswordHandler,
+		"GetUserTasksHandler":  ah.GetUserTasksHandler,
+		"GetAuditLogHandler":   ah.GetAuditLogHandler,
+	}
+	for name, handler := range handlers {
+		t.Run(name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			handler(rr, httptest.NewRequest(http.MethodGet, "/admin/users/7", nil))
+
+			if rr.Code != http.StatusInternalServerError {
+				t.Errorf("%s without claims = %d, want %d", name, rr.Code, http.StatusInternalServerError)
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 
 	"github.com/Jashanveer-Singh/todo-go/internal/models"
@@ -23,16 +25,77 @@ func (ah authHandler) Login(w http.ResponseWriter, r *http.Request) {
 
 	err := json.NewDecoder(r.Body).Decode(&userReq)
 	if err != nil {
-		http.Error(w, "Invalid Body", http.StatusBadRequest)
+		writeError(w, r, invalidBody())
 		return
 	}
 
-	token, appErr := ah.authService.Login(userReq.Username, userReq.Password)
+	tokens, appErr := ah.authService.Login(userReq.Username, userReq.Password)
 	if appErr != nil {
-		http.Error(w, appErr.Message, appErr.Code)
+		writeError(w, r, appErr)
 		return
 	}
 
+	writeTokens(w, tokens)
+}
+
+func (ah authHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	refreshReq := models.RefreshRequestDto{}
+
+	err := json.NewDecoder(r.Body).Decode(&refreshReq)
+	if err != nil || refreshReq.RefreshToken == "" {
+		writeError(w, r, invalidBody())
+		return
+	}
+
+	tokens, appErr := ah.authService.Refresh(refreshReq.RefreshToken)
+	if appErr != nil {
+		writeError(w, r, appErr)
+		return
+	}
+
+	writeTokens(w, tokens)
+}
+
+// Logout revokes the caller's access token and, when the body names one, its
+// refresh token. The body is optional.
+func (ah authHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value("claims").(models.Claims)
+	logoutReq := models.LogoutRequestDto{}
+
+	err := json.NewDecoder(r.Body).Decode(&logoutReq)
+	if err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, r, invalidBody())
+		return
+	}
+
+	appErr := ah.authService.Logout(claims, logoutReq.RefreshToken)
+	if appErr != nil {
+		writeError(w, r, appErr)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	w.Write([]byte(""))
+}
+
+func (ah authHandler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value("claims").(models.Claims)
+
+	appErr := ah.authService.LogoutAll(claims)
+	if appErr != nil {
+		writeError(w, r, appErr)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	w.Write([]byte(""))
+}
+
+func writeTokens(w http.ResponseWriter, tokens models.TokenPairDto) {
+	tokensjson, _ := json.Marshal(tokens)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
 	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(token))
+	w.Write(tokensjson)
 }
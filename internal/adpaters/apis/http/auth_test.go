@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
 	"github.com/Jashanveer-Singh/todo-go/test/mocks"
 	"github.com/golang/mock/gomock"
 )
@@ -25,29 +26,33 @@ func Test_authHandler_Login(t *testing.T) {
 			setupMAS:     func(mus *mocks.MockAuthService) {},
 			requestBody:  strings.NewReader("adsfj;lsdj"),
 			wantStatus:   http.StatusBadRequest,
-			responseBody: "Invalid Body\n",
+			responseBody: problemBody(http.StatusBadRequest, "invalid_body", "Invalid Body"),
 		},
 		{
 			name: "user service returns error",
 			setupMAS: func(mus *mocks.MockAuthService) {
 				mus.EXPECT().Login("jass", "password").
-					Return("", &errr.AppError{
+					Return(models.TokenPairDto{}, &errr.AppError{
 						Code:    http.StatusInternalServerError,
 						Message: "error message from auth service",
 					})
 			},
 			requestBody:  strings.NewReader(`{"username": "jass", "password": "password"}`),
 			wantStatus:   http.StatusInternalServerError,
-			responseBody: "error message from auth service\n",
+			responseBody: problemBody(http.StatusInternalServerError, "internal_server_error", "error message from auth service"),
 		},
 		{
 			name: "successful response",
 			setupMAS: func(mus *mocks.MockAuthService) {
-				mus.EXPECT().Login("jass", "password").Return("token", nil)
+				mus.EXPECT().Login("jass", "password").Return(models.TokenPairDto{
+					AccessToken:  "token",
+					RefreshToken: "refresh",
+					TokenType:    "Bearer",
+				}, nil)
 			},
 			requestBody:  strings.NewReader(`{"username": "jass", "password": "password"}`),
 			wantStatus:   http.StatusOK,
-			responseBody: "token",
+			responseBody: `{"access_token":"token","refresh_token":"refresh","token_type":"Bearer"}`,
 		},
 	}
 	for _, tt := range tests {
@@ -71,3 +76,154 @@ func Test_authHandler_Login(t *testing.T) {
 		})
 	}
 }
+
+func Test_authHandler_Refresh(t *testing.T) {
+	tests := []struct {
+		name         string
+		setupMAS     func(mas *mocks.MockAuthService)
+		requestBody  io.Reader
+		wantStatus   int
+		responseBody string
+	}{
+		{
+			name:         "missing refresh token",
+			setupMAS:     func(mas *mocks.MockAuthService) {},
+			requestBody:  strings.NewReader(`{}`),
+			wantStatus:   http.StatusBadRequest,
+			responseBody: problemBody(http.StatusBadRequest, "invalid_body", "Invalid Body"),
+		},
+		{
+			name: "reused refresh token",
+			setupMAS: func(mas *mocks.MockAuthService) {
+				mas.EXPECT().Refresh("old").Return(
+					models.TokenPairDto{},
+					errr.NewUnauthenticatedError("Refresh token was already used, sign in again"),
+				)
+			},
+			requestBody: strings.NewReader(`{"refresh_token": "old"}`),
+			wantStatus:  http.StatusUnauthorized,
+			responseBody: problemBody(
+				http.StatusUnauthorized, "unauthorized", "Refresh token was already used, sign in again",
+			),
+		},
+		{
+			name: "rotated tokens",
+			setupMAS: func(mas *mocks.MockAuthService) {
+				mas.EXPECT().Refresh("old").Return(models.TokenPairDto{
+					AccessToken:  "token",
+					RefreshToken: "new",
+					TokenType:    "Bearer",
+				}, nil)
+			},
+			requestBody:  strings.NewReader(`{"refresh_token": "old"}`),
+			wantStatus:   http.StatusOK,
+			responseBody: `{"access_token":"token","refresh_token":"new","token_type":"Bearer"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/auth/refresh", tt.requestBody)
+			rr := httptest.NewRecorder()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockAuthService := mocks.NewMockAuthService(ctrl)
+			tt.setupMAS(mockAuthService)
+			NewAuthHandler(mockAuthService).Refresh(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("wanted status code %d, got %d.", tt.wantStatus, rr.Code)
+			}
+			if rr.Body.String() != tt.responseBody {
+				t.Errorf("wanted response body: %s, got %s.", tt.responseBody, rr.Body)
+			}
+			if tt.wantStatus == http.StatusOK && rr.Header().Get("Cache-Control") != "no-store" {
+				t.Errorf("wanted Cache-Control no-store, got %q", rr.Header().Get("Cache-Control"))
+			}
+		})
+	}
+}
+
+func Test_authHandler_Logout(t *testing.T) {
+	claims := models.Claims{ID: 4321, TokenID: "jti"}
+
+	tests := []struct {
+		name         string
+		url          string
+		requestBody  string
+		setupMAS     func(mas *mocks.MockAuthService)
+		wantStatus   int
+		responseBody string
+	}{
+		{
+			name:       "logout without a body",
+			url:        "/auth/logout",
+			setupMAS:   func(mas *mocks.MockAuthService) { mas.EXPECT().Logout(claims, "").Return(nil) },
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:        "logout with the refresh token",
+			url:         "/auth/logout",
+			requestBody: `{"refresh_token": "refresh"}`,
+			setupMAS: func(mas *mocks.MockAuthService) {
+				mas.EXPECT().Logout(claims, "refresh").Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:         "logout with an invalid body",
+			url:          "/auth/logout",
+			requestBody:  `{"refresh_token":`,
+			setupMAS:     func(mas *mocks.MockAuthService) {},
+			wantStatus:   http.StatusBadRequest,
+			responseBody: problemBody(http.StatusBadRequest, "invalid_body", "Invalid Body"),
+		},
+		{
+			name:       "logout everywhere",
+			url:        "/auth/logout-all",
+			setupMAS:   func(mas *mocks.MockAuthService) { mas.EXPECT().LogoutAll(claims).Return(nil) },
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name: "logout everywhere fails",
+			url:  "/auth/logout-all",
+			setupMAS: func(mas *mocks.MockAuthService) {
+				mas.EXPECT().LogoutAll(claims).
+					Return(errr.NewUnexpectedError("Unable to revoke token due to internal server error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+			responseBody: problemBody(
+				http.StatusInternalServerError, "internal_server_error",
+				"Unable to revoke token due to internal server error",
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.url, strings.NewReader(tt.requestBody))
+			req.Header.Set("Authorization", "Bearer token")
+			rr := httptest.NewRecorder()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockAuthService := mocks.NewMockAuthService(ctrl)
+			tt.setupMAS(mockAuthService)
+			mockTokenProvider := mocks.NewMockTokenProvider(ctrl)
+			mockTokenProvider.EXPECT().ValidateToken("token").Return(claims, nil)
+
+			router := newRouter(
+				newTaskHandler(nil), newLabelHandler(nil), newProjectHandler(nil),
+				newWorkflowHandler(nil), NewUserHandler(nil), NewAuthHandler(mockAuthService),
+				newAdminHandler(nil), newAccessTokenHandler(nil), newKeyHandler(nil),
+				NewAuthMiddleware(mockTokenProvider, noRevocations(t), nil),
+			)
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("wanted status code %d, got %d.", tt.wantStatus, rr.Code)
+			}
+			if rr.Body.String() != tt.responseBody {
+				t.Errorf("wanted response body: %s, got %s.", tt.responseBody, rr.Body)
+			}
+		})
+	}
+}
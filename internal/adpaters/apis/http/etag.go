@@ -0,0 +1,62 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+)
+
+// taskETag is the strong entity tag of a task at version.
+func taskETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// bodyETag is a weak entity tag of a response body, for listings that have
+// no version of their own.
+func bodyETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// ifMatchVersion reads the version of the task the client last saw from
+// If-Match. A missing header or "*" gives 0, which skips the check: If-Match
+// is optional, so clients that don't send it keep overwriting the task
+// whatever its version.
+func ifMatchVersion(r *http.Request) (int64, *errr.AppError) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "" || value == "*" {
+		return 0, nil
+	}
+
+	// Weak tags never match If-Match, so anything but a single strong tag
+	// of a version fails.
+	tag, ok := strings.CutPrefix(value, `"`)
+	if ok {
+		tag, ok = strings.CutSuffix(tag, `"`)
+	}
+	version, err := strconv.ParseInt(tag, 10, 64)
+	if !ok || err != nil || version < 1 {
+		return 0, errr.NewPreconditionFailedError("If-Match must be the ETag of the task")
+	}
+	return version, nil
+}
+
+// noneMatch reports whether If-None-Match of the request lets a response
+// with etag through, comparing the tags weakly.
+func noneMatch(r *http.Request, etag string) bool {
+	value := r.Header.Get("If-None-Match")
+	if value == "" {
+		return true
+	}
+	for _, tag := range strings.Split(value, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,186 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/test/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+func Test_ifMatchVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		ifMatch string
+		want    int64
+		wantErr bool
+	}{
+		{name: "no header", want: 0},
+		{name: "any version", ifMatch: "*", want: 0},
+		{name: "version", ifMatch: `"7"`, want: 7},
+		{name: "weak tag never matches", ifMatch: `W/"7"`, wantErr: true},
+		{name: "unquoted", ifMatch: "7", wantErr: true},
+		{name: "several tags", ifMatch: `"7", "8"`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/tasks/1", nil)
+			req.Header.Set("If-Match", tt.ifMatch)
+
+			got, appErr := ifMatchVersion(req)
+			if tt.wantErr {
+				if appErr == nil || appErr.Code != http.StatusPreconditionFailed {
+					t.Errorf("ifMatchVersion() = %v, %v, want precondition failed", got, appErr)
+				}
+				return
+			}
+			if appErr != nil || got != tt.want {
+				t.Errorf("ifMatchVersion() = %v, %v, want %v", got, appErr, tt.want)
+			}
+		})
+	}
+}
+
+func Test_noneMatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		want        bool
+	}{
+		{name: "no header", want: true},
+		{name: "same tag", ifNoneMatch: `W/"abc"`, want: false},
+		{name: "compared weakly", ifNoneMatch: `"abc"`, want: false},
+		{name: "one of several", ifNoneMatch: `"def", W/"abc"`, want: false},
+		{name: "any tag", ifNoneMatch: "*", want: false},
+		{name: "other tag", ifNoneMatch: `W/"def"`, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+			req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			if got := noneMatch(req, `W/"abc"`); got != tt.want {
+				t.Errorf("noneMatch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_taskHandler_conditionalRequests(t *testing.T) {
+	claims := models.Claims{ID: 4321}
+	withClaims := func(req *http.Request) *http.Request {
+		return req.WithContext(context.WithValue(req.Context(), "claims", claims))
+	}
+
+	t.Run("unchanged listing is not modified", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mts := mocks.NewMockTaskService(ctrl)
+		page := models.TaskPageDto{Tasks: []models.TaskResponseDto{{ID: "1", Title: "title", Version: 2}}}
+		mts.EXPECT().GetTasks(claims, models.TaskFilterDto{}).Return(page, nil).Times(2)
+		th := newTaskHandler(mts)
+
+		rr := httptest.NewRecorder()
+		th.GetTasksHandler(rr, withClaims(httptest.NewRequest(http.MethodGet, "/tasks", nil)))
+		etag := rr.Header().Get("ETag")
+		if rr.Code != http.StatusOK || !strings.HasPrefix(etag, `W/"`) {
+			t.Fatalf("GetTasksHandler() = %d with ETag %q, want 200 with a weak tag", rr.Code, etag)
+		}
+
+		req := withClaims(httptest.NewRequest(http.MethodGet, "/tasks", nil))
+		req.Header.Set("If-None-Match", etag)
+		rr = httptest.NewRecorder()
+		th.GetTasksHandler(rr, req)
+		if rr.Code != http.StatusNotModified || rr.Body.Len() != 0 || rr.Header().Get("ETag") != etag {
+			t.Errorf("GetTasksHandler() = %d %q, want 304 without a body", rr.Code, rr.Body)
+		}
+	})
+
+	t.Run("task carries its version as ETag", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mts := mocks.NewMockTaskService(ctrl)
+		mts.EXPECT().GetTask("1", claims).Return(models.TaskResponseDto{ID: "1", Version: 3}, nil)
+
+		req := withClaims(httptest.NewRequest(http.MethodGet, "/tasks/1", nil))
+		req.SetPathValue("id", "1")
+		rr := httptest.NewRecorder()
+		newTaskHandler(mts).GetTaskHandler(rr, req)
+		if got := rr.Header().Get("ETag"); got != `"3"` {
+			t.Errorf("ETag = %q, want %q", got, `"3"`)
+		}
+	})
+
+	t.Run("update is based on the version of If-Match", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mts := mocks.NewMockTaskService(ctrl)
+		mts.EXPECT().
+			UpdateTask("1", int64(3), models.TaskRequestDto{Title: "title"}, claims).
+			Return(int64(0), errr.NewPreconditionFailedError("Task has changed since it was read"))
+
+		req := withClaims(httptest.NewRequest(http.MethodPut, "/tasks/1", strings.NewReader(`{"title":"title"}`)))
+		req.SetPathValue("id", "1")
+		req.Header.Set("If-Match", `"3"`)
+		rr := httptest.NewRecorder()
+		newTaskHandler(mts).UpdateTaskHandler(rr, req)
+		want := problemBody(http.StatusPreconditionFailed, "precondition_failed", "Task has changed since it was read")
+		if rr.Code != http.StatusPreconditionFailed || rr.Body.String() != want {
+			t.Errorf("UpdateTaskHandler() = %d %s, want 412 %s", rr.Code, rr.Body, want)
+		}
+	})
+
+	t.Run("update answers with the ETag of the new version", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mts := mocks.NewMockTaskService(ctrl)
+		mts.EXPECT().
+			UpdateTask("1", int64(3), models.TaskRequestDto{Title: "title"}, claims).
+			Return(int64(4), nil)
+
+		req := withClaims(httptest.NewRequest(http.MethodPut, "/tasks/1", strings.NewReader(`{"title":"title"}`)))
+		req.SetPathValue("id", "1")
+		req.Header.Set("If-Match", `"3"`)
+		rr := httptest.NewRecorder()
+		newTaskHandler(mts).UpdateTaskHandler(rr, req)
+		if got := rr.Header().Get("ETag"); rr.Code != http.StatusNoContent || got != `"4"` {
+			t.Errorf("UpdateTaskHandler() = %d with ETag %q, want 204 with %q", rr.Code, got, `"4"`)
+		}
+	})
+
+	t.Run("patch answers with the ETag of the new version", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mts := mocks.NewMockTaskService(ctrl)
+		mts.EXPECT().
+			PatchTask("1", int64(0), models.TaskPatch{Format: models.MergePatch, Doc: []byte(`{}`)}, claims).
+			Return(int64(4), nil)
+
+		req := withClaims(httptest.NewRequest(http.MethodPatch, "/tasks/1", strings.NewReader(`{}`)))
+		req.SetPathValue("id", "1")
+		req.Header.Set("Content-Type", mergePatchContentType)
+		rr := httptest.NewRecorder()
+		newTaskHandler(mts).PatchTaskHandler(rr, req)
+		if got := rr.Header().Get("ETag"); rr.Code != http.StatusNoContent || got != `"4"` {
+			t.Errorf("PatchTaskHandler() = %d with ETag %q, want 204 with %q", rr.Code, got, `"4"`)
+		}
+	})
+
+	t.Run("delete with an unusable If-Match", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		req := withClaims(httptest.NewRequest(http.MethodDelete, "/tasks/1", nil))
+		req.SetPathValue("id", "1")
+		req.Header.Set("If-Match", `W/"3"`)
+		rr := httptest.NewRecorder()
+		newTaskHandler(mocks.NewMockTaskService(ctrl)).DeleteTaskHandler(rr, req)
+		if rr.Code != http.StatusPreconditionFailed {
+			t.Errorf("DeleteTaskHandler() = %d, want 412", rr.Code)
+		}
+	})
+}
+
+func Test_bodyETag(t *testing.T) {
+	a, b := bodyETag([]byte(`{"tasks":[]}`)), bodyETag([]byte(`{"tasks":[{}]}`))
+	if a == b || a != bodyETag([]byte(`{"tasks":[]}`)) {
+		t.Errorf("bodyETag() = %q and %q, want stable tags that differ by body", a, b)
+	}
+}
@@ -0,0 +1,28 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/ports"
+)
+
+type keyHandler struct {
+	tokenProvider ports.TokenProvider
+}
+
+func newKeyHandler(tokenProvider ports.TokenProvider) *keyHandler {
+	return &keyHandler{
+		tokenProvider: tokenProvider,
+	}
+}
+
+// GetJWKSHandler serves the public keys other services verify tokens with.
+func (kh keyHandler) GetJWKSHandler(w http.ResponseWriter, r *http.Request) {
+	keysjson, _ := json.Marshal(kh.tokenProvider.PublicKeys())
+
+	// a verifier seeing a kid it doesn't know fetches the keys again.
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.Header().Set("Content-Type", "application/jwk-set+json")
+	w.Write(keysjson)
+}
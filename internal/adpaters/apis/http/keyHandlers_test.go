@@ -0,0 +1,44 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/test/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+func Test_keyHandler_GetJWKSHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockTokenProvider := mocks.NewMockTokenProvider(ctrl)
+	mockTokenProvider.EXPECT().PublicKeys().Return(models.JSONWebKeySet{Keys: []models.JSONWebKey{
+		{Kty: "OKP", Kid: "new", Use: "sig", Alg: "EdDSA", Crv: "Ed25519", X: "x"},
+		{Kty: "EC", Kid: "old", Use: "sig", Alg: "ES256", Crv: "P-256", X: "x", Y: "y"},
+	}})
+
+	router := newRouter(
+		newTaskHandler(nil), newLabelHandler(nil), newProjectHandler(nil),
+		newWorkflowHandler(nil), NewUserHandler(nil), NewAuthHandler(nil),
+		newAdminHandler(nil), newAccessTokenHandler(nil), newKeyHandler(mockTokenProvider),
+		NewAuthMiddleware(mockTokenProvider, nil, nil),
+	)
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("wanted status code %d, got %d.", http.StatusOK, rr.Code)
+	}
+	if got := rr.Header().Get("Content-Type"); got != "application/jwk-set+json" {
+		t.Errorf("wanted content type application/jwk-set+json, got %s.", got)
+	}
+	want := `{"keys":[` +
+		`{"kty":"OKP","kid":"new","use":"sig","alg":"EdDSA","crv":"Ed25519","x":"x"},` +
+		`{"kty":"EC","kid":"old","use":"sig","alg":"ES256","crv":"P-256","x":"x","y":"y"}]}`
+	if rr.Body.String() != want {
+		t.Errorf("wanted response body: %s, got %s.", want, rr.Body)
+	}
+}
@@ -0,0 +1,106 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/internal/ports"
+)
+
+type labelHandler struct {
+	ls ports.LabelService
+}
+
+func newLabelHandler(ls ports.LabelService) *labelHandler {
+	return &labelHandler{
+		ls,
+	}
+}
+
+func (lh labelHandler) GetLabelsHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(models.Claims)
+	if !ok {
+		writeError(w, r, errr.NewUnexpectedError("Unexpected error in authentication"))
+		return
+	}
+
+	labelRes, appErr := lh.ls.GetLabels(claims)
+	if appErr != nil {
+		writeError(w, r, appErr)
+		return
+	}
+
+	labelsjson, _ := json.Marshal(labelRes)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(labelsjson)
+}
+
+func (lh labelHandler) CreateLabelHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(models.Claims)
+	if !ok {
+		writeError(w, r, errr.NewUnexpectedError("Unexpected error in authentication"))
+		return
+	}
+	var labelReq models.LabelRequestDto
+	err := json.NewDecoder(r.Body).Decode(&labelReq)
+	if err != nil {
+		writeError(w, r, invalidBody())
+		return
+	}
+
+	labelRes, appErr := lh.ls.CreateLabel(labelReq, claims)
+	if appErr != nil {
+		writeError(w, r, appErr)
+		return
+	}
+
+	labeljson, _ := json.Marshal(labelRes)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	w.Write(labeljson)
+}
+
+func (lh labelHandler) UpdateLabelHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(models.Claims)
+	if !ok {
+		writeError(w, r, errr.NewUnexpectedError("Unexpected error in authentication"))
+		return
+	}
+	id := r.PathValue("id")
+
+	var labelReq models.LabelRequestDto
+	err := json.NewDecoder(r.Body).Decode(&labelReq)
+	if err != nil {
+		writeError(w, r, invalidBody())
+		return
+	}
+
+	appErr := lh.ls.UpdateLabel(id, labelReq, claims)
+	if appErr != nil {
+		writeError(w, r, appErr)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (lh labelHandler) DeleteLabelHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(models.Claims)
+	if !ok {
+		writeError(w, r, errr.NewUnexpectedError("Unexpected error in authentication"))
+		return
+	}
+	id := r.PathValue("id")
+
+	appErr := lh.ls.DeleteLabel(id, claims)
+	if appErr != nil {
+		writeError(w, r, appErr)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,124 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/test/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+func Test_labelHandler_CreateLabelHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		setupMLS     func(*mocks.MockLabelService)
+		requestBody  io.Reader
+		wantStatus   int
+		responseBody string
+	}{
+		{
+			name: "successfully created label",
+			setupMLS: func(mls *mocks.MockLabelService) {
+				mls.EXPECT().CreateLabel(
+					models.LabelRequestDto{Name: "work", Color: "#1e90ff"},
+					models.Claims{ID: 4321},
+				).Return(models.LabelResponseDto{ID: "7", Name: "work", Color: "#1e90ff"}, nil)
+			},
+			requestBody:  strings.NewReader(`{"name":"work","color":"#1e90ff"}`),
+			wantStatus:   http.StatusCreated,
+			responseBody: `{"id":"7","name":"work","color":"#1e90ff"}`,
+		},
+		{
+			name:         "invalid body",
+			setupMLS:     func(mls *mocks.MockLabelService) {},
+			requestBody:  strings.NewReader(`{"name":`),
+			wantStatus:   http.StatusBadRequest,
+			responseBody: problemBody(http.StatusBadRequest, "invalid_body", "Invalid Body"),
+		},
+		{
+			name: "label service returns error",
+			setupMLS: func(mls *mocks.MockLabelService) {
+				mls.EXPECT().CreateLabel(gomock.Any(), gomock.Any()).Return(
+					models.LabelResponseDto{},
+					&errr.AppError{Code: http.StatusConflict, Message: "label already exists"},
+				)
+			},
+			requestBody:  strings.NewReader(`{"name":"work","color":"#1e90ff"}`),
+			wantStatus:   http.StatusConflict,
+			responseBody: problemBody(http.StatusConflict, "conflict", "label already exists"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/labels", tt.requestBody)
+			req = req.WithContext(context.WithValue(req.Context(), "claims", models.Claims{
+				ID: 4321,
+			}))
+			rr := httptest.NewRecorder()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockLabelService := mocks.NewMockLabelService(ctrl)
+			tt.setupMLS(mockLabelService)
+			lh := newLabelHandler(mockLabelService)
+			lh.CreateLabelHandler(rr, req)
+			if rr.Code != tt.wantStatus {
+				t.Errorf("wanted status code %d, got %d.", tt.wantStatus, rr.Code)
+			}
+			if rr.Body.String() != tt.responseBody {
+				t.Errorf("wanted response body: %s, got %s.", tt.responseBody, rr.Body)
+			}
+		})
+	}
+}
+
+func Test_labelHandler_DeleteLabelHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		setupMLS   func(*mocks.MockLabelService)
+		wantStatus int
+	}{
+		{
+			name: "successfully deleted label",
+			setupMLS: func(mls *mocks.MockLabelService) {
+				mls.EXPECT().DeleteLabel("7", models.Claims{ID: 4321}).Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name: "label of another user",
+			setupMLS: func(mls *mocks.MockLabelService) {
+				mls.EXPECT().DeleteLabel("7", models.Claims{ID: 4321}).Return(
+					&errr.AppError{Code: http.StatusForbidden, Message: "Unauthorized to delete label"},
+				)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodDelete, "/labels/7", nil)
+			req.SetPathValue("id", "7")
+			req = req.WithContext(context.WithValue(req.Context(), "claims", models.Claims{
+				ID: 4321,
+			}))
+			rr := httptest.NewRecorder()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockLabelService := mocks.NewMockLabelService(ctrl)
+			tt.setupMLS(mockLabelService)
+			lh := newLabelHandler(mockLabelService)
+			lh.DeleteLabelHandler(rr, req)
+			if rr.Code != tt.wantStatus {
+				t.Errorf("wanted status code %d, got %d.", tt.wantStatus, rr.Code)
+			}
+		})
+	}
+}
@@ -6,17 +6,27 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
 	"github.com/Jashanveer-Singh/todo-go/internal/ports"
 )
 
-func NewAuthMiddleware(tokenProvider ports.TokenProvider) *AuthMiddleware {
+func NewAuthMiddleware(
+	tokenProvider ports.TokenProvider,
+	revocationRepo ports.RevocationRepo,
+	accessTokenService ports.AccessTokenService,
+) *AuthMiddleware {
 	return &AuthMiddleware{
-		tokenProvider: tokenProvider,
+		tokenProvider:      tokenProvider,
+		revocationRepo:     revocationRepo,
+		accessTokenService: accessTokenService,
 	}
 }
 
 type AuthMiddleware struct {
-	tokenProvider ports.TokenProvider
+	tokenProvider      ports.TokenProvider
+	revocationRepo     ports.RevocationRepo
+	accessTokenService ports.AccessTokenService
 }
 
 func getBearerToken(r *http.Request) (string, error) {
@@ -27,25 +37,114 @@ func getBearerToken(r *http.Request) (string, error) {
 	return authHeader[7:], nil
 }
 
+// isAuthenticatedMiddleware lets through signed in users only, personal
+// access tokens are refused.
 func (am AuthMiddleware) isAuthenticatedMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return am.authenticate("", next)
+}
+
+// isAuthorizedMiddleware lets through signed in users and personal access
+// tokens with scope.
+func (am AuthMiddleware) isAuthorizedMiddleware(scope string, next http.HandlerFunc) http.HandlerFunc {
+	return am.authenticate(scope, next)
+}
+
+// authenticate puts the claims of the bearer token in the context. Personal
+// access tokens are only accepted when they have scope, which must not be
+// empty.
+func (am AuthMiddleware) authenticate(scope string, next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		token, err := getBearerToken(r)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusUnauthorized)
+			writeError(w, r, unauthenticated(err.Error(), "invalid_authorization_header"))
+			return
 		}
 		if token == "" {
-			http.Error(w, "missing token", http.StatusUnauthorized)
+			writeError(w, r, unauthenticated("missing token", "missing_token"))
 			return
 		}
 
-		claims, err := am.tokenProvider.ValidateToken(token)
-		if err != nil {
-			http.Error(w, "invalid token", http.StatusUnauthorized)
+		var claims models.Claims
+		var appErr *errr.AppError
+		if strings.HasPrefix(token, models.AccessTokenPrefix) {
+			claims, appErr = am.authenticateAccessToken(token, scope)
+		} else {
+			claims, appErr = am.authenticateJWT(token)
+		}
+		if appErr != nil {
+			writeError(w, r, appErr)
 			return
 		}
 
 		r = r.WithContext(context.WithValue(r.Context(), "claims", claims))
 		next.ServeHTTP(w, r)
-		// return claims, nil
 	}
 }
+
+func (am AuthMiddleware) authenticateJWT(token string) (models.Claims, *errr.AppError) {
+	claims, err := am.tokenProvider.ValidateToken(token)
+	if err != nil {
+		return models.Claims{}, unauthenticated("invalid token", "invalid_token")
+	}
+
+	revoked, appErr := am.revocationRepo.IsRevoked(claims)
+	if appErr != nil {
+		return models.Claims{}, appErr
+	}
+	if revoked {
+		return models.Claims{}, unauthenticated("token has been revoked", "revoked_token")
+	}
+
+	return claims, nil
+}
+
+func (am AuthMiddleware) authenticateAccessToken(token string, scope string) (models.Claims, *errr.AppError) {
+	if scope == "" {
+		return models.Claims{}, insufficientScope("Personal access tokens can't be used here")
+	}
+
+	claims, appErr := am.accessTokenService.Authenticate(token)
+	if appErr != nil {
+		if appErr.Code == http.StatusUnauthorized {
+			appErr.Reason = "invalid_token"
+		}
+		return models.Claims{}, appErr
+	}
+	if !claims.HasScope(scope) {
+		return models.Claims{}, insufficientScope("This requires the " + scope + " scope")
+	}
+
+	return claims, nil
+}
+
+// hasRoleMiddleware lets through only the users with role. It runs inside
+// isAuthenticatedMiddleware, which puts the claims in the context.
+func (am AuthMiddleware) hasRoleMiddleware(role string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := r.Context().Value("claims").(models.Claims)
+		if !ok || claims.Role != role {
+			appErr := errr.NewUnauthorizedError("This requires the " + role + " role")
+			appErr.Reason = "insufficient_role"
+			writeError(w, r, appErr)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	}
+}
+
+// insufficientScope is a 403 for personal access tokens without the scope
+// of the route.
+func insufficientScope(message string) *errr.AppError {
+	appErr := errr.NewUnauthorizedError(message)
+	appErr.Reason = "insufficient_scope"
+	return appErr
+}
+
+// unauthenticated is a 401 telling the client why its credentials were
+// refused.
+func unauthenticated(message string, reason string) *errr.AppError {
+	appErr := errr.NewUnauthenticatedError(message)
+	appErr.Reason = reason
+	return appErr
+}
@@ -0,0 +1,168 @@
+package http
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+)
+
+const (
+	problemContentType = "application/problem+json"
+	requestIDHeader    = "X-Request-ID"
+	// maxRequestIDLength caps the request ids accepted from clients.
+	maxRequestIDLength = 64
+)
+
+// problem is an RFC 7807 problem details object. Code, Errors and TraceID are
+// extension members.
+type problem struct {
+	Type    string            `json:"type"`
+	Title   string            `json:"title"`
+	Status  int               `json:"status"`
+	Detail  string            `json:"detail,omitempty"`
+	Code    string            `json:"code"`
+	Errors  []errr.FieldError `json:"errors,omitempty"`
+	TraceID string            `json:"trace_id,omitempty"`
+}
+
+// writeError renders appErr as application/problem+json. The trace id is the
+// one of the request unless appErr carries its own.
+func writeError(w http.ResponseWriter, r *http.Request, appErr *errr.AppError) {
+	p := newProblem(r, appErr)
+	if p.Status >= http.StatusInternalServerError {
+		log.Printf("request %s failed: %s", p.TraceID, appErr.Message)
+	}
+
+	body, _ := json.Marshal(p)
+
+	w.Header().Set("Content-Type", problemContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	w.Write(append(body, '\n'))
+}
+
+// newProblem describes appErr, an error of the request r.
+func newProblem(r *http.Request, appErr *errr.AppError) problem {
+	status := appErr.Code
+	if http.StatusText(status) == "" || status < http.StatusBadRequest {
+		status = http.StatusInternalServerError
+	}
+	traceID := appErr.TraceID
+	if traceID == "" {
+		traceID, _ = r.Context().Value("requestID").(string)
+	}
+
+	return problem{
+		Type:    "about:blank",
+		Title:   http.StatusText(status),
+		Status:  status,
+		Detail:  appErr.Message,
+		Code:    appErr.ReasonCode(),
+		Errors:  appErr.Fields,
+		TraceID: traceID,
+	}
+}
+
+// withRequestID tags every request with an id, the one the client sent in
+// X-Request-ID when it is usable or a random one, and echoes it back. Error
+// responses carry it as their trace id.
+func withRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if !isValidRequestID(id) {
+			id = newRequestID()
+		}
+
+		w.Header().Set(requestIDHeader, id)
+		r = r.WithContext(context.WithValue(r.Context(), "requestID", id))
+		next.ServeHTTP(w, r)
+	})
+}
+
+// withProblems answers the requests no route of mux matches with problem
+// details, where http.ServeMux answers in plain text. The 405 keeps the Allow
+// header of the mux.
+func withProblems(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler, pattern := mux.Handler(r)
+		if pattern != "" {
+			mux.ServeHTTP(w, r)
+			return
+		}
+
+		// Without a pattern the handler is one of the mux, which only sets
+		// headers and a status worth keeping.
+		rec := &statusRecorder{header: http.Header{}}
+		handler.ServeHTTP(rec, r)
+		switch rec.status {
+		case http.StatusNotFound:
+			writeError(w, r, errr.NewNotFoundError("No route matches "+r.URL.Path))
+		case http.StatusMethodNotAllowed:
+			w.Header().Set("Allow", rec.header.Get("Allow"))
+			writeError(w, r, &errr.AppError{
+				Code:    http.StatusMethodNotAllowed,
+				Message: "Method " + r.Method + " is not allowed, use " + rec.header.Get("Allow"),
+			})
+		default:
+			handler.ServeHTTP(w, r)
+		}
+	})
+}
+
+// statusRecorder keeps the headers and status of a response, dropping its
+// body.
+type statusRecorder struct {
+	header http.Header
+	status int
+}
+
+func (sr *statusRecorder) Header() http.Header {
+	return sr.header
+}
+
+func (sr *statusRecorder) Write(b []byte) (int, error) {
+	if sr.status == 0 {
+		sr.status = http.StatusOK
+	}
+	return len(b), nil
+}
+
+func (sr *statusRecorder) WriteHeader(status int) {
+	if sr.status == 0 {
+		sr.status = status
+	}
+}
+
+// isValidRequestID reports whether id is short and printable ascii, safe to
+// echo and log.
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < '!' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// invalidBody is the error for a request body that isn't the JSON expected.
+func invalidBody() *errr.AppError {
+	return &errr.AppError{
+		Code:    http.StatusBadRequest,
+		Message: "Invalid Body",
+		Reason:  "invalid_body",
+	}
+}
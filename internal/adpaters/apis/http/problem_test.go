@@ -0,0 +1,278 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/test/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+// problemBody is the problem+json writeError renders for a request without
+// an id.
+func problemBody(status int, code string, detail string) string {
+	return fmt.Sprintf(
+		`{"type":"about:blank","title":%q,"status":%d,"detail":%q,"code":%q}`+"\n",
+		http.StatusText(status), status, detail, code,
+	)
+}
+
+// noRevocations is a revocation store that revokes no token.
+func noRevocations(t *testing.T) *mocks.MockRevocationRepo {
+	mrr := mocks.NewMockRevocationRepo(gomock.NewController(t))
+	mrr.EXPECT().IsRevoked(gomock.Any()).Return(false, nil).AnyTimes()
+	return mrr
+}
+
+func Test_writeError(t *testing.T) {
+	tests := []struct {
+		name      string
+		appErr    *errr.AppError
+		requestID string
+		want      string
+	}{
+		{
+			name:   "reason follows from the status",
+			appErr: errr.NewNotFoundError("no task found with id"),
+			want:   problemBody(http.StatusNotFound, "not_found", "no task found with id"),
+		},
+		{
+			name: "field errors and the request id",
+			appErr: &errr.AppError{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid task",
+				Reason:  "validation_failed",
+				Fields: []errr.FieldError{
+					{Field: "title", Reason: "required", Message: "Title is required"},
+				},
+			},
+			requestID: "abc",
+			want: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Invalid task",` +
+				`"code":"validation_failed","errors":[{"field":"title","code":"required",` +
+				`"message":"Title is required"}],"trace_id":"abc"}` + "\n",
+		},
+		{
+			name:      "trace id of the error wins",
+			appErr:    &errr.AppError{Code: http.StatusConflict, Message: "taken", TraceID: "def"},
+			requestID: "abc",
+			want: `{"type":"about:blank","title":"Conflict","status":409,"detail":"taken",` +
+				`"code":"conflict","trace_id":"def"}` + "\n",
+		},
+		{
+			name:   "unknown status is an internal error",
+			appErr: &errr.AppError{Code: 0, Message: "error message"},
+			want: `{"type":"about:blank","title":"Internal Server Error","status":500,` +
+				`"detail":"error message","code":"error"}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+			if tt.requestID != "" {
+				req = req.WithContext(context.WithValue(req.Context(), "requestID", tt.requestID))
+			}
+			rr := httptest.NewRecorder()
+
+			writeError(rr, req, tt.appErr)
+			if got := rr.Header().Get("Content-Type"); got != "application/problem+json" {
+				t.Errorf("wanted content type application/problem+json, got %s.", got)
+			}
+			if rr.Body.String() != tt.want {
+				t.Errorf("wanted response body: %s, got %s.", tt.want, rr.Body)
+			}
+		})
+	}
+}
+
+func Test_withRequestID(t *testing.T) {
+	tests := []struct {
+		name      string
+		requestID string
+		keep      bool
+	}{
+		{name: "id of the client is kept", requestID: "req-42", keep: true},
+		{name: "missing id is generated"},
+		{name: "unprintable id is replaced", requestID: "a\tb"},
+		{name: "long id is replaced", requestID: strings.Repeat("a", 65)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+			req.Header.Set("X-Request-ID", tt.requestID)
+			rr := httptest.NewRecorder()
+
+			var seen string
+			withRequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen, _ = r.Context().Value("requestID").(string)
+			})).ServeHTTP(rr, req)
+
+			got := rr.Header().Get("X-Request-ID")
+			if got != seen || got == "" {
+				t.Errorf("echoed id %q, handler saw %q", got, seen)
+			}
+			if tt.keep != (got == tt.requestID) {
+				t.Errorf("request id = %q, sent %q", got, tt.requestID)
+			}
+		})
+	}
+}
+
+func Test_withProblems(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /tasks", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("DELETE /tasks/{id}", func(w http.ResponseWriter, r *http.Request) {})
+	handler := withRequestID(withProblems(mux))
+
+	tests := []struct {
+		name         string
+		method       string
+		target       string
+		status       int
+		allow        string
+		responseBody string
+	}{
+		{
+			name:   "matching route",
+			method: http.MethodGet,
+			target: "/tasks",
+			status: http.StatusNoContent,
+		},
+		{
+			name:   "unknown route",
+			method: http.MethodGet,
+			target: "/nothing",
+			status: http.StatusNotFound,
+			responseBody: `{"type":"about:blank","title":"Not Found","status":404,` +
+				`"detail":"No route matches /nothing","code":"not_found","trace_id":"req-42"}` + "\n",
+		},
+		{
+			name:   "method of another route",
+			method: http.MethodPost,
+			target: "/tasks/7",
+			status: http.StatusMethodNotAllowed,
+			allow:  "DELETE",
+			responseBody: `{"type":"about:blank","title":"Method Not Allowed","status":405,` +
+				`"detail":"Method POST is not allowed, use DELETE","code":"method_not_allowed","trace_id":"req-42"}` + "\n",
+		},
+		{
+			name:   "path that isn't clean is redirected",
+			method: http.MethodGet,
+			target: "/tasks/../tasks",
+			status: http.StatusTemporaryRedirect,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			req.Header.Set("X-Request-ID", "req-42")
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tt.status {
+				t.Errorf("status = %d, want %d", rr.Code, tt.status)
+			}
+			if got := rr.Header().Get("Allow"); got != tt.allow {
+				t.Errorf("Allow = %q, want %q", got, tt.allow)
+			}
+			if tt.responseBody == "" {
+				return
+			}
+			if got := rr.Header().Get("Content-Type"); got != "application/problem+json" {
+				t.Errorf("Content-Type = %q, want application/problem+json", got)
+			}
+			if rr.Body.String() != tt.responseBody {
+				t.Errorf("body = %s, want %s", rr.Body.String(), tt.responseBody)
+			}
+		})
+	}
+}
+
+func TestAuthMiddleware_isAuthenticatedMiddleware(t *testing.T) {
+	tests := []struct {
+		name          string
+		authorization string
+		setupMTP      func(*mocks.MockTokenProvider)
+		revoked       bool
+		wantStatus    int
+		responseBody  string
+	}{
+		{
+			name:          "no bearer token",
+			authorization: "Basic abc",
+			setupMTP:      func(mtp *mocks.MockTokenProvider) {},
+			wantStatus:    http.StatusUnauthorized,
+			responseBody: problemBody(
+				http.StatusUnauthorized, "invalid_authorization_header", "Invalid authorization header format",
+			),
+		},
+		{
+			name:          "empty token",
+			authorization: "Bearer ",
+			setupMTP:      func(mtp *mocks.MockTokenProvider) {},
+			wantStatus:    http.StatusUnauthorized,
+			responseBody:  problemBody(http.StatusUnauthorized, "missing_token", "missing token"),
+		},
+		{
+			name:          "invalid token",
+			authorization: "Bearer token",
+			setupMTP: func(mtp *mocks.MockTokenProvider) {
+				mtp.EXPECT().ValidateToken("token").Return(models.Claims{}, fmt.Errorf("expired"))
+			},
+			wantStatus:   http.StatusUnauthorized,
+			responseBody: problemBody(http.StatusUnauthorized, "invalid_token", "invalid token"),
+		},
+		{
+			name:          "valid token",
+			authorization: "Bearer token",
+			setupMTP: func(mtp *mocks.MockTokenProvider) {
+				mtp.EXPECT().ValidateToken("token").Return(models.Claims{ID: 4321}, nil)
+			},
+			wantStatus:   http.StatusOK,
+			responseBody: "4321",
+		},
+		{
+			name:          "revoked token",
+			authorization: "Bearer token",
+			setupMTP: func(mtp *mocks.MockTokenProvider) {
+				mtp.EXPECT().ValidateToken("token").Return(models.Claims{ID: 4321, TokenID: "jti"}, nil)
+			},
+			revoked:      true,
+			wantStatus:   http.StatusUnauthorized,
+			responseBody: problemBody(http.StatusUnauthorized, "revoked_token", "token has been revoked"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+			req.Header.Set("Authorization", tt.authorization)
+			rr := httptest.NewRecorder()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockTokenProvider := mocks.NewMockTokenProvider(ctrl)
+			tt.setupMTP(mockTokenProvider)
+
+			mockRevocationRepo := mocks.NewMockRevocationRepo(ctrl)
+			mockRevocationRepo.EXPECT().IsRevoked(gomock.Any()).Return(tt.revoked, nil).AnyTimes()
+
+			am := NewAuthMiddleware(mockTokenProvider, mockRevocationRepo, nil)
+			am.isAuthenticatedMiddleware(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, r.Context().Value("claims").(models.Claims).ID)
+			})(rr, req)
+			if rr.Code != tt.wantStatus {
+				t.Errorf("wanted status code %d, got %d.", tt.wantStatus, rr.Code)
+			}
+			if rr.Body.String() != tt.responseBody {
+				t.Errorf("wanted response body: %s, got %s.", tt.responseBody, rr.Body)
+			}
+		})
+	}
+}
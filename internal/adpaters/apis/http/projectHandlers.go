@@ -0,0 +1,126 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/internal/ports"
+)
+
+type projectHandler struct {
+	ps ports.ProjectService
+}
+
+func newProjectHandler(ps ports.ProjectService) *projectHandler {
+	return &projectHandler{
+		ps,
+	}
+}
+
+func (ph projectHandler) GetProjectsHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(models.Claims)
+	if !ok {
+		writeError(w, r, errr.NewUnexpectedError("Unexpected error in authentication"))
+		return
+	}
+
+	projectRes, appErr := ph.ps.GetProjects(claims)
+	if appErr != nil {
+		writeError(w, r, appErr)
+		return
+	}
+
+	projectsjson, _ := json.Marshal(projectRes)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(projectsjson)
+}
+
+func (ph projectHandler) GetProjectHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(models.Claims)
+	if !ok {
+		writeError(w, r, errr.NewUnexpectedError("Unexpected error in authentication"))
+		return
+	}
+	id := r.PathValue("id")
+
+	projectRes, appErr := ph.ps.GetProject(id, claims)
+	if appErr != nil {
+		writeError(w, r, appErr)
+		return
+	}
+
+	projectjson, _ := json.Marshal(projectRes)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(projectjson)
+}
+
+func (ph projectHandler) CreateProjectHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(models.Claims)
+	if !ok {
+		writeError(w, r, errr.NewUnexpectedError("Unexpected error in authentication"))
+		return
+	}
+	var projectReq models.ProjectRequestDto
+	err := json.NewDecoder(r.Body).Decode(&projectReq)
+	if err != nil {
+		writeError(w, r, invalidBody())
+		return
+	}
+
+	projectRes, appErr := ph.ps.CreateProject(projectReq, claims)
+	if appErr != nil {
+		writeError(w, r, appErr)
+		return
+	}
+
+	projectjson, _ := json.Marshal(projectRes)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	w.Write(projectjson)
+}
+
+func (ph projectHandler) UpdateProjectHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(models.Claims)
+	if !ok {
+		writeError(w, r, errr.NewUnexpectedError("Unexpected error in authentication"))
+		return
+	}
+	id := r.PathValue("id")
+
+	var projectReq models.ProjectRequestDto
+	err := json.NewDecoder(r.Body).Decode(&projectReq)
+	if err != nil {
+		writeError(w, r, invalidBody())
+		return
+	}
+
+	appErr := ph.ps.UpdateProject(id, projectReq, claims)
+	if appErr != nil {
+		writeError(w, r, appErr)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (ph projectHandler) DeleteProjectHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(models.Claims)
+	if !ok {
+		writeError(w, r, errr.NewUnexpectedError("Unexpected error in authentication"))
+		return
+	}
+	id := r.PathValue("id")
+
+	appErr := ph.ps.DeleteProject(id, claims)
+	if appErr != nil {
+		writeError(w, r, appErr)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,133 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/test/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+func Test_projectHandler_CreateProjectHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		setupMPS     func(*mocks.MockProjectService)
+		requestBody  io.Reader
+		wantStatus   int
+		responseBody string
+	}{
+		{
+			name: "successfully created project",
+			setupMPS: func(mps *mocks.MockProjectService) {
+				mps.EXPECT().CreateProject(
+					models.ProjectRequestDto{Name: "work", Color: "#1e90ff"},
+					models.Claims{ID: 4321},
+				).Return(models.ProjectResponseDto{ID: "7", Name: "work", Color: "#1e90ff"}, nil)
+			},
+			requestBody:  strings.NewReader(`{"name":"work","color":"#1e90ff"}`),
+			wantStatus:   http.StatusCreated,
+			responseBody: `{"id":"7","name":"work","color":"#1e90ff","archived":false,"position":0}`,
+		},
+		{
+			name:         "invalid body",
+			setupMPS:     func(mps *mocks.MockProjectService) {},
+			requestBody:  strings.NewReader(`{"name":`),
+			wantStatus:   http.StatusBadRequest,
+			responseBody: problemBody(http.StatusBadRequest, "invalid_body", "Invalid Body"),
+		},
+		{
+			name: "project service returns error",
+			setupMPS: func(mps *mocks.MockProjectService) {
+				mps.EXPECT().CreateProject(gomock.Any(), gomock.Any()).Return(
+					models.ProjectResponseDto{},
+					&errr.AppError{Code: http.StatusConflict, Message: "project already exists"},
+				)
+			},
+			requestBody:  strings.NewReader(`{"name":"work","color":"#1e90ff"}`),
+			wantStatus:   http.StatusConflict,
+			responseBody: problemBody(http.StatusConflict, "conflict", "project already exists"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/projects", tt.requestBody)
+			req = req.WithContext(context.WithValue(req.Context(), "claims", models.Claims{
+				ID: 4321,
+			}))
+			rr := httptest.NewRecorder()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockProjectService := mocks.NewMockProjectService(ctrl)
+			tt.setupMPS(mockProjectService)
+			ph := newProjectHandler(mockProjectService)
+			ph.CreateProjectHandler(rr, req)
+			if rr.Code != tt.wantStatus {
+				t.Errorf("wanted status code %d, got %d.", tt.wantStatus, rr.Code)
+			}
+			if rr.Body.String() != tt.responseBody {
+				t.Errorf("wanted response body: %s, got %s.", tt.responseBody, rr.Body)
+			}
+		})
+	}
+}
+
+func Test_taskHandler_GetProjectTasksHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		setupMTS     func(*mocks.MockTaskService)
+		wantStatus   int
+		responseBody string
+	}{
+		{
+			name: "lists tasks of the project",
+			setupMTS: func(mts *mocks.MockTaskService) {
+				mts.EXPECT().GetProjectTasks(
+					"7", models.Claims{ID: 4321}, models.TaskFilterDto{Label: "3"},
+				).Return(models.TaskPageDto{Tasks: []models.TaskResponseDto{}}, nil)
+			},
+			wantStatus:   http.StatusOK,
+			responseBody: `{"tasks":[]}`,
+		},
+		{
+			name: "project of another user",
+			setupMTS: func(mts *mocks.MockTaskService) {
+				mts.EXPECT().GetProjectTasks("7", gomock.Any(), gomock.Any()).Return(
+					models.TaskPageDto{},
+					&errr.AppError{Code: http.StatusForbidden, Message: "Unauthorized to view project"},
+				)
+			},
+			wantStatus:   http.StatusForbidden,
+			responseBody: problemBody(http.StatusForbidden, "forbidden", "Unauthorized to view project"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/projects/7/tasks?label=3", nil)
+			req.SetPathValue("id", "7")
+			req = req.WithContext(context.WithValue(req.Context(), "claims", models.Claims{
+				ID: 4321,
+			}))
+			rr := httptest.NewRecorder()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockTaskService := mocks.NewMockTaskService(ctrl)
+			tt.setupMTS(mockTaskService)
+			th := newTaskHandler(mockTaskService)
+			th.GetProjectTasksHandler(rr, req)
+			if rr.Code != tt.wantStatus {
+				t.Errorf("wanted status code %d, got %d.", tt.wantStatus, rr.Code)
+			}
+			if rr.Body.String() != tt.responseBody {
+				t.Errorf("wanted response body: %s, got %s.", tt.responseBody, rr.Body)
+			}
+		})
+	}
+}
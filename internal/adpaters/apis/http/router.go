@@ -2,35 +2,258 @@ package http
 
 import (
 	"net/http"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
 )
 
 func newRouter(
 	taskHandler *taskHandler,
+	labelHandler *labelHandler,
+	projectHandler *projectHandler,
+	workflowHandler *workflowHandler,
 	userHandler *userHandler,
 	authHandler *authHandler,
+	adminHandler *adminHandler,
+	accessTokenHandler *accessTokenHandler,
+	keyHandler *keyHandler,
 	authMiddleware *AuthMiddleware,
 ) http.Handler {
 	mux := http.NewServeMux()
 
 	mux.HandleFunc(
 		"GET /tasks",
-		authMiddleware.isAuthenticatedMiddleware(taskHandler.GetTasksHandler),
+		authMiddleware.isAuthorizedMiddleware(models.ScopeTasksRead, taskHandler.GetTasksHandler),
+	)
+	mux.HandleFunc(
+		"GET /tasks/search",
+		authMiddleware.isAuthorizedMiddleware(
+			models.ScopeTasksRead,
+			taskHandler.SearchTasksHandler,
+		),
+	)
+	mux.HandleFunc(
+		"GET /tasks/{id}",
+		authMiddleware.isAuthorizedMiddleware(models.ScopeTasksRead, taskHandler.GetTaskHandler),
+	)
+	mux.HandleFunc(
+		"GET /tasks/{id}/occurrences",
+		authMiddleware.isAuthorizedMiddleware(
+			models.ScopeTasksRead,
+			taskHandler.GetOccurrencesHandler,
+		),
 	)
 	mux.HandleFunc(
 		"POST /tasks",
-		authMiddleware.isAuthenticatedMiddleware(taskHandler.CreateTaskHandler),
+		authMiddleware.isAuthorizedMiddleware(
+			models.ScopeTasksWrite,
+			taskHandler.CreateTaskHandler,
+		),
 	)
 	mux.HandleFunc(
 		"PUT /tasks/{id}",
-		authMiddleware.isAuthenticatedMiddleware(taskHandler.UpdateTaskHandler),
+		authMiddleware.isAuthorizedMiddleware(
+			models.ScopeTasksWrite,
+			taskHandler.UpdateTaskHandler,
+		),
+	)
+	mux.HandleFunc(
+		"POST /tasks/batch",
+		authMiddleware.isAuthorizedMiddleware(
+			models.ScopeTasksWrite,
+			taskHandler.BatchTasksHandler,
+		),
+	)
+	mux.HandleFunc(
+		"PATCH /tasks/{id}",
+		authMiddleware.isAuthorizedMiddleware(models.ScopeTasksWrite, taskHandler.PatchTaskHandler),
 	)
 	mux.HandleFunc(
 		"DELETE /tasks/{id}",
-		authMiddleware.isAuthenticatedMiddleware(taskHandler.DeleteTaskHandler),
+		authMiddleware.isAuthorizedMiddleware(
+			models.ScopeTasksWrite,
+			taskHandler.DeleteTaskHandler,
+		),
+	)
+	mux.HandleFunc(
+		"POST /tasks/{id}/restore",
+		authMiddleware.isAuthorizedMiddleware(
+			models.ScopeTasksWrite,
+			taskHandler.RestoreTaskHandler,
+		),
+	)
+	mux.HandleFunc(
+		"GET /trash",
+		authMiddleware.isAuthorizedMiddleware(models.ScopeTasksRead, taskHandler.GetTrashHandler),
+	)
+	mux.HandleFunc(
+		"DELETE /trash/{id}",
+		authMiddleware.isAuthorizedMiddleware(models.ScopeTasksWrite, taskHandler.PurgeTaskHandler),
+	)
+
+	mux.HandleFunc(
+		"GET /labels",
+		authMiddleware.isAuthorizedMiddleware(models.ScopeTasksRead, labelHandler.GetLabelsHandler),
+	)
+	mux.HandleFunc(
+		"POST /labels",
+		authMiddleware.isAuthorizedMiddleware(
+			models.ScopeTasksWrite,
+			labelHandler.CreateLabelHandler,
+		),
+	)
+	mux.HandleFunc(
+		"PUT /labels/{id}",
+		authMiddleware.isAuthorizedMiddleware(
+			models.ScopeTasksWrite,
+			labelHandler.UpdateLabelHandler,
+		),
+	)
+	mux.HandleFunc(
+		"DELETE /labels/{id}",
+		authMiddleware.isAuthorizedMiddleware(
+			models.ScopeTasksWrite,
+			labelHandler.DeleteLabelHandler,
+		),
+	)
+
+	mux.HandleFunc(
+		"GET /projects",
+		authMiddleware.isAuthorizedMiddleware(
+			models.ScopeTasksRead,
+			projectHandler.GetProjectsHandler,
+		),
+	)
+	mux.HandleFunc(
+		"GET /projects/{id}",
+		authMiddleware.isAuthorizedMiddleware(
+			models.ScopeTasksRead,
+			projectHandler.GetProjectHandler,
+		),
+	)
+	mux.HandleFunc(
+		"GET /projects/{id}/tasks",
+		authMiddleware.isAuthorizedMiddleware(
+			models.ScopeTasksRead,
+			taskHandler.GetProjectTasksHandler,
+		),
+	)
+	mux.HandleFunc(
+		"POST /projects",
+		authMiddleware.isAuthorizedMiddleware(
+			models.ScopeTasksWrite,
+			projectHandler.CreateProjectHandler,
+		),
+	)
+	mux.HandleFunc(
+		"PUT /projects/{id}",
+		authMiddleware.isAuthorizedMiddleware(
+			models.ScopeTasksWrite,
+			projectHandler.UpdateProjectHandler,
+		),
+	)
+	mux.HandleFunc(
+		"DELETE /projects/{id}",
+		authMiddleware.isAuthorizedMiddleware(
+			models.ScopeTasksWrite,
+			projectHandler.DeleteProjectHandler,
+		),
+	)
+
+	mux.HandleFunc(
+		"GET /workflow",
+		authMiddleware.isAuthorizedMiddleware(
+			models.ScopeTasksRead,
+			workflowHandler.GetWorkflowHandler,
+		),
+	)
+	mux.HandleFunc(
+		"PUT /workflow",
+		authMiddleware.isAuthorizedMiddleware(
+			models.ScopeTasksWrite,
+			workflowHandler.UpdateWorkflowHandler,
+		),
 	)
 
 	mux.HandleFunc("POST /users", userHandler.CreateUserHandler)
 	mux.HandleFunc("POST /auth", authHandler.Login)
+	mux.HandleFunc("POST /auth/refresh", authHandler.Refresh)
+	mux.HandleFunc("GET /.well-known/jwks.json", keyHandler.GetJWKSHandler)
+	mux.HandleFunc(
+		"POST /auth/logout",
+		authMiddleware.isAuthenticatedMiddleware(authHandler.Logout),
+	)
+	mux.HandleFunc(
+		"POST /auth/logout-all",
+		authMiddleware.isAuthenticatedMiddleware(authHandler.LogoutAll),
+	)
+
+	mux.HandleFunc(
+		"POST /users/me/tokens",
+		authMiddleware.isAuthenticatedMiddleware(accessTokenHandler.CreateAccessTokenHandler),
+	)
+	mux.HandleFunc(
+		"GET /users/me/tokens",
+		authMiddleware.isAuthenticatedMiddleware(accessTokenHandler.GetAccessTokensHandler),
+	)
+	mux.HandleFunc(
+		"DELETE /users/me/tokens/{id}",
+		authMiddleware.isAuthenticatedMiddleware(accessTokenHandler.DeleteAccessTokenHandler),
+	)
+
+	mux.HandleFunc(
+		"GET /admin/users",
+		authMiddleware.isAuthenticatedMiddleware(
+			adminHandler.auditDenials(
+				models.AuditListUsers,
+				authMiddleware.hasRoleMiddleware(models.RoleAdmin, adminHandler.GetUsersHandler),
+			),
+		),
+	)
+	mux.HandleFunc(
+		"POST /admin/users/{id}/disable",
+		authMiddleware.isAuthenticatedMiddleware(
+			adminHandler.auditDenials(
+				models.AuditDisableUser,
+				authMiddleware.hasRoleMiddleware(models.RoleAdmin, adminHandler.DisableUserHandler),
+			),
+		),
+	)
+	mux.HandleFunc(
+		"POST /admin/users/{id}/enable",
+		authMiddleware.isAuthenticatedMiddleware(
+			adminHandler.auditDenials(
+				models.AuditEnableUser,
+				authMiddleware.hasRoleMiddleware(models.RoleAdmin, adminHandler.EnableUserHandler),
+			),
+		),
+	)
+	mux.HandleFunc(
+		"POST /admin/users/{id}/password",
+		authMiddleware.isAuthenticatedMiddleware(
+			adminHandler.auditDenials(
+				models.AuditResetPassword,
+				authMiddleware.hasRoleMiddleware(models.RoleAdmin, adminHandler.ResetPasswordHandler),
+			),
+		),
+	)
+	mux.HandleFunc(
+		"GET /admin/users/{id}/tasks",
+		authMiddleware.isAuthenticatedMiddleware(
+			adminHandler.auditDenials(
+				models.AuditViewTasks,
+				authMiddleware.hasRoleMiddleware(models.RoleAdmin, adminHandler.GetUserTasksHandler),
+			),
+		),
+	)
+	mux.HandleFunc(
+		"GET /admin/audit",
+		authMiddleware.isAuthenticatedMiddleware(
+			adminHandler.auditDenials(
+				models.AuditViewAuditLog,
+				authMiddleware.hasRoleMiddleware(models.RoleAdmin, adminHandler.GetAuditLogHandler),
+			),
+		),
+	)
 
-	return mux
+	return withProblems(mux)
 }
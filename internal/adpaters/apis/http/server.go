@@ -8,30 +8,65 @@ import (
 
 func NewHttpServer(
 	taskService ports.TaskService,
+	labelService ports.LabelService,
+	projectService ports.ProjectService,
+	workflowService ports.WorkflowService,
 	userService ports.UserService,
 	authService ports.AuthService,
+	adminService ports.AdminService,
+	accessTokenService ports.AccessTokenService,
 	tokenProvider ports.TokenProvider,
+	revocationRepo ports.RevocationRepo,
 ) httpServer {
 	return httpServer{
-		taskService:   taskService,
-		userService:   userService,
-		tokenProvider: tokenProvider,
-		authService:   authService,
+		taskService:        taskService,
+		labelService:       labelService,
+		projectService:     projectService,
+		workflowService:    workflowService,
+		userService:        userService,
+		tokenProvider:      tokenProvider,
+		revocationRepo:     revocationRepo,
+		authService:        authService,
+		adminService:       adminService,
+		accessTokenService: accessTokenService,
 	}
 }
 
 type httpServer struct {
-	taskService   ports.TaskService
-	userService   ports.UserService
-	tokenProvider ports.TokenProvider
-	authService   ports.AuthService
+	taskService        ports.TaskService
+	labelService       ports.LabelService
+	projectService     ports.ProjectService
+	workflowService    ports.WorkflowService
+	userService        ports.UserService
+	tokenProvider      ports.TokenProvider
+	revocationRepo     ports.RevocationRepo
+	authService        ports.AuthService
+	adminService       ports.AdminService
+	accessTokenService ports.AccessTokenService
 }
 
 func (hs httpServer) ListenAndServe(addr string) {
 	taskHandler := newTaskHandler(hs.taskService)
+	labelHandler := newLabelHandler(hs.labelService)
+	projectHandler := newProjectHandler(hs.projectService)
+	workflowHandler := newWorkflowHandler(hs.workflowService)
 	userHandler := NewUserHandler(hs.userService)
 	authHandler := NewAuthHandler(hs.authService)
-	authMiddleware := NewAuthMiddleware(hs.tokenProvider)
-	router := newRouter(taskHandler, userHandler, authHandler, authMiddleware)
-	http.ListenAndServe(addr, router)
+	adminHandler := newAdminHandler(hs.adminService)
+	accessTokenHandler := newAccessTokenHandler(hs.accessTokenService)
+	keyHandler := newKeyHandler(hs.tokenProvider)
+	authMiddleware := NewAuthMiddleware(hs.tokenProvider, hs.revocationRepo, hs.accessTokenService)
+	router := newRouter(
+		taskHandler,
+		labelHandler,
+		projectHandler,
+		workflowHandler,
+		userHandler,
+		authHandler,
+		adminHandler,
+		accessTokenHandler,
+		keyHandler,
+		authMiddleware,
+	)
+	http.ListenAndServe(addr, withRequestID(router))
 }
@@ -2,12 +2,21 @@ package http
 
 import (
 	"encoding/json"
+	"io"
+	"mime"
 	"net/http"
+	"net/url"
 
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
 	"github.com/Jashanveer-Singh/todo-go/internal/models"
 	"github.com/Jashanveer-Singh/todo-go/internal/ports"
 )
 
+const (
+	mergePatchContentType = "application/merge-patch+json"
+	jsonPatchContentType  = "application/json-patch+json"
+)
+
 type taskHandler struct {
 	ts ports.TaskService
 }
@@ -21,78 +30,357 @@ func newTaskHandler(ts ports.TaskService) *taskHandler {
 func (th taskHandler) GetTasksHandler(w http.ResponseWriter, r *http.Request) {
 	claims, ok := r.Context().Value("claims").(models.Claims)
 	if !ok {
-		http.Error(w, "Unexpected error in authentication", http.StatusInternalServerError)
+		writeError(w, r, errr.NewUnexpectedError("Unexpected error in authentication"))
+		return
+	}
+	filter := taskFilter(r.URL.Query())
+	filter.Project = r.URL.Query().Get("project")
+	page, appErr := th.ts.GetTasks(claims, filter)
+
+	if appErr != nil {
+		writeError(w, r, appErr)
+		return
+	}
+
+	pagejson, _ := json.Marshal(page)
+
+	etag := bodyETag(pagejson)
+	w.Header().Set("ETag", etag)
+	if !noneMatch(r, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(pagejson)
+}
+
+// taskFilter reads the filters, ordering and paging of a task listing from
+// the query string.
+func taskFilter(query url.Values) models.TaskFilterDto {
+	return models.TaskFilterDto{
+		DueBefore: query.Get("due_before"),
+		DueAfter:  query.Get("due_after"),
+		Status:    query.Get("status"),
+		Sort:      query.Get("sort"),
+		Order:     query.Get("order"),
+		Label:     query.Get("label"),
+		Limit:     query.Get("limit"),
+		After:     query.Get("after"),
+	}
+}
+
+func (th taskHandler) GetProjectTasksHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(models.Claims)
+	if !ok {
+		writeError(w, r, errr.NewUnexpectedError("Unexpected error in authentication"))
+		return
 	}
-	taskRes, appErr := th.ts.GetTasks(claims)
+	id := r.PathValue("id")
 
+	page, appErr := th.ts.GetProjectTasks(id, claims, taskFilter(r.URL.Query()))
 	if appErr != nil {
-		http.Error(w, appErr.Message, appErr.Code)
+		writeError(w, r, appErr)
 		return
 	}
 
-	tasksjson, _ := json.Marshal(taskRes)
+	pagejson, _ := json.Marshal(page)
 
-	w.Header().Set("content", "application/json")
-	w.Write(tasksjson)
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(pagejson)
+}
+
+func (th taskHandler) SearchTasksHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(models.Claims)
+	if !ok {
+		writeError(w, r, errr.NewUnexpectedError("Unexpected error in authentication"))
+		return
+	}
+
+	results, appErr := th.ts.SearchTasks(
+		r.URL.Query().Get("q"),
+		r.URL.Query().Get("limit"),
+		claims,
+	)
+	if appErr != nil {
+		writeError(w, r, appErr)
+		return
+	}
+
+	resultsjson, _ := json.Marshal(results)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(resultsjson)
+}
+
+func (th taskHandler) GetTaskHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(models.Claims)
+	if !ok {
+		writeError(w, r, errr.NewUnexpectedError("Unexpected error in authentication"))
+		return
+	}
+	id := r.PathValue("id")
+
+	task, appErr := th.ts.GetTask(id, claims)
+	if appErr != nil {
+		writeError(w, r, appErr)
+		return
+	}
+
+	taskjson, _ := json.Marshal(task)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", taskETag(task.Version))
+	w.Write(taskjson)
+}
+
+func (th taskHandler) GetOccurrencesHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(models.Claims)
+	if !ok {
+		writeError(w, r, errr.NewUnexpectedError("Unexpected error in authentication"))
+		return
+	}
+	id := r.PathValue("id")
+
+	occurrences, appErr := th.ts.GetOccurrences(id, r.URL.Query().Get("count"), claims)
+	if appErr != nil {
+		writeError(w, r, appErr)
+		return
+	}
+
+	occurrencesjson, _ := json.Marshal(occurrences)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(occurrencesjson)
 }
 
 func (th taskHandler) UpdateTaskHandler(w http.ResponseWriter, r *http.Request) {
 	claims, ok := r.Context().Value("claims").(models.Claims)
 	if !ok {
-		http.Error(w, "Unexpected error in authentication", http.StatusInternalServerError)
+		writeError(w, r, errr.NewUnexpectedError("Unexpected error in authentication"))
+		return
 	}
 	id := r.PathValue("id")
+	version, appErr := ifMatchVersion(r)
+	if appErr != nil {
+		writeError(w, r, appErr)
+		return
+	}
 
 	var taskReq models.TaskRequestDto
 
 	err := json.NewDecoder(r.Body).Decode(&taskReq)
 	if err != nil {
-		http.Error(w, "Invalid Body", http.StatusBadRequest)
+		writeError(w, r, invalidBody())
 		return
 	}
 
-	appErr := th.ts.UpdateTask(id, taskReq, claims)
+	version, appErr = th.ts.UpdateTask(id, version, taskReq, claims)
 
 	if appErr != nil {
-		http.Error(w, appErr.Message, appErr.Code)
+		writeError(w, r, appErr)
 		return
 	}
 
+	w.Header().Set("ETag", taskETag(version))
 	w.WriteHeader(http.StatusNoContent)
 	w.Write([]byte(""))
 }
 
+// PatchTaskHandler changes a task by a JSON Merge Patch or a JSON Patch, told
+// apart by the content type of the request.
+func (th taskHandler) PatchTaskHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(models.Claims)
+	if !ok {
+		writeError(w, r, errr.NewUnexpectedError("Unexpected error in authentication"))
+		return
+	}
+	id := r.PathValue("id")
+	version, appErr := ifMatchVersion(r)
+	if appErr != nil {
+		writeError(w, r, appErr)
+		return
+	}
+
+	patch := models.TaskPatch{}
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case mergePatchContentType:
+		patch.Format = models.MergePatch
+	case jsonPatchContentType:
+		patch.Format = models.JSONPatch
+	default:
+		w.Header().Set("Accept-Patch", mergePatchContentType+", "+jsonPatchContentType)
+		writeError(w, r, &errr.AppError{
+			Code:    http.StatusUnsupportedMediaType,
+			Message: "Patch must be " + mergePatchContentType + " or " + jsonPatchContentType,
+		})
+		return
+	}
+
+	var err error
+	patch.Doc, err = io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, r, invalidBody())
+		return
+	}
+
+	version, appErr = th.ts.PatchTask(id, version, patch, claims)
+	if appErr != nil {
+		writeError(w, r, appErr)
+		return
+	}
+
+	w.Header().Set("ETag", taskETag(version))
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (th taskHandler) CreateTaskHandler(w http.ResponseWriter, r *http.Request) {
 	claims, ok := r.Context().Value("claims").(models.Claims)
 	if !ok {
-		http.Error(w, "Unexpected error in authentication", http.StatusInternalServerError)
+		writeError(w, r, errr.NewUnexpectedError("Unexpected error in authentication"))
+		return
 	}
 	var taskReq models.TaskRequestDto
 	err := json.NewDecoder(r.Body).Decode(&taskReq)
 	if err != nil {
-		http.Error(w, "Invalid Body", http.StatusBadRequest)
+		writeError(w, r, invalidBody())
 		return
 	}
 
-	appErr := th.ts.CreateTask(taskReq, claims)
+	task, appErr := th.ts.CreateTask(taskReq, claims)
 	if appErr != nil {
-		http.Error(w, appErr.Message, appErr.Code)
+		writeError(w, r, appErr)
 		return
 	}
 
+	taskjson, _ := json.Marshal(task)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", "/tasks/"+task.ID)
+	w.Header().Set("ETag", taskETag(task.Version))
 	w.WriteHeader(http.StatusCreated)
-	w.Write([]byte("task created successfully"))
+	w.Write(taskjson)
+}
+
+// operationResult is the outcome of an operation of a batch as sent to the
+// client, with the status the operation would have answered on its own.
+type operationResult struct {
+	Status int                     `json:"status"`
+	Task   *models.TaskResponseDto `json:"task,omitempty"`
+	Error  *problem                `json:"error,omitempty"`
+}
+
+// BatchTasksHandler runs a batch of task operations. A failed atomic batch
+// answers with the error of the operation that failed it.
+func (th taskHandler) BatchTasksHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(models.Claims)
+	if !ok {
+		writeError(w, r, errr.NewUnexpectedError("Unexpected error in authentication"))
+		return
+	}
+	var batch models.TaskBatchDto
+	err := json.NewDecoder(r.Body).Decode(&batch)
+	if err != nil {
+		writeError(w, r, invalidBody())
+		return
+	}
+
+	results, appErr := th.ts.Batch(batch, claims)
+	if appErr != nil {
+		writeError(w, r, appErr)
+		return
+	}
+
+	body := struct {
+		Results []operationResult `json:"results"`
+	}{Results: make([]operationResult, 0, len(results))}
+	for _, result := range results {
+		item := operationResult{Status: http.StatusNoContent, Task: result.Task}
+		switch {
+		case result.Err != nil:
+			p := newProblem(r, result.Err)
+			item.Status, item.Error = p.Status, &p
+		case result.Op == models.BatchCreate:
+			item.Status = http.StatusCreated
+		}
+		body.Results = append(body.Results, item)
+	}
+	resultsjson, _ := json.Marshal(body)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(resultsjson)
 }
 
 func (th taskHandler) DeleteTaskHandler(w http.ResponseWriter, r *http.Request) {
 	claims, ok := r.Context().Value("claims").(models.Claims)
 	if !ok {
-		http.Error(w, "Unexpected error in authentication", http.StatusInternalServerError)
+		writeError(w, r, errr.NewUnexpectedError("Unexpected error in authentication"))
+		return
 	}
 	id := r.PathValue("id")
-	appErr := th.ts.DeleteTask(id, claims)
+	version, appErr := ifMatchVersion(r)
+	if appErr != nil {
+		writeError(w, r, appErr)
+		return
+	}
+
+	appErr = th.ts.DeleteTask(id, version, claims)
+	if appErr != nil {
+		writeError(w, r, appErr)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	w.Write([]byte(""))
+}
+
+func (th taskHandler) GetTrashHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(models.Claims)
+	if !ok {
+		writeError(w, r, errr.NewUnexpectedError("Unexpected error in authentication"))
+		return
+	}
+
+	trash, appErr := th.ts.GetTrash(claims)
+	if appErr != nil {
+		writeError(w, r, appErr)
+		return
+	}
+
+	trashjson, _ := json.Marshal(trash)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(trashjson)
+}
+
+func (th taskHandler) RestoreTaskHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(models.Claims)
+	if !ok {
+		writeError(w, r, errr.NewUnexpectedError("Unexpected error in authentication"))
+		return
+	}
+
+	appErr := th.ts.RestoreTask(r.PathValue("id"), claims)
+	if appErr != nil {
+		writeError(w, r, appErr)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	w.Write([]byte(""))
+}
+
+func (th taskHandler) PurgeTaskHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(models.Claims)
+	if !ok {
+		writeError(w, r, errr.NewUnexpectedError("Unexpected error in authentication"))
+		return
+	}
+
+	appErr := th.ts.PurgeTask(r.PathValue("id"), claims)
 	if appErr != nil {
-		http.Error(w, appErr.Message, appErr.Code)
+		writeError(w, r, appErr)
 		return
 	}
 
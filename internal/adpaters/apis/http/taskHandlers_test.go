@@ -18,7 +18,7 @@ func Test_taskHandler_GetTasksHandler(t *testing.T) {
 	tests := []struct {
 		name     string
 		setupMTS func(*mocks.MockTaskService)
-		// url          string
+		url      string
 		// requestBody  io.Reader
 		wantStatus   int
 		responseBody string
@@ -26,33 +26,98 @@ func Test_taskHandler_GetTasksHandler(t *testing.T) {
 		{
 			name: "successful response",
 			setupMTS: func(mts *mocks.MockTaskService) {
-				mts.EXPECT().GetTasks(models.Claims{ID: 4321}).Return([]models.TaskResponseDto{
+				mts.EXPECT().GetTasks(models.Claims{ID: 4321}, models.TaskFilterDto{}).Return(models.TaskPageDto{Tasks: []models.TaskResponseDto{
 					{
 						ID:     "1234",
 						Title:  "title",
 						Desc:   "desc",
 						Status: "Pending",
 					},
+				}}, nil)
+			},
+			wantStatus:   http.StatusOK,
+			responseBody: `{"tasks":[{"id":"1234","title":"title","desc":"desc","status":"Pending","overdue":false}]}`,
+		},
+		{
+			name: "filtered by due date and sorted",
+			url:  "/tasks?due_before=2020-01-02T00:00:00Z&due_after=2020-01-01T00:00:00Z&sort=priority",
+			setupMTS: func(mts *mocks.MockTaskService) {
+				mts.EXPECT().GetTasks(models.Claims{ID: 4321}, models.TaskFilterDto{
+					DueBefore: "2020-01-02T00:00:00Z",
+					DueAfter:  "2020-01-01T00:00:00Z",
+					Sort:      "priority",
+				}).Return(models.TaskPageDto{Tasks: []models.TaskResponseDto{
+					{
+						ID:       "1234",
+						Title:    "title",
+						Desc:     "desc",
+						Status:   "Pending",
+						Priority: "High",
+						DueAt:    "2020-01-01T12:00:00Z",
+						Overdue:  true,
+					},
+				}}, nil)
+			},
+			wantStatus:   http.StatusOK,
+			responseBody: `{"tasks":[{"id":"1234","title":"title","desc":"desc","status":"Pending","priority":"High","due_at":"2020-01-01T12:00:00Z","overdue":true}]}`,
+		},
+		{
+			name: "filtered by label",
+			url:  "/tasks?label=7",
+			setupMTS: func(mts *mocks.MockTaskService) {
+				mts.EXPECT().GetTasks(models.Claims{ID: 4321}, models.TaskFilterDto{
+					Label: "7",
+				}).Return(models.TaskPageDto{Tasks: []models.TaskResponseDto{
+					{
+						ID:       "1234",
+						Title:    "title",
+						Desc:     "desc",
+						Status:   "Pending",
+						LabelIDs: []string{"7"},
+					},
+				}}, nil)
+			},
+			wantStatus:   http.StatusOK,
+			responseBody: `{"tasks":[{"id":"1234","title":"title","desc":"desc","status":"Pending","overdue":false,"label_ids":["7"]}]}`,
+		},
+		{
+			name: "paged by status",
+			url:  "/tasks?status=done&sort=title&order=desc&limit=1&after=abc",
+			setupMTS: func(mts *mocks.MockTaskService) {
+				mts.EXPECT().GetTasks(models.Claims{ID: 4321}, models.TaskFilterDto{
+					Status: "done",
+					Sort:   "title",
+					Order:  "desc",
+					Limit:  "1",
+					After:  "abc",
+				}).Return(models.TaskPageDto{
+					Tasks: []models.TaskResponseDto{
+						{ID: "1234", Title: "title", Desc: "desc", Status: "Done"},
+					},
+					NextCursor: "def",
 				}, nil)
 			},
 			wantStatus:   http.StatusOK,
-			responseBody: `[{"id":"1234","title":"title","desc":"desc","status":"Pending"}]`,
+			responseBody: `{"tasks":[{"id":"1234","title":"title","desc":"desc","status":"Done","overdue":false}],"next_cursor":"def"}`,
 		},
 		{
 			name: "task service get task returns error",
 			setupMTS: func(mts *mocks.MockTaskService) {
-				mts.EXPECT().GetTasks(models.Claims{ID: 4321}).Return(nil, &errr.AppError{
+				mts.EXPECT().GetTasks(models.Claims{ID: 4321}, models.TaskFilterDto{}).Return(models.TaskPageDto{}, &errr.AppError{
 					Code:    http.StatusInternalServerError,
 					Message: "error message",
 				})
 			},
 			wantStatus:   http.StatusInternalServerError,
-			responseBody: "error message\n",
+			responseBody: problemBody(http.StatusInternalServerError, "internal_server_error", "error message"),
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+			if tt.url == "" {
+				tt.url = "/tasks"
+			}
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
 			req = req.WithContext(context.WithValue(req.Context(), "claims", models.Claims{
 				ID: 4321,
 			}))
@@ -86,11 +151,11 @@ func Test_taskHandler_UpdateTaskHandler(t *testing.T) {
 		{
 			name: "successful response",
 			setupMTS: func(mts *mocks.MockTaskService) {
-				mts.EXPECT().UpdateTask("1234324", models.TaskRequestDto{
+				mts.EXPECT().UpdateTask("1234324", int64(0), models.TaskRequestDto{
 					Title:  "title",
 					Desc:   "desc",
 					Status: "Pending",
-				}, models.Claims{ID: 4321}).Return(nil)
+				}, models.Claims{ID: 4321}).Return(int64(2), nil)
 			},
 			url: "/tasks/1234324",
 			requestBody: strings.NewReader(`{
@@ -102,43 +167,31 @@ func Test_taskHandler_UpdateTaskHandler(t *testing.T) {
 			responseBody: "",
 		},
 		{
-			name: "only title is updated",
+			name: "fields left out are passed on empty",
 			setupMTS: func(mts *mocks.MockTaskService) {
-				mts.EXPECT().UpdateTask("1234324", models.TaskRequestDto{
+				mts.EXPECT().UpdateTask("1234324", int64(0), models.TaskRequestDto{
 					Title: "title",
-				}, models.Claims{ID: 4321}).Return(nil)
+				}, models.Claims{ID: 4321}).Return(int64(2), nil)
 			},
 			url:          "/tasks/1234324",
 			requestBody:  strings.NewReader(`{"title": "title"}`),
 			wantStatus:   http.StatusNoContent,
 			responseBody: "",
 		},
-		{
-			name: "only description is updated",
-			setupMTS: func(mts *mocks.MockTaskService) {
-				mts.EXPECT().UpdateTask("1234324", models.TaskRequestDto{
-					Desc: "desc",
-				}, models.Claims{ID: 4321}).Return(nil)
-			},
-			url:          "/tasks/1234324",
-			requestBody:  strings.NewReader(`{"desc": "desc"}`),
-			wantStatus:   http.StatusNoContent,
-			responseBody: "",
-		},
 		{
 			name:         "invalid task update request",
 			setupMTS:     func(mts *mocks.MockTaskService) {},
 			url:          "/tasks/1234324",
 			requestBody:  strings.NewReader(`{"desc": 123}`),
 			wantStatus:   http.StatusBadRequest,
-			responseBody: "Invalid Body\n",
+			responseBody: problemBody(http.StatusBadRequest, "invalid_body", "Invalid Body"),
 		},
 		{
 			name: "task service returns error",
 			setupMTS: func(mts *mocks.MockTaskService) {
 				mts.EXPECT().
-					UpdateTask("123", models.TaskRequestDto{}, models.Claims{ID: 4321}).
-					Return(&errr.AppError{
+					UpdateTask("123", int64(0), models.TaskRequestDto{}, models.Claims{ID: 4321}).
+					Return(int64(0), &errr.AppError{
 						Code:    http.StatusBadGateway,
 						Message: "error message",
 					})
@@ -146,7 +199,7 @@ func Test_taskHandler_UpdateTaskHandler(t *testing.T) {
 			url:          "/tasks/123",
 			requestBody:  strings.NewReader("{}"),
 			wantStatus:   http.StatusBadGateway,
-			responseBody: "error message\n",
+			responseBody: problemBody(http.StatusBadGateway, "bad_gateway", "error message"),
 		},
 	}
 	for _, tt := range tests {
@@ -164,11 +217,182 @@ func Test_taskHandler_UpdateTaskHandler(t *testing.T) {
 			mockTokenProvider := mocks.NewMockTokenProvider(tokenProviderCtrl)
 			mockTokenProvider.EXPECT().ValidateToken("token").Return(models.Claims{ID: 4321}, nil)
 
-			am := NewAuthMiddleware(mockTokenProvider)
+			am := NewAuthMiddleware(mockTokenProvider, noRevocations(t), nil)
 			th := newTaskHandler(mockTaskService)
 			uh := NewUserHandler(nil)
 			ah := NewAuthHandler(nil)
-			router := newRouter(th, uh, ah, am)
+			router := newRouter(
+				th, newLabelHandler(nil), newProjectHandler(nil), newWorkflowHandler(nil),
+				uh, ah, newAdminHandler(nil), newAccessTokenHandler(nil), newKeyHandler(nil), am,
+			)
+			router.ServeHTTP(rr, req)
+			if rr.Code != tt.wantStatus {
+				t.Errorf("wanted status code %d, got %d.", tt.wantStatus, rr.Code)
+			}
+			if rr.Body.String() != tt.responseBody {
+				t.Errorf("wanted response body: %s, got %s.", tt.responseBody, rr.Body)
+			}
+		})
+	}
+}
+
+func Test_taskHandler_PatchTaskHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		setupMTS     func(*mocks.MockTaskService)
+		contentType  string
+		requestBody  string
+		wantStatus   int
+		responseBody string
+	}{
+		{
+			name: "merge patch",
+			setupMTS: func(mts *mocks.MockTaskService) {
+				mts.EXPECT().PatchTask("123", int64(0), models.TaskPatch{
+					Format: models.MergePatch,
+					Doc:    []byte(`{"due_at": null}`),
+				}, models.Claims{ID: 4321}).Return(int64(2), nil)
+			},
+			contentType: "application/merge-patch+json",
+			requestBody: `{"due_at": null}`,
+			wantStatus:  http.StatusNoContent,
+		},
+		{
+			name: "json patch",
+			setupMTS: func(mts *mocks.MockTaskService) {
+				mts.EXPECT().PatchTask("123", int64(0), models.TaskPatch{
+					Format: models.JSONPatch,
+					Doc:    []byte(`[{"op": "remove", "path": "/due_at"}]`),
+				}, models.Claims{ID: 4321}).Return(int64(2), nil)
+			},
+			contentType: "application/json-patch+json; charset=utf-8",
+			requestBody: `[{"op": "remove", "path": "/due_at"}]`,
+			wantStatus:  http.StatusNoContent,
+		},
+		{
+			name:        "plain json is not a patch",
+			setupMTS:    func(mts *mocks.MockTaskService) {},
+			contentType: "application/json",
+			requestBody: `{"due_at": null}`,
+			wantStatus:  http.StatusUnsupportedMediaType,
+			responseBody: problemBody(
+				http.StatusUnsupportedMediaType, "unsupported_media_type",
+				"Patch must be application/merge-patch+json or application/json-patch+json",
+			),
+		},
+		{
+			name: "task service returns error",
+			setupMTS: func(mts *mocks.MockTaskService) {
+				mts.EXPECT().PatchTask("123", int64(0), gomock.Any(), models.Claims{ID: 4321}).
+					Return(int64(0), errr.NewDuplicateError("Task does not pass the test of the patch"))
+			},
+			contentType:  "application/json-patch+json",
+			requestBody:  `[{"op": "test", "path": "/title", "value": "title"}]`,
+			wantStatus:   http.StatusConflict,
+			responseBody: problemBody(http.StatusConflict, "conflict", "Task does not pass the test of the patch"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPatch, "/tasks/123", strings.NewReader(tt.requestBody))
+			req.Header.Set("Authorization", "Bearer token")
+			req.Header.Set("Content-Type", tt.contentType)
+			rr := httptest.NewRecorder()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockTaskService := mocks.NewMockTaskService(ctrl)
+			tt.setupMTS(mockTaskService)
+			mockTokenProvider := mocks.NewMockTokenProvider(ctrl)
+			mockTokenProvider.EXPECT().ValidateToken("token").Return(models.Claims{ID: 4321}, nil)
+
+			router := newRouter(
+				newTaskHandler(mockTaskService), newLabelHandler(nil), newProjectHandler(nil),
+				newWorkflowHandler(nil), NewUserHandler(nil), NewAuthHandler(nil),
+				newAdminHandler(nil), newAccessTokenHandler(nil), newKeyHandler(nil),
+				NewAuthMiddleware(mockTokenProvider, noRevocations(t), nil),
+			)
+			router.ServeHTTP(rr, req)
+			if rr.Code != tt.wantStatus {
+				t.Errorf("wanted status code %d, got %d.", tt.wantStatus, rr.Code)
+			}
+			if rr.Body.String() != tt.responseBody {
+				t.Errorf("wanted response body: %s, got %s.", tt.responseBody, rr.Body)
+			}
+		})
+	}
+}
+
+func Test_taskHandler_BatchTasksHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		setupMTS     func(*mocks.MockTaskService)
+		requestBody  string
+		wantStatus   int
+		responseBody string
+	}{
+		{
+			name: "results of every operation",
+			setupMTS: func(mts *mocks.MockTaskService) {
+				mts.EXPECT().Batch(models.TaskBatchDto{
+					Mode: models.BatchPerItem,
+					Operations: []models.TaskOperationDto{
+						{Op: models.BatchCreate, Task: &models.TaskRequestDto{Title: "title", Desc: "desc"}},
+						{Op: models.BatchDelete, ID: "1"},
+						{Op: models.BatchDelete, ID: "2"},
+					},
+				}, models.Claims{ID: 4321}).Return([]models.TaskOperationResult{
+					{Op: models.BatchCreate, Task: &models.TaskResponseDto{ID: "3", Title: "title"}},
+					{Op: models.BatchDelete},
+					{Op: models.BatchDelete, Err: errr.NewNotFoundError("no task found with id")},
+				}, nil)
+			},
+			requestBody: `{"mode": "per_item", "operations": [
+				{"op": "create", "task": {"title": "title", "desc": "desc"}},
+				{"op": "delete", "id": "1"},
+				{"op": "delete", "id": "2"}
+			]}`,
+			wantStatus: http.StatusOK,
+			responseBody: `{"results":[` +
+				`{"status":201,"task":{"id":"3","title":"title","desc":"","status":"","overdue":false}},` +
+				`{"status":204},` +
+				`{"status":404,"error":{"type":"about:blank","title":"Not Found","status":404,"detail":"no task found with id","code":"not_found"}}]}`,
+		},
+		{
+			name: "failed atomic batch",
+			setupMTS: func(mts *mocks.MockTaskService) {
+				mts.EXPECT().Batch(gomock.Any(), models.Claims{ID: 4321}).
+					Return(nil, errr.NewNotFoundError("Operation 1: no task found with id"))
+			},
+			requestBody:  `{"operations": [{"op": "delete", "id": "1"}, {"op": "delete", "id": "2"}]}`,
+			wantStatus:   http.StatusNotFound,
+			responseBody: problemBody(http.StatusNotFound, "not_found", "Operation 1: no task found with id"),
+		},
+		{
+			name:         "invalid body",
+			setupMTS:     func(mts *mocks.MockTaskService) {},
+			requestBody:  `{"operations": {}}`,
+			wantStatus:   http.StatusBadRequest,
+			responseBody: problemBody(http.StatusBadRequest, "invalid_body", "Invalid Body"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/tasks/batch", strings.NewReader(tt.requestBody))
+			req.Header.Set("Authorization", "Bearer token")
+			rr := httptest.NewRecorder()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockTaskService := mocks.NewMockTaskService(ctrl)
+			tt.setupMTS(mockTaskService)
+			mockTokenProvider := mocks.NewMockTokenProvider(ctrl)
+			mockTokenProvider.EXPECT().ValidateToken("token").Return(models.Claims{ID: 4321}, nil)
+
+			router := newRouter(
+				newTaskHandler(mockTaskService), newLabelHandler(nil), newProjectHandler(nil),
+				newWorkflowHandler(nil), NewUserHandler(nil), NewAuthHandler(nil),
+				newAdminHandler(nil), newAccessTokenHandler(nil), newKeyHandler(nil),
+				NewAuthMiddleware(mockTokenProvider, noRevocations(t), nil),
+			)
 			router.ServeHTTP(rr, req)
 			if rr.Code != tt.wantStatus {
 				t.Errorf("wanted status code %d, got %d.", tt.wantStatus, rr.Code)
@@ -191,7 +415,7 @@ func Test_taskHandler_DeleteTaskHandler(t *testing.T) {
 		{
 			name: "successful response",
 			setupMTS: func(mts *mocks.MockTaskService) {
-				mts.EXPECT().DeleteTask("1234", models.Claims{ID: 4321}).Return(nil)
+				mts.EXPECT().DeleteTask("1234", int64(0), models.Claims{ID: 4321}).Return(nil)
 			},
 			url:          "/tasks/1234",
 			wantStatus:   http.StatusNoContent,
@@ -200,14 +424,14 @@ func Test_taskHandler_DeleteTaskHandler(t *testing.T) {
 		{
 			name: "task service returns error",
 			setupMTS: func(mts *mocks.MockTaskService) {
-				mts.EXPECT().DeleteTask("1234", models.Claims{ID: 4321}).Return(&errr.AppError{
+				mts.EXPECT().DeleteTask("1234", int64(0), models.Claims{ID: 4321}).Return(&errr.AppError{
 					Code:    http.StatusBadGateway,
 					Message: "error message",
 				})
 			},
 			url:          "/tasks/1234",
 			wantStatus:   http.StatusBadGateway,
-			responseBody: "error message\n",
+			responseBody: problemBody(http.StatusBadGateway, "bad_gateway", "error message"),
 		},
 	}
 
@@ -226,11 +450,94 @@ func Test_taskHandler_DeleteTaskHandler(t *testing.T) {
 			mockTokenProvider := mocks.NewMockTokenProvider(tokenProviderCtrl)
 			mockTokenProvider.EXPECT().ValidateToken("token").Return(models.Claims{ID: 4321}, nil)
 
-			am := NewAuthMiddleware(mockTokenProvider)
+			am := NewAuthMiddleware(mockTokenProvider, noRevocations(t), nil)
 			th := newTaskHandler(mockTaskService)
 			uh := NewUserHandler(nil)
 			ah := NewAuthHandler(nil)
-			router := newRouter(th, uh, ah, am)
+			router := newRouter(
+				th, newLabelHandler(nil), newProjectHandler(nil), newWorkflowHandler(nil),
+				uh, ah, newAdminHandler(nil), newAccessTokenHandler(nil), newKeyHandler(nil), am,
+			)
+			router.ServeHTTP(rr, req)
+			if rr.Code != tt.wantStatus {
+				t.Errorf("wanted status code %d, got %d.", tt.wantStatus, rr.Code)
+			}
+			if rr.Body.String() != tt.responseBody {
+				t.Errorf("wanted response body: %s, got %s.", tt.responseBody, rr.Body)
+			}
+		})
+	}
+}
+
+func Test_taskHandler_trash(t *testing.T) {
+	tests := []struct {
+		name         string
+		setupMTS     func(*mocks.MockTaskService)
+		method       string
+		target       string
+		wantStatus   int
+		responseBody string
+	}{
+		{
+			name: "list trash",
+			setupMTS: func(mts *mocks.MockTaskService) {
+				mts.EXPECT().GetTrash(models.Claims{ID: 4321}).Return([]models.TaskResponseDto{
+					{ID: "1", Title: "title", DeletedAt: "2025-02-01T12:00:00Z"},
+				}, nil)
+			},
+			method:       http.MethodGet,
+			target:       "/trash",
+			wantStatus:   http.StatusOK,
+			responseBody: `[{"id":"1","title":"title","desc":"","status":"","overdue":false,"deleted_at":"2025-02-01T12:00:00Z"}]`,
+		},
+		{
+			name: "restore task",
+			setupMTS: func(mts *mocks.MockTaskService) {
+				mts.EXPECT().RestoreTask("1", models.Claims{ID: 4321}).Return(nil)
+			},
+			method:     http.MethodPost,
+			target:     "/tasks/1/restore",
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name: "restore subtask of a task in the trash",
+			setupMTS: func(mts *mocks.MockTaskService) {
+				mts.EXPECT().RestoreTask("2", models.Claims{ID: 4321}).
+					Return(errr.NewDuplicateError("Parent task is in the trash, restore it first"))
+			},
+			method:       http.MethodPost,
+			target:       "/tasks/2/restore",
+			wantStatus:   http.StatusConflict,
+			responseBody: problemBody(http.StatusConflict, "conflict", "Parent task is in the trash, restore it first"),
+		},
+		{
+			name: "purge task",
+			setupMTS: func(mts *mocks.MockTaskService) {
+				mts.EXPECT().PurgeTask("1", models.Claims{ID: 4321}).Return(nil)
+			},
+			method:     http.MethodDelete,
+			target:     "/trash/1",
+			wantStatus: http.StatusNoContent,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			req.Header.Set("Authorization", "Bearer token")
+			rr := httptest.NewRecorder()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockTaskService := mocks.NewMockTaskService(ctrl)
+			tt.setupMTS(mockTaskService)
+			mockTokenProvider := mocks.NewMockTokenProvider(ctrl)
+			mockTokenProvider.EXPECT().ValidateToken("token").Return(models.Claims{ID: 4321}, nil)
+
+			router := newRouter(
+				newTaskHandler(mockTaskService), newLabelHandler(nil), newProjectHandler(nil),
+				newWorkflowHandler(nil), NewUserHandler(nil), NewAuthHandler(nil),
+				newAdminHandler(nil), newAccessTokenHandler(nil), newKeyHandler(nil),
+				NewAuthMiddleware(mockTokenProvider, noRevocations(t), nil),
+			)
 			router.ServeHTTP(rr, req)
 			if rr.Code != tt.wantStatus {
 				t.Errorf("wanted status code %d, got %d.", tt.wantStatus, rr.Code)
@@ -248,6 +555,7 @@ func Test_taskHandler_CreateTaskHandler(t *testing.T) {
 		setupMTS     func(*mocks.MockTaskService)
 		requestBody  io.Reader
 		wantStatus   int
+		wantLocation string
 		responseBody string
 	}{
 		{
@@ -256,32 +564,38 @@ func Test_taskHandler_CreateTaskHandler(t *testing.T) {
 				mts.EXPECT().CreateTask(models.TaskRequestDto{
 					Title: "title",
 					Desc:  "desc",
-				}, models.Claims{ID: 4321}).Return(nil)
+				}, models.Claims{ID: 4321}).Return(models.TaskResponseDto{
+					ID:     "99",
+					Title:  "title",
+					Desc:   "desc",
+					Status: "Waiting",
+				}, nil)
 			},
 			requestBody:  strings.NewReader(`{"title":"title","desc":"desc"}`),
 			wantStatus:   http.StatusCreated,
-			responseBody: "task created successfully",
+			wantLocation: "/tasks/99",
+			responseBody: `{"id":"99","title":"title","desc":"desc","status":"Waiting","overdue":false}`,
 		},
 		{
 			name: "task service returns error",
 			setupMTS: func(mts *mocks.MockTaskService) {
 				mts.EXPECT().
 					CreateTask(models.TaskRequestDto{}, models.Claims{ID: 4321}).
-					Return(&errr.AppError{
+					Return(models.TaskResponseDto{}, &errr.AppError{
 						Code:    http.StatusBadGateway,
 						Message: "error message",
 					})
 			},
 			requestBody:  strings.NewReader("{}"),
 			wantStatus:   http.StatusBadGateway,
-			responseBody: "error message\n",
+			responseBody: problemBody(http.StatusBadGateway, "bad_gateway", "error message"),
 		},
 		{
 			name:         "invalid request body",
 			setupMTS:     func(mts *mocks.MockTaskService) {},
 			requestBody:  strings.NewReader(`{"lod":'`),
 			wantStatus:   http.StatusBadRequest,
-			responseBody: "Invalid Body\n",
+			responseBody: problemBody(http.StatusBadRequest, "invalid_body", "Invalid Body"),
 		},
 	}
 	for _, tt := range tests {
@@ -301,6 +615,190 @@ func Test_taskHandler_CreateTaskHandler(t *testing.T) {
 			if rr.Code != tt.wantStatus {
 				t.Errorf("wanted status code %d, got %d.", tt.wantStatus, rr.Code)
 			}
+			if got := rr.Header().Get("Location"); got != tt.wantLocation {
+				t.Errorf("wanted location %q, got %q.", tt.wantLocation, got)
+			}
+			if rr.Body.String() != tt.responseBody {
+				t.Errorf("wanted response body: %s, got %s.", tt.responseBody, rr.Body)
+			}
+		})
+	}
+}
+
+func Test_taskHandler_GetOccurrencesHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		setupMTS     func(*mocks.MockTaskService)
+		wantStatus   int
+		responseBody string
+	}{
+		{
+			name: "successful response",
+			setupMTS: func(mts *mocks.MockTaskService) {
+				mts.EXPECT().GetOccurrences("7", "2", models.Claims{ID: 4321}).Return(
+					[]models.OccurrenceDto{
+						{DueAt: "2025-03-04T09:00:00Z"},
+						{DueAt: "2025-03-05T09:00:00Z"},
+					}, nil)
+			},
+			wantStatus:   http.StatusOK,
+			responseBody: `[{"due_at":"2025-03-04T09:00:00Z"},{"due_at":"2025-03-05T09:00:00Z"}]`,
+		},
+		{
+			name: "task does not recur",
+			setupMTS: func(mts *mocks.MockTaskService) {
+				mts.EXPECT().GetOccurrences("7", "2", models.Claims{ID: 4321}).Return(
+					nil,
+					&errr.AppError{Code: http.StatusBadRequest, Message: "Task does not recur"},
+				)
+			},
+			wantStatus:   http.StatusBadRequest,
+			responseBody: problemBody(http.StatusBadRequest, "bad_request", "Task does not recur"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/tasks/7/occurrences?count=2", nil)
+			req.SetPathValue("id", "7")
+			req = req.WithContext(context.WithValue(req.Context(), "claims", models.Claims{
+				ID: 4321,
+			}))
+			rr := httptest.NewRecorder()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockTaskService := mocks.NewMockTaskService(ctrl)
+			tt.setupMTS(mockTaskService)
+			th := newTaskHandler(mockTaskService)
+			th.GetOccurrencesHandler(rr, req)
+			if rr.Code != tt.wantStatus {
+				t.Errorf("wanted status code %d, got %d.", tt.wantStatus, rr.Code)
+			}
+			if rr.Body.String() != tt.responseBody {
+				t.Errorf("wanted response body: %s, got %s.", tt.responseBody, rr.Body)
+			}
+		})
+	}
+}
+
+func Test_taskHandler_SearchTasksHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		setupMTS     func(*mocks.MockTaskService)
+		wantStatus   int
+		responseBody string
+	}{
+		{
+			name: "successful response",
+			setupMTS: func(mts *mocks.MockTaskService) {
+				mts.EXPECT().SearchTasks("milk", "5", models.Claims{ID: 4321}).Return(
+					[]models.SearchResultDto{{
+						Task:       models.TaskResponseDto{ID: "1", Title: "Buy milk", Status: "Pending"},
+						Score:      1.25,
+						Highlights: models.SearchHighlightsDto{Title: "Buy <mark>milk</mark>"},
+					}}, nil)
+			},
+			wantStatus: http.StatusOK,
+			responseBody: `[{"task":{"id":"1","title":"Buy milk","desc":"","status":"Pending","overdue":false},` +
+				`"score":1.25,"highlights":{"title":"Buy \u003cmark\u003emilk\u003c/mark\u003e"}}]`,
+		},
+		{
+			name: "invalid query",
+			setupMTS: func(mts *mocks.MockTaskService) {
+				mts.EXPECT().SearchTasks("milk", "5", models.Claims{ID: 4321}).Return(
+					nil,
+					&errr.AppError{Code: http.StatusBadRequest, Message: "Invalid limit, use 1 to 100"},
+				)
+			},
+			wantStatus:   http.StatusBadRequest,
+			responseBody: problemBody(http.StatusBadRequest, "bad_request", "Invalid limit, use 1 to 100"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/tasks/search?q=milk&limit=5", nil)
+			req = req.WithContext(context.WithValue(req.Context(), "claims", models.Claims{
+				ID: 4321,
+			}))
+			rr := httptest.NewRecorder()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockTaskService := mocks.NewMockTaskService(ctrl)
+			tt.setupMTS(mockTaskService)
+			th := newTaskHandler(mockTaskService)
+			th.SearchTasksHandler(rr, req)
+			if rr.Code != tt.wantStatus {
+				t.Errorf("wanted status code %d, got %d.", tt.wantStatus, rr.Code)
+			}
+			if rr.Body.String() != tt.responseBody {
+				t.Errorf("wanted response body: %s, got %s.", tt.responseBody, rr.Body)
+			}
+		})
+	}
+}
+
+func Test_taskHandler_GetTaskHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		setupMTS     func(*mocks.MockTaskService)
+		wantStatus   int
+		responseBody string
+	}{
+		{
+			name: "successful response",
+			setupMTS: func(mts *mocks.MockTaskService) {
+				mts.EXPECT().GetTask("7", models.Claims{ID: 4321}).Return(
+					models.TaskResponseDto{ID: "7", Title: "title", Status: "Pending"}, nil)
+			},
+			wantStatus:   http.StatusOK,
+			responseBody: `{"id":"7","title":"title","desc":"","status":"Pending","overdue":false}`,
+		},
+		{
+			name: "task not found",
+			setupMTS: func(mts *mocks.MockTaskService) {
+				mts.EXPECT().GetTask("7", models.Claims{ID: 4321}).Return(
+					models.TaskResponseDto{},
+					&errr.AppError{Code: http.StatusNotFound, Message: "no task found with id"},
+				)
+			},
+			wantStatus:   http.StatusNotFound,
+			responseBody: problemBody(http.StatusNotFound, "not_found", "no task found with id"),
+		},
+		{
+			name: "task of another user",
+			setupMTS: func(mts *mocks.MockTaskService) {
+				mts.EXPECT().GetTask("7", models.Claims{ID: 4321}).Return(
+					models.TaskResponseDto{},
+					&errr.AppError{Code: http.StatusForbidden, Message: "Unauthorized to get task"},
+				)
+			},
+			wantStatus:   http.StatusForbidden,
+			responseBody: problemBody(http.StatusForbidden, "forbidden", "Unauthorized to get task"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/tasks/7", nil)
+			req.Header.Set("Authorization", "Bearer token")
+			rr := httptest.NewRecorder()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockTaskService := mocks.NewMockTaskService(ctrl)
+			tt.setupMTS(mockTaskService)
+			mockTokenProvider := mocks.NewMockTokenProvider(ctrl)
+			mockTokenProvider.EXPECT().ValidateToken("token").Return(models.Claims{ID: 4321}, nil)
+
+			router := newRouter(
+				newTaskHandler(mockTaskService), newLabelHandler(nil), newProjectHandler(nil),
+				newWorkflowHandler(nil), NewUserHandler(nil), NewAuthHandler(nil),
+				newAdminHandler(nil), newAccessTokenHandler(nil), newKeyHandler(nil),
+				NewAuthMiddleware(mockTokenProvider, noRevocations(t), nil),
+			)
+			router.ServeHTTP(rr, req)
+			if rr.Code != tt.wantStatus {
+				t.Errorf("wanted status code %d, got %d.", tt.wantStatus, rr.Code)
+			}
 			if rr.Body.String() != tt.responseBody {
 				t.Errorf("wanted response body: %s, got %s.", tt.responseBody, rr.Body)
 			}
@@ -23,16 +23,20 @@ func (uh userHandler) CreateUserHandler(w http.ResponseWriter, r *http.Request)
 
 	err := json.NewDecoder(r.Body).Decode(&userReq)
 	if err != nil {
-		http.Error(w, "Invalid Body", http.StatusBadRequest)
+		writeError(w, r, invalidBody())
 		return
 	}
 
-	appErr := uh.userService.CreateUser(userReq)
+	user, appErr := uh.userService.CreateUser(userReq)
 	if appErr != nil {
-		http.Error(w, appErr.Message, appErr.Code)
+		writeError(w, r, appErr)
 		return
 	}
 
+	userjson, _ := json.Marshal(user)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", "/users/"+user.ID)
 	w.WriteHeader(http.StatusCreated)
-	w.Write([]byte("User Created Successfully"))
+	w.Write(userjson)
 }
@@ -19,6 +19,7 @@ func Test_userHandler_CreateUserHandler(t *testing.T) {
 		setupMUS     func(mus *mocks.MockUserService)
 		requestBody  io.Reader
 		wantStatus   int
+		wantLocation string
 		responseBody string
 	}{
 		{
@@ -26,7 +27,7 @@ func Test_userHandler_CreateUserHandler(t *testing.T) {
 			setupMUS:     func(mus *mocks.MockUserService) {},
 			requestBody:  strings.NewReader("adsfj;lsdj"),
 			wantStatus:   http.StatusBadRequest,
-			responseBody: "Invalid Body\n",
+			responseBody: problemBody(http.StatusBadRequest, "invalid_body", "Invalid Body"),
 		},
 		{
 			name: "user service returns error",
@@ -34,14 +35,14 @@ func Test_userHandler_CreateUserHandler(t *testing.T) {
 				mus.EXPECT().CreateUser(models.UserRequestDto{
 					Username: "jass",
 					Password: "password",
-				}).Return(&errr.AppError{
+				}).Return(models.UserResponseDto{}, &errr.AppError{
 					Code:    http.StatusInternalServerError,
 					Message: "error message from user service",
 				})
 			},
 			requestBody:  strings.NewReader(`{"username": "jass", "password": "password"}`),
 			wantStatus:   http.StatusInternalServerError,
-			responseBody: "error message from user service\n",
+			responseBody: problemBody(http.StatusInternalServerError, "internal_server_error", "error message from user service"),
 		},
 		{
 			name: "successful response",
@@ -49,11 +50,12 @@ func Test_userHandler_CreateUserHandler(t *testing.T) {
 				mus.EXPECT().CreateUser(models.UserRequestDto{
 					Username: "jass",
 					Password: "password",
-				}).Return(nil)
+				}).Return(models.UserResponseDto{ID: "77", Username: "jass", Role: models.RoleUser}, nil)
 			},
 			requestBody:  strings.NewReader(`{"username": "jass", "password": "password"}`),
 			wantStatus:   http.StatusCreated,
-			responseBody: "User Created Successfully",
+			wantLocation: "/users/77",
+			responseBody: `{"id":"77","username":"jass","role":"user"}`,
 		},
 	}
 	for _, tt := range tests {
@@ -71,6 +73,9 @@ func Test_userHandler_CreateUserHandler(t *testing.T) {
 			if rr.Code != tt.wantStatus {
 				t.Errorf("wanted status code %d, got %d.", tt.wantStatus, rr.Code)
 			}
+			if got := rr.Header().Get("Location"); got != tt.wantLocation {
+				t.Errorf("wanted location %q, got %q.", tt.wantLocation, got)
+			}
 			if rr.Body.String() != tt.responseBody {
 				t.Errorf("wanted response body: %s, got %s.", tt.responseBody, rr.Body)
 			}
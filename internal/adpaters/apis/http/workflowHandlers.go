@@ -0,0 +1,62 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/internal/ports"
+)
+
+type workflowHandler struct {
+	ws ports.WorkflowService
+}
+
+func newWorkflowHandler(ws ports.WorkflowService) *workflowHandler {
+	return &workflowHandler{
+		ws,
+	}
+}
+
+func (wh workflowHandler) GetWorkflowHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(models.Claims)
+	if !ok {
+		writeError(w, r, errr.NewUnexpectedError("Unexpected error in authentication"))
+		return
+	}
+
+	workflowRes, appErr := wh.ws.GetWorkflow(claims)
+	if appErr != nil {
+		writeError(w, r, appErr)
+		return
+	}
+
+	workflowjson, _ := json.Marshal(workflowRes)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(workflowjson)
+}
+
+func (wh workflowHandler) UpdateWorkflowHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(models.Claims)
+	if !ok {
+		writeError(w, r, errr.NewUnexpectedError("Unexpected error in authentication"))
+		return
+	}
+
+	var workflowReq models.WorkflowDto
+	err := json.NewDecoder(r.Body).Decode(&workflowReq)
+	if err != nil {
+		writeError(w, r, invalidBody())
+		return
+	}
+
+	appErr := wh.ws.UpdateWorkflow(workflowReq, claims)
+	if appErr != nil {
+		writeError(w, r, appErr)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,82 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/test/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+func Test_workflowHandler_UpdateWorkflowHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		setupMWS     func(*mocks.MockWorkflowService)
+		requestBody  string
+		wantStatus   int
+		responseBody string
+	}{
+		{
+			name: "successfully updated workflow",
+			setupMWS: func(mws *mocks.MockWorkflowService) {
+				mws.EXPECT().UpdateWorkflow(
+					models.WorkflowDto{Statuses: []models.WorkflowStatusDto{
+						{Name: "Review", Next: []string{"Done"}},
+						{Name: "Done", Terminal: true},
+					}},
+					models.Claims{ID: 4321},
+				).Return(nil)
+			},
+			requestBody: `{"statuses":[
+				{"name":"Review","next":["Done"]},
+				{"name":"Done","terminal":true}
+			]}`,
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:         "invalid body",
+			setupMWS:     func(mws *mocks.MockWorkflowService) {},
+			requestBody:  `{"statuses":`,
+			wantStatus:   http.StatusBadRequest,
+			responseBody: problemBody(http.StatusBadRequest, "invalid_body", "Invalid Body"),
+		},
+		{
+			name: "status still in use",
+			setupMWS: func(mws *mocks.MockWorkflowService) {
+				mws.EXPECT().UpdateWorkflow(gomock.Any(), gomock.Any()).Return(
+					errr.NewDuplicateError("Status Pending is still used by tasks"),
+				)
+			},
+			requestBody:  `{"statuses":[{"name":"Done","terminal":true}]}`,
+			wantStatus:   http.StatusConflict,
+			responseBody: problemBody(http.StatusConflict, "conflict", "Status Pending is still used by tasks"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/workflow", strings.NewReader(tt.requestBody))
+			req = req.WithContext(context.WithValue(req.Context(), "claims", models.Claims{
+				ID: 4321,
+			}))
+			rr := httptest.NewRecorder()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockWorkflowService := mocks.NewMockWorkflowService(ctrl)
+			tt.setupMWS(mockWorkflowService)
+			wh := newWorkflowHandler(mockWorkflowService)
+			wh.UpdateWorkflowHandler(rr, req)
+			if rr.Code != tt.wantStatus {
+				t.Errorf("wanted status code %d, got %d.", tt.wantStatus, rr.Code)
+			}
+			if rr.Body.String() != tt.responseBody {
+				t.Errorf("wanted response body: %s, got %s.", tt.responseBody, rr.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package idgen
+
+import "sync/atomic"
+
+// NewSequenceGenerator returns a generator handing out start+1, start+2, ...
+func NewSequenceGenerator(start int64) *sequenceGenerator {
+	sg := &sequenceGenerator{}
+	sg.last.Store(start)
+	return sg
+}
+
+type sequenceGenerator struct {
+	last atomic.Int64
+}
+
+func (sg *sequenceGenerator) NextID() int64 {
+	return sg.last.Add(1)
+}
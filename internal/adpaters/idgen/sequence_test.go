@@ -0,0 +1,40 @@
+package idgen
+
+import (
+	"sync"
+	"testing"
+)
+
+func Test_sequenceGenerator_NextID(t *testing.T) {
+	sg := NewSequenceGenerator(41)
+
+	if got := sg.NextID(); got != 42 {
+		t.Errorf("NextID() = %d, want 42", got)
+	}
+	if got := sg.NextID(); got != 43 {
+		t.Errorf("NextID() = %d, want 43", got)
+	}
+}
+
+func Test_sequenceGenerator_NextID_Concurrent(t *testing.T) {
+	sg := NewSequenceGenerator(0)
+	ids := make([]int64, 1000)
+
+	var wg sync.WaitGroup
+	for i := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ids[i] = sg.NextID()
+		}()
+	}
+	wg.Wait()
+
+	seen := map[int64]bool{}
+	for _, id := range ids {
+		if seen[id] {
+			t.Fatalf("NextID() returned %d twice", id)
+		}
+		seen[id] = true
+	}
+}
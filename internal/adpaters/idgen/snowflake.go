@@ -0,0 +1,54 @@
+package idgen
+
+import (
+	"sync"
+	"time"
+)
+
+// Snowflake ids are laid out as 41 bits of milliseconds since epoch, 4 bits of
+// node and 8 bits of sequence. That keeps them below 2^53 so they survive
+// being decoded as float64, which is what json and jwt claims do.
+const (
+	nodeBits     = 4
+	sequenceBits = 8
+	maxNode      = 1<<nodeBits - 1
+	maxSequence  = 1<<sequenceBits - 1
+)
+
+var epoch = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+func NewSnowflakeGenerator(node int64) *snowflakeGenerator {
+	return &snowflakeGenerator{
+		node: node & maxNode,
+		now:  time.Now,
+	}
+}
+
+type snowflakeGenerator struct {
+	mu       sync.Mutex
+	node     int64
+	lastTick int64
+	sequence int64
+	now      func() time.Time
+}
+
+func (sg *snowflakeGenerator) NextID() int64 {
+	sg.mu.Lock()
+	defer sg.mu.Unlock()
+
+	tick := sg.now().Sub(epoch).Milliseconds()
+	if tick <= sg.lastTick {
+		// same millisecond, or the clock went backwards: keep counting on
+		// the last tick and borrow the next one once the sequence runs out.
+		tick = sg.lastTick
+		sg.sequence = (sg.sequence + 1) & maxSequence
+		if sg.sequence == 0 {
+			tick++
+		}
+	} else {
+		sg.sequence = 0
+	}
+	sg.lastTick = tick
+
+	return tick<<(nodeBits+sequenceBits) | sg.node<<sequenceBits | sg.sequence
+}
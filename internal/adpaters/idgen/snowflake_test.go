@@ -0,0 +1,77 @@
+package idgen
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_snowflakeGenerator_NextID(t *testing.T) {
+	tests := []struct {
+		name  string
+		times []time.Time
+	}{
+		{
+			name: "same millisecond",
+			times: []time.Time{
+				epoch.Add(time.Hour),
+				epoch.Add(time.Hour),
+				epoch.Add(time.Hour),
+			},
+		},
+		{
+			name: "clock goes backwards",
+			times: []time.Time{
+				epoch.Add(time.Hour),
+				epoch.Add(time.Minute),
+				epoch.Add(time.Second),
+			},
+		},
+		{
+			name: "clock moves forward",
+			times: []time.Time{
+				epoch.Add(time.Second),
+				epoch.Add(time.Minute),
+				epoch.Add(time.Hour),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sg := NewSnowflakeGenerator(3)
+			var last int64
+			for _, now := range tt.times {
+				sg.now = func() time.Time { return now }
+				got := sg.NextID()
+				if got <= last {
+					t.Errorf("NextID() = %d, want more than %d", got, last)
+				}
+				last = got
+			}
+		})
+	}
+}
+
+func Test_snowflakeGenerator_NextID_SequenceOverflow(t *testing.T) {
+	sg := NewSnowflakeGenerator(0)
+	now := epoch.Add(time.Hour)
+	sg.now = func() time.Time { return now }
+
+	seen := map[int64]bool{}
+	for range 3 * (maxSequence + 1) {
+		id := sg.NextID()
+		if seen[id] {
+			t.Fatalf("NextID() returned %d twice", id)
+		}
+		seen[id] = true
+	}
+}
+
+func Test_snowflakeGenerator_NextID_FitsInFloat64(t *testing.T) {
+	sg := NewSnowflakeGenerator(maxNode)
+	sg.now = func() time.Time { return epoch.AddDate(60, 0, 0) }
+
+	id := sg.NextID()
+	if int64(float64(id)) != id {
+		t.Errorf("NextID() = %d, does not survive a float64 round trip", id)
+	}
+}
@@ -0,0 +1,95 @@
+package jwttoken
+
+import (
+	"errors"
+	"os"
+	"time"
+)
+
+// NewKeyDir keeps signing keys as PEM files in dir. New keys are generated for
+// alg every rotation, a rotation of 0 keeps the newest key.
+func NewKeyDir(dir string, alg string, rotation time.Duration) KeyDir {
+	return KeyDir{
+		dir:      dir,
+		alg:      alg,
+		rotation: rotation,
+	}
+}
+
+type KeyDir struct {
+	dir      string
+	alg      string
+	rotation time.Duration
+}
+
+// Load reads the keys in the directory into a key set for tokens valid for
+// validityPeriod. Every key retired when the key after it was created, keys
+// that verify no token anymore are deleted. The newest key signs, unless
+// Maintain has to replace it.
+func (kd KeyDir) Load(validityPeriod time.Duration, now time.Time) (*KeySet, error) {
+	err := os.MkdirAll(kd.dir, 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := LoadKeys(kd.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	keySet := NewKeySet(validityPeriod)
+	for _, key := range keys {
+		keySet.Rotate(key, key.CreatedAt)
+	}
+
+	_, err = kd.Maintain(keySet, now)
+	if err != nil {
+		return nil, err
+	}
+	return keySet, nil
+}
+
+// Maintain deletes the keys that verify no token at now anymore and replaces
+// the signing key when there is none, it is due for rotation or it isn't for
+// the algorithm of the directory. It reports whether it replaced the key.
+func (kd KeyDir) Maintain(keySet *KeySet, now time.Time) (bool, error) {
+	var errs []error
+	for _, key := range keySet.Prune(now) {
+		errs = append(errs, removeKeyFile(key))
+	}
+
+	signing, ok := keySet.SigningKey()
+	if ok && signing.Alg() == kd.alg && !kd.isDue(signing, now) {
+		return false, errors.Join(errs...)
+	}
+
+	key, err := GenerateKey(kd.alg)
+	if err != nil {
+		return false, errors.Join(append(errs, err)...)
+	}
+	key, err = WriteKey(kd.dir, key, now)
+	if err != nil {
+		return false, errors.Join(append(errs, err)...)
+	}
+	keySet.Rotate(key, now)
+
+	return true, errors.Join(errs...)
+}
+
+// NextMaintenance is when Maintain has to run next: when the signing key is
+// due for rotation or the oldest retired key expires, whichever is first.
+func (kd KeyDir) NextMaintenance(keySet *KeySet) time.Time {
+	next, ok := keySet.nextExpiry()
+	signing, _ := keySet.SigningKey()
+	if kd.rotation > 0 {
+		due := signing.CreatedAt.Add(kd.rotation)
+		if !ok || due.Before(next) {
+			return due
+		}
+	}
+	return next
+}
+
+func (kd KeyDir) isDue(key Key, now time.Time) bool {
+	return kd.rotation > 0 && !now.Before(key.CreatedAt.Add(kd.rotation))
+}
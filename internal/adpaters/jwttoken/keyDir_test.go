@@ -0,0 +1,151 @@
+package jwttoken
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+var keyDirNow = time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+
+// writeTestKey saves a new key for alg to dir as if it was created at createdAt.
+func writeTestKey(t *testing.T, dir string, alg string, createdAt time.Time) Key {
+	key, err := GenerateKey(alg)
+	if err != nil {
+		t.Fatalf("expected no error generating a key, got %v", err)
+	}
+	key, err = WriteKey(dir, key, createdAt)
+	if err != nil {
+		t.Fatalf("expected no error writing the key, got %v", err)
+	}
+	return key
+}
+
+func publishedKids(keys *KeySet, now time.Time) []string {
+	kids := []string{}
+	for _, key := range keys.publicKeys(now).Keys {
+		kids = append(kids, key.Kid)
+	}
+	return kids
+}
+
+func Test_KeyDir_Load_generates_a_key_when_there_is_none(t *testing.T) {
+	dir := t.TempDir()
+
+	keys, err := NewKeyDir(dir, AlgEdDSA, 24*time.Hour).Load(time.Hour, keyDirNow)
+	if err != nil {
+		t.Fatalf("expected no error loading keys, got %v", err)
+	}
+	signing, ok := keys.SigningKey()
+	if !ok || signing.Alg() != AlgEdDSA || !signing.CreatedAt.Equal(keyDirNow) {
+		t.Errorf("expected a new EdDSA key created now, got %v", signing)
+	}
+	loaded, _ := LoadKeys(dir)
+	if len(loaded) != 1 || loaded[0].ID != signing.ID {
+		t.Errorf("expected the new key to be saved, got %v", loaded)
+	}
+}
+
+func Test_KeyDir_Load_keeps_a_key_that_is_not_due(t *testing.T) {
+	dir := t.TempDir()
+	key := writeTestKey(t, dir, AlgES256, keyDirNow.Add(-time.Hour))
+
+	keys, err := NewKeyDir(dir, AlgES256, 24*time.Hour).Load(time.Hour, keyDirNow)
+	if err != nil {
+		t.Fatalf("expected no error loading keys, got %v", err)
+	}
+	signing, _ := keys.SigningKey()
+	if signing.ID != key.ID {
+		t.Errorf("expected %s to keep signing, got %s", key.ID, signing.ID)
+	}
+}
+
+func Test_KeyDir_Load_rotates_an_overdue_key(t *testing.T) {
+	dir := t.TempDir()
+	key := writeTestKey(t, dir, AlgES256, keyDirNow.Add(-25*time.Hour))
+
+	keys, err := NewKeyDir(dir, AlgES256, 24*time.Hour).Load(time.Hour, keyDirNow)
+	if err != nil {
+		t.Fatalf("expected no error loading keys, got %v", err)
+	}
+	signing, _ := keys.SigningKey()
+	if signing.ID == key.ID || !signing.CreatedAt.Equal(keyDirNow) {
+		t.Errorf("expected a new signing key created now, got %v", signing)
+	}
+	if kids := publishedKids(keys, keyDirNow); len(kids) != 2 || kids[1] != key.ID {
+		t.Errorf("expected the overdue key to verify tokens until they expire, got %v", kids)
+	}
+}
+
+func Test_KeyDir_Load_rotates_when_the_algorithm_changed(t *testing.T) {
+	dir := t.TempDir()
+	key := writeTestKey(t, dir, AlgES256, keyDirNow.Add(-time.Hour))
+
+	keys, err := NewKeyDir(dir, AlgEdDSA, 0).Load(time.Hour, keyDirNow)
+	if err != nil {
+		t.Fatalf("expected no error loading keys, got %v", err)
+	}
+	signing, _ := keys.SigningKey()
+	if signing.ID == key.ID || signing.Alg() != AlgEdDSA {
+		t.Errorf("expected a new EdDSA signing key, got %v", signing)
+	}
+}
+
+func Test_KeyDir_Load_retires_keys_when_the_next_key_was_created(t *testing.T) {
+	dir := t.TempDir()
+	expired := writeTestKey(t, dir, AlgES256, keyDirNow.Add(-72*time.Hour))
+	// replaced 2 hours ago, its tokens expired an hour ago.
+	retired := writeTestKey(t, dir, AlgES256, keyDirNow.Add(-48*time.Hour))
+	// replaced 30 minutes ago, its tokens are still valid.
+	verifying := writeTestKey(t, dir, AlgES256, keyDirNow.Add(-2*time.Hour))
+	signing := writeTestKey(t, dir, AlgES256, keyDirNow.Add(-30*time.Minute))
+
+	keys, err := NewKeyDir(dir, AlgES256, 24*time.Hour).Load(time.Hour, keyDirNow)
+	if err != nil {
+		t.Fatalf("expected no error loading keys, got %v", err)
+	}
+
+	kids := publishedKids(keys, keyDirNow)
+	if len(kids) != 2 || kids[0] != signing.ID || kids[1] != verifying.ID {
+		t.Errorf("expected only %s and %s to be published, got %v", signing.ID, verifying.ID, kids)
+	}
+	for _, key := range []Key{expired, retired} {
+		if _, err := os.Stat(key.fp); !os.IsNotExist(err) {
+			t.Errorf("expected the file of the expired key %s to be deleted, got %v", key.ID, err)
+		}
+	}
+	if _, err := os.Stat(verifying.fp); err != nil {
+		t.Errorf("expected the file of the verifying key to be kept, got %v", err)
+	}
+}
+
+func Test_KeyDir_Maintain(t *testing.T) {
+	dir := t.TempDir()
+	keyDir := NewKeyDir(dir, AlgES256, 24*time.Hour)
+	keys, err := keyDir.Load(time.Hour, keyDirNow)
+	if err != nil {
+		t.Fatalf("expected no error loading keys, got %v", err)
+	}
+	first, _ := keys.SigningKey()
+
+	if next := keyDir.NextMaintenance(keys); !next.Equal(keyDirNow.Add(24 * time.Hour)) {
+		t.Errorf("expected the next maintenance at the rotation, got %v", next)
+	}
+
+	rotatedAt := keyDirNow.Add(24 * time.Hour)
+	rotated, err := keyDir.Maintain(keys, rotatedAt)
+	if err != nil || !rotated {
+		t.Fatalf("expected the key to be rotated, got %v, %v", rotated, err)
+	}
+	if next := keyDir.NextMaintenance(keys); !next.Equal(rotatedAt.Add(time.Hour)) {
+		t.Errorf("expected the next maintenance when the retired key expires, got %v", next)
+	}
+
+	rotated, err = keyDir.Maintain(keys, rotatedAt.Add(time.Hour))
+	if err != nil || rotated {
+		t.Fatalf("expected the key not to be rotated, got %v, %v", rotated, err)
+	}
+	if _, err := os.Stat(first.fp); !os.IsNotExist(err) {
+		t.Errorf("expected the file of the expired key to be deleted, got %v", err)
+	}
+}
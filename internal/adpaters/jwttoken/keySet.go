@@ -0,0 +1,123 @@
+package jwttoken
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+)
+
+// NewKeySet holds the keys of tokens that are valid for validityPeriod.
+func NewKeySet(validityPeriod time.Duration) *KeySet {
+	return &KeySet{
+		mu:             sync.RWMutex{},
+		validityPeriod: validityPeriod,
+	}
+}
+
+// KeySet is the newest key, which signs tokens, and the keys it replaced,
+// which verify the tokens they signed until those expired.
+type KeySet struct {
+	mu             sync.RWMutex
+	validityPeriod time.Duration
+	signing        Key
+	retired        []retiredKey
+}
+
+type retiredKey struct {
+	Key
+	// until is when the last token the key signed expires.
+	until time.Time
+}
+
+// Rotate makes key the signing key at now. The key it replaces keeps
+// verifying tokens for the validity period.
+func (ks *KeySet) Rotate(key Key, now time.Time) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if ks.signing.ID != "" && ks.signing.ID != key.ID {
+		ks.retired = append(ks.retired, retiredKey{
+			Key:   ks.signing,
+			until: now.Add(ks.validityPeriod),
+		})
+	}
+	ks.signing = key
+}
+
+// Prune forgets the retired keys that verify no token at now anymore and
+// returns them.
+func (ks *KeySet) Prune(now time.Time) []Key {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	pruned := []Key{}
+	kept := ks.retired[:0]
+	for _, key := range ks.retired {
+		if now.Before(key.until) {
+			kept = append(kept, key)
+		} else {
+			pruned = append(pruned, key.Key)
+		}
+	}
+	ks.retired = kept
+	return pruned
+}
+
+// nextExpiry returns when the first retired key stops verifying tokens,
+// false when there are no retired keys.
+func (ks *KeySet) nextExpiry() (time.Time, bool) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	var next time.Time
+	for _, key := range ks.retired {
+		if next.IsZero() || key.until.Before(next) {
+			next = key.until
+		}
+	}
+	return next, !next.IsZero()
+}
+
+// SigningKey returns the key new tokens are signed with, false when there
+// is none yet.
+func (ks *KeySet) SigningKey() (Key, bool) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	return ks.signing, ks.signing.ID != ""
+}
+
+// verifyingKey returns the key with id when it verifies tokens at now.
+func (ks *KeySet) verifyingKey(id string, now time.Time) (Key, bool) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	if id != "" && id == ks.signing.ID {
+		return ks.signing, true
+	}
+	for _, key := range ks.retired {
+		if key.ID == id && now.Before(key.until) {
+			return key.Key, true
+		}
+	}
+	return Key{}, false
+}
+
+// publicKeys returns the keys that verify tokens at now, the signing key
+// first.
+func (ks *KeySet) publicKeys(now time.Time) models.JSONWebKeySet {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	keys := []models.JSONWebKey{}
+	if ks.signing.ID != "" {
+		keys = append(keys, ks.signing.public)
+	}
+	for i := len(ks.retired) - 1; i >= 0; i-- {
+		if now.Before(ks.retired[i].until) {
+			keys = append(keys, ks.retired[i].public)
+		}
+	}
+	return models.JSONWebKeySet{Keys: keys}
+}
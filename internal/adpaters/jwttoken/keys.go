@@ -0,0 +1,287 @@
+package jwttoken
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Algorithms tokens can be signed with.
+const (
+	AlgRS256 = "RS256"
+	AlgES256 = "ES256"
+	AlgEdDSA = "EdDSA"
+)
+
+const minRSAKeyBits = 2048
+
+// Key is a private key tokens are signed with. Its ID, the RFC 7638
+// thumbprint of the public key, is the kid header of the tokens it signs.
+type Key struct {
+	ID string
+	// CreatedAt is when the key file was written, zero for keys that
+	// weren't read from or written to a file.
+	CreatedAt time.Time
+	method    jwt.SigningMethod
+	private   crypto.Signer
+	public    models.JSONWebKey
+	// fp is the file the key was read from or written to.
+	fp string
+}
+
+// Alg is the algorithm the key signs with.
+func (k Key) Alg() string {
+	return k.method.Alg()
+}
+
+// newKey picks the algorithm from the type of the private key: RS256 for RSA,
+// ES256 for P-256 and EdDSA for Ed25519 keys.
+func newKey(private crypto.Signer) (Key, error) {
+	var method jwt.SigningMethod
+	switch k := private.(type) {
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < minRSAKeyBits {
+			return Key{}, fmt.Errorf("RSA keys must have at least %d bits", minRSAKeyBits)
+		}
+		method = jwt.SigningMethodRS256
+	case *ecdsa.PrivateKey:
+		if k.Curve != elliptic.P256() {
+			return Key{}, errors.New("ECDSA keys must use the P-256 curve")
+		}
+		method = jwt.SigningMethodES256
+	case ed25519.PrivateKey:
+		method = jwt.SigningMethodEdDSA
+	default:
+		return Key{}, fmt.Errorf("unsupported key type %T", private)
+	}
+
+	public, err := publicJWK(private.Public())
+	if err != nil {
+		return Key{}, err
+	}
+	id := thumbprint(public)
+	public.Kid = id
+	public.Use = "sig"
+	public.Alg = method.Alg()
+
+	return Key{
+		ID:      id,
+		method:  method,
+		private: private,
+		public:  public,
+	}, nil
+}
+
+// GenerateKey creates a new key for alg.
+func GenerateKey(alg string) (Key, error) {
+	var private crypto.Signer
+	var err error
+	switch alg {
+	case AlgRS256:
+		private, err = rsa.GenerateKey(rand.Reader, minRSAKeyBits)
+	case AlgES256:
+		private, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case AlgEdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return Key{}, fmt.Errorf("unsupported algorithm %s, use %s, %s or %s", alg, AlgRS256, AlgES256, AlgEdDSA)
+	}
+	if err != nil {
+		return Key{}, err
+	}
+
+	return newKey(private)
+}
+
+// LoadKey reads a PEM encoded private key in PKCS #8, PKCS #1 or SEC 1 form.
+// The key was created when the file was last modified.
+func LoadKey(fp string) (Key, error) {
+	info, err := os.Stat(fp)
+	if err != nil {
+		return Key{}, err
+	}
+	data, err := os.ReadFile(fp)
+	if err != nil {
+		return Key{}, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return Key{}, fmt.Errorf("%s is not PEM encoded", fp)
+	}
+
+	var private any
+	switch block.Type {
+	case "PRIVATE KEY":
+		private, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		private, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		private, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		return Key{}, fmt.Errorf("%s holds a %s, not a private key", fp, block.Type)
+	}
+	if err != nil {
+		return Key{}, fmt.Errorf("unable to parse %s.\n%w", fp, err)
+	}
+
+	signer, ok := private.(crypto.Signer)
+	if !ok {
+		return Key{}, fmt.Errorf("unsupported key type %T in %s", private, fp)
+	}
+	key, err := newKey(signer)
+	if err != nil {
+		return Key{}, fmt.Errorf("can't use %s.\n%w", fp, err)
+	}
+	key.CreatedAt = info.ModTime().UTC()
+	key.fp = fp
+	return key, nil
+}
+
+// LoadKeys reads every .pem file in dir, oldest key first. Keys created at
+// the same time are ordered by file name.
+func LoadKeys(dir string) ([]Key, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []Key{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".pem") {
+			continue
+		}
+		key, err := LoadKey(path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	// os.ReadDir orders by file name, which the stable sort keeps for ties.
+	slices.SortStableFunc(keys, func(a, b Key) int { return a.CreatedAt.Compare(b.CreatedAt) })
+
+	return keys, nil
+}
+
+// WriteKey saves the key in PKCS #8 form to dir as created at now and
+// returns it with its file.
+func WriteKey(dir string, key Key, now time.Time) (Key, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key.private)
+	if err != nil {
+		return Key{}, err
+	}
+
+	fp := path.Join(dir, now.UTC().Format("20060102T150405.000000000Z")+".pem")
+	file, err := os.OpenFile(fp, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return Key{}, err
+	}
+	err = pem.Encode(file, &pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if err != nil {
+		file.Close()
+		return Key{}, err
+	}
+	err = file.Close()
+	if err != nil {
+		return Key{}, err
+	}
+	// the modification time is when LoadKeys reads the key was created.
+	err = os.Chtimes(fp, now, now)
+	if err != nil {
+		return Key{}, err
+	}
+
+	key.CreatedAt = now.UTC()
+	key.fp = fp
+	return key, nil
+}
+
+// removeKeyFile deletes the file of the key, if it has one.
+func removeKeyFile(key Key) error {
+	if key.fp == "" {
+		return nil
+	}
+	err := os.Remove(key.fp)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// publicJWK encodes the members of a public key, without kid, use and alg.
+func publicJWK(public crypto.PublicKey) (models.JSONWebKey, error) {
+	switch k := public.(type) {
+	case *rsa.PublicKey:
+		return models.JSONWebKey{
+			Kty: "RSA",
+			N:   encodeBytes(k.N.Bytes()),
+			E:   encodeBytes(big.NewInt(int64(k.E)).Bytes()),
+		}, nil
+	case *ecdsa.PublicKey:
+		ecdhKey, err := k.ECDH()
+		if err != nil {
+			return models.JSONWebKey{}, err
+		}
+		// the point is 0x04 followed by x and y, both as long as the curve.
+		point := ecdhKey.Bytes()
+		size := (len(point) - 1) / 2
+		return models.JSONWebKey{
+			Kty: "EC",
+			Crv: k.Curve.Params().Name,
+			X:   encodeBytes(point[1 : 1+size]),
+			Y:   encodeBytes(point[1+size:]),
+		}, nil
+	case ed25519.PublicKey:
+		return models.JSONWebKey{
+			Kty: "OKP",
+			Crv: "Ed25519",
+			X:   encodeBytes(k),
+		}, nil
+	}
+	return models.JSONWebKey{}, fmt.Errorf("unsupported key type %T", public)
+}
+
+// thumbprint is the RFC 7638 thumbprint of the key: the sha256 of its
+// required members, ordered by name.
+func thumbprint(jwk models.JSONWebKey) string {
+	members := map[string]string{"kty": jwk.Kty}
+	switch jwk.Kty {
+	case "RSA":
+		members["n"] = jwk.N
+		members["e"] = jwk.E
+	case "EC":
+		members["crv"] = jwk.Crv
+		members["x"] = jwk.X
+		members["y"] = jwk.Y
+	case "OKP":
+		members["crv"] = jwk.Crv
+		members["x"] = jwk.X
+	}
+
+	// json.Marshal orders map keys, as the thumbprint requires.
+	canonical, _ := json.Marshal(members)
+	sum := sha256.Sum256(canonical)
+	return encodeBytes(sum[:])
+}
+
+func encodeBytes(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,118 @@
+package jwttoken
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+)
+
+func Test_thumbprint(t *testing.T) {
+	// the example of RFC 7638, section 3.1.
+	jwk := models.JSONWebKey{
+		Kty: "RSA",
+		N:   "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+		E:   "AQAB",
+	}
+
+	want := "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"
+	if got := thumbprint(jwk); got != want {
+		t.Errorf("thumbprint() = %s, want %s", got, want)
+	}
+}
+
+// writePEM saves der as a PEM block of blockType in dir.
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) {
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	err := os.WriteFile(path.Join(dir, name), data, 0600)
+	if err != nil {
+		t.Fatalf("expected no error writing %s, got %v", name, err)
+	}
+}
+
+func Test_LoadKeys(t *testing.T) {
+	dir := t.TempDir()
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	writePEM(t, dir, "1-rsa.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ecDER, _ := x509.MarshalECPrivateKey(ecKey)
+	writePEM(t, dir, "2-ec.pem", "EC PRIVATE KEY", ecDER)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	edDER, _ := x509.MarshalPKCS8PrivateKey(edKey)
+	writePEM(t, dir, "3-ed25519.pem", "PRIVATE KEY", edDER)
+	os.WriteFile(path.Join(dir, "README"), []byte("not a key"), 0600)
+
+	keys, err := LoadKeys(dir)
+	if err != nil {
+		t.Fatalf("expected no error loading keys, got %v", err)
+	}
+
+	wantAlgs := []string{AlgRS256, AlgES256, AlgEdDSA}
+	if len(keys) != len(wantAlgs) {
+		t.Fatalf("expected %d keys, got %d", len(wantAlgs), len(keys))
+	}
+	for i, key := range keys {
+		if key.method.Alg() != wantAlgs[i] || key.public.Alg != wantAlgs[i] {
+			t.Errorf("expected key %d to sign with %s, got %s", i, wantAlgs[i], key.method.Alg())
+		}
+		if key.ID == "" || key.public.Kid != key.ID {
+			t.Errorf("expected key %d to have its id as kid, got %q and %q", i, key.ID, key.public.Kid)
+		}
+	}
+}
+
+func Test_LoadKey_refuses_weak_keys(t *testing.T) {
+	dir := t.TempDir()
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 1024)
+	writePEM(t, dir, "rsa.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	ecDER, _ := x509.MarshalECPrivateKey(ecKey)
+	writePEM(t, dir, "ec.pem", "EC PRIVATE KEY", ecDER)
+
+	for _, name := range []string{"rsa.pem", "ec.pem"} {
+		_, err := LoadKey(path.Join(dir, name))
+		if err == nil {
+			t.Errorf("expected an error loading %s", name)
+		}
+	}
+}
+
+func Test_WriteKey(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+
+	var written []Key
+	for i, alg := range []string{AlgEdDSA, AlgES256} {
+		key, err := GenerateKey(alg)
+		if err != nil {
+			t.Fatalf("expected no error generating a %s key, got %v", alg, err)
+		}
+		key, err = WriteKey(dir, key, now.Add(time.Duration(i)*time.Hour))
+		if err != nil {
+			t.Fatalf("expected no error writing the key, got %v", err)
+		}
+		written = append(written, key)
+	}
+
+	keys, err := LoadKeys(dir)
+	if err != nil {
+		t.Fatalf("expected no error loading keys, got %v", err)
+	}
+	if len(keys) != 2 || keys[0].ID != written[0].ID || keys[1].ID != written[1].ID {
+		t.Errorf("expected the keys in the order they were written, got %v", keys)
+	}
+	for i, key := range keys {
+		if !key.CreatedAt.Equal(written[i].CreatedAt) {
+			t.Errorf("expected key %d to be created at %v, got %v", i, written[i].CreatedAt, key.CreatedAt)
+		}
+	}
+}
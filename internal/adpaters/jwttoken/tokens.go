@@ -1,6 +1,9 @@
 package jwttoken
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"time"
 
 	"github.com/Jashanveer-Singh/todo-go/internal/models"
@@ -8,11 +11,12 @@ import (
 )
 
 func NewJWTTokenProvider(
-	secretKey, issuer, audience string,
+	keys *KeySet,
+	issuer, audience string,
 	validtiyPeriod time.Duration,
 ) jwtToken {
 	return jwtToken{
-		secretKey:      secretKey,
+		keys:           keys,
 		issuer:         issuer,
 		audience:       audience,
 		validtiyPeriod: validtiyPeriod,
@@ -20,32 +24,49 @@ func NewJWTTokenProvider(
 }
 
 type jwtToken struct {
-	secretKey      string
+	keys           *KeySet
 	issuer         string
 	audience       string
 	validtiyPeriod time.Duration
 }
 
 func (jt jwtToken) GenerateToken(claims models.Claims) (string, error) {
+	key, ok := jt.keys.SigningKey()
+	if !ok {
+		return "", errors.New("no key to sign tokens with")
+	}
+
+	tokenID, err := newTokenID()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
 	jwtClaims := jwt.MapClaims{
 		"iss":  jt.issuer,
 		"aud":  jt.audience,
-		"exp":  time.Now().Add(jt.validtiyPeriod).Unix(),
-		"iat":  time.Now().Unix(),
+		"exp":  now.Add(jt.validtiyPeriod).Unix(),
+		"iat":  now.Unix(),
+		"jti":  tokenID,
 		"id":   claims.ID,
 		"role": claims.Role,
+		"tz":   claims.TimeZone,
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwtClaims)
-	return token.SignedString([]byte(jt.secretKey))
+	token := jwt.NewWithClaims(key.method, jwtClaims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.private)
 }
 
 func (jt jwtToken) ValidateToken(tokenString string) (models.Claims, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (any, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, jwt.ErrTokenMalformed
+		kid, _ := token.Header["kid"].(string)
+		key, ok := jt.keys.verifyingKey(kid, time.Now())
+		// the algorithm must be the key's, or a token could pick a weaker one.
+		if !ok || token.Method.Alg() != key.method.Alg() {
+			return nil, jwt.ErrTokenUnverifiable
 		}
-		return []byte(jt.secretKey), nil
+		return key.private.Public(), nil
 	})
 	if err != nil {
 		return models.Claims{}, err
@@ -67,6 +88,10 @@ func (jt jwtToken) ValidateToken(tokenString string) (models.Claims, error) {
 	return jt.extractClaims(claims)
 }
 
+func (jt jwtToken) PublicKeys() models.JSONWebKeySet {
+	return jt.keys.publicKeys(time.Now())
+}
+
 func (jt jwtToken) extractClaims(claims jwt.MapClaims) (models.Claims, error) {
 	id, ok := claims["id"].(float64)
 	if !ok {
@@ -78,8 +103,41 @@ func (jt jwtToken) extractClaims(claims jwt.MapClaims) (models.Claims, error) {
 		return models.Claims{}, jwt.ErrTokenInvalidClaims
 	}
 
+	// tokens issued before time zones existed carry no tz claim.
+	timeZone, _ := claims["tz"].(string)
+
+	// tokens without an id can't be revoked, so they are refused.
+	tokenID, ok := claims["jti"].(string)
+	if !ok || tokenID == "" {
+		return models.Claims{}, jwt.ErrTokenInvalidClaims
+	}
+
+	issuedAt, err := claims.GetIssuedAt()
+	if err != nil || issuedAt == nil {
+		return models.Claims{}, jwt.ErrTokenInvalidClaims
+	}
+	expiresAt, err := claims.GetExpirationTime()
+	if err != nil || expiresAt == nil {
+		return models.Claims{}, jwt.ErrTokenInvalidClaims
+	}
+
 	return models.Claims{
-		ID:   int64(id),
-		Role: role,
+		ID:        int64(id),
+		Role:      role,
+		TimeZone:  timeZone,
+		TokenID:   tokenID,
+		IssuedAt:  issuedAt.Time,
+		ExpiresAt: expiresAt.Time,
 	}, nil
 }
+
+// newTokenID returns a random id for the jti claim.
+func newTokenID() (string, error) {
+	id := make([]byte, 16)
+	_, err := rand.Read(id)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(id), nil
+}
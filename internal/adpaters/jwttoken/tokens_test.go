@@ -5,10 +5,24 @@ import (
 	"time"
 
 	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/golang-jwt/jwt/v5"
 )
 
+// newTestKeys is a key set signing with a new key for alg.
+func newTestKeys(t *testing.T, alg string) *KeySet {
+	key, err := GenerateKey(alg)
+	if err != nil {
+		t.Fatalf("expected no error generating a key, got %v", err)
+	}
+
+	keys := NewKeySet(time.Hour)
+	keys.Rotate(key, time.Now())
+	return keys
+}
+
 func Test_jwttoken_GenerateToken(t *testing.T) {
-	jwtTokenProvider := NewJWTTokenProvider("mysecretkey", "myissuer", "myaudience", time.Hour)
+	keys := newTestKeys(t, AlgES256)
+	jwtTokenProvider := NewJWTTokenProvider(keys, "myissuer", "myaudience", time.Hour)
 	claims := models.Claims{
 		ID:   1,
 		Role: "",
@@ -24,7 +38,8 @@ func Test_jwttoken_GenerateToken(t *testing.T) {
 }
 
 func Test_jwttoken_ValidateToken_when_valid(t *testing.T) {
-	jwtTokenProvider := NewJWTTokenProvider("mysecretkey", "myissuer", "myaudience", time.Hour)
+	keys := newTestKeys(t, AlgES256)
+	jwtTokenProvider := NewJWTTokenProvider(keys, "myissuer", "myaudience", time.Hour)
 	claims := models.Claims{
 		ID:   1,
 		Role: "",
@@ -47,20 +62,93 @@ func Test_jwttoken_ValidateToken_when_valid(t *testing.T) {
 	}
 }
 
-func Test_jwttoken_ValidateToken_when_signed_with_invalid_secret(t *testing.T) {
+func Test_jwttoken_ValidateToken_keeps_time_zone(t *testing.T) {
+	keys := newTestKeys(t, AlgES256)
+	jwtTokenProvider := NewJWTTokenProvider(keys, "myissuer", "myaudience", time.Hour)
+	claims := models.Claims{
+		ID:       1,
+		TimeZone: "Asia/Kolkata",
+	}
+
+	token, err := jwtTokenProvider.GenerateToken(claims)
+	if err != nil {
+		t.Fatalf("expected no error generating token, got %v", err)
+	}
+
+	validatedClaims, err := jwtTokenProvider.ValidateToken(token)
+	if err != nil {
+		t.Fatalf("expected no error validating token, got %v", err)
+	}
+	if validatedClaims.ID != claims.ID || validatedClaims.TimeZone != claims.TimeZone {
+		t.Errorf("expected claims to be %v, got %v", claims, validatedClaims)
+	}
+}
+
+func Test_jwttoken_ValidateToken_reads_token_id_and_lifetime(t *testing.T) {
+	keys := newTestKeys(t, AlgES256)
+	jwtTokenProvider := NewJWTTokenProvider(keys, "myissuer", "myaudience", time.Hour)
+
+	first, _ := jwtTokenProvider.GenerateToken(models.Claims{ID: 1})
+	second, _ := jwtTokenProvider.GenerateToken(models.Claims{ID: 1})
+	firstClaims, err := jwtTokenProvider.ValidateToken(first)
+	if err != nil {
+		t.Fatalf("expected no error validating token, got %v", err)
+	}
+	secondClaims, err := jwtTokenProvider.ValidateToken(second)
+	if err != nil {
+		t.Fatalf("expected no error validating token, got %v", err)
+	}
+
+	if firstClaims.TokenID == "" || firstClaims.TokenID == secondClaims.TokenID {
+		t.Errorf("expected distinct token ids, got %q and %q", firstClaims.TokenID, secondClaims.TokenID)
+	}
+	if firstClaims.ExpiresAt.Sub(firstClaims.IssuedAt) != time.Hour {
+		t.Errorf(
+			"expected the token to live an hour, got issued at %v, expires at %v",
+			firstClaims.IssuedAt, firstClaims.ExpiresAt,
+		)
+	}
+}
+
+func Test_jwttoken_ValidateToken_when_token_id_is_missing(t *testing.T) {
+	keys := newTestKeys(t, AlgES256)
+	key, _ := keys.SigningKey()
+	withoutID := jwt.NewWithClaims(key.method, jwt.MapClaims{
+		"iss":  "myissuer",
+		"aud":  "myaudience",
+		"exp":  time.Now().Add(time.Hour).Unix(),
+		"iat":  time.Now().Unix(),
+		"id":   1,
+		"role": "",
+	})
+	withoutID.Header["kid"] = key.ID
+	token, err := withoutID.SignedString(key.private)
+	if err != nil {
+		t.Fatalf("expected no error signing token, got %v", err)
+	}
+
+	jwtTokenProvider := NewJWTTokenProvider(keys, "myissuer", "myaudience", time.Hour)
+	_, err = jwtTokenProvider.ValidateToken(token)
+	if err == nil {
+		t.Errorf("expected error while validating a token without jti, got %v", err)
+	}
+}
+
+func Test_jwttoken_ValidateToken_when_signed_with_another_key(t *testing.T) {
+	keys := newTestKeys(t, AlgES256)
 	claims := models.Claims{
 		ID:   1,
 		Role: "",
 	}
 
-	jwtTokenProvider := NewJWTTokenProvider("mysecretkey", "myissuer", "myaudience", time.Hour)
+	jwtTokenProvider := NewJWTTokenProvider(keys, "myissuer", "myaudience", time.Hour)
 	token, err := jwtTokenProvider.GenerateToken(claims)
 	if err != nil {
 		t.Fatalf("expected no error generating token, got %v", err)
 	}
 
 	anotherJWTTokenProvider := NewJWTTokenProvider(
-		"anothersecretkey",
+		newTestKeys(t, AlgES256),
 		"myissuer",
 		"myaudience",
 		time.Hour,
@@ -73,19 +161,20 @@ func Test_jwttoken_ValidateToken_when_signed_with_invalid_secret(t *testing.T) {
 }
 
 func Test_jwttoken_ValidateToken_when_signed_with_invalid_iss_or_aud(t *testing.T) {
+	keys := newTestKeys(t, AlgES256)
 	claims := models.Claims{
 		ID:   1,
 		Role: "",
 	}
 
-	jwtTokenProvider := NewJWTTokenProvider("mysecretkey", "myissuer", "myaudience", time.Hour)
+	jwtTokenProvider := NewJWTTokenProvider(keys, "myissuer", "myaudience", time.Hour)
 	token, err := jwtTokenProvider.GenerateToken(claims)
 	if err != nil {
 		t.Fatalf("expected no error generating token, got %v", err)
 	}
 
 	anotherJWTTokenProvider := NewJWTTokenProvider(
-		"mysecretkey",
+		keys,
 		"anotherissuer",
 		"myaudience",
 		time.Hour,
@@ -97,7 +186,7 @@ func Test_jwttoken_ValidateToken_when_signed_with_invalid_iss_or_aud(t *testing.
 	}
 
 	anotherJWTTokenProvider = NewJWTTokenProvider(
-		"mysecretkey",
+		keys,
 		"myissuer",
 		"anotheraudience",
 		time.Hour,
@@ -107,3 +196,88 @@ func Test_jwttoken_ValidateToken_when_signed_with_invalid_iss_or_aud(t *testing.
 		t.Errorf("expected error while validating token, got %v", err)
 	}
 }
+
+func Test_jwttoken_signs_with_every_algorithm(t *testing.T) {
+	for _, alg := range []string{AlgRS256, AlgES256, AlgEdDSA} {
+		t.Run(alg, func(t *testing.T) {
+			keys := newTestKeys(t, alg)
+			jwtTokenProvider := NewJWTTokenProvider(keys, "myissuer", "myaudience", time.Hour)
+
+			token, err := jwtTokenProvider.GenerateToken(models.Claims{ID: 1})
+			if err != nil {
+				t.Fatalf("expected no error generating token, got %v", err)
+			}
+			parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+			if err != nil {
+				t.Fatalf("expected no error parsing token, got %v", err)
+			}
+			key, _ := keys.SigningKey()
+			if parsed.Header["alg"] != alg || parsed.Header["kid"] != key.ID {
+				t.Errorf("expected alg %s and kid %s, got %v", alg, key.ID, parsed.Header)
+			}
+
+			claims, err := jwtTokenProvider.ValidateToken(token)
+			if err != nil || claims.ID != 1 {
+				t.Errorf("expected the token to validate, got %v, %v", claims, err)
+			}
+		})
+	}
+}
+
+func Test_jwttoken_ValidateToken_when_algorithm_is_not_the_keys(t *testing.T) {
+	keys := newTestKeys(t, AlgES256)
+	key, _ := keys.SigningKey()
+	other, _ := GenerateKey(AlgEdDSA)
+
+	// a token claiming the kid of the ES256 key but signed with EdDSA.
+	forged := jwt.NewWithClaims(other.method, jwt.MapClaims{
+		"iss": "myissuer",
+		"aud": "myaudience",
+		"exp": time.Now().Add(time.Hour).Unix(),
+		"iat": time.Now().Unix(),
+		"jti": "jti",
+		"id":  1,
+	})
+	forged.Header["kid"] = key.ID
+	token, err := forged.SignedString(other.private)
+	if err != nil {
+		t.Fatalf("expected no error signing token, got %v", err)
+	}
+
+	jwtTokenProvider := NewJWTTokenProvider(keys, "myissuer", "myaudience", time.Hour)
+	_, err = jwtTokenProvider.ValidateToken(token)
+	if err == nil {
+		t.Errorf("expected error while validating a token signed with another algorithm")
+	}
+}
+
+func Test_jwttoken_ValidateToken_after_rotation(t *testing.T) {
+	keys := newTestKeys(t, AlgES256)
+	jwtTokenProvider := NewJWTTokenProvider(keys, "myissuer", "myaudience", time.Hour)
+	oldKey, _ := keys.SigningKey()
+	token, err := jwtTokenProvider.GenerateToken(models.Claims{ID: 1})
+	if err != nil {
+		t.Fatalf("expected no error generating token, got %v", err)
+	}
+
+	newKey, _ := GenerateKey(AlgEdDSA)
+	keys.Rotate(newKey, time.Now())
+
+	_, err = jwtTokenProvider.ValidateToken(token)
+	if err != nil {
+		t.Errorf("expected a token of the retired key to validate, got %v", err)
+	}
+	publicKeys := jwtTokenProvider.PublicKeys().Keys
+	if len(publicKeys) != 2 || publicKeys[0].Kid != newKey.ID || publicKeys[1].Kid != oldKey.ID {
+		t.Errorf("expected the new and the retired key to be published, got %v", publicKeys)
+	}
+
+	pruned := keys.Prune(time.Now().Add(time.Hour))
+	if len(pruned) != 1 || pruned[0].ID != oldKey.ID {
+		t.Errorf("expected the retired key to be pruned once its tokens expired, pruned %v", pruned)
+	}
+	_, err = jwtTokenProvider.ValidateToken(token)
+	if err == nil {
+		t.Errorf("expected error while validating a token of a pruned key")
+	}
+}
@@ -0,0 +1,273 @@
+package file
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/internal/ports"
+)
+
+func NewAccessTokenRepo(fp string, idGen ports.IDGenerator) *accessTokenRepo {
+	_, err := os.Stat(fp)
+	if os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "file: %s does not exist\n", fp)
+	}
+	if os.IsPermission(err) {
+		fmt.Fprintf(os.Stderr, "not enough permissions for file: %s\n", fp)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "can't use the file: %s\n%s", fp, err.Error())
+		os.Exit(1)
+	}
+
+	ar := &accessTokenRepo{
+		mu:      sync.RWMutex{},
+		fp:      fp,
+		journal: newJournal(fp, accessTokenMutation),
+		idGen:   idGen,
+	}
+
+	err = ar.recover()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to recover the file: %s\n%s\n", fp, err.Error())
+	}
+
+	return ar
+}
+
+type accessTokenRepo struct {
+	mu      sync.RWMutex
+	fp      string
+	journal journal[models.AccessToken]
+	idGen   ports.IDGenerator
+}
+
+func (ar *accessTokenRepo) getAccessTokens() ([]models.AccessToken, error) {
+	tokens := make([]models.AccessToken, 0)
+
+	tokenjson, err := os.ReadFile(ar.fp)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read access tokens from file.\n%w", err)
+	}
+	if len(tokenjson) != 0 {
+		err = json.Unmarshal(tokenjson, &tokens)
+		if err != nil {
+			return nil, fmt.Errorf("unable to unmarshal/decode json.\n%w", err)
+		}
+	}
+
+	return tokens, nil
+}
+
+// load reads the access tokens like getAccessTokens, first recovering the
+// file when it is corrupted. The caller must hold the write lock.
+func (ar *accessTokenRepo) load() ([]models.AccessToken, error) {
+	tokens, err := ar.getAccessTokens()
+	if isCorrupted(err) {
+		err = quarantine(ar.fp)
+		if err != nil {
+			return nil, err
+		}
+		return ar.getAccessTokens()
+	}
+
+	return tokens, err
+}
+
+// read returns the access tokens for the getters, recovering the file under
+// the write lock only when it is corrupted.
+func (ar *accessTokenRepo) read() ([]models.AccessToken, error) {
+	ar.mu.RLock()
+	tokens, err := ar.getAccessTokens()
+	ar.mu.RUnlock()
+	if isCorrupted(err) {
+		ar.mu.Lock()
+		tokens, err = ar.load()
+		ar.mu.Unlock()
+	}
+
+	return tokens, err
+}
+
+func (ar *accessTokenRepo) write(tokens []models.AccessToken) error {
+	tokenjson, _ := json.Marshal(tokens)
+
+	err := writeFileAtomic(ar.fp, tokenjson, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to write access tokens to file.\n%s", err.Error())
+	}
+
+	return nil
+}
+
+// recover replays mutations journaled before a crash onto the access tokens
+// file.
+func (ar *accessTokenRepo) recover() error {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	tokens, err := ar.load()
+	if err != nil {
+		return err
+	}
+
+	entries, err := ar.journal.entries()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	for _, entry := range entries {
+		tokens = entry.apply(tokens)
+	}
+
+	err = ar.write(tokens)
+	if err != nil {
+		return err
+	}
+
+	return ar.journal.clear()
+}
+
+// commit journals the entry and then writes it applied to tokens. The caller
+// must hold the write lock.
+func (ar *accessTokenRepo) commit(entry mutation[models.AccessToken], tokens []models.AccessToken) error {
+	undo, err := ar.journal.append(entry)
+	if err != nil {
+		return err
+	}
+
+	err = ar.write(entry.apply(tokens))
+	if err != nil {
+		undo()
+		return err
+	}
+
+	ar.journal.clear()
+	return nil
+}
+
+func (ar *accessTokenRepo) SaveAccessToken(token models.AccessToken) (models.AccessToken, *errr.AppError) {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+	tokens, err := ar.load()
+	if err != nil {
+		return models.AccessToken{}, errr.NewUnexpectedError(
+			"Unable to save access token due to internal server error",
+		)
+	}
+
+	token.ID = ar.idGen.NextID()
+	err = ar.commit(&putAccessToken{AccessToken: token}, tokens)
+	if err != nil {
+		return models.AccessToken{}, errr.NewUnexpectedError(
+			"Unable to save access token due to internal server error",
+		)
+	}
+
+	return token, nil
+}
+
+func (ar *accessTokenRepo) GetAccessTokens(userID int64) ([]models.AccessToken, *errr.AppError) {
+	tokens, err := ar.read()
+	if err != nil {
+		return nil, errr.NewUnexpectedError("Unable to get access tokens due to internal server error")
+	}
+
+	userTokens := []models.AccessToken{}
+	for _, token := range tokens {
+		if token.UserID == userID {
+			userTokens = append(userTokens, token)
+		}
+	}
+
+	return userTokens, nil
+}
+
+func (ar *accessTokenRepo) GetAccessTokenByHash(hash string) (models.AccessToken, *errr.AppError) {
+	tokens, err := ar.read()
+	if err != nil {
+		return models.AccessToken{}, errr.NewUnexpectedError(
+			"Unable to get access token due to internal server error",
+		)
+	}
+
+	i := slices.IndexFunc(tokens, func(t models.AccessToken) bool { return t.Hash == hash })
+	if i == -1 {
+		return models.AccessToken{}, errr.NewNotFoundError("Access token not found")
+	}
+
+	return tokens[i], nil
+}
+
+func (ar *accessTokenRepo) TouchAccessToken(id int64, usedAt time.Time) *errr.AppError {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+	tokens, err := ar.load()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to update access token due to internal server error")
+	}
+
+	i := slices.IndexFunc(tokens, func(t models.AccessToken) bool { return t.ID == id })
+	if i == -1 {
+		return errr.NewNotFoundError("Access token not found")
+	}
+
+	token := tokens[i]
+	token.LastUsedAt = usedAt
+	err = ar.commit(&putAccessToken{AccessToken: token}, tokens)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to update access token due to internal server error")
+	}
+
+	return nil
+}
+
+func (ar *accessTokenRepo) DeleteAccessToken(id int64, userID int64) *errr.AppError {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+	tokens, err := ar.load()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to delete access token due to internal server error")
+	}
+
+	i := slices.IndexFunc(tokens, func(t models.AccessToken) bool { return t.ID == id })
+	if i == -1 {
+		return errr.NewNotFoundError("Access token not found")
+	}
+	if tokens[i].UserID != userID {
+		return errr.NewUnauthorizedError("Unauthorized to delete access token")
+	}
+
+	err = ar.commit(&deleteAccessToken{ID: id}, tokens)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to delete access token due to internal server error")
+	}
+
+	return nil
+}
+
+func (ar *accessTokenRepo) RevokeUserAccessTokens(userID int64) *errr.AppError {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+	tokens, err := ar.load()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to revoke access tokens due to internal server error")
+	}
+
+	err = ar.commit(&revokeUserAccessTokens{UserID: userID}, tokens)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to revoke access tokens due to internal server error")
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package file
+
+import (
+	"net/http"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/adpaters/idgen"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+)
+
+func Test_accessTokenRepo(t *testing.T) {
+	fp := path.Join(t.TempDir(), "access_tokens.json")
+	os.WriteFile(fp, []byte(`[]`), 0600)
+	ar := NewAccessTokenRepo(fp, idgen.NewSequenceGenerator(0))
+
+	createdAt := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	ci := models.AccessToken{
+		UserID:    1,
+		Name:      "ci",
+		Hash:      "ci hash",
+		Scopes:    []string{models.ScopeTasksRead, models.ScopeTasksWrite},
+		CreatedAt: createdAt,
+		ExpiresAt: createdAt.Add(24 * time.Hour),
+	}
+	cron := models.AccessToken{
+		UserID:    2,
+		Name:      "cron",
+		Hash:      "cron hash",
+		Scopes:    []string{models.ScopeTasksRead},
+		CreatedAt: createdAt,
+	}
+	for _, token := range []*models.AccessToken{&ci, &cron} {
+		saved, appErr := ar.SaveAccessToken(*token)
+		if appErr != nil {
+			t.Fatalf("SaveAccessToken() failed: %v", appErr)
+		}
+		*token = saved
+	}
+	if ci.ID != 1 || cron.ID != 2 {
+		t.Fatalf("SaveAccessToken() gave ids %d and %d, want 1 and 2", ci.ID, cron.ID)
+	}
+
+	usedAt := createdAt.Add(time.Hour)
+	appErr := ar.TouchAccessToken(ci.ID, usedAt)
+	if appErr != nil {
+		t.Fatalf("TouchAccessToken() failed: %v", appErr)
+	}
+	ci.LastUsedAt = usedAt
+	// the tokens are read back from the file.
+	ar = NewAccessTokenRepo(fp, nil)
+
+	got, appErr := ar.GetAccessTokens(1)
+	if appErr != nil || !reflect.DeepEqual(got, []models.AccessToken{ci}) {
+		t.Errorf("GetAccessTokens() = %v, %v, want %v", got, appErr, []models.AccessToken{ci})
+	}
+
+	token, appErr := ar.GetAccessTokenByHash("cron hash")
+	if appErr != nil || !reflect.DeepEqual(token, cron) {
+		t.Errorf("GetAccessTokenByHash() = %v, %v, want %v", token, appErr, cron)
+	}
+
+	appErr = ar.DeleteAccessToken(cron.ID, 1)
+	if appErr == nil || appErr.Code != http.StatusForbidden {
+		t.Errorf("DeleteAccessToken() of another user's token = %v, want a 403", appErr)
+	}
+	appErr = ar.DeleteAccessToken(cron.ID, 2)
+	if appErr != nil {
+		t.Fatalf("DeleteAccessToken() failed: %v", appErr)
+	}
+	_, appErr = ar.GetAccessTokenByHash("cron hash")
+	if appErr == nil || appErr.Code != http.StatusNotFound {
+		t.Errorf("GetAccessTokenByHash() of a deleted token = %v, want a 404", appErr)
+	}
+	appErr = ar.DeleteAccessToken(cron.ID, 2)
+	if appErr == nil || appErr.Code != http.StatusNotFound {
+		t.Errorf("DeleteAccessToken() of a deleted token = %v, want a 404", appErr)
+	}
+}
+
+func Test_accessTokenRepo_RevokeUserAccessTokens(t *testing.T) {
+	fp := path.Join(t.TempDir(), "access_tokens.json")
+	os.WriteFile(fp, []byte(`[]`), 0600)
+	ar := NewAccessTokenRepo(fp, idgen.NewSequenceGenerator(0))
+
+	createdAt := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	for _, token := range []models.AccessToken{
+		{UserID: 1234, Name: "ci", Hash: "a", CreatedAt: createdAt},
+		{UserID: 1234, Name: "cron", Hash: "b", CreatedAt: createdAt},
+		{UserID: 4321, Name: "ci", Hash: "c", CreatedAt: createdAt},
+	} {
+		_, appErr := ar.SaveAccessToken(token)
+		if appErr != nil {
+			t.Fatalf("SaveAccessToken() failed: %v", appErr)
+		}
+	}
+
+	appErr := ar.RevokeUserAccessTokens(1234)
+	if appErr != nil {
+		t.Fatalf("RevokeUserAccessTokens() failed: %v", appErr)
+	}
+	for _, hash := range []string{"a", "b"} {
+		_, appErr = NewAccessTokenRepo(fp, nil).GetAccessTokenByHash(hash)
+		if appErr == nil || appErr.Code != http.StatusNotFound {
+			t.Errorf("GetAccessTokenByHash(%q) after revoking its user's tokens = %v, want a 404", hash, appErr)
+		}
+	}
+	_, appErr = NewAccessTokenRepo(fp, nil).GetAccessTokenByHash("c")
+	if appErr != nil {
+		t.Errorf("GetAccessTokenByHash() of another user's token failed: %v", appErr)
+	}
+}
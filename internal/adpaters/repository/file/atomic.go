@@ -0,0 +1,98 @@
+package file
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// writeFileAtomic replaces the content of fp with data through a synced
+// temporary file that is renamed over fp, so a crash never leaves fp half
+// written. The previous version of fp is kept as fp.bak.
+func writeFileAtomic(fp string, data []byte, perm os.FileMode) error {
+	info, err := os.Stat(fp)
+	if err == nil {
+		perm = info.Mode().Perm()
+		// a live file that was made read-only must stay untouched.
+		f, err := os.OpenFile(fp, os.O_WRONLY, 0)
+		if err != nil {
+			return err
+		}
+		f.Close()
+	}
+
+	dir := filepath.Dir(fp)
+	tmp, err := os.CreateTemp(dir, filepath.Base(fp)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	err = os.Chmod(tmp.Name(), perm)
+	if err != nil {
+		return err
+	}
+
+	if info != nil {
+		os.Remove(fp + ".bak")
+		os.Link(fp, fp+".bak")
+	}
+
+	err = os.Rename(tmp.Name(), fp)
+	if err != nil {
+		return err
+	}
+
+	return syncDir(dir)
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	return d.Sync()
+}
+
+// isCorrupted reports whether err comes from decoding a damaged json file.
+func isCorrupted(err error) bool {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	return errors.As(err, &syntaxErr) || errors.As(err, &typeErr)
+}
+
+// quarantine moves the corrupted file at fp aside and puts its last good
+// backup in its place, or an empty file when there is no usable backup.
+func quarantine(fp string) error {
+	corrupted := fmt.Sprintf("%s.corrupted-%d", fp, time.Now().Unix())
+	err := os.Rename(fp, corrupted)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "file: %s is corrupted, moved it to %s\n", fp, corrupted)
+
+	backup, err := os.ReadFile(fp + ".bak")
+	if err != nil || !json.Valid(backup) {
+		backup = []byte{}
+	} else {
+		fmt.Fprintf(os.Stderr, "file: %s restored from %s.bak\n", fp, fp)
+	}
+
+	return writeFileAtomic(fp, backup, 0644)
+}
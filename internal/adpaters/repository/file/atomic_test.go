@@ -0,0 +1,74 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/adpaters/idgen"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+)
+
+func Test_writeFileAtomic(t *testing.T) {
+	fp := getTempTasksPath(t)
+	os.WriteFile(fp, []byte("old"), 0600)
+
+	err := writeFileAtomic(fp, []byte("new"), 0644)
+	if err != nil {
+		t.Fatalf("writeFileAtomic() failed: %v", err)
+	}
+
+	got, _ := os.ReadFile(fp)
+	if string(got) != "new" {
+		t.Errorf("wanted file content new, got %s", got)
+	}
+	backup, _ := os.ReadFile(fp + ".bak")
+	if string(backup) != "old" {
+		t.Errorf("wanted backup content old, got %s", backup)
+	}
+	info, _ := os.Stat(fp)
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("wanted file mode to be kept as 0600, got %v", info.Mode().Perm())
+	}
+	leftovers, _ := filepath.Glob(fp + ".tmp-*")
+	if len(leftovers) != 0 {
+		t.Errorf("wanted no temporary files left, got %v", leftovers)
+	}
+}
+
+func TestNewTaskRepo_RestoresCorruptedFileFromBackup(t *testing.T) {
+	fp := getTempTasksPath(t)
+	os.WriteFile(fp, []byte(`[{"id": 1, "ti`), 0644)
+	os.WriteFile(fp+".bak", []byte(`[{"id": 1, "title": "title", "user_id": 1234}]`), 0644)
+
+	tr := NewTaskRepo(fp, idgen.NewSequenceGenerator(0))
+
+	got, appErr := tr.GetTasks(models.NewTaskQuery(1234))
+	if appErr != nil {
+		t.Fatalf("GetTasks() failed: %v", appErr)
+	}
+	want := []models.Task{{ID: 1, Title: "title", UserID: 1234, Version: 1}}
+	if !equalTasks(got, want) {
+		t.Errorf("wanted %v, got %v", want, got)
+	}
+	corrupted, _ := filepath.Glob(fp + ".corrupted-*")
+	if len(corrupted) != 1 {
+		t.Errorf("wanted corrupted file to be kept aside, got %v", corrupted)
+	}
+}
+
+func Test_taskRepo_GetTasks_RecoversFileCorruptedAtRuntime(t *testing.T) {
+	fp := getTempTasksPath(t)
+	os.WriteFile(fp, []byte(""), 0644)
+	tr := NewTaskRepo(fp, idgen.NewSequenceGenerator(0))
+	tr.SaveTask(models.Task{Title: "title", Desc: "desc", UserID: 1234})
+	os.WriteFile(fp, []byte("{garbage"), 0644)
+
+	got, appErr := tr.GetTasks(models.NewTaskQuery(1234))
+	if appErr != nil {
+		t.Fatalf("GetTasks() failed: %v", appErr)
+	}
+	if len(got) != 0 {
+		t.Errorf("wanted the last good version of the file, got %v", got)
+	}
+}
@@ -0,0 +1,181 @@
+package file
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/internal/ports"
+)
+
+func NewAuditRepo(fp string, idGen ports.IDGenerator) *auditRepo {
+	_, err := os.Stat(fp)
+	if os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "file: %s does not exist\n", fp)
+	}
+	if os.IsPermission(err) {
+		fmt.Fprintf(os.Stderr, "not enough permissions for file: %s\n", fp)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "can't use the file: %s\n%s", fp, err.Error())
+		os.Exit(1)
+	}
+
+	ar := &auditRepo{
+		mu:      sync.RWMutex{},
+		fp:      fp,
+		journal: newJournal(fp, auditMutation),
+		idGen:   idGen,
+	}
+
+	err = ar.recover()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to recover the file: %s\n%s\n", fp, err.Error())
+	}
+
+	return ar
+}
+
+type auditRepo struct {
+	mu      sync.RWMutex
+	fp      string
+	journal journal[models.AuditEntry]
+	idGen   ports.IDGenerator
+}
+
+func (ar *auditRepo) getAuditEntries() ([]models.AuditEntry, error) {
+	entries := make([]models.AuditEntry, 0)
+
+	entryjson, err := os.ReadFile(ar.fp)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read audit log from file.\n%w", err)
+	}
+	if len(entryjson) != 0 {
+		err = json.Unmarshal(entryjson, &entries)
+		if err != nil {
+			return nil, fmt.Errorf("unable to unmarshal/decode json.\n%w", err)
+		}
+	}
+
+	// Only successful actions were recorded before outcomes were.
+	for i := range entries {
+		if entries[i].Outcome == "" {
+			entries[i].Outcome = models.AuditSucceeded
+		}
+	}
+
+	return entries, nil
+}
+
+// load reads the audit log like getAuditEntries, first recovering the file
+// when it is corrupted. The caller must hold the write lock.
+func (ar *auditRepo) load() ([]models.AuditEntry, error) {
+	entries, err := ar.getAuditEntries()
+	if isCorrupted(err) {
+		err = quarantine(ar.fp)
+		if err != nil {
+			return nil, err
+		}
+		return ar.getAuditEntries()
+	}
+
+	return entries, err
+}
+
+func (ar *auditRepo) write(entries []models.AuditEntry) error {
+	entryjson, _ := json.Marshal(entries)
+
+	err := writeFileAtomic(ar.fp, entryjson, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to write audit log to file.\n%s", err.Error())
+	}
+
+	return nil
+}
+
+// recover replays mutations journaled before a crash onto the audit log file.
+func (ar *auditRepo) recover() error {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	entries, err := ar.load()
+	if err != nil {
+		return err
+	}
+
+	journaled, err := ar.journal.entries()
+	if err != nil {
+		return err
+	}
+	if len(journaled) == 0 {
+		return nil
+	}
+
+	for _, entry := range journaled {
+		entries = entry.apply(entries)
+	}
+
+	err = ar.write(entries)
+	if err != nil {
+		return err
+	}
+
+	return ar.journal.clear()
+}
+
+// commit journals the entry and then writes it applied to entries. The caller
+// must hold the write lock.
+func (ar *auditRepo) commit(entry mutation[models.AuditEntry], entries []models.AuditEntry) error {
+	undo, err := ar.journal.append(entry)
+	if err != nil {
+		return err
+	}
+
+	err = ar.write(entry.apply(entries))
+	if err != nil {
+		undo()
+		return err
+	}
+
+	ar.journal.clear()
+	return nil
+}
+
+func (ar *auditRepo) SaveAuditEntry(entry models.AuditEntry) *errr.AppError {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+	entries, err := ar.load()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to save audit entry due to internal server error")
+	}
+
+	entry.ID = ar.idGen.NextID()
+	err = ar.commit(&putAuditEntry{AuditEntry: entry}, entries)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to save audit entry due to internal server error")
+	}
+
+	return nil
+}
+
+func (ar *auditRepo) GetAuditEntries() ([]models.AuditEntry, *errr.AppError) {
+	ar.mu.RLock()
+	entries, err := ar.getAuditEntries()
+	ar.mu.RUnlock()
+	if isCorrupted(err) {
+		ar.mu.Lock()
+		entries, err = ar.load()
+		ar.mu.Unlock()
+	}
+	if err != nil {
+		return nil, errr.NewUnexpectedError("Unable to get audit log due to internal server error")
+	}
+
+	slices.Reverse(entries)
+	return entries, nil
+}
@@ -0,0 +1,54 @@
+package file
+
+import (
+	"os"
+	"path"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/adpaters/idgen"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+)
+
+func Test_auditRepo(t *testing.T) {
+	fp := path.Join(t.TempDir(), "audit.json")
+	os.WriteFile(fp, []byte(`[]`), 0600)
+	ar := NewAuditRepo(fp, idgen.NewSequenceGenerator(0))
+
+	at := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	for _, entry := range []models.AuditEntry{
+		{ActorID: 1, Action: models.AuditListUsers, Outcome: models.AuditSucceeded, At: at},
+		{
+			ActorID: 2, Action: models.AuditDisableUser, TargetID: 77,
+			Outcome: models.AuditDenied, Reason: "insufficient_role", At: at.Add(time.Minute),
+		},
+	} {
+		appErr := ar.SaveAuditEntry(entry)
+		if appErr != nil {
+			t.Fatalf("SaveAuditEntry() failed: %v", appErr)
+		}
+	}
+
+	got, appErr := NewAuditRepo(fp, nil).GetAuditEntries()
+	want := []models.AuditEntry{
+		{
+			ID: 2, ActorID: 2, Action: models.AuditDisableUser, TargetID: 77,
+			Outcome: models.AuditDenied, Reason: "insufficient_role", At: at.Add(time.Minute),
+		},
+		{ID: 1, ActorID: 1, Action: models.AuditListUsers, Outcome: models.AuditSucceeded, At: at},
+	}
+	if appErr != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAuditEntries() = %v, %v, want %v", got, appErr, want)
+	}
+}
+
+func Test_auditRepo_entries_without_outcome(t *testing.T) {
+	fp := path.Join(t.TempDir(), "audit.json")
+	os.WriteFile(fp, []byte(`[{"id":1,"actor_id":1,"action":"list_users","at":"2025-02-01T12:00:00Z"}]`), 0600)
+
+	got, appErr := NewAuditRepo(fp, nil).GetAuditEntries()
+	if appErr != nil || len(got) != 1 || got[0].Outcome != models.AuditSucceeded {
+		t.Errorf("GetAuditEntries() = %v, %v, want an entry that succeeded", got, appErr)
+	}
+}
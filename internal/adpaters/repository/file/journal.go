@@ -0,0 +1,504 @@
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+)
+
+const (
+	opPut    = "put"
+	opDelete = "delete"
+	// opBatch applies the mutations of a batch, journaled as one so a crash
+	// keeps all of them or none.
+	opBatch        = "batch"
+	opRevokeFamily = "revoke_family"
+	opRevokeUser   = "revoke_user"
+	opPrune        = "prune"
+)
+
+// mutation is one change of the records of a repository file. Mutations
+// carry the full resulting records so replaying them more than once is
+// harmless.
+type mutation[T any] interface {
+	// op names the mutation in the journal.
+	op() string
+	apply(records []T) []T
+}
+
+// put replaces the record that same reports to be the one put, or adds it.
+func put[T any](records []T, record T, same func(T) bool) []T {
+	i := slices.IndexFunc(records, same)
+	if i == -1 {
+		return append(records, record)
+	}
+	records[i] = record
+	return records
+}
+
+// putTask adds or replaces a task.
+type putTask struct {
+	Task models.Task `json:"task"`
+}
+
+func (m *putTask) op() string { return opPut }
+
+func (m *putTask) apply(tasks []models.Task) []models.Task {
+	return put(tasks, m.Task, func(t models.Task) bool { return t.ID == m.Task.ID })
+}
+
+// deleteTask deletes a task along with its subtasks.
+type deleteTask struct {
+	ID int64 `json:"id"`
+}
+
+func (m *deleteTask) op() string { return opDelete }
+
+func (m *deleteTask) apply(tasks []models.Task) []models.Task {
+	descendants := models.Descendants(tasks, m.ID)
+	return slices.DeleteFunc(tasks, func(t models.Task) bool {
+		return t.ID == m.ID || descendants[t.ID]
+	})
+}
+
+// taskBatch applies its mutations in order.
+type taskBatch struct {
+	Entries []mutation[models.Task]
+}
+
+// putTasks journals the tasks changed in place as one batch.
+func putTasks(changed []models.Task) *taskBatch {
+	entries := make([]mutation[models.Task], len(changed))
+	for i := range changed {
+		entries[i] = &putTask{Task: changed[i]}
+	}
+	return &taskBatch{Entries: entries}
+}
+
+func (m *taskBatch) op() string { return opBatch }
+
+func (m *taskBatch) apply(tasks []models.Task) []models.Task {
+	for _, entry := range m.Entries {
+		tasks = entry.apply(tasks)
+	}
+	return tasks
+}
+
+func (m *taskBatch) MarshalJSON() ([]byte, error) {
+	entries := make([]json.RawMessage, len(m.Entries))
+	for i, entry := range m.Entries {
+		line, err := encodeMutation(entry)
+		if err != nil {
+			return nil, err
+		}
+		entries[i] = line
+	}
+	return json.Marshal(struct {
+		Entries []json.RawMessage `json:"entries"`
+	}{entries})
+}
+
+func (m *taskBatch) UnmarshalJSON(data []byte) error {
+	var batch struct {
+		Entries []json.RawMessage `json:"entries"`
+	}
+	err := json.Unmarshal(data, &batch)
+	if err != nil {
+		return err
+	}
+
+	m.Entries = make([]mutation[models.Task], len(batch.Entries))
+	for i, line := range batch.Entries {
+		m.Entries[i], err = decodeMutation(line, taskMutation)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func taskMutation(op string) mutation[models.Task] {
+	switch op {
+	case opPut:
+		return &putTask{}
+	case opDelete:
+		return &deleteTask{}
+	case opBatch:
+		return &taskBatch{}
+	}
+	return nil
+}
+
+// putUser adds or replaces a user.
+type putUser struct {
+	User models.User `json:"user"`
+}
+
+func (m *putUser) op() string { return opPut }
+
+func (m *putUser) apply(users []models.User) []models.User {
+	return put(users, m.User, func(u models.User) bool { return u.ID == m.User.ID })
+}
+
+func userMutation(op string) mutation[models.User] {
+	if op == opPut {
+		return &putUser{}
+	}
+	return nil
+}
+
+// putLabel adds or replaces a label.
+type putLabel struct {
+	Label models.Label `json:"label"`
+}
+
+func (m *putLabel) op() string { return opPut }
+
+func (m *putLabel) apply(labels []models.Label) []models.Label {
+	return put(labels, m.Label, func(l models.Label) bool { return l.ID == m.Label.ID })
+}
+
+// deleteLabel deletes a label, which the tasks file still has to be rid of
+// until the mutation is cleared from the journal.
+type deleteLabel struct {
+	ID int64 `json:"id"`
+}
+
+func (m *deleteLabel) op() string { return opDelete }
+
+func (m *deleteLabel) apply(labels []models.Label) []models.Label {
+	return slices.DeleteFunc(labels, func(l models.Label) bool { return l.ID == m.ID })
+}
+
+func labelMutation(op string) mutation[models.Label] {
+	switch op {
+	case opPut:
+		return &putLabel{}
+	case opDelete:
+		return &deleteLabel{}
+	}
+	return nil
+}
+
+// putProject adds or replaces a project.
+type putProject struct {
+	Project models.Project `json:"project"`
+}
+
+func (m *putProject) op() string { return opPut }
+
+func (m *putProject) apply(projects []models.Project) []models.Project {
+	return put(projects, m.Project, func(p models.Project) bool { return p.ID == m.Project.ID })
+}
+
+// deleteProject deletes a project, which the tasks file still has to be rid
+// of until the mutation is cleared from the journal.
+type deleteProject struct {
+	ID int64 `json:"id"`
+}
+
+func (m *deleteProject) op() string { return opDelete }
+
+func (m *deleteProject) apply(projects []models.Project) []models.Project {
+	return slices.DeleteFunc(projects, func(p models.Project) bool { return p.ID == m.ID })
+}
+
+func projectMutation(op string) mutation[models.Project] {
+	switch op {
+	case opPut:
+		return &putProject{}
+	case opDelete:
+		return &deleteProject{}
+	}
+	return nil
+}
+
+// putWorkflow replaces the workflow of its user.
+type putWorkflow struct {
+	Workflow models.Workflow `json:"workflow"`
+}
+
+func (m *putWorkflow) op() string { return opPut }
+
+func (m *putWorkflow) apply(workflows []models.Workflow) []models.Workflow {
+	return put(workflows, m.Workflow, func(w models.Workflow) bool { return w.UserID == m.Workflow.UserID })
+}
+
+func workflowMutation(op string) mutation[models.Workflow] {
+	if op == opPut {
+		return &putWorkflow{}
+	}
+	return nil
+}
+
+// putRefreshToken adds or replaces a refresh token.
+type putRefreshToken struct {
+	RefreshToken models.RefreshToken `json:"refresh_token"`
+}
+
+func (m *putRefreshToken) op() string { return opPut }
+
+func (m *putRefreshToken) apply(tokens []models.RefreshToken) []models.RefreshToken {
+	return put(tokens, m.RefreshToken, func(t models.RefreshToken) bool {
+		return t.Hash == m.RefreshToken.Hash
+	})
+}
+
+// revokeTokenFamily removes every refresh token of a family.
+type revokeTokenFamily struct {
+	FamilyID string `json:"key"`
+}
+
+func (m *revokeTokenFamily) op() string { return opRevokeFamily }
+
+func (m *revokeTokenFamily) apply(tokens []models.RefreshToken) []models.RefreshToken {
+	return slices.DeleteFunc(tokens, func(t models.RefreshToken) bool { return t.FamilyID == m.FamilyID })
+}
+
+// revokeUserRefreshTokens removes every refresh token of a user.
+type revokeUserRefreshTokens struct {
+	UserID int64 `json:"id"`
+}
+
+func (m *revokeUserRefreshTokens) op() string { return opRevokeUser }
+
+func (m *revokeUserRefreshTokens) apply(tokens []models.RefreshToken) []models.RefreshToken {
+	return slices.DeleteFunc(tokens, func(t models.RefreshToken) bool { return t.UserID == m.UserID })
+}
+
+func refreshTokenMutation(op string) mutation[models.RefreshToken] {
+	switch op {
+	case opPut:
+		return &putRefreshToken{}
+	case opRevokeFamily:
+		return &revokeTokenFamily{}
+	case opRevokeUser:
+		return &revokeUserRefreshTokens{}
+	}
+	return nil
+}
+
+// putRevocation adds a revocation, revocations are never replaced.
+type putRevocation struct {
+	Revocation models.Revocation `json:"revocation"`
+}
+
+func (m *putRevocation) op() string { return opPut }
+
+func (m *putRevocation) apply(revocations []models.Revocation) []models.Revocation {
+	// replaying a put must not add the revocation twice.
+	if slices.ContainsFunc(revocations, m.Revocation.IsSame) {
+		return revocations
+	}
+	return append(revocations, m.Revocation)
+}
+
+// pruneRevocations removes the revocations that expired before a time.
+type pruneRevocations struct {
+	Now time.Time `json:"time"`
+}
+
+func (m *pruneRevocations) op() string { return opPrune }
+
+func (m *pruneRevocations) apply(revocations []models.Revocation) []models.Revocation {
+	return slices.DeleteFunc(revocations, func(r models.Revocation) bool { return r.IsExpired(m.Now) })
+}
+
+func revocationMutation(op string) mutation[models.Revocation] {
+	switch op {
+	case opPut:
+		return &putRevocation{}
+	case opPrune:
+		return &pruneRevocations{}
+	}
+	return nil
+}
+
+// putAuditEntry appends an entry to the audit log, entries are never
+// replaced.
+type putAuditEntry struct {
+	AuditEntry models.AuditEntry `json:"audit_entry"`
+}
+
+func (m *putAuditEntry) op() string { return opPut }
+
+func (m *putAuditEntry) apply(entries []models.AuditEntry) []models.AuditEntry {
+	if slices.ContainsFunc(entries, func(e models.AuditEntry) bool { return e.ID == m.AuditEntry.ID }) {
+		return entries
+	}
+	return append(entries, m.AuditEntry)
+}
+
+func auditMutation(op string) mutation[models.AuditEntry] {
+	if op == opPut {
+		return &putAuditEntry{}
+	}
+	return nil
+}
+
+// putAccessToken adds or replaces an access token.
+type putAccessToken struct {
+	AccessToken models.AccessToken `json:"access_token"`
+}
+
+func (m *putAccessToken) op() string { return opPut }
+
+func (m *putAccessToken) apply(tokens []models.AccessToken) []models.AccessToken {
+	return put(tokens, m.AccessToken, func(t models.AccessToken) bool { return t.ID == m.AccessToken.ID })
+}
+
+// deleteAccessToken deletes an access token.
+type deleteAccessToken struct {
+	ID int64 `json:"id"`
+}
+
+func (m *deleteAccessToken) op() string { return opDelete }
+
+func (m *deleteAccessToken) apply(tokens []models.AccessToken) []models.AccessToken {
+	return slices.DeleteFunc(tokens, func(t models.AccessToken) bool { return t.ID == m.ID })
+}
+
+// revokeUserAccessTokens removes every access token of a user.
+type revokeUserAccessTokens struct {
+	UserID int64 `json:"id"`
+}
+
+func (m *revokeUserAccessTokens) op() string { return opRevokeUser }
+
+func (m *revokeUserAccessTokens) apply(tokens []models.AccessToken) []models.AccessToken {
+	return slices.DeleteFunc(tokens, func(t models.AccessToken) bool { return t.UserID == m.UserID })
+}
+
+func accessTokenMutation(op string) mutation[models.AccessToken] {
+	switch op {
+	case opPut:
+		return &putAccessToken{}
+	case opDelete:
+		return &deleteAccessToken{}
+	case opRevokeUser:
+		return &revokeUserAccessTokens{}
+	}
+	return nil
+}
+
+// encodeMutation writes m as one JSON object of its op and its fields.
+func encodeMutation[T any](m mutation[T]) ([]byte, error) {
+	fields, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	op, err := json.Marshal(m.op())
+	if err != nil {
+		return nil, err
+	}
+
+	line := append([]byte(`{"op":`), op...)
+	if len(fields) > len("{}") {
+		line = append(line, ',')
+	}
+	return append(line, fields[1:]...), nil
+}
+
+// decodeMutation reads a mutation written by encodeMutation, making it with
+// mutation for its op.
+func decodeMutation[T any](data []byte, mutation func(op string) mutation[T]) (mutation[T], error) {
+	var entry struct {
+		Op string `json:"op"`
+	}
+	err := json.Unmarshal(data, &entry)
+	if err != nil {
+		return nil, err
+	}
+
+	m := mutation(entry.Op)
+	if m == nil {
+		return nil, fmt.Errorf("unknown journal op %q", entry.Op)
+	}
+	return m, json.Unmarshal(data, m)
+}
+
+// journal is the append-only write-ahead log kept next to a repository file
+// of records T. A mutation is appended and synced before the file is
+// rewritten, and the journal is cleared once the rewrite succeeded.
+type journal[T any] struct {
+	fp string
+	// mutation makes an empty mutation for an op to read the journal into.
+	mutation func(op string) mutation[T]
+}
+
+func newJournal[T any](fp string, mutation func(op string) mutation[T]) journal[T] {
+	return journal[T]{
+		fp:       fp + ".journal",
+		mutation: mutation,
+	}
+}
+
+// append durably adds entry to the journal and returns a function that takes
+// it back out again.
+func (j journal[T]) append(entry mutation[T]) (undo func(), err error) {
+	line, err := encodeMutation(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(j.fp, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = f.Write(append(line, '\n'))
+	if err == nil {
+		err = f.Sync()
+	}
+	if err != nil {
+		os.Truncate(j.fp, info.Size())
+		return nil, err
+	}
+
+	return func() { os.Truncate(j.fp, info.Size()) }, nil
+}
+
+// entries returns the journaled mutations in order. A trailing entry torn by
+// a crash is ignored.
+func (j journal[T]) entries() ([]mutation[T], error) {
+	data, err := os.ReadFile(j.fp)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []mutation[T]{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	for scanner.Scan() {
+		entry, err := decodeMutation(scanner.Bytes(), j.mutation)
+		if err != nil {
+			break
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func (j journal[T]) clear() error {
+	err := os.Remove(j.fp)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,185 @@
+package file
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/adpaters/idgen"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+)
+
+func Test_journal_entries(t *testing.T) {
+	tests := []struct {
+		name        string
+		setupFile   func(fp string)
+		wantEntries int
+	}{
+		{
+			name:        "no journal",
+			setupFile:   func(fp string) {},
+			wantEntries: 0,
+		},
+		{
+			name: "complete entries",
+			setupFile: func(fp string) {
+				os.WriteFile(fp, []byte(`{"op":"put","task":{"id":1}}
+{"op":"delete","id":1}
+`), 0644)
+			},
+			wantEntries: 2,
+		},
+		{
+			name: "trailing entry torn by a crash",
+			setupFile: func(fp string) {
+				os.WriteFile(fp, []byte(`{"op":"put","task":{"id":1}}
+{"op":"put","ta`), 0644)
+			},
+			wantEntries: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j := newJournal(getTempTasksPath(t), taskMutation)
+			tt.setupFile(j.fp)
+			got, err := j.entries()
+			if err != nil {
+				t.Fatalf("entries() failed: %v", err)
+			}
+			if len(got) != tt.wantEntries {
+				t.Errorf("entries() returned %d entries, want %d", len(got), tt.wantEntries)
+			}
+		})
+	}
+}
+
+func Test_journal_appendUndo(t *testing.T) {
+	j := newJournal(getTempTasksPath(t), taskMutation)
+
+	_, err := j.append(&putTask{Task: models.Task{ID: 1}})
+	if err != nil {
+		t.Fatalf("append() failed: %v", err)
+	}
+	undo, err := j.append(&deleteTask{ID: 1})
+	if err != nil {
+		t.Fatalf("append() failed: %v", err)
+	}
+	undo()
+
+	got, _ := j.entries()
+	if len(got) != 1 || got[0].op() != opPut {
+		t.Errorf("wanted only the put entry after undo, got %v", got)
+	}
+}
+
+func Test_journal_batch(t *testing.T) {
+	j := newJournal(getTempTasksPath(t), taskMutation)
+
+	batch := &taskBatch{Entries: []mutation[models.Task]{
+		&putTask{Task: models.Task{ID: 2, Title: "new", Version: 1}},
+		&deleteTask{ID: 1},
+	}}
+	_, err := j.append(batch)
+	if err != nil {
+		t.Fatalf("append() failed: %v", err)
+	}
+
+	got, err := j.entries()
+	if err != nil || len(got) != 1 {
+		t.Fatalf("entries() = %v, %v, want the batch", got, err)
+	}
+	tasks := got[0].apply([]models.Task{{ID: 1, Title: "old", Version: 1}})
+	want := []models.Task{{ID: 2, Title: "new", Version: 1}}
+	if !equalTasks(tasks, want) {
+		t.Errorf("replayed batch gave %v, want %v", tasks, want)
+	}
+}
+
+func TestNewTaskRepo_ReplaysDetach(t *testing.T) {
+	tasksjson := []byte(`[{"id": 1, "title": "a", "user_id": 1234, "label_ids": [5], "project_id": 6, "version": 1}]`)
+	fp := getTempTasksPath(t)
+	os.WriteFile(fp, tasksjson, 0644)
+	tr := NewTaskRepo(fp, idgen.NewSequenceGenerator(0))
+
+	err := tr.detachLabel(5)
+	if err != nil {
+		t.Fatalf("detachLabel() failed: %v", err)
+	}
+	detached, _ := tr.GetTasks(models.NewTaskQuery(1234))
+	want := []models.Task{{ID: 1, Title: "a", UserID: 1234, ProjectID: 6, Version: 2}}
+	if !equalTasks(detached, want) {
+		t.Fatalf("tasks after detachLabel() = %v, want %v", detached, want)
+	}
+	detachedjson, _ := os.ReadFile(fp)
+
+	// The detach is journaled as the tasks it changed, so replaying it after
+	// a crash gives the same tasks whether or not the file was written.
+	for name, taskjson := range map[string][]byte{"before": tasksjson, "after": detachedjson} {
+		os.WriteFile(fp, taskjson, 0644)
+		_, err = tr.journal.append(putTasks(detached))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, _ := NewTaskRepo(fp, idgen.NewSequenceGenerator(0)).GetTasks(models.NewTaskQuery(1234))
+		if !equalTasks(got, want) {
+			t.Errorf("tasks replayed %s the file was written = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestNewRevocationRepo_ReplaysJournal(t *testing.T) {
+	fp := path.Join(t.TempDir(), "revocations.json")
+	os.WriteFile(fp, []byte(`[{"token_id":"jti","user_id":1234,"expires_at":"2025-02-01T12:00:00Z"}]`), 0600)
+	// the same revocation journaled again, with its time in another zone.
+	os.WriteFile(fp+".journal", []byte(`{"op":"put","revocation":{"token_id":"jti","user_id":1234,"expires_at":"2025-02-01T17:30:00+05:30"}}
+{"op":"put","revocation":{"token_id":"other","user_id":1234,"expires_at":"2025-02-01T12:00:00Z"}}
+`), 0600)
+
+	vr := NewRevocationRepo(fp)
+
+	got, err := vr.getRevocations()
+	if err != nil || len(got) != 2 {
+		t.Errorf("revocations after replay = %v, %v, want jti and other once each", got, err)
+	}
+}
+
+func TestNewTaskRepo_ReplaysJournal(t *testing.T) {
+	fp := getTempTasksPath(t)
+	os.WriteFile(fp, []byte(`[{"id": 1, "title": "old", "user_id": 1234}]`), 0644)
+	os.WriteFile(fp+".journal", []byte(`{"op":"put","task":{"id":2,"title":"new","user_id":1234}}
+{"op":"put","task":{"id":2,"title":"newer","user_id":1234}}
+{"op":"delete","id":1}
+`), 0644)
+
+	tr := NewTaskRepo(fp, idgen.NewSequenceGenerator(0))
+
+	got, appErr := tr.GetTasks(models.NewTaskQuery(1234))
+	if appErr != nil {
+		t.Fatalf("GetTasks() failed: %v", appErr)
+	}
+	want := []models.Task{{ID: 2, Title: "newer", UserID: 1234, Version: 1}}
+	if !equalTasks(got, want) {
+		t.Errorf("wanted %v, got %v", want, got)
+	}
+	if _, err := os.Stat(fp + ".journal"); !os.IsNotExist(err) {
+		t.Errorf("wanted journal to be cleared after replay, got err %v", err)
+	}
+}
+
+func TestNewUserRepo_ReplaysJournal(t *testing.T) {
+	fp := path.Join(t.TempDir(), "users.json")
+	os.WriteFile(fp, []byte(""), 0644)
+	os.WriteFile(fp+".journal", []byte(`{"op":"put","user":{"id":1,"username":"user"}}
+`), 0644)
+
+	ur := NewUserRepo(fp, idgen.NewSequenceGenerator(0))
+
+	got, appErr := ur.GetUserByUsername("user")
+	if appErr != nil {
+		t.Fatalf("GetUserByUsername() failed: %v", appErr)
+	}
+	if got.ID != 1 {
+		t.Errorf("wanted user with id 1, got %v", got)
+	}
+}
@@ -0,0 +1,300 @@
+package file
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/internal/ports"
+)
+
+// NewLabelRepo stores labels in fp. Deleting a label also detaches it from
+// the tasks kept by tasks.
+func NewLabelRepo(fp string, tasks *taskRepo, idGen ports.IDGenerator) *labelRepo {
+	_, err := os.Stat(fp)
+	if os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "file: %s does not exist\n", fp)
+	}
+	if os.IsPermission(err) {
+		fmt.Fprintf(os.Stderr, "not enough permissions for file: %s\n", fp)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "can't use the file: %s\n%s", fp, err.Error())
+		os.Exit(1)
+	}
+
+	lr := &labelRepo{
+		mu:      sync.RWMutex{},
+		fp:      fp,
+		journal: newJournal(fp, labelMutation),
+		tasks:   tasks,
+		idGen:   idGen,
+	}
+
+	err = lr.recover()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to recover the file: %s\n%s\n", fp, err.Error())
+	}
+
+	return lr
+}
+
+type labelRepo struct {
+	mu      sync.RWMutex
+	fp      string
+	journal journal[models.Label]
+	tasks   *taskRepo
+	idGen   ports.IDGenerator
+}
+
+func (lr *labelRepo) getLabels() ([]models.Label, error) {
+	labels := make([]models.Label, 0)
+
+	labeljson, err := os.ReadFile(lr.fp)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read labels from file.\n%w", err)
+	}
+	if len(labeljson) != 0 {
+		err = json.Unmarshal(labeljson, &labels)
+		if err != nil {
+			return nil, fmt.Errorf("unable to unmarshal/decode json.\n%w", err)
+		}
+	}
+
+	return labels, nil
+}
+
+// load reads the labels like getLabels, first recovering the file when it is
+// corrupted. The caller must hold the write lock.
+func (lr *labelRepo) load() ([]models.Label, error) {
+	labels, err := lr.getLabels()
+	if isCorrupted(err) {
+		err = quarantine(lr.fp)
+		if err != nil {
+			return nil, err
+		}
+		return lr.getLabels()
+	}
+
+	return labels, err
+}
+
+func (lr *labelRepo) write(labels []models.Label) error {
+	labeljson, _ := json.Marshal(labels)
+
+	err := writeFileAtomic(lr.fp, labeljson, 0644)
+	if err != nil {
+		return fmt.Errorf("unable to write labels to file.\n%s", err.Error())
+	}
+
+	return nil
+}
+
+// commit journals entry and then writes labels, the result of applying it.
+func (lr *labelRepo) commit(entry mutation[models.Label], labels []models.Label) error {
+	undo, err := lr.journal.append(entry)
+	if err != nil {
+		return fmt.Errorf("unable to journal labels.\n%s", err.Error())
+	}
+
+	err = lr.write(labels)
+	if err != nil {
+		undo()
+		return err
+	}
+
+	lr.journal.clear()
+	return nil
+}
+
+// recover replays mutations journaled before a crash onto the labels file,
+// then takes labels that no longer exist off the tasks, finishing any label
+// deletion that had not yet reached the tasks file.
+func (lr *labelRepo) recover() error {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	labels, err := lr.load()
+	if err != nil {
+		return err
+	}
+
+	entries, err := lr.journal.entries()
+	if err != nil {
+		return err
+	}
+	if len(entries) != 0 {
+		for _, entry := range entries {
+			labels = entry.apply(labels)
+		}
+
+		err = lr.write(labels)
+		if err != nil {
+			return err
+		}
+		err = lr.journal.clear()
+		if err != nil {
+			return err
+		}
+	}
+
+	exists := make(map[int64]bool, len(labels))
+	for _, label := range labels {
+		exists[label.ID] = true
+	}
+	return lr.tasks.detachLabels(func(id int64) bool { return !exists[id] })
+}
+
+func hasLabelNamed(labels []models.Label, userID int64, name string, exceptID int64) bool {
+	for _, label := range labels {
+		if label.UserID == userID && label.ID != exceptID && strings.EqualFold(label.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (lr *labelRepo) SaveLabel(label models.Label) (models.Label, *errr.AppError) {
+	label.ID = lr.idGen.NextID()
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	labels, err := lr.load()
+	if err != nil {
+		return models.Label{}, errr.NewUnexpectedError("Unable to save label due to internal server error")
+	}
+
+	if hasLabelNamed(labels, label.UserID, label.Name, 0) {
+		return models.Label{}, errr.NewDuplicateError("label already exists")
+	}
+
+	labels = append(labels, label)
+
+	err = lr.commit(&putLabel{Label: label}, labels)
+	if err != nil {
+		return models.Label{}, errr.NewUnexpectedError("Unable to save label due to internal server error")
+	}
+
+	return label, nil
+}
+
+func (lr *labelRepo) UpdateLabel(id int64, label models.Label) *errr.AppError {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	labels, err := lr.load()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to update label due to internal server error")
+	}
+
+	i := -1
+	for j := range labels {
+		if labels[j].ID == id {
+			i = j
+			break
+		}
+	}
+	if i == -1 {
+		return errr.NewNotFoundError("no label found with id")
+	}
+	if labels[i].UserID != label.UserID {
+		return errr.NewUnauthorizedError("Unauthorized to update label")
+	}
+
+	if len(label.Name) != 0 {
+		if hasLabelNamed(labels, label.UserID, label.Name, id) {
+			return errr.NewDuplicateError("label already exists")
+		}
+		labels[i].Name = label.Name
+	}
+	if len(label.Color) != 0 {
+		labels[i].Color = label.Color
+	}
+	updated := labels[i]
+
+	err = lr.commit(&putLabel{Label: updated}, labels)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to update label due to internal server error")
+	}
+
+	return nil
+}
+
+// DeleteLabel removes the label and then detaches it from every task. The
+// tasks are detached once the lock of the labels is released, as a
+// transaction of the tasks reads labels while holding the lock of the tasks.
+// A task saved meanwhile was checked against the labels within its
+// transaction, so it either missed the label or is detached along with the
+// others. A crash before the tasks are detached is finished on the next
+// start.
+func (lr *labelRepo) DeleteLabel(id int64, userID int64) *errr.AppError {
+	appErr := lr.removeLabel(id, userID)
+	if appErr != nil {
+		return appErr
+	}
+
+	err := lr.tasks.detachLabel(id)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to delete label due to internal server error")
+	}
+
+	return nil
+}
+
+// removeLabel takes the label with id out of the labels file.
+func (lr *labelRepo) removeLabel(id int64, userID int64) *errr.AppError {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	labels, err := lr.load()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to delete label due to internal server error")
+	}
+
+	i := -1
+	for j := range labels {
+		if labels[j].ID == id {
+			i = j
+			break
+		}
+	}
+	if i == -1 {
+		return errr.NewNotFoundError("no label found with id")
+	}
+	if labels[i].UserID != userID {
+		return errr.NewUnauthorizedError("Unauthorized to delete label")
+	}
+
+	entry := &deleteLabel{ID: id}
+	err = lr.commit(entry, entry.apply(labels))
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to delete label due to internal server error")
+	}
+
+	return nil
+}
+
+func (lr *labelRepo) GetLabels(userID int64) ([]models.Label, *errr.AppError) {
+	lr.mu.RLock()
+	labels, err := lr.getLabels()
+	lr.mu.RUnlock()
+	if isCorrupted(err) {
+		lr.mu.Lock()
+		labels, err = lr.load()
+		lr.mu.Unlock()
+	}
+	if err != nil {
+		return nil, errr.NewUnexpectedError("Unable to get labels due to internal server error")
+	}
+
+	filteredLabels := []models.Label{}
+	for _, label := range labels {
+		if label.UserID == userID {
+			filteredLabels = append(filteredLabels, label)
+		}
+	}
+
+	return filteredLabels, nil
+}
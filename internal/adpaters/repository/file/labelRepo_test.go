@@ -0,0 +1,164 @@
+package file
+
+import (
+	"net/http"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/adpaters/idgen"
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/internal/ports"
+)
+
+func newTestLabelRepo(t *testing.T, tasksjson, labelsjson string) (*labelRepo, *taskRepo) {
+	dir := t.TempDir()
+	tasksFile := path.Join(dir, "tasks.json")
+	labelsFile := path.Join(dir, "labels.json")
+	os.WriteFile(tasksFile, []byte(tasksjson), 0644)
+	os.WriteFile(labelsFile, []byte(labelsjson), 0644)
+
+	tr := NewTaskRepo(tasksFile, idgen.NewSequenceGenerator(100))
+	return NewLabelRepo(labelsFile, tr, idgen.NewSequenceGenerator(200)), tr
+}
+
+func Test_labelRepo_SaveLabel(t *testing.T) {
+	lr, _ := newTestLabelRepo(t, `[]`, `[{"id":1,"user_id":1234,"name":"Work","color":"#ffffff"}]`)
+
+	label, appErr := lr.SaveLabel(models.Label{UserID: 1234, Name: "home", Color: "#000000"})
+	if appErr != nil {
+		t.Fatalf("SaveLabel() failed: %v", appErr)
+	}
+	if label.ID != 201 {
+		t.Errorf("SaveLabel() id = %d, want 201", label.ID)
+	}
+
+	_, appErr = lr.SaveLabel(models.Label{UserID: 1234, Name: "work", Color: "#000000"})
+	if appErr == nil || appErr.Code != http.StatusConflict {
+		t.Errorf("SaveLabel() with a taken name = %v, want conflict", appErr)
+	}
+
+	_, appErr = lr.SaveLabel(models.Label{UserID: 99, Name: "work", Color: "#000000"})
+	if appErr != nil {
+		t.Errorf("SaveLabel() with a name taken by another user failed: %v", appErr)
+	}
+}
+
+func Test_labelRepo_DeleteLabel(t *testing.T) {
+	tasksjson := `[
+		{"id":1,"title":"a","user_id":1234,"label_ids":[1,2]},
+		{"id":2,"title":"b","user_id":1234,"label_ids":[1]},
+		{"id":3,"title":"c","user_id":1234}
+	]`
+	labelsjson := `[
+		{"id":1,"user_id":1234,"name":"work","color":"#ffffff"},
+		{"id":2,"user_id":1234,"name":"home","color":"#000000"}
+	]`
+
+	t.Run("other user can't delete", func(t *testing.T) {
+		lr, _ := newTestLabelRepo(t, tasksjson, labelsjson)
+		appErr := lr.DeleteLabel(1, 99)
+		if appErr == nil || appErr.Code != http.StatusForbidden {
+			t.Errorf("DeleteLabel() = %v, want forbidden", appErr)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		lr, _ := newTestLabelRepo(t, tasksjson, labelsjson)
+		appErr := lr.DeleteLabel(5, 1234)
+		if appErr == nil || appErr.Code != http.StatusNotFound {
+			t.Errorf("DeleteLabel() = %v, want not found", appErr)
+		}
+	})
+
+	t.Run("detaches label from tasks", func(t *testing.T) {
+		lr, tr := newTestLabelRepo(t, tasksjson, labelsjson)
+		appErr := lr.DeleteLabel(1, 1234)
+		if appErr != nil {
+			t.Fatalf("DeleteLabel() failed: %v", appErr)
+		}
+
+		labels, _ := lr.GetLabels(1234)
+		if len(labels) != 1 || labels[0].ID != 2 {
+			t.Errorf("labels after delete = %v, want only label 2", labels)
+		}
+		tasks, _ := tr.GetTasks(models.NewTaskQuery(1234))
+		want := []models.Task{
+			{ID: 1, Title: "a", UserID: 1234, LabelIDs: []int64{2}, Version: 2},
+			{ID: 2, Title: "b", UserID: 1234, Version: 2},
+			{ID: 3, Title: "c", UserID: 1234, Version: 1},
+		}
+		if !equalTasks(tasks, want) {
+			t.Errorf("tasks after delete = %v, want %v", tasks, want)
+		}
+	})
+
+	t.Run("journaled delete is finished on recovery", func(t *testing.T) {
+		lr, tr := newTestLabelRepo(t, tasksjson, labelsjson)
+		_, err := lr.journal.append(&deleteLabel{ID: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		lr = NewLabelRepo(lr.fp, tr, idgen.NewSequenceGenerator(0))
+
+		labels, _ := lr.GetLabels(1234)
+		if len(labels) != 1 || labels[0].ID != 2 {
+			t.Errorf("labels after recovery = %v, want only label 2", labels)
+		}
+		tasks, _ := tr.GetTasks(models.NewTaskQuery(1234))
+		for _, task := range tasks {
+			if task.HasLabel(1) {
+				t.Errorf("task %d still has the deleted label", task.ID)
+			}
+		}
+	})
+
+	t.Run("labels that no longer exist are detached on recovery", func(t *testing.T) {
+		lr, tr := newTestLabelRepo(t, tasksjson, `[{"id":2,"user_id":1234,"name":"home","color":"#000000"}]`)
+
+		tasks, _ := tr.GetTasks(models.NewTaskQuery(1234))
+		want := []models.Task{
+			{ID: 1, Title: "a", UserID: 1234, LabelIDs: []int64{2}, Version: 2},
+			{ID: 2, Title: "b", UserID: 1234, Version: 2},
+			{ID: 3, Title: "c", UserID: 1234, Version: 1},
+		}
+		if !equalTasks(tasks, want) {
+			t.Errorf("tasks after recovery = %v, want %v", tasks, want)
+		}
+		if labels, _ := lr.GetLabels(1234); len(labels) != 1 {
+			t.Errorf("labels after recovery = %v, want label 2", labels)
+		}
+	})
+
+	t.Run("task saved while the label is deleted loses it too", func(t *testing.T) {
+		lr, tr := newTestLabelRepo(t, tasksjson, labelsjson)
+
+		deleted := make(chan *errr.AppError)
+		appErr := tr.Transaction(func(repo ports.TaskRepo) *errr.AppError {
+			// The label was checked before the deletion removes it, and its
+			// tasks are detached only once the transaction is done.
+			go func() { deleted <- lr.DeleteLabel(1, 1234) }()
+			for labels, _ := lr.GetLabels(1234); len(labels) == 2; labels, _ = lr.GetLabels(1234) {
+				time.Sleep(time.Millisecond)
+			}
+			_, appErr := repo.SaveTask(models.Task{Title: "d", UserID: 1234, LabelIDs: []int64{1}})
+			return appErr
+		})
+		if appErr != nil {
+			t.Fatalf("Transaction() failed: %v", appErr)
+		}
+		if appErr := <-deleted; appErr != nil {
+			t.Fatalf("DeleteLabel() failed: %v", appErr)
+		}
+
+		tasks, _ := tr.GetTasks(models.NewTaskQuery(1234))
+		for _, task := range tasks {
+			if task.HasLabel(1) {
+				t.Errorf("task %d still has the deleted label", task.ID)
+			}
+		}
+	})
+}
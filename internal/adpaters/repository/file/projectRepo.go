@@ -0,0 +1,325 @@
+package file
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/internal/ports"
+)
+
+// NewProjectRepo stores projects in fp. Deleting a project also moves its
+// tasks, kept by tasks, out of it.
+func NewProjectRepo(fp string, tasks *taskRepo, idGen ports.IDGenerator) *projectRepo {
+	_, err := os.Stat(fp)
+	if os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "file: %s does not exist\n", fp)
+	}
+	if os.IsPermission(err) {
+		fmt.Fprintf(os.Stderr, "not enough permissions for file: %s\n", fp)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "can't use the file: %s\n%s", fp, err.Error())
+		os.Exit(1)
+	}
+
+	pr := &projectRepo{
+		mu:      sync.RWMutex{},
+		fp:      fp,
+		journal: newJournal(fp, projectMutation),
+		tasks:   tasks,
+		idGen:   idGen,
+	}
+
+	err = pr.recover()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to recover the file: %s\n%s\n", fp, err.Error())
+	}
+
+	return pr
+}
+
+type projectRepo struct {
+	mu      sync.RWMutex
+	fp      string
+	journal journal[models.Project]
+	tasks   *taskRepo
+	idGen   ports.IDGenerator
+}
+
+func (pr *projectRepo) getProjects() ([]models.Project, error) {
+	projects := make([]models.Project, 0)
+
+	projectjson, err := os.ReadFile(pr.fp)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read projects from file.\n%w", err)
+	}
+	if len(projectjson) != 0 {
+		err = json.Unmarshal(projectjson, &projects)
+		if err != nil {
+			return nil, fmt.Errorf("unable to unmarshal/decode json.\n%w", err)
+		}
+	}
+
+	return projects, nil
+}
+
+// load reads the projects like getProjects, first recovering the file when it
+// is corrupted. The caller must hold the write lock.
+func (pr *projectRepo) load() ([]models.Project, error) {
+	projects, err := pr.getProjects()
+	if isCorrupted(err) {
+		err = quarantine(pr.fp)
+		if err != nil {
+			return nil, err
+		}
+		return pr.getProjects()
+	}
+
+	return projects, err
+}
+
+func (pr *projectRepo) write(projects []models.Project) error {
+	projectjson, _ := json.Marshal(projects)
+
+	err := writeFileAtomic(pr.fp, projectjson, 0644)
+	if err != nil {
+		return fmt.Errorf("unable to write projects to file.\n%s", err.Error())
+	}
+
+	return nil
+}
+
+// commit journals entry and then writes projects, the result of applying it.
+func (pr *projectRepo) commit(entry mutation[models.Project], projects []models.Project) error {
+	undo, err := pr.journal.append(entry)
+	if err != nil {
+		return fmt.Errorf("unable to journal projects.\n%s", err.Error())
+	}
+
+	err = pr.write(projects)
+	if err != nil {
+		undo()
+		return err
+	}
+
+	pr.journal.clear()
+	return nil
+}
+
+// recover replays mutations journaled before a crash onto the projects file,
+// then moves the tasks out of projects that no longer exist, finishing any
+// project deletion that had not yet reached the tasks file.
+func (pr *projectRepo) recover() error {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	projects, err := pr.load()
+	if err != nil {
+		return err
+	}
+
+	entries, err := pr.journal.entries()
+	if err != nil {
+		return err
+	}
+	if len(entries) != 0 {
+		for _, entry := range entries {
+			projects = entry.apply(projects)
+		}
+
+		err = pr.write(projects)
+		if err != nil {
+			return err
+		}
+		err = pr.journal.clear()
+		if err != nil {
+			return err
+		}
+	}
+
+	exists := make(map[int64]bool, len(projects))
+	for _, project := range projects {
+		exists[project.ID] = true
+	}
+	return pr.tasks.detachProjects(func(id int64) bool { return !exists[id] })
+}
+
+func hasProjectNamed(projects []models.Project, userID int64, name string, exceptID int64) bool {
+	for _, project := range projects {
+		if project.UserID == userID && project.ID != exceptID &&
+			strings.EqualFold(project.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// findProject returns the index of the project with id, which userID must
+// own to action it.
+func findProject(projects []models.Project, id int64, userID int64, action string) (int, *errr.AppError) {
+	i := slices.IndexFunc(projects, func(p models.Project) bool { return p.ID == id })
+	if i == -1 {
+		return -1, errr.NewNotFoundError("no project found with id")
+	}
+	if projects[i].UserID != userID {
+		return -1, errr.NewUnauthorizedError("Unauthorized to " + action + " project")
+	}
+	return i, nil
+}
+
+// SaveProject adds the project after the other projects of its user.
+func (pr *projectRepo) SaveProject(project models.Project) (models.Project, *errr.AppError) {
+	project.ID = pr.idGen.NextID()
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	projects, err := pr.load()
+	if err != nil {
+		return models.Project{}, errr.NewUnexpectedError("Unable to save project due to internal server error")
+	}
+
+	if hasProjectNamed(projects, project.UserID, project.Name, 0) {
+		return models.Project{}, errr.NewDuplicateError("project already exists")
+	}
+
+	project.Position = 0
+	for _, p := range projects {
+		if p.UserID == project.UserID && p.Position >= project.Position {
+			project.Position = p.Position + 1
+		}
+	}
+	projects = append(projects, project)
+
+	err = pr.commit(&putProject{Project: project}, projects)
+	if err != nil {
+		return models.Project{}, errr.NewUnexpectedError("Unable to save project due to internal server error")
+	}
+
+	return project, nil
+}
+
+func (pr *projectRepo) GetProject(id int64, userID int64) (models.Project, *errr.AppError) {
+	pr.mu.RLock()
+	projects, err := pr.getProjects()
+	pr.mu.RUnlock()
+	if isCorrupted(err) {
+		pr.mu.Lock()
+		projects, err = pr.load()
+		pr.mu.Unlock()
+	}
+	if err != nil {
+		return models.Project{}, errr.NewUnexpectedError("Unable to get project due to internal server error")
+	}
+
+	i, appErr := findProject(projects, id, userID, "view")
+	if appErr != nil {
+		return models.Project{}, appErr
+	}
+
+	return projects[i], nil
+}
+
+func (pr *projectRepo) UpdateProject(id int64, project models.Project) *errr.AppError {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	projects, err := pr.load()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to update project due to internal server error")
+	}
+
+	i, appErr := findProject(projects, id, project.UserID, "update")
+	if appErr != nil {
+		return appErr
+	}
+	if hasProjectNamed(projects, project.UserID, project.Name, id) {
+		return errr.NewDuplicateError("project already exists")
+	}
+
+	project.ID = id
+	projects[i] = project
+
+	err = pr.commit(&putProject{Project: project}, projects)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to update project due to internal server error")
+	}
+
+	return nil
+}
+
+// DeleteProject removes the project and then moves its tasks out of it. The
+// tasks are moved once the lock of the projects is released, as a
+// transaction of the tasks reads projects while holding the lock of the
+// tasks. A task saved meanwhile was checked against the projects within its
+// transaction, so it either missed the project or is moved out along with
+// the others. A crash before the tasks are moved is finished on the next
+// start.
+func (pr *projectRepo) DeleteProject(id int64, userID int64) *errr.AppError {
+	appErr := pr.removeProject(id, userID)
+	if appErr != nil {
+		return appErr
+	}
+
+	err := pr.tasks.detachProject(id)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to delete project due to internal server error")
+	}
+
+	return nil
+}
+
+// removeProject takes the project with id out of the projects file.
+func (pr *projectRepo) removeProject(id int64, userID int64) *errr.AppError {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	projects, err := pr.load()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to delete project due to internal server error")
+	}
+
+	_, appErr := findProject(projects, id, userID, "delete")
+	if appErr != nil {
+		return appErr
+	}
+
+	entry := &deleteProject{ID: id}
+	err = pr.commit(entry, entry.apply(projects))
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to delete project due to internal server error")
+	}
+
+	return nil
+}
+
+// GetProjects returns the projects of the user in order of their position.
+func (pr *projectRepo) GetProjects(userID int64) ([]models.Project, *errr.AppError) {
+	pr.mu.RLock()
+	projects, err := pr.getProjects()
+	pr.mu.RUnlock()
+	if isCorrupted(err) {
+		pr.mu.Lock()
+		projects, err = pr.load()
+		pr.mu.Unlock()
+	}
+	if err != nil {
+		return nil, errr.NewUnexpectedError("Unable to get projects due to internal server error")
+	}
+
+	filteredProjects := []models.Project{}
+	for _, project := range projects {
+		if project.UserID == userID {
+			filteredProjects = append(filteredProjects, project)
+		}
+	}
+	slices.SortStableFunc(filteredProjects, func(a, b models.Project) int {
+		return cmp.Compare(a.Position, b.Position)
+	})
+
+	return filteredProjects, nil
+}
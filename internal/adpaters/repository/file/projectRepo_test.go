@@ -0,0 +1,168 @@
+package file
+
+import (
+	"net/http"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/adpaters/idgen"
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/internal/ports"
+)
+
+func newTestProjectRepo(t *testing.T, tasksjson, projectsjson string) (*projectRepo, *taskRepo) {
+	dir := t.TempDir()
+	tasksFile := path.Join(dir, "tasks.json")
+	projectsFile := path.Join(dir, "projects.json")
+	os.WriteFile(tasksFile, []byte(tasksjson), 0644)
+	os.WriteFile(projectsFile, []byte(projectsjson), 0644)
+
+	tr := NewTaskRepo(tasksFile, idgen.NewSequenceGenerator(100))
+	return NewProjectRepo(projectsFile, tr, idgen.NewSequenceGenerator(200)), tr
+}
+
+func Test_projectRepo_SaveProject(t *testing.T) {
+	pr, _ := newTestProjectRepo(t, `[]`, `[
+		{"id":1,"user_id":1234,"name":"Work","color":"#ffffff","position":3},
+		{"id":2,"user_id":99,"name":"Home","color":"#ffffff","position":7}
+	]`)
+
+	project, appErr := pr.SaveProject(models.Project{UserID: 1234, Name: "home", Color: "#000000"})
+	if appErr != nil {
+		t.Fatalf("SaveProject() failed: %v", appErr)
+	}
+	if project.ID != 201 || project.Position != 4 {
+		t.Errorf("SaveProject() = %+v, want id 201 at position 4", project)
+	}
+
+	_, appErr = pr.SaveProject(models.Project{UserID: 1234, Name: "work", Color: "#000000"})
+	if appErr == nil || appErr.Code != http.StatusConflict {
+		t.Errorf("SaveProject() with a taken name = %v, want conflict", appErr)
+	}
+
+	projects, _ := pr.GetProjects(1234)
+	if len(projects) != 2 || projects[0].ID != 1 || projects[1].ID != 201 {
+		t.Errorf("GetProjects() = %v, want projects 1 and 201 in order", projects)
+	}
+}
+
+func Test_projectRepo_GetProject(t *testing.T) {
+	pr, _ := newTestProjectRepo(t, `[]`, `[{"id":1,"user_id":1234,"name":"Work","color":"#ffffff"}]`)
+
+	_, appErr := pr.GetProject(1, 99)
+	if appErr == nil || appErr.Code != http.StatusForbidden {
+		t.Errorf("GetProject() of another user = %v, want forbidden", appErr)
+	}
+	_, appErr = pr.GetProject(5, 1234)
+	if appErr == nil || appErr.Code != http.StatusNotFound {
+		t.Errorf("GetProject() of a missing project = %v, want not found", appErr)
+	}
+	project, appErr := pr.GetProject(1, 1234)
+	if appErr != nil || project.Name != "Work" {
+		t.Errorf("GetProject() = %v, %v, want project Work", project, appErr)
+	}
+}
+
+func Test_projectRepo_DeleteProject(t *testing.T) {
+	tasksjson := `[
+		{"id":1,"title":"a","user_id":1234,"project_id":1},
+		{"id":2,"title":"b","user_id":1234,"project_id":2}
+	]`
+	projectsjson := `[
+		{"id":1,"user_id":1234,"name":"work","color":"#ffffff"},
+		{"id":2,"user_id":1234,"name":"home","color":"#000000","position":1}
+	]`
+
+	t.Run("other user can't delete", func(t *testing.T) {
+		pr, _ := newTestProjectRepo(t, tasksjson, projectsjson)
+		appErr := pr.DeleteProject(1, 99)
+		if appErr == nil || appErr.Code != http.StatusForbidden {
+			t.Errorf("DeleteProject() = %v, want forbidden", appErr)
+		}
+	})
+
+	t.Run("moves tasks out of the project", func(t *testing.T) {
+		pr, tr := newTestProjectRepo(t, tasksjson, projectsjson)
+		appErr := pr.DeleteProject(1, 1234)
+		if appErr != nil {
+			t.Fatalf("DeleteProject() failed: %v", appErr)
+		}
+
+		projects, _ := pr.GetProjects(1234)
+		if len(projects) != 1 || projects[0].ID != 2 {
+			t.Errorf("projects after delete = %v, want only project 2", projects)
+		}
+		tasks, _ := tr.GetTasks(models.NewTaskQuery(1234))
+		want := []models.Task{
+			{ID: 1, Title: "a", UserID: 1234, Version: 2},
+			{ID: 2, Title: "b", UserID: 1234, ProjectID: 2, Version: 1},
+		}
+		if !equalTasks(tasks, want) {
+			t.Errorf("tasks after delete = %v, want %v", tasks, want)
+		}
+	})
+
+	t.Run("journaled delete is finished on recovery", func(t *testing.T) {
+		pr, tr := newTestProjectRepo(t, tasksjson, projectsjson)
+		_, err := pr.journal.append(&deleteProject{ID: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pr = NewProjectRepo(pr.fp, tr, idgen.NewSequenceGenerator(0))
+
+		projects, _ := pr.GetProjects(1234)
+		if len(projects) != 1 || projects[0].ID != 2 {
+			t.Errorf("projects after recovery = %v, want only project 2", projects)
+		}
+		tasks, _ := tr.GetTasks(models.NewTaskQuery(1234))
+		if tasks[0].ProjectID != 0 {
+			t.Errorf("task 1 is still in the deleted project")
+		}
+	})
+
+	t.Run("tasks leave projects that no longer exist on recovery", func(t *testing.T) {
+		_, tr := newTestProjectRepo(t, tasksjson, `[{"id":2,"user_id":1234,"name":"home","color":"#000000","position":1}]`)
+
+		tasks, _ := tr.GetTasks(models.NewTaskQuery(1234))
+		want := []models.Task{
+			{ID: 1, Title: "a", UserID: 1234, Version: 2},
+			{ID: 2, Title: "b", UserID: 1234, ProjectID: 2, Version: 1},
+		}
+		if !equalTasks(tasks, want) {
+			t.Errorf("tasks after recovery = %v, want %v", tasks, want)
+		}
+	})
+
+	t.Run("task saved while the project is deleted leaves it too", func(t *testing.T) {
+		pr, tr := newTestProjectRepo(t, tasksjson, projectsjson)
+
+		deleted := make(chan *errr.AppError)
+		appErr := tr.Transaction(func(repo ports.TaskRepo) *errr.AppError {
+			// The project was checked before the deletion removes it, and
+			// its tasks are moved out only once the transaction is done.
+			go func() { deleted <- pr.DeleteProject(1, 1234) }()
+			for projects, _ := pr.GetProjects(1234); len(projects) == 2; projects, _ = pr.GetProjects(1234) {
+				time.Sleep(time.Millisecond)
+			}
+			_, appErr := repo.SaveTask(models.Task{Title: "c", UserID: 1234, ProjectID: 1})
+			return appErr
+		})
+		if appErr != nil {
+			t.Fatalf("Transaction() failed: %v", appErr)
+		}
+		if appErr := <-deleted; appErr != nil {
+			t.Fatalf("DeleteProject() failed: %v", appErr)
+		}
+
+		tasks, _ := tr.GetTasks(models.NewTaskQuery(1234))
+		for _, task := range tasks {
+			if task.ProjectID == 1 {
+				t.Errorf("task %d is still in the deleted project", task.ID)
+			}
+		}
+	})
+}
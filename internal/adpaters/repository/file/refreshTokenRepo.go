@@ -0,0 +1,233 @@
+package file
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+)
+
+func NewRefreshTokenRepo(fp string) *refreshTokenRepo {
+	_, err := os.Stat(fp)
+	if os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "file: %s does not exist\n", fp)
+	}
+	if os.IsPermission(err) {
+		fmt.Fprintf(os.Stderr, "not enough permissions for file: %s\n", fp)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "can't use the file: %s\n%s", fp, err.Error())
+		os.Exit(1)
+	}
+
+	rr := &refreshTokenRepo{
+		mu:      sync.RWMutex{},
+		fp:      fp,
+		journal: newJournal(fp, refreshTokenMutation),
+	}
+
+	err = rr.recover()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to recover the file: %s\n%s\n", fp, err.Error())
+	}
+
+	return rr
+}
+
+type refreshTokenRepo struct {
+	mu      sync.RWMutex
+	fp      string
+	journal journal[models.RefreshToken]
+}
+
+func (rr *refreshTokenRepo) getRefreshTokens() ([]models.RefreshToken, error) {
+	tokens := make([]models.RefreshToken, 0)
+
+	tokenjson, err := os.ReadFile(rr.fp)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read refresh tokens from file.\n%w", err)
+	}
+	if len(tokenjson) != 0 {
+		err = json.Unmarshal(tokenjson, &tokens)
+		if err != nil {
+			return nil, fmt.Errorf("unable to unmarshal/decode json.\n%w", err)
+		}
+	}
+
+	return tokens, nil
+}
+
+// load reads the refresh tokens like getRefreshTokens, first recovering the
+// file when it is corrupted. The caller must hold the write lock.
+func (rr *refreshTokenRepo) load() ([]models.RefreshToken, error) {
+	tokens, err := rr.getRefreshTokens()
+	if isCorrupted(err) {
+		err = quarantine(rr.fp)
+		if err != nil {
+			return nil, err
+		}
+		return rr.getRefreshTokens()
+	}
+
+	return tokens, err
+}
+
+func (rr *refreshTokenRepo) write(tokens []models.RefreshToken) error {
+	tokenjson, _ := json.Marshal(tokens)
+
+	err := writeFileAtomic(rr.fp, tokenjson, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to write refresh tokens to file.\n%s", err.Error())
+	}
+
+	return nil
+}
+
+// recover replays mutations journaled before a crash onto the refresh tokens
+// file.
+func (rr *refreshTokenRepo) recover() error {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	tokens, err := rr.load()
+	if err != nil {
+		return err
+	}
+
+	entries, err := rr.journal.entries()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	for _, entry := range entries {
+		tokens = entry.apply(tokens)
+	}
+
+	err = rr.write(tokens)
+	if err != nil {
+		return err
+	}
+
+	return rr.journal.clear()
+}
+
+// commit journals the entry and then writes it applied to tokens. The caller
+// must hold the write lock.
+func (rr *refreshTokenRepo) commit(entry mutation[models.RefreshToken], tokens []models.RefreshToken) error {
+	undo, err := rr.journal.append(entry)
+	if err != nil {
+		return err
+	}
+
+	err = rr.write(entry.apply(tokens))
+	if err != nil {
+		undo()
+		return err
+	}
+
+	rr.journal.clear()
+	return nil
+}
+
+func (rr *refreshTokenRepo) SaveRefreshToken(token models.RefreshToken) *errr.AppError {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	tokens, err := rr.load()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to save refresh token due to internal server error")
+	}
+
+	err = rr.commit(&putRefreshToken{RefreshToken: token}, tokens)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to save refresh token due to internal server error")
+	}
+
+	return nil
+}
+
+func (rr *refreshTokenRepo) GetRefreshToken(hash string) (models.RefreshToken, *errr.AppError) {
+	rr.mu.RLock()
+	tokens, err := rr.getRefreshTokens()
+	rr.mu.RUnlock()
+	if isCorrupted(err) {
+		rr.mu.Lock()
+		tokens, err = rr.load()
+		rr.mu.Unlock()
+	}
+	if err != nil {
+		return models.RefreshToken{}, errr.NewUnexpectedError(
+			"Unable to get refresh token due to internal server error",
+		)
+	}
+
+	i := slices.IndexFunc(tokens, func(t models.RefreshToken) bool { return t.Hash == hash })
+	if i == -1 {
+		return models.RefreshToken{}, errr.NewNotFoundError("Refresh token not found")
+	}
+
+	return tokens[i], nil
+}
+
+func (rr *refreshTokenRepo) UseRefreshToken(hash string, usedAt time.Time) *errr.AppError {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	tokens, err := rr.load()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to use refresh token due to internal server error")
+	}
+
+	i := slices.IndexFunc(tokens, func(t models.RefreshToken) bool { return t.Hash == hash })
+	if i == -1 || !tokens[i].UsedAt.IsZero() {
+		return errr.NewDuplicateError("Refresh token was already used")
+	}
+
+	token := tokens[i]
+	token.UsedAt = usedAt
+	err = rr.commit(&putRefreshToken{RefreshToken: token}, tokens)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to use refresh token due to internal server error")
+	}
+
+	return nil
+}
+
+func (rr *refreshTokenRepo) RevokeTokenFamily(familyID string) *errr.AppError {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	tokens, err := rr.load()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to revoke refresh tokens due to internal server error")
+	}
+
+	err = rr.commit(&revokeTokenFamily{FamilyID: familyID}, tokens)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to revoke refresh tokens due to internal server error")
+	}
+
+	return nil
+}
+
+func (rr *refreshTokenRepo) RevokeUserTokens(userID int64) *errr.AppError {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	tokens, err := rr.load()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to revoke refresh tokens due to internal server error")
+	}
+
+	err = rr.commit(&revokeUserRefreshTokens{UserID: userID}, tokens)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to revoke refresh tokens due to internal server error")
+	}
+
+	return nil
+}
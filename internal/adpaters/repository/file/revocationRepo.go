@@ -0,0 +1,202 @@
+package file
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+)
+
+func NewRevocationRepo(fp string) *revocationRepo {
+	_, err := os.Stat(fp)
+	if os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "file: %s does not exist\n", fp)
+	}
+	if os.IsPermission(err) {
+		fmt.Fprintf(os.Stderr, "not enough permissions for file: %s\n", fp)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "can't use the file: %s\n%s", fp, err.Error())
+		os.Exit(1)
+	}
+
+	vr := &revocationRepo{
+		mu:      sync.RWMutex{},
+		fp:      fp,
+		journal: newJournal(fp, revocationMutation),
+	}
+
+	err = vr.recover()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to recover the file: %s\n%s\n", fp, err.Error())
+	}
+
+	return vr
+}
+
+type revocationRepo struct {
+	mu      sync.RWMutex
+	fp      string
+	journal journal[models.Revocation]
+}
+
+func (vr *revocationRepo) getRevocations() ([]models.Revocation, error) {
+	revocations := make([]models.Revocation, 0)
+
+	revocationjson, err := os.ReadFile(vr.fp)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read revocations from file.\n%w", err)
+	}
+	if len(revocationjson) != 0 {
+		err = json.Unmarshal(revocationjson, &revocations)
+		if err != nil {
+			return nil, fmt.Errorf("unable to unmarshal/decode json.\n%w", err)
+		}
+	}
+
+	return revocations, nil
+}
+
+// load reads the revocations like getRevocations, first recovering the
+// file when it is corrupted. The caller must hold the write lock.
+func (vr *revocationRepo) load() ([]models.Revocation, error) {
+	revocations, err := vr.getRevocations()
+	if isCorrupted(err) {
+		err = quarantine(vr.fp)
+		if err != nil {
+			return nil, err
+		}
+		return vr.getRevocations()
+	}
+
+	return revocations, err
+}
+
+func (vr *revocationRepo) write(revocations []models.Revocation) error {
+	revocationjson, _ := json.Marshal(revocations)
+
+	err := writeFileAtomic(vr.fp, revocationjson, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to write revocations to file.\n%s", err.Error())
+	}
+
+	return nil
+}
+
+// recover replays mutations journaled before a crash onto the revocations
+// file.
+func (vr *revocationRepo) recover() error {
+	vr.mu.Lock()
+	defer vr.mu.Unlock()
+
+	revocations, err := vr.load()
+	if err != nil {
+		return err
+	}
+
+	entries, err := vr.journal.entries()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	for _, entry := range entries {
+		revocations = entry.apply(revocations)
+	}
+
+	err = vr.write(revocations)
+	if err != nil {
+		return err
+	}
+
+	return vr.journal.clear()
+}
+
+// commit journals the entry and then writes it applied to revocations. The
+// caller must hold the write lock.
+func (vr *revocationRepo) commit(entry mutation[models.Revocation], revocations []models.Revocation) error {
+	undo, err := vr.journal.append(entry)
+	if err != nil {
+		return err
+	}
+
+	err = vr.write(entry.apply(revocations))
+	if err != nil {
+		undo()
+		return err
+	}
+
+	vr.journal.clear()
+	return nil
+}
+
+func (vr *revocationRepo) SaveRevocation(revocation models.Revocation) *errr.AppError {
+	vr.mu.Lock()
+	defer vr.mu.Unlock()
+	revocations, err := vr.load()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to revoke token due to internal server error")
+	}
+
+	err = vr.commit(&putRevocation{Revocation: revocation}, revocations)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to revoke token due to internal server error")
+	}
+
+	return nil
+}
+
+func (vr *revocationRepo) IsRevoked(claims models.Claims) (bool, *errr.AppError) {
+	vr.mu.RLock()
+	revocations, err := vr.getRevocations()
+	vr.mu.RUnlock()
+	if isCorrupted(err) {
+		vr.mu.Lock()
+		revocations, err = vr.load()
+		vr.mu.Unlock()
+	}
+	if err != nil {
+		return false, errr.NewUnexpectedError("Unable to check token due to internal server error")
+	}
+
+	for _, revocation := range revocations {
+		if revocation.Revokes(claims) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (vr *revocationRepo) PruneRevocations(now time.Time) (int, *errr.AppError) {
+	vr.mu.Lock()
+	defer vr.mu.Unlock()
+	revocations, err := vr.load()
+	if err != nil {
+		return 0, errr.NewUnexpectedError("Unable to prune revocations due to internal server error")
+	}
+
+	pruned := 0
+	for _, revocation := range revocations {
+		if revocation.IsExpired(now) {
+			pruned++
+		}
+	}
+	if pruned == 0 {
+		return 0, nil
+	}
+
+	err = vr.commit(&pruneRevocations{Now: now}, revocations)
+	if err != nil {
+		return 0, errr.NewUnexpectedError("Unable to prune revocations due to internal server error")
+	}
+
+	return pruned, nil
+}
@@ -0,0 +1,58 @@
+package file
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+)
+
+func Test_revocationRepo(t *testing.T) {
+	fp := path.Join(t.TempDir(), "revocations.json")
+	os.WriteFile(fp, []byte(`[]`), 0600)
+	vr := NewRevocationRepo(fp)
+
+	now := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	revocations := []models.Revocation{
+		{TokenID: "revoked", UserID: 1234, ExpiresAt: now.Add(time.Minute)},
+		{UserID: 4321, IssuedUntil: now, ExpiresAt: now.Add(15 * time.Minute)},
+	}
+	for _, revocation := range revocations {
+		appErr := vr.SaveRevocation(revocation)
+		if appErr != nil {
+			t.Fatalf("SaveRevocation() failed: %v", appErr)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		claims models.Claims
+		want   bool
+	}{
+		{"revoked token", models.Claims{ID: 1234, TokenID: "revoked", IssuedAt: now}, true},
+		{"another token", models.Claims{ID: 1234, TokenID: "other", IssuedAt: now}, false},
+		{"token of a logged out user", models.Claims{ID: 4321, TokenID: "a", IssuedAt: now.Add(-time.Minute)}, true},
+		{"token issued after logging out", models.Claims{ID: 4321, TokenID: "b", IssuedAt: now.Add(time.Second)}, false},
+	}
+	for _, tt := range tests {
+		got, appErr := NewRevocationRepo(fp).IsRevoked(tt.claims)
+		if appErr != nil || got != tt.want {
+			t.Errorf("IsRevoked() of %s = %v, %v, want %v", tt.name, got, appErr, tt.want)
+		}
+	}
+
+	pruned, appErr := vr.PruneRevocations(now.Add(time.Minute))
+	if appErr != nil || pruned != 1 {
+		t.Errorf("PruneRevocations() = %d, %v, want 1 pruned", pruned, appErr)
+	}
+	revoked, _ := NewRevocationRepo(fp).IsRevoked(tests[0].claims)
+	if revoked {
+		t.Errorf("IsRevoked() after pruning its revocation = true, want false")
+	}
+	revoked, _ = NewRevocationRepo(fp).IsRevoked(tests[2].claims)
+	if !revoked {
+		t.Errorf("IsRevoked() of an unexpired revocation after pruning = false, want true")
+	}
+}
@@ -4,14 +4,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/Jashanveer-Singh/todo-go/internal/errr"
 	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/internal/ports"
 )
 
-func NewTaskRepo(fp string) *taskRepo {
+func NewTaskRepo(fp string, idGen ports.IDGenerator) *taskRepo {
 	_, err := os.Stat(fp)
 	if os.IsNotExist(err) {
 		fmt.Fprintf(os.Stderr, "file: %s does not exist\n", fp)
@@ -25,39 +27,98 @@ func NewTaskRepo(fp string) *taskRepo {
 		os.Exit(1)
 	}
 
-	return &taskRepo{
-		mu: sync.RWMutex{},
-		fp: fp,
+	tr := &taskRepo{
+		mu:      sync.RWMutex{},
+		fp:      fp,
+		journal: newJournal(fp, taskMutation),
+		idGen:   idGen,
 	}
+
+	err = tr.recover()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to recover the file: %s\n%s\n", fp, err.Error())
+	}
+
+	rekeyed, err := tr.rekeyDuplicateIDs()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to re-key duplicate task ids in: %s\n%s\n", fp, err.Error())
+	}
+	if rekeyed > 0 {
+		fmt.Fprintf(os.Stderr, "re-keyed %d tasks with duplicate ids in: %s\n", rekeyed, fp)
+	}
+
+	return tr
 }
 
 type taskRepo struct {
-	mu sync.RWMutex
-	fp string
+	mu      sync.RWMutex
+	fp      string
+	journal journal[models.Task]
+	idGen   ports.IDGenerator
+	// tx is the transaction of a repo handed out by Transaction.
+	tx *transaction
+}
+
+// transaction holds the tasks as changed so far by a repo handed out by
+// Transaction, along with the mutations that changed them.
+type transaction struct {
+	tasks   []models.Task
+	entries []mutation[models.Task]
 }
 
 func (tr *taskRepo) getTasks() ([]models.Task, error) {
+	if tr.tx != nil {
+		return slices.Clone(tr.tx.tasks), nil
+	}
+
 	tasks := make([]models.Task, 0)
 
 	taskjson, err := os.ReadFile(tr.fp)
 	if err != nil {
-		return nil, fmt.Errorf("unable to read Tasks from file.\n%s", err.Error())
+		return nil, fmt.Errorf("unable to read Tasks from file.\n%w", err)
 	}
 	if len(taskjson) != 0 {
 
 		err = json.Unmarshal(taskjson, &tasks)
 		if err != nil {
-			return nil, fmt.Errorf("unable to unmarshal/decode json.\n%s", err.Error())
+			return nil, fmt.Errorf("unable to unmarshal/decode json.\n%w", err)
+		}
+	}
+
+	// Tasks written before workflows have no done flag, but the status they
+	// were completed with is always terminal. Tasks written before versions
+	// start at the first.
+	for i := range tasks {
+		if tasks[i].Status == models.StatusDone {
+			tasks[i].Done = true
+		}
+		if tasks[i].Version == 0 {
+			tasks[i].Version = 1
 		}
 	}
 
 	return tasks, nil
 }
 
+// load reads the tasks like getTasks, first recovering the file when it is
+// corrupted. The caller must hold the write lock.
+func (tr *taskRepo) load() ([]models.Task, error) {
+	tasks, err := tr.getTasks()
+	if isCorrupted(err) {
+		err = quarantine(tr.fp)
+		if err != nil {
+			return nil, err
+		}
+		return tr.getTasks()
+	}
+
+	return tasks, err
+}
+
 func (tr *taskRepo) write(tasks []models.Task) error {
 	taskjson, _ := json.Marshal(tasks)
 
-	err := os.WriteFile(tr.fp, taskjson, 0644)
+	err := writeFileAtomic(tr.fp, taskjson, 0644)
 	if err != nil {
 		return fmt.Errorf("unable to write tasks to file.\n%s", err.Error())
 	}
@@ -65,50 +126,298 @@ func (tr *taskRepo) write(tasks []models.Task) error {
 	return nil
 }
 
-func (tr *taskRepo) SaveTask(task models.Task) *errr.AppError {
-	task.ID = time.Now().Unix()
+// commit journals entry and then writes tasks, the result of applying it.
+// Within a transaction both are only kept until the transaction commits.
+func (tr *taskRepo) commit(entry mutation[models.Task], tasks []models.Task) error {
+	if tr.tx != nil {
+		tr.tx.entries = append(tr.tx.entries, entry)
+		tr.tx.tasks = tasks
+		return nil
+	}
+
+	undo, err := tr.journal.append(entry)
+	if err != nil {
+		return fmt.Errorf("unable to journal tasks.\n%s", err.Error())
+	}
+
+	err = tr.write(tasks)
+	if err != nil {
+		undo()
+		return err
+	}
+
+	tr.journal.clear()
+	return nil
+}
+
+// recover replays mutations journaled before a crash onto the tasks file.
+func (tr *taskRepo) recover() error {
 	tr.mu.Lock()
 	defer tr.mu.Unlock()
-	tasks, err := tr.getTasks()
+
+	tasks, err := tr.load()
 	if err != nil {
-		return errr.NewUnexpectedError("Unable to save task due to internal server error")
+		return err
 	}
 
-	tasks = append(tasks, task)
+	entries, err := tr.journal.entries()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	for _, entry := range entries {
+		tasks = entry.apply(tasks)
+	}
 
 	err = tr.write(tasks)
 	if err != nil {
-		return errr.NewUnexpectedError("Unable to save task due to internal server error")
+		return err
+	}
+
+	return tr.journal.clear()
+}
+
+// rekeyDuplicateIDs gives a fresh id to every task sharing its id with an
+// earlier task, a leftover from ids being the creation time in seconds.
+func (tr *taskRepo) rekeyDuplicateIDs() (int, error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	tasks, err := tr.load()
+	if err != nil {
+		return 0, err
+	}
+
+	taken := make(map[int64]bool, len(tasks))
+	for _, task := range tasks {
+		taken[task.ID] = true
+	}
+
+	rekeyed := 0
+	seen := make(map[int64]bool, len(tasks))
+	for i := range tasks {
+		if seen[tasks[i].ID] {
+			id := tr.idGen.NextID()
+			for taken[id] {
+				id = tr.idGen.NextID()
+			}
+			taken[id] = true
+			tasks[i].ID = id
+			rekeyed++
+		}
+		seen[tasks[i].ID] = true
+	}
+	if rekeyed == 0 {
+		return 0, nil
+	}
+
+	return rekeyed, tr.write(tasks)
+}
+
+// Transaction holds the lock of the tasks while fn runs. fn may read labels,
+// projects and workflows, so their repos never take the lock of the tasks
+// while holding their own.
+func (tr *taskRepo) Transaction(fn func(repo ports.TaskRepo) *errr.AppError) *errr.AppError {
+	if tr.tx != nil {
+		return fn(tr)
+	}
+
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tasks, err := tr.load()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to change tasks due to internal server error")
+	}
+
+	txRepo := &taskRepo{
+		fp:      tr.fp,
+		journal: tr.journal,
+		idGen:   tr.idGen,
+		tx:      &transaction{tasks: tasks},
+	}
+	appErr := fn(txRepo)
+	if appErr != nil {
+		return appErr
+	}
+	if len(txRepo.tx.entries) == 0 {
+		return nil
+	}
+
+	err = tr.commit(&taskBatch{Entries: txRepo.tx.entries}, txRepo.tx.tasks)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to change tasks due to internal server error")
 	}
 
 	return nil
 }
 
+// lockTasks takes the lock of the tasks and returns them along with the
+// function releasing it, so none of them changes until then.
+func (tr *taskRepo) lockTasks() ([]models.Task, func(), error) {
+	tr.mu.Lock()
+	tasks, err := tr.load()
+	if err != nil {
+		tr.mu.Unlock()
+		return nil, nil, err
+	}
+
+	return tasks, tr.mu.Unlock, nil
+}
+
+// detachLabel takes the deleted label with id off every task.
+func (tr *taskRepo) detachLabel(id int64) error {
+	return tr.detachLabels(func(l int64) bool { return l == id })
+}
+
+// detachLabels takes the labels gone reports off every task.
+func (tr *taskRepo) detachLabels(gone func(id int64) bool) error {
+	return tr.detach(func(task *models.Task) bool {
+		if !slices.ContainsFunc(task.LabelIDs, gone) {
+			return false
+		}
+		task.LabelIDs = slices.DeleteFunc(slices.Clone(task.LabelIDs), gone)
+		return true
+	})
+}
+
+// detachProject moves every task out of the deleted project with id.
+func (tr *taskRepo) detachProject(id int64) error {
+	return tr.detachProjects(func(p int64) bool { return p == id })
+}
+
+// detachProjects moves every task out of the projects gone reports.
+func (tr *taskRepo) detachProjects(gone func(id int64) bool) error {
+	return tr.detach(func(task *models.Task) bool {
+		if task.ProjectID == 0 || !gone(task.ProjectID) {
+			return false
+		}
+		task.ProjectID = 0
+		return true
+	})
+}
+
+// detach commits the tasks change reports to have changed, at their next
+// version. They are journaled as they end up, so a replay sets rather than
+// repeats the change.
+func (tr *taskRepo) detach(change func(task *models.Task) bool) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	tasks, err := tr.load()
+	if err != nil {
+		return err
+	}
+
+	var changed []models.Task
+	for i := range tasks {
+		if change(&tasks[i]) {
+			tasks[i].Version++
+			changed = append(changed, tasks[i])
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	return tr.commit(putTasks(changed), tasks)
+}
+
+// checkParent makes sure the parent of the new subtask task exists, belongs
+// to the same user and leaves room for another level of nesting.
+func checkParent(tasks []models.Task, task models.Task) *errr.AppError {
+	i := slices.IndexFunc(tasks, func(t models.Task) bool { return t.ID == task.ParentID })
+	if i == -1 || tasks[i].InTrash() {
+		return errr.NewNotFoundError("no parent task found with id")
+	}
+	if tasks[i].UserID != task.UserID {
+		return errr.NewUnauthorizedError("Unauthorized to add subtask")
+	}
+	if models.TaskDepth(tasks, task.ParentID) >= models.MaxTaskDepth {
+		return errr.NewBadRequestError(
+			fmt.Sprintf("Subtasks can't be nested more than %d deep", models.MaxTaskDepth),
+		)
+	}
+	return nil
+}
+
+// hasUnfinishedSubtasks reports whether any subtask below the task with id
+// is not done yet, which keeps the task from being completed.
+func hasUnfinishedSubtasks(tasks []models.Task, id int64) bool {
+	descendants := models.Descendants(tasks, id)
+	return slices.ContainsFunc(tasks, func(t models.Task) bool {
+		return descendants[t.ID] && !t.Done && !t.InTrash()
+	})
+}
+
+func (tr *taskRepo) SaveTask(task models.Task) (models.Task, *errr.AppError) {
+	task.ID = tr.idGen.NextID()
+	task.Version = 1
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tasks, err := tr.load()
+	if err != nil {
+		return models.Task{}, errr.NewUnexpectedError("Unable to save task due to internal server error")
+	}
+
+	if task.ParentID != 0 {
+		appErr := checkParent(tasks, task)
+		if appErr != nil {
+			return models.Task{}, appErr
+		}
+	}
+
+	tasks = append(tasks, task)
+
+	err = tr.commit(&putTask{Task: task}, tasks)
+	if err != nil {
+		return models.Task{}, errr.NewUnexpectedError("Unable to save task due to internal server error")
+	}
+
+	return task, nil
+}
+
 func (tr *taskRepo) UpdateTask(id int64, task models.Task) *errr.AppError {
 	tr.mu.Lock()
 	defer tr.mu.Unlock()
-	tasks, err := tr.getTasks()
+	tasks, err := tr.load()
 	if err != nil {
 		return errr.NewUnexpectedError("Unable to create task due to internal server error")
 	}
 
 	notFound := true
+	var updated models.Task
 
 	for i := range tasks {
-		if tasks[i].ID == id {
+		if tasks[i].ID == id && !tasks[i].InTrash() {
 			notFound = false
 			if tasks[i].UserID != task.UserID {
 				return errr.NewUnauthorizedError("Unauthorized to update task")
 			}
-			if len(task.Title) != 0 {
-				tasks[i].Title = task.Title
+			if task.Version != 0 && task.Version != tasks[i].Version {
+				return errr.NewPreconditionFailedError("Task has changed since it was read")
 			}
-			if len(task.Desc) != 0 {
-				tasks[i].Desc = task.Desc
+			if task.Recurrence != nil && !task.IsRecurring() {
+				task.Recurrence = nil
 			}
-			if task.IsValidStatus() {
-				tasks[i].Status = task.Status
+			if !task.HasValidDates() {
+				return errr.NewBadRequestError("Start date must not be after due date")
 			}
+			if !task.HasValidRecurrence() {
+				return errr.NewBadRequestError("Recurring tasks need a due date")
+			}
+			if task.Done && !tasks[i].Done && hasUnfinishedSubtasks(tasks, id) {
+				return errr.NewDuplicateError("Task has unfinished subtasks")
+			}
+			task.ID = tasks[i].ID
+			task.ParentID = tasks[i].ParentID
+			task.SeriesID = tasks[i].SeriesID
+			task.CreatedAt = tasks[i].CreatedAt
+			task.Version = tasks[i].Version + 1
+			tasks[i] = task
+			updated = tasks[i]
 			break
 		}
 	}
@@ -117,7 +426,7 @@ func (tr *taskRepo) UpdateTask(id int64, task models.Task) *errr.AppError {
 		return errr.NewNotFoundError("no task found with id")
 	}
 
-	err = tr.write(tasks)
+	err = tr.commit(&putTask{Task: updated}, tasks)
 	if err != nil {
 		return errr.NewUnexpectedError("Unable to create task due to internal server error")
 	}
@@ -125,10 +434,10 @@ func (tr *taskRepo) UpdateTask(id int64, task models.Task) *errr.AppError {
 	return nil
 }
 
-func (tr *taskRepo) DeleteTask(id int64, userID int64) *errr.AppError {
+func (tr *taskRepo) DeleteTask(id int64, userID int64, version int64, deletedAt time.Time) *errr.AppError {
 	tr.mu.Lock()
 	defer tr.mu.Unlock()
-	tasks, err := tr.getTasks()
+	tasks, err := tr.load()
 	if err != nil {
 		return errr.NewUnexpectedError("Unable to delete task due to internal server error")
 	}
@@ -136,42 +445,211 @@ func (tr *taskRepo) DeleteTask(id int64, userID int64) *errr.AppError {
 	notFound := true
 
 	for i := range tasks {
-		if tasks[i].ID == id {
+		if tasks[i].ID == id && !tasks[i].InTrash() {
 			notFound = false
 			if tasks[i].UserID != userID {
 				return errr.NewUnauthorizedError("Unauthorized to delete task")
 			}
-			tasks = append(tasks[:i], tasks[i+1:]...)
+			if version != 0 && version != tasks[i].Version {
+				return errr.NewPreconditionFailedError("Task has changed since it was read")
+			}
 			break
 		}
 	}
 
 	if notFound {
-		return errr.NewUnexpectedError("no task found with id")
+		return errr.NewNotFoundError("no task found with id")
 	}
 
-	err = tr.write(tasks)
+	// Subtasks already in the trash keep the time they were deleted at, so
+	// restoring the task leaves them there.
+	descendants := models.Descendants(tasks, id)
+	var changed []models.Task
+	for i := range tasks {
+		if (tasks[i].ID == id || descendants[tasks[i].ID]) && !tasks[i].InTrash() {
+			tasks[i].DeletedAt = deletedAt
+			tasks[i].Version++
+			changed = append(changed, tasks[i])
+		}
+	}
+
+	err = tr.commit(putTasks(changed), tasks)
 	if err != nil {
-		return errr.NewUnexpectedError("Unable to create task due to internal server error")
+		return errr.NewUnexpectedError("Unable to delete task due to internal server error")
 	}
 
 	return nil
 }
 
-func (tr *taskRepo) GetTasks(userID int64) ([]models.Task, *errr.AppError) {
+func (tr *taskRepo) GetTask(id int64, userID int64) (models.Task, *errr.AppError) {
 	tr.mu.RLock()
-	defer tr.mu.RUnlock()
+	tasks, err := tr.getTasks()
+	tr.mu.RUnlock()
+	if isCorrupted(err) {
+		tr.mu.Lock()
+		tasks, err = tr.load()
+		tr.mu.Unlock()
+	}
+	if err != nil {
+		return models.Task{}, errr.NewUnexpectedError("Unable to get task due to internal server error")
+	}
+
+	i := slices.IndexFunc(tasks, func(t models.Task) bool { return t.ID == id })
+	if i == -1 || tasks[i].InTrash() {
+		return models.Task{}, errr.NewNotFoundError("no task found with id")
+	}
+	if tasks[i].UserID != userID {
+		return models.Task{}, errr.NewUnauthorizedError("Unauthorized to get task")
+	}
 
+	return tasks[i], nil
+}
+
+func (tr *taskRepo) GetTasks(query models.TaskQuery) ([]models.Task, *errr.AppError) {
+	tr.mu.RLock()
 	tasks, err := tr.getTasks()
+	tr.mu.RUnlock()
+	if isCorrupted(err) {
+		tr.mu.Lock()
+		tasks, err = tr.load()
+		tr.mu.Unlock()
+	}
 	if err != nil {
 		return nil, errr.NewUnexpectedError("Unable to create task due to internal server error")
 	}
-	filteredTasks := []models.Task{}
+
+	return query.Apply(tasks), nil
+}
+
+func (tr *taskRepo) GetAllTasks() ([]models.Task, *errr.AppError) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tasks, err := tr.load()
+	if err != nil {
+		return nil, errr.NewUnexpectedError("Unable to get tasks due to internal server error")
+	}
+
+	return slices.DeleteFunc(tasks, models.Task.InTrash), nil
+}
+
+func (tr *taskRepo) GetTrash(userID int64) ([]models.Task, *errr.AppError) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tasks, err := tr.load()
+	if err != nil {
+		return nil, errr.NewUnexpectedError("Unable to get trash due to internal server error")
+	}
+
+	return models.Trash(tasks, userID), nil
+}
+
+// trashed returns the index of the task with id, which has to be in the
+// trash of the user.
+func trashed(tasks []models.Task, id int64, userID int64) (int, *errr.AppError) {
+	i := slices.IndexFunc(tasks, func(t models.Task) bool { return t.ID == id })
+	if i == -1 || !tasks[i].InTrash() {
+		return 0, errr.NewNotFoundError("no task found in the trash with id")
+	}
+	if tasks[i].UserID != userID {
+		return 0, errr.NewUnauthorizedError("Unauthorized to change task")
+	}
+	return i, nil
+}
+
+func (tr *taskRepo) RestoreTask(id int64, userID int64) *errr.AppError {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tasks, err := tr.load()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to restore task due to internal server error")
+	}
+
+	i, appErr := trashed(tasks, id, userID)
+	if appErr != nil {
+		return appErr
+	}
+	parent := slices.IndexFunc(tasks, func(t models.Task) bool { return t.ID == tasks[i].ParentID })
+	if parent != -1 && tasks[parent].InTrash() {
+		return errr.NewDuplicateError("Parent task is in the trash, restore it first")
+	}
+
+	// Only the subtasks deleted with the task come back with it.
+	deletedAt := tasks[i].DeletedAt
+	restore := map[int64]bool{id: true}
+	for queue := []int64{id}; len(queue) > 0; queue = queue[1:] {
+		for _, task := range tasks {
+			if task.ParentID == queue[0] && task.DeletedAt.Equal(deletedAt) && !restore[task.ID] {
+				restore[task.ID] = true
+				queue = append(queue, task.ID)
+			}
+		}
+	}
+	var changed []models.Task
+	for j := range tasks {
+		if restore[tasks[j].ID] {
+			tasks[j].DeletedAt = time.Time{}
+			tasks[j].Version++
+			changed = append(changed, tasks[j])
+		}
+	}
+
+	err = tr.commit(putTasks(changed), tasks)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to restore task due to internal server error")
+	}
+
+	return nil
+}
+
+func (tr *taskRepo) PurgeTask(id int64, userID int64) *errr.AppError {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tasks, err := tr.load()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to purge task due to internal server error")
+	}
+
+	_, appErr := trashed(tasks, id, userID)
+	if appErr != nil {
+		return appErr
+	}
+
+	// Subtasks go with their parent, all of them are in the trash too.
+	entry := &deleteTask{ID: id}
+	err = tr.commit(entry, entry.apply(tasks))
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to purge task due to internal server error")
+	}
+
+	return nil
+}
+
+func (tr *taskRepo) PurgeTrash(deletedBefore time.Time) (int, *errr.AppError) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tasks, err := tr.load()
+	if err != nil {
+		return 0, errr.NewUnexpectedError("Unable to purge trash due to internal server error")
+	}
+
+	// Subtasks are never deleted after their parent, so none outlives it.
+	var entries []mutation[models.Task]
+	purged := 0
 	for _, task := range tasks {
-		if task.UserID == userID {
-			filteredTasks = append(filteredTasks, task)
+		if task.InTrash() && task.DeletedAt.Before(deletedBefore) {
+			entries = append(entries, &deleteTask{ID: task.ID})
+			purged++
 		}
 	}
+	if purged == 0 {
+		return 0, nil
+	}
+
+	entry := &taskBatch{Entries: entries}
+	err = tr.commit(entry, entry.apply(tasks))
+	if err != nil {
+		return 0, errr.NewUnexpectedError("Unable to purge trash due to internal server error")
+	}
 
-	return filteredTasks, nil
+	return purged, nil
 }
@@ -6,14 +6,28 @@ import (
 	"os"
 	"os/exec"
 	"path"
+	"reflect"
 	"slices"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/Jashanveer-Singh/todo-go/internal/adpaters/idgen"
 	"github.com/Jashanveer-Singh/todo-go/internal/errr"
 	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/internal/ports"
 )
 
+// trashedAt is when the tests move tasks to the trash.
+var trashedAt = time.Date(2020, time.January, 5, 10, 0, 0, 0, time.UTC)
+
+func equalTasks(a, b []models.Task) bool {
+	return slices.EqualFunc(a, b, func(x, y models.Task) bool {
+		return reflect.DeepEqual(x, y)
+	})
+}
+
 func getTempTasksPath(t *testing.T) string {
 	return path.Join(t.TempDir(), "tasks.json")
 }
@@ -23,7 +37,7 @@ func TestNewTaskRepo_ValidFile(t *testing.T) {
 	fp := path.Join(dir, "tasks.json")
 	os.WriteFile(fp, []byte(""), 0644)
 
-	got := NewTaskRepo(fp)
+	got := NewTaskRepo(fp, idgen.NewSequenceGenerator(0))
 
 	if got.fp != fp {
 		t.Errorf("wanted file pointer(fp): %s, got: %s.", fp, got.fp)
@@ -35,7 +49,7 @@ func TestNewtaskRepo_FileDoesNotExist(t *testing.T) {
 	fp := path.Join(dir, "tasks.json")
 
 	if os.Getenv("BE_CRASHER") == "1" {
-		NewTaskRepo(fp)
+		NewTaskRepo(fp, idgen.NewSequenceGenerator(0))
 	}
 
 	var stderr bytes.Buffer
@@ -130,19 +144,44 @@ func Test_taskRepo_getTasks(t *testing.T) {
 			},
 			want: []models.Task{
 				{
-					ID:     12234,
-					Status: 0,
-					Desc:   "any desc",
-					Title:  "any title",
+					ID:      12234,
+					Status:  0,
+					Desc:    "any desc",
+					Title:   "any title",
+					Version: 1,
 				},
 			},
 			wantErr: false,
 		},
+		{
+			name: "tasks done before workflows are marked done",
+			fp:   getTempTasksPath(t),
+			setupFile: func(fp string) {
+				os.WriteFile(fp, []byte(`[{"id": 1, "status": 1}, {"id": 2, "status": 2}]`), 0644)
+			},
+			want: []models.Task{
+				{ID: 1, Status: 1, Done: true, Version: 1},
+				{ID: 2, Status: 2, Version: 1},
+			},
+			wantErr: false,
+		},
+		{
+			name: "tasks written before versions start at the first",
+			fp:   getTempTasksPath(t),
+			setupFile: func(fp string) {
+				os.WriteFile(fp, []byte(`[{"id": 1}, {"id": 2, "version": 4}]`), 0644)
+			},
+			want: []models.Task{
+				{ID: 1, Version: 1},
+				{ID: 2, Version: 4},
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setupFile(tt.fp)
-			tr := NewTaskRepo(tt.fp)
+			tr := &taskRepo{fp: tt.fp}
 			got, gotErr := tr.getTasks()
 			if gotErr != nil {
 				if !tt.wantErr {
@@ -153,7 +192,7 @@ func Test_taskRepo_getTasks(t *testing.T) {
 			if tt.wantErr {
 				t.Fatal("getTasks() succeeded unexpectedly, got: ", got)
 			}
-			if !slices.Equal(got, tt.want) {
+			if !equalTasks(got, tt.want) {
 				t.Errorf("getTasks() = %#v, want %#v", got, tt.want)
 			}
 		})
@@ -233,7 +272,7 @@ func Test_taskRepo_write(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setupFile(tt.fp)
-			tr := NewTaskRepo(tt.fp)
+			tr := NewTaskRepo(tt.fp, idgen.NewSequenceGenerator(0))
 			gotErr := tr.write(tt.tasks)
 			if gotErr != nil {
 				if !tt.wantErr {
@@ -268,7 +307,7 @@ func Test_taskRepo_SaveTask(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name: "tasks read failure due to corrupted file",
+			name: "corrupted file is recovered",
 			fp:   getTempTasksPath(t),
 			setupFile: func(fp string) {
 				os.WriteFile(fp, []byte("asdf"), 0666)
@@ -279,7 +318,7 @@ func Test_taskRepo_SaveTask(t *testing.T) {
 				Desc:   "some desc",
 				Status: 0,
 			},
-			wantErr: true,
+			wantErr: false,
 		},
 		{
 			name: "task write failure",
@@ -332,14 +371,21 @@ func Test_taskRepo_SaveTask(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setupFile(tt.fp)
-			tr := NewTaskRepo(tt.fp)
-			gotErr := tr.SaveTask(tt.task)
+			tr := NewTaskRepo(tt.fp, idgen.NewSequenceGenerator(0))
+			got, gotErr := tr.SaveTask(tt.task)
 			if tt.wantErr && gotErr == nil {
 				t.Errorf("SaveTask() successed unexpectedly")
 			}
 			if !tt.wantErr && gotErr != nil {
 				t.Errorf("SaveTask failed. got %v", gotErr)
 			}
+			if tt.wantErr || gotErr != nil {
+				return
+			}
+			stored, appErr := tr.GetTask(got.ID, tt.task.UserID)
+			if got.ID == 0 || appErr != nil || !reflect.DeepEqual(got, stored) {
+				t.Errorf("SaveTask() = %v, stored %v, %v", got, stored, appErr)
+			}
 		})
 	}
 }
@@ -382,7 +428,38 @@ func Test_taskRepo_UpdateTask(t *testing.T) {
 			// errMessage: "no task found with id",
 		},
 		{
-			name: "tasks read failure due to corrupted file",
+			name: "task changed since the version the update is based on",
+			fp:   getTempTasksPath(t),
+			setupFile: func(fp string) {
+				os.WriteFile(fp, []byte(`[{"id": 12234, "title": "any title", "version": 3}]`), 0666)
+			},
+			id:         12234,
+			task:       models.Task{Title: "title", Version: 2},
+			wantErr:    true,
+			errMessage: "Task has changed since it was read",
+		},
+		{
+			name: "start after the due date",
+			fp:   getTempTasksPath(t),
+			setupFile: func(fp string) {
+				os.WriteFile(fp, []byte(`[{
+					"title": "any title",
+					"desc": "any desc",
+					"status": 0,
+					"id": 12234
+					}]`), 0666)
+			},
+			id: 12234,
+			task: models.Task{
+				Title:   "any title",
+				DueAt:   time.Date(2020, time.January, 2, 10, 0, 0, 0, time.UTC),
+				StartAt: time.Date(2020, time.January, 3, 10, 0, 0, 0, time.UTC),
+			},
+			wantErr:    true,
+			errMessage: "Start date must not be after due date",
+		},
+		{
+			name: "corrupted file is recovered",
 			fp:   getTempTasksPath(t),
 			setupFile: func(fp string) {
 				os.WriteFile(fp, []byte(`adsf`), 0666)
@@ -390,13 +467,13 @@ func Test_taskRepo_UpdateTask(t *testing.T) {
 			id:         12234,
 			task:       models.Task{},
 			wantErr:    true,
-			errMessage: "Unable to create task due to internal server error",
+			errMessage: "no task found with id",
 		},
 		{
 			name: "unable to write tasks",
 			fp:   getTempTasksPath(t),
 			setupFile: func(fp string) {
-				os.WriteFile(fp, []byte(`adsf`), 0444)
+				os.WriteFile(fp, []byte(`[{"id": 12234}]`), 0444)
 			},
 			id:         12234,
 			task:       models.Task{},
@@ -408,7 +485,7 @@ func Test_taskRepo_UpdateTask(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setupFile(tt.fp)
-			tr := NewTaskRepo(tt.fp)
+			tr := NewTaskRepo(tt.fp, idgen.NewSequenceGenerator(0))
 			gotErr := tr.UpdateTask(tt.id, tt.task)
 			// TODO: update the condition below to compare got with tt.want.
 			if tt.wantErr && gotErr == nil {
@@ -448,20 +525,20 @@ func Test_taskRepo_DeleteTask(t *testing.T) {
 			errMessage: "no task found with id",
 		},
 		{
-			name: "tasks read failure due to corrupted file",
+			name: "corrupted file is recovered",
 			fp:   getTempTasksPath(t),
 			setupFile: func(fp string) {
 				os.WriteFile(fp, []byte(`adsf`), 0666)
 			},
 			id:         12234,
 			wantErr:    true,
-			errMessage: "Unable to delete task due to internal server error",
+			errMessage: "no task found with id",
 		},
 		{
 			name: "unable to write tasks",
 			fp:   getTempTasksPath(t),
 			setupFile: func(fp string) {
-				os.WriteFile(fp, []byte(`adsf`), 0444)
+				os.WriteFile(fp, []byte(`[{"id": 12234}]`), 0444)
 			},
 			id:         12234,
 			wantErr:    true,
@@ -487,8 +564,8 @@ func Test_taskRepo_DeleteTask(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setupFile(tt.fp)
-			tr := NewTaskRepo(tt.fp)
-			gotErr := tr.DeleteTask(tt.id, tt.userID)
+			tr := NewTaskRepo(tt.fp, idgen.NewSequenceGenerator(0))
+			gotErr := tr.DeleteTask(tt.id, tt.userID, 0, trashedAt)
 			if tt.wantErr && gotErr == nil {
 				t.Errorf("DeleteTask() successed unexpectedly")
 				return
@@ -506,6 +583,22 @@ func Test_taskRepo_DeleteTask(t *testing.T) {
 	}
 }
 
+func Test_taskRepo_DeleteTask_when_id_is_unknown(t *testing.T) {
+	fp := getTempTasksPath(t)
+	os.WriteFile(fp, []byte(`[
+		{"id": 1, "title": "kept", "user_id": 1234},
+		{"id": 2, "title": "trashed", "user_id": 1234, "deleted_at": "2025-01-01T00:00:00Z"}
+	]`), 0666)
+	tr := NewTaskRepo(fp, idgen.NewSequenceGenerator(0))
+
+	for _, id := range []int64{3, 2} {
+		appErr := tr.DeleteTask(id, 1234, 0, trashedAt)
+		if appErr == nil || appErr.Code != http.StatusNotFound {
+			t.Errorf("DeleteTask(%d) = %v, want a not found error", id, appErr)
+		}
+	}
+}
+
 func Test_taskRepo_GetTasks(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -517,10 +610,10 @@ func Test_taskRepo_GetTasks(t *testing.T) {
 		wantErr   bool
 	}{
 		{
-			name: "tasks read failure due to corrupted file",
+			name: "tasks read failure",
 			fp:   getTempTasksPath(t),
 			setupFile: func(fp string) {
-				os.WriteFile(fp, []byte("asdfaf"), 0666)
+				os.WriteFile(fp, []byte(""), 0333)
 			},
 			wantErr: true,
 			err: &errr.AppError{
@@ -528,6 +621,14 @@ func Test_taskRepo_GetTasks(t *testing.T) {
 				Message: "Unable to create task due to internal server error",
 			},
 		},
+		{
+			name: "corrupted file is recovered",
+			fp:   getTempTasksPath(t),
+			setupFile: func(fp string) {
+				os.WriteFile(fp, []byte("asdfaf"), 0666)
+			},
+			want: []models.Task{},
+		},
 		{
 			name: "tasks read successfully",
 			fp:   getTempTasksPath(t),
@@ -543,11 +644,12 @@ func Test_taskRepo_GetTasks(t *testing.T) {
 			userID: 1234,
 			want: []models.Task{
 				{
-					ID:     12234,
-					Status: 0,
-					Desc:   "any desc",
-					Title:  "any title",
-					UserID: 1234,
+					ID:      12234,
+					Status:  0,
+					Desc:    "any desc",
+					Title:   "any title",
+					UserID:  1234,
+					Version: 1,
 				},
 			},
 		},
@@ -556,8 +658,8 @@ func Test_taskRepo_GetTasks(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setupFile(tt.fp)
-			tr := NewTaskRepo(tt.fp)
-			got, err := tr.GetTasks(tt.userID)
+			tr := NewTaskRepo(tt.fp, idgen.NewSequenceGenerator(0))
+			got, err := tr.GetTasks(models.NewTaskQuery(tt.userID))
 			// TODO: update the condition below to compare got with tt.want.
 			if tt.wantErr && err == nil {
 				t.Errorf("GetTasks successed unexpectedly")
@@ -566,10 +668,368 @@ func Test_taskRepo_GetTasks(t *testing.T) {
 				t.Errorf("GetTasks() Failed, got err %v", err)
 			}
 			if !tt.wantErr && err == nil {
-				if !slices.Equal(got, tt.want) {
+				if !equalTasks(got, tt.want) {
 					t.Errorf("Wanted %v, got %v", tt.want, got)
 				}
 			}
 		})
 	}
 }
+
+func Test_taskRepo_SaveTask_UniqueIDs(t *testing.T) {
+	fp := getTempTasksPath(t)
+	os.WriteFile(fp, []byte(""), 0644)
+	tr := NewTaskRepo(fp, idgen.NewSequenceGenerator(0))
+
+	for range 3 {
+		tr.SaveTask(models.Task{Title: "title", Desc: "desc", UserID: 1234})
+	}
+
+	got, _ := tr.GetTasks(models.NewTaskQuery(1234))
+	ids := []int64{}
+	for _, task := range got {
+		ids = append(ids, task.ID)
+	}
+	if !slices.Equal(ids, []int64{1, 2, 3}) {
+		t.Errorf("wanted ids [1 2 3], got %v", ids)
+	}
+}
+
+func TestNewTaskRepo_RekeysDuplicateIDs(t *testing.T) {
+	fp := getTempTasksPath(t)
+	os.WriteFile(fp, []byte(`[
+		{"id": 1, "title": "first", "user_id": 1234},
+		{"id": 1, "title": "second", "user_id": 1234},
+		{"id": 2, "title": "third", "user_id": 1234}
+	]`), 0644)
+
+	tr := NewTaskRepo(fp, idgen.NewSequenceGenerator(0))
+
+	got, _ := tr.GetTasks(models.NewTaskQuery(1234))
+	want := []models.Task{
+		{ID: 1, Title: "first", UserID: 1234, Version: 1},
+		{ID: 2, Title: "third", UserID: 1234, Version: 1},
+		{ID: 3, Title: "second", UserID: 1234, Version: 1},
+	}
+	if !equalTasks(got, want) {
+		t.Errorf("wanted %v, got %v", want, got)
+	}
+}
+
+func Test_taskRepo_subtasks(t *testing.T) {
+	newRepo := func(t *testing.T) *taskRepo {
+		fp := getTempTasksPath(t)
+		os.WriteFile(fp, []byte(`[
+			{"id": 1, "title": "project", "user_id": 1234},
+			{"id": 2, "title": "step", "user_id": 1234, "parent_id": 1},
+			{"id": 3, "title": "detail", "user_id": 1234, "parent_id": 2},
+			{"id": 4, "title": "other", "user_id": 1234}
+		]`), 0644)
+		return NewTaskRepo(fp, idgen.NewSequenceGenerator(100))
+	}
+
+	t.Run("subtask of another user's task", func(t *testing.T) {
+		tr := newRepo(t)
+		_, appErr := tr.SaveTask(models.Task{Title: "t", UserID: 99, ParentID: 1})
+		if appErr == nil || appErr.Code != http.StatusForbidden {
+			t.Errorf("SaveTask() = %v, want forbidden", appErr)
+		}
+	})
+
+	t.Run("nested too deep", func(t *testing.T) {
+		tr := newRepo(t)
+		_, appErr := tr.SaveTask(models.Task{Title: "t", UserID: 1234, ParentID: 3})
+		if appErr == nil || appErr.Code != http.StatusBadRequest {
+			t.Errorf("SaveTask() = %v, want bad request", appErr)
+		}
+	})
+
+	t.Run("completing with unfinished subtasks is blocked", func(t *testing.T) {
+		tr := newRepo(t)
+		appErr := tr.UpdateTask(1, models.Task{UserID: 1234, Status: 1, Done: true})
+		if appErr == nil || appErr.Code != http.StatusConflict {
+			t.Errorf("UpdateTask() = %v, want conflict", appErr)
+		}
+
+		tr.UpdateTask(3, models.Task{UserID: 1234, Status: 1, Done: true})
+		tr.UpdateTask(2, models.Task{UserID: 1234, Status: 1, Done: true})
+		appErr = tr.UpdateTask(1, models.Task{UserID: 1234, Status: 1, Done: true})
+		if appErr != nil {
+			t.Errorf("UpdateTask() with finished subtasks failed: %v", appErr)
+		}
+	})
+
+	t.Run("deleting cascades to subtasks", func(t *testing.T) {
+		tr := newRepo(t)
+		appErr := tr.DeleteTask(1, 1234, 0, trashedAt)
+		if appErr != nil {
+			t.Fatalf("DeleteTask() failed: %v", appErr)
+		}
+
+		got, _ := tr.GetTasks(models.NewTaskQuery(1234))
+		want := []models.Task{{ID: 4, Title: "other", UserID: 1234, Version: 1}}
+		if !equalTasks(got, want) {
+			t.Errorf("wanted %v, got %v", want, got)
+		}
+	})
+}
+
+func Test_taskRepo_UpdateTask_replaces(t *testing.T) {
+	fp := getTempTasksPath(t)
+	os.WriteFile(fp, []byte(`[
+		{"id": 1, "title": "parent", "user_id": 1234},
+		{"id": 12234, "title": "any title", "desc": "any desc", "priority": 2, "user_id": 1234,
+		 "parent_id": 1, "label_ids": [3], "due_at": "2020-01-02T10:00:00Z",
+		 "created_at": "2020-01-01T10:00:00Z", "version": 4}
+	]`), 0666)
+	tr := NewTaskRepo(fp, idgen.NewSequenceGenerator(0))
+
+	updatedAt := time.Date(2020, time.January, 3, 10, 0, 0, 0, time.UTC)
+	appErr := tr.UpdateTask(12234, models.Task{
+		ID: 99, Title: "title", UserID: 1234, ParentID: 7, UpdatedAt: updatedAt, Version: 4,
+	})
+	if appErr != nil {
+		t.Fatalf("UpdateTask() failed: %v", appErr)
+	}
+
+	got, _ := tr.GetTask(12234, 1234)
+	want := models.Task{
+		ID: 12234, Title: "title", UserID: 1234, ParentID: 1,
+		CreatedAt: time.Date(2020, time.January, 1, 10, 0, 0, 0, time.UTC),
+		UpdatedAt: updatedAt, Version: 5,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetTask() = %v, want %v", got, want)
+	}
+}
+
+func Test_taskRepo_GetTask(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         int64
+		userID     int64
+		want       models.Task
+		errMessage string
+	}{
+		{
+			name:   "task found",
+			id:     12234,
+			userID: 1234,
+			want: models.Task{
+				ID: 12234, Title: "any title", UserID: 1234, LabelIDs: []int64{3}, Version: 1,
+			},
+		},
+		{
+			name:       "task not found",
+			id:         99,
+			userID:     1234,
+			errMessage: "no task found with id",
+		},
+		{
+			name:       "task belongs to another user",
+			id:         12234,
+			userID:     4321,
+			errMessage: "Unauthorized to get task",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fp := getTempTasksPath(t)
+			os.WriteFile(fp, []byte(`[
+				{"id": 12234, "title": "any title", "user_id": 1234, "label_ids": [3]},
+				{"id": 12235, "title": "other title", "user_id": 4321}
+			]`), 0666)
+			tr := NewTaskRepo(fp, idgen.NewSequenceGenerator(0))
+
+			got, gotErr := tr.GetTask(tt.id, tt.userID)
+			if tt.errMessage != "" {
+				if gotErr == nil || gotErr.Message != tt.errMessage {
+					t.Errorf("GetTask() err = %v, want %v", gotErr, tt.errMessage)
+				}
+				return
+			}
+			if gotErr != nil {
+				t.Fatalf("GetTask() failed, got err %v", gotErr.Message)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetTask() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_taskRepo_Transaction(t *testing.T) {
+	t.Run("changes are committed together", func(t *testing.T) {
+		fp := getTempTasksPath(t)
+		os.WriteFile(fp, []byte(`[{"id": 1, "title": "old", "user_id": 1234}]`), 0666)
+		tr := NewTaskRepo(fp, idgen.NewSequenceGenerator(100))
+
+		appErr := tr.Transaction(func(tx ports.TaskRepo) *errr.AppError {
+			if _, appErr := tx.SaveTask(models.Task{Title: "new", UserID: 1234}); appErr != nil {
+				return appErr
+			}
+			return tx.DeleteTask(1, 1234, 0, trashedAt)
+		})
+		if appErr != nil {
+			t.Fatalf("Transaction() failed: %v", appErr)
+		}
+		// A new repo replays the batch from the journal.
+		got, _ := NewTaskRepo(fp, idgen.NewSequenceGenerator(100)).GetTasks(models.NewTaskQuery(1234))
+		if len(got) != 1 || got[0].Title != "new" {
+			t.Errorf("GetTasks() = %v, want only the new task", got)
+		}
+	})
+
+	t.Run("failure undoes every change", func(t *testing.T) {
+		fp := getTempTasksPath(t)
+		os.WriteFile(fp, []byte(`[{"id": 1, "title": "old", "user_id": 1234}]`), 0666)
+		tr := NewTaskRepo(fp, idgen.NewSequenceGenerator(100))
+
+		appErr := tr.Transaction(func(tx ports.TaskRepo) *errr.AppError {
+			tx.SaveTask(models.Task{Title: "new", UserID: 1234})
+			tx.DeleteTask(1, 1234, 0, trashedAt)
+			return errr.NewNotFoundError("stop")
+		})
+		if appErr == nil || appErr.Message != "stop" {
+			t.Fatalf("Transaction() err = %v, want stop", appErr)
+		}
+		got, _ := tr.GetTasks(models.NewTaskQuery(1234))
+		if len(got) != 1 || got[0].Title != "old" {
+			t.Errorf("GetTasks() = %v, want only the old task", got)
+		}
+	})
+}
+
+// A batch of the task service checks labels and projects while its
+// transaction holds the lock of the tasks, so deleting them meanwhile must
+// not take the lock of the tasks while holding their own.
+func Test_taskRepo_Transaction_alongside_deletes(t *testing.T) {
+	dir := t.TempDir()
+	tasksFile := path.Join(dir, "tasks.json")
+	labelsFile := path.Join(dir, "labels.json")
+	projectsFile := path.Join(dir, "projects.json")
+	os.WriteFile(tasksFile, []byte(`[]`), 0644)
+	os.WriteFile(labelsFile, []byte(`[{"id":1,"user_id":1234,"name":"work","color":"#ffffff"}]`), 0644)
+	os.WriteFile(projectsFile, []byte(`[{"id":1,"user_id":1234,"name":"work","color":"#ffffff"}]`), 0644)
+	tr := NewTaskRepo(tasksFile, idgen.NewSequenceGenerator(100))
+	lr := NewLabelRepo(labelsFile, tr, idgen.NewSequenceGenerator(200))
+	pr := NewProjectRepo(projectsFile, tr, idgen.NewSequenceGenerator(300))
+
+	began := make(chan struct{})
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		tr.Transaction(func(tx ports.TaskRepo) *errr.AppError {
+			close(began)
+			for range 20 {
+				task := models.Task{Title: "t", UserID: 1234}
+				if labels, _ := lr.GetLabels(1234); len(labels) == 1 {
+					task.LabelIDs = []int64{1}
+				}
+				if _, appErr := pr.GetProject(1, 1234); appErr == nil {
+					task.ProjectID = 1
+				}
+				tx.SaveTask(task)
+				time.Sleep(time.Millisecond)
+			}
+			return nil
+		})
+	}()
+	go func() {
+		defer wg.Done()
+		<-began
+		lr.DeleteLabel(1, 1234)
+	}()
+	go func() {
+		defer wg.Done()
+		<-began
+		pr.DeleteProject(1, 1234)
+	}()
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("transaction and deletes of its labels and projects deadlocked")
+	}
+
+	tasks, _ := tr.GetTasks(models.NewTaskQuery(1234))
+	if len(tasks) != 20 {
+		t.Fatalf("GetTasks() = %d tasks, want 20", len(tasks))
+	}
+	for _, task := range tasks {
+		if task.HasLabel(1) || task.ProjectID == 1 {
+			t.Errorf("task %d kept the deleted label or project", task.ID)
+		}
+	}
+}
+
+func Test_taskRepo_trash(t *testing.T) {
+	fp := getTempTasksPath(t)
+	os.WriteFile(fp, []byte(`[
+		{"id": 1, "title": "parent", "user_id": 1234},
+		{"id": 2, "title": "subtask", "user_id": 1234, "parent_id": 1},
+		{"id": 3, "title": "deleted before", "user_id": 1234, "parent_id": 1}
+	]`), 0666)
+	tr := NewTaskRepo(fp, idgen.NewSequenceGenerator(100))
+
+	earlier := trashedAt.Add(-time.Hour)
+	if appErr := tr.DeleteTask(3, 1234, 0, earlier); appErr != nil {
+		t.Fatalf("DeleteTask() failed: %v", appErr)
+	}
+	if appErr := tr.DeleteTask(1, 1234, 0, trashedAt); appErr != nil {
+		t.Fatalf("DeleteTask() failed: %v", appErr)
+	}
+
+	if _, appErr := tr.GetTask(2, 1234); appErr == nil || appErr.Code != http.StatusNotFound {
+		t.Errorf("GetTask() of a subtask in the trash = %v, want not found", appErr)
+	}
+	if tasks, _ := tr.GetTasks(models.NewTaskQuery(1234)); len(tasks) != 0 {
+		t.Errorf("GetTasks() = %v, want none", tasks)
+	}
+	trash, appErr := tr.GetTrash(1234)
+	if appErr != nil || len(trash) != 2 || trash[0].ID != 1 || !trash[0].DeletedAt.Equal(trashedAt) ||
+		trash[1].ID != 3 {
+		t.Fatalf("GetTrash() = %v, %v, want the parent and the subtask deleted before it", trash, appErr)
+	}
+
+	appErr = tr.RestoreTask(3, 1234)
+	if appErr == nil || appErr.Message != "Parent task is in the trash, restore it first" {
+		t.Errorf("RestoreTask() of a subtask = %v, want its parent in the trash", appErr)
+	}
+	if appErr = tr.RestoreTask(1, 1234); appErr != nil {
+		t.Fatalf("RestoreTask() failed: %v", appErr)
+	}
+	tr = NewTaskRepo(fp, idgen.NewSequenceGenerator(100))
+	tasks, _ := tr.GetTasks(models.NewTaskQuery(1234))
+	if len(tasks) != 2 || tasks[0].ID != 1 || tasks[0].Version != 3 || tasks[1].ID != 2 {
+		t.Errorf("GetTasks() after restore = %v, want the parent and the subtask deleted with it", tasks)
+	}
+
+	purged, appErr := tr.PurgeTrash(trashedAt)
+	if appErr != nil || purged != 1 {
+		t.Errorf("PurgeTrash() = %v, %v, want 1 task purged", purged, appErr)
+	}
+	if trash, _ := tr.GetTrash(1234); len(trash) != 0 {
+		t.Errorf("GetTrash() after purge = %v, want none", trash)
+	}
+
+	tr.DeleteTask(1, 1234, 0, trashedAt)
+	if appErr = tr.PurgeTask(1, 4321); appErr == nil || appErr.Code != http.StatusForbidden {
+		t.Errorf("PurgeTask() of another user = %v, want forbidden", appErr)
+	}
+	if appErr = tr.PurgeTask(1, 1234); appErr != nil {
+		t.Fatalf("PurgeTask() failed: %v", appErr)
+	}
+	if left, _ := tr.getTasks(); len(left) != 0 {
+		t.Errorf("tasks after purging the parent = %v, want none", left)
+	}
+	if appErr = tr.RestoreTask(1, 1234); appErr == nil || appErr.Code != http.StatusNotFound {
+		t.Errorf("RestoreTask() of a purged task = %v, want not found", appErr)
+	}
+}
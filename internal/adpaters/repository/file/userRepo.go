@@ -1,17 +1,19 @@
 package file
 
 import (
+	"cmp"
 	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
 	"sync"
-	"time"
 
 	"github.com/Jashanveer-Singh/todo-go/internal/errr"
 	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/internal/ports"
 )
 
-func NewUserRepo(fp string) *userRepo {
+func NewUserRepo(fp string, idGen ports.IDGenerator) *userRepo {
 	_, err := os.Stat(fp)
 	if os.IsNotExist(err) {
 		fmt.Fprintf(os.Stderr, "file: %s does not exist\n", fp)
@@ -25,15 +27,26 @@ func NewUserRepo(fp string) *userRepo {
 		os.Exit(1)
 	}
 
-	return &userRepo{
-		mu: sync.RWMutex{},
-		fp: fp,
+	ur := &userRepo{
+		mu:      sync.RWMutex{},
+		fp:      fp,
+		journal: newJournal(fp, userMutation),
+		idGen:   idGen,
 	}
+
+	err = ur.recover()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to recover the file: %s\n%s\n", fp, err.Error())
+	}
+
+	return ur
 }
 
 type userRepo struct {
-	mu sync.RWMutex
-	fp string
+	mu      sync.RWMutex
+	fp      string
+	journal journal[models.User]
+	idGen   ports.IDGenerator
 }
 
 func (ur *userRepo) readUsersFromFile() ([]models.User, error) {
@@ -41,23 +54,38 @@ func (ur *userRepo) readUsersFromFile() ([]models.User, error) {
 
 	userjson, err := os.ReadFile(ur.fp)
 	if err != nil {
-		return nil, fmt.Errorf("unable to read Tasks from file.\n%s", err.Error())
+		return nil, fmt.Errorf("unable to read Tasks from file.\n%w", err)
 	}
 	if len(userjson) != 0 {
 
 		err = json.Unmarshal(userjson, &users)
 		if err != nil {
-			return nil, fmt.Errorf("unable to unmarshal/decode json.\n%s", err.Error())
+			return nil, fmt.Errorf("unable to unmarshal/decode json.\n%w", err)
 		}
 	}
 
 	return users, nil
 }
 
+// load reads the users like readUsersFromFile, first recovering the file when
+// it is corrupted. The caller must hold the write lock.
+func (ur *userRepo) load() ([]models.User, error) {
+	users, err := ur.readUsersFromFile()
+	if isCorrupted(err) {
+		err = quarantine(ur.fp)
+		if err != nil {
+			return nil, err
+		}
+		return ur.readUsersFromFile()
+	}
+
+	return users, err
+}
+
 func (ur *userRepo) writeUsersToFile(users []models.User) error {
 	userjson, _ := json.Marshal(users)
 
-	err := os.WriteFile(ur.fp, []byte(userjson), 0666)
+	err := writeFileAtomic(ur.fp, []byte(userjson), 0666)
 	if err != nil {
 		return fmt.Errorf("failed to write users to file.\n%s", err.Error())
 	}
@@ -65,11 +93,86 @@ func (ur *userRepo) writeUsersToFile(users []models.User) error {
 	return nil
 }
 
-func (ur *userRepo) GetUserByUsername(username string) (models.User, *errr.AppError) {
+// commit journals entry and then writes users, the result of applying it.
+func (ur *userRepo) commit(entry mutation[models.User], users []models.User) error {
+	undo, err := ur.journal.append(entry)
+	if err != nil {
+		return fmt.Errorf("unable to journal users.\n%s", err.Error())
+	}
+
+	err = ur.writeUsersToFile(users)
+	if err != nil {
+		undo()
+		return err
+	}
+
+	ur.journal.clear()
+	return nil
+}
+
+// recover replays mutations journaled before a crash onto the users file.
+func (ur *userRepo) recover() error {
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	users, err := ur.load()
+	if err != nil {
+		return err
+	}
+
+	entries, err := ur.journal.entries()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	for _, entry := range entries {
+		users = entry.apply(users)
+	}
+
+	err = ur.writeUsersToFile(users)
+	if err != nil {
+		return err
+	}
+
+	return ur.journal.clear()
+}
+
+func (ur *userRepo) GetUser(id int64) (models.User, *errr.AppError) {
 	ur.mu.RLock()
-	defer ur.mu.RUnlock()
+	users, err := ur.readUsersFromFile()
+	ur.mu.RUnlock()
+	if isCorrupted(err) {
+		ur.mu.Lock()
+		users, err = ur.load()
+		ur.mu.Unlock()
+	}
+	if err != nil {
+		return models.User{}, errr.NewUnexpectedError(
+			"Unable to get user due to internal server error",
+		)
+	}
+
+	for i := range users {
+		if users[i].ID == id {
+			return users[i], nil
+		}
+	}
 
+	return models.User{}, errr.NewNotFoundError("User not Found")
+}
+
+func (ur *userRepo) GetUserByUsername(username string) (models.User, *errr.AppError) {
+	ur.mu.RLock()
 	users, err := ur.readUsersFromFile()
+	ur.mu.RUnlock()
+	if isCorrupted(err) {
+		ur.mu.Lock()
+		users, err = ur.load()
+		ur.mu.Unlock()
+	}
 	if err != nil {
 		return models.User{}, errr.NewUnexpectedError(
 			"Unable to save user due to internal server error",
@@ -85,27 +188,67 @@ func (ur *userRepo) GetUserByUsername(username string) (models.User, *errr.AppEr
 	return models.User{}, errr.NewNotFoundError("User not Found")
 }
 
-func (ur *userRepo) CreateUser(user models.User) *errr.AppError {
-	ur.mu.RLock()
-	defer ur.mu.RUnlock()
+func (ur *userRepo) CreateUser(user models.User) (models.User, *errr.AppError) {
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
 
-	users, err := ur.readUsersFromFile()
+	users, err := ur.load()
 	if err != nil {
-		return errr.NewUnexpectedError("Unable to save user due to internal server error")
+		return models.User{}, errr.NewUnexpectedError("Unable to save user due to internal server error")
 	}
 
 	for i := range users {
 		if users[i].Username == user.Username {
-			return errr.NewDuplicateError("user already exists")
+			return models.User{}, errr.NewDuplicateError("user already exists")
 		}
 	}
 
-	user.ID = time.Now().Unix()
+	user.ID = ur.idGen.NextID()
 	users = append(users, user)
 
-	err = ur.writeUsersToFile(users)
+	err = ur.commit(&putUser{User: user}, users)
+	if err != nil {
+		return models.User{}, errr.NewUnexpectedError("Unable to save user due to internal server error")
+	}
+
+	return user, nil
+}
+
+func (ur *userRepo) GetUsers() ([]models.User, *errr.AppError) {
+	ur.mu.RLock()
+	users, err := ur.readUsersFromFile()
+	ur.mu.RUnlock()
+	if isCorrupted(err) {
+		ur.mu.Lock()
+		users, err = ur.load()
+		ur.mu.Unlock()
+	}
+	if err != nil {
+		return nil, errr.NewUnexpectedError("Unable to get users due to internal server error")
+	}
+
+	slices.SortFunc(users, func(a, b models.User) int { return cmp.Compare(a.Username, b.Username) })
+	return users, nil
+}
+
+func (ur *userRepo) UpdateUser(user models.User) *errr.AppError {
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	users, err := ur.load()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to update user due to internal server error")
+	}
+
+	i := slices.IndexFunc(users, func(u models.User) bool { return u.ID == user.ID })
+	if i == -1 {
+		return errr.NewNotFoundError("User not Found")
+	}
+	users[i] = user
+
+	err = ur.commit(&putUser{User: user}, users)
 	if err != nil {
-		return errr.NewUnexpectedError("Unable to save user due to internal server error")
+		return errr.NewUnexpectedError("Unable to update user due to internal server error")
 	}
 
 	return nil
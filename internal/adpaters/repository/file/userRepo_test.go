@@ -4,10 +4,12 @@ import (
 	"net/http"
 	"os"
 	"path"
+	"reflect"
 	"slices"
 	"strings"
 	"testing"
 
+	"github.com/Jashanveer-Singh/todo-go/internal/adpaters/idgen"
 	"github.com/Jashanveer-Singh/todo-go/internal/errr"
 	"github.com/Jashanveer-Singh/todo-go/internal/models"
 )
@@ -68,7 +70,7 @@ func Test_userRepo_readUsersFromFile(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setupFile(tt.fp)
-			tr := NewUserRepo(tt.fp)
+			tr := &userRepo{fp: tt.fp}
 			got, gotErr := tr.readUsersFromFile()
 			if gotErr != nil {
 				if !tt.wantErr {
@@ -156,7 +158,7 @@ func Test_userRepo_writeUsersToFile(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setupFile(tt.fp)
-			tr := NewUserRepo(tt.fp)
+			tr := NewUserRepo(tt.fp, idgen.NewSequenceGenerator(0))
 			gotErr := tr.writeUsersToFile(tt.user)
 			if gotErr != nil {
 				if !tt.wantErr {
@@ -188,7 +190,7 @@ func Test_userRepo_GetUserByUsername(t *testing.T) {
 			name: "read to file failed",
 			fp:   getTempUsersPath(t),
 			setupFile: func(fp string) {
-				os.WriteFile(fp, []byte("asdfs"), 0666)
+				os.WriteFile(fp, []byte(""), 0333)
 			},
 			username: "user",
 			appError: &errr.AppError{
@@ -196,6 +198,18 @@ func Test_userRepo_GetUserByUsername(t *testing.T) {
 				Message: "Unable to save user due to internal server error",
 			},
 		},
+		{
+			name: "corrupted file is recovered",
+			fp:   getTempUsersPath(t),
+			setupFile: func(fp string) {
+				os.WriteFile(fp, []byte("asdfs"), 0666)
+			},
+			username: "user",
+			appError: &errr.AppError{
+				Code:    http.StatusNotFound,
+				Message: "User not Found",
+			},
+		},
 		{
 			name: "user not found",
 			fp:   getTempTasksPath(t),
@@ -234,7 +248,7 @@ func Test_userRepo_GetUserByUsername(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setupFile(tt.fp)
-			ur := NewUserRepo(tt.fp)
+			ur := NewUserRepo(tt.fp, idgen.NewSequenceGenerator(0))
 			gotUser, gotAppErr := ur.GetUserByUsername(tt.username)
 			// TODO: update the condition below to compare got with tt.want.
 			if tt.appError == nil && gotAppErr != nil {
@@ -255,7 +269,7 @@ func Test_userRepo_GetUserByUsername(t *testing.T) {
 				)
 				return
 			}
-			if *tt.appError != *gotAppErr {
+			if !reflect.DeepEqual(tt.appError, gotAppErr) {
 				t.Errorf("wanted app err: %v, got: %v.", tt.appError, gotAppErr)
 			}
 		})
@@ -276,7 +290,7 @@ func Test_userRepo_CreateUser(t *testing.T) {
 			name: "read to file failed",
 			fp:   getTempUsersPath(t),
 			setupFile: func(fp string) {
-				os.WriteFile(fp, []byte("asdfs"), 0666)
+				os.WriteFile(fp, []byte(""), 0333)
 			},
 			wantAppErr: &errr.AppError{
 				Code:    http.StatusInternalServerError,
@@ -322,8 +336,8 @@ func Test_userRepo_CreateUser(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setupFile(tt.fp)
-			ur := NewUserRepo(tt.fp)
-			gotAppErr := ur.CreateUser(tt.user)
+			ur := NewUserRepo(tt.fp, idgen.NewSequenceGenerator(0))
+			created, gotAppErr := ur.CreateUser(tt.user)
 			if tt.wantAppErr == nil && gotAppErr != nil {
 				t.Errorf("CreateUser() failed. wanted app err: %v", tt.wantAppErr)
 				return
@@ -337,10 +351,43 @@ func Test_userRepo_CreateUser(t *testing.T) {
 			}
 
 			if tt.wantAppErr != nil && gotAppErr != nil {
-				if *tt.wantAppErr != *gotAppErr {
+				if !reflect.DeepEqual(tt.wantAppErr, gotAppErr) {
 					t.Errorf("want app err: %v, got %v", tt.wantAppErr, gotAppErr)
 				}
+				return
+			}
+			stored, appErr := ur.GetUserByUsername(tt.user.Username)
+			if created.ID == 0 || appErr != nil || created != stored {
+				t.Errorf("CreateUser() = %v, stored %v, %v", created, stored, appErr)
 			}
 		})
 	}
 }
+
+func Test_userRepo_UpdateUser(t *testing.T) {
+	fp := getTempUsersPath(t)
+	os.WriteFile(fp, []byte(`[
+		{"id":2,"username":"zed","password":"hash"},
+		{"id":1,"username":"amy","password":"hash","role":"admin"}
+	]`), 0666)
+	ur := NewUserRepo(fp, idgen.NewSequenceGenerator(0))
+
+	want := models.User{ID: 2, Username: "zed", Password: "new hash", Role: models.RoleUser, Disabled: true}
+	appErr := ur.UpdateUser(want)
+	if appErr != nil {
+		t.Fatalf("UpdateUser() failed: %v", appErr)
+	}
+	appErr = ur.UpdateUser(models.User{ID: 3, Username: "nobody"})
+	if appErr == nil || appErr.Code != http.StatusNotFound {
+		t.Errorf("UpdateUser() of a missing user = %v, want not found", appErr)
+	}
+
+	users, appErr := NewUserRepo(fp, nil).GetUsers()
+	wantUsers := []models.User{
+		{ID: 1, Username: "amy", Password: "hash", Role: models.RoleAdmin},
+		want,
+	}
+	if appErr != nil || !reflect.DeepEqual(users, wantUsers) {
+		t.Errorf("GetUsers() = %v, %v, want %v", users, appErr, wantUsers)
+	}
+}
@@ -0,0 +1,191 @@
+package file
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+)
+
+// NewWorkflowRepo stores workflows in fp. Saving a workflow checks it against
+// the tasks kept by tasks.
+func NewWorkflowRepo(fp string, tasks *taskRepo) *workflowRepo {
+	_, err := os.Stat(fp)
+	if os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "file: %s does not exist\n", fp)
+	}
+	if os.IsPermission(err) {
+		fmt.Fprintf(os.Stderr, "not enough permissions for file: %s\n", fp)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "can't use the file: %s\n%s", fp, err.Error())
+		os.Exit(1)
+	}
+
+	wr := &workflowRepo{
+		mu:      sync.RWMutex{},
+		fp:      fp,
+		journal: newJournal(fp, workflowMutation),
+		tasks:   tasks,
+	}
+
+	err = wr.recover()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to recover the file: %s\n%s\n", fp, err.Error())
+	}
+
+	return wr
+}
+
+type workflowRepo struct {
+	mu      sync.RWMutex
+	fp      string
+	journal journal[models.Workflow]
+	tasks   *taskRepo
+}
+
+func (wr *workflowRepo) getWorkflows() ([]models.Workflow, error) {
+	workflows := make([]models.Workflow, 0)
+
+	workflowjson, err := os.ReadFile(wr.fp)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read workflows from file.\n%w", err)
+	}
+	if len(workflowjson) != 0 {
+		err = json.Unmarshal(workflowjson, &workflows)
+		if err != nil {
+			return nil, fmt.Errorf("unable to unmarshal/decode json.\n%w", err)
+		}
+	}
+
+	return workflows, nil
+}
+
+// load reads the workflows like getWorkflows, first recovering the file when
+// it is corrupted. The caller must hold the write lock.
+func (wr *workflowRepo) load() ([]models.Workflow, error) {
+	workflows, err := wr.getWorkflows()
+	if isCorrupted(err) {
+		err = quarantine(wr.fp)
+		if err != nil {
+			return nil, err
+		}
+		return wr.getWorkflows()
+	}
+
+	return workflows, err
+}
+
+func (wr *workflowRepo) write(workflows []models.Workflow) error {
+	workflowjson, _ := json.Marshal(workflows)
+
+	err := writeFileAtomic(wr.fp, workflowjson, 0644)
+	if err != nil {
+		return fmt.Errorf("unable to write workflows to file.\n%s", err.Error())
+	}
+
+	return nil
+}
+
+// recover replays mutations journaled before a crash onto the workflows file.
+func (wr *workflowRepo) recover() error {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+
+	workflows, err := wr.load()
+	if err != nil {
+		return err
+	}
+
+	entries, err := wr.journal.entries()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	for _, entry := range entries {
+		workflows = entry.apply(workflows)
+	}
+
+	err = wr.write(workflows)
+	if err != nil {
+		return err
+	}
+
+	return wr.journal.clear()
+}
+
+func (wr *workflowRepo) GetWorkflow(userID int64) (models.Workflow, *errr.AppError) {
+	wr.mu.RLock()
+	workflows, err := wr.getWorkflows()
+	wr.mu.RUnlock()
+	if isCorrupted(err) {
+		wr.mu.Lock()
+		workflows, err = wr.load()
+		wr.mu.Unlock()
+	}
+	if err != nil {
+		return models.Workflow{}, errr.NewUnexpectedError("Unable to get workflow due to internal server error")
+	}
+
+	i := slices.IndexFunc(workflows, func(w models.Workflow) bool { return w.UserID == userID })
+	if i == -1 {
+		return models.DefaultWorkflow(userID), nil
+	}
+
+	return workflows[i], nil
+}
+
+// SaveWorkflow holds the lock of the tasks while it checks and saves the
+// workflow. It takes that lock before its own, as a transaction of the tasks
+// reads workflows while holding it.
+func (wr *workflowRepo) SaveWorkflow(workflow models.Workflow) *errr.AppError {
+	tasks, unlock, err := wr.tasks.lockTasks()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to save workflow due to internal server error")
+	}
+	defer unlock()
+
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	workflows, err := wr.load()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to save workflow due to internal server error")
+	}
+
+	stored := models.DefaultWorkflow(workflow.UserID)
+	if i := slices.IndexFunc(workflows, func(w models.Workflow) bool { return w.UserID == workflow.UserID }); i != -1 {
+		stored = workflows[i]
+	}
+	statuses := []int{}
+	for _, task := range tasks {
+		if task.UserID == workflow.UserID {
+			statuses = append(statuses, task.Status)
+		}
+	}
+	if id, ok := workflow.MissingStatus(statuses); ok {
+		return errr.NewDuplicateError(fmt.Sprintf("Status %s is still used by tasks", stored.StatusName(id)))
+	}
+
+	entry := &putWorkflow{Workflow: workflow}
+	undo, err := wr.journal.append(entry)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to save workflow due to internal server error")
+	}
+
+	err = wr.write(entry.apply(workflows))
+	if err != nil {
+		undo()
+		return errr.NewUnexpectedError("Unable to save workflow due to internal server error")
+	}
+
+	wr.journal.clear()
+	return nil
+}
@@ -0,0 +1,102 @@
+package file
+
+import (
+	"net/http"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/adpaters/idgen"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+)
+
+func newTestWorkflowRepo(t *testing.T, tasksjson, workflowsjson string) *workflowRepo {
+	dir := t.TempDir()
+	tasksFile := path.Join(dir, "tasks.json")
+	workflowsFile := path.Join(dir, "workflows.json")
+	os.WriteFile(tasksFile, []byte(tasksjson), 0644)
+	os.WriteFile(workflowsFile, []byte(workflowsjson), 0644)
+
+	tr := NewTaskRepo(tasksFile, idgen.NewSequenceGenerator(100))
+	return NewWorkflowRepo(workflowsFile, tr)
+}
+
+func Test_workflowRepo_GetWorkflow(t *testing.T) {
+	wr := newTestWorkflowRepo(t, `[]`, `[]`)
+
+	workflow, appErr := wr.GetWorkflow(1234)
+	if appErr != nil {
+		t.Fatalf("GetWorkflow() failed: %v", appErr)
+	}
+	if !reflect.DeepEqual(workflow, models.DefaultWorkflow(1234)) {
+		t.Errorf("GetWorkflow() = %v, want the default workflow", workflow)
+	}
+
+	want := models.Workflow{
+		UserID: 1234,
+		Statuses: []models.WorkflowStatus{
+			{ID: 3, Name: "Review", Next: []int{models.StatusDone}},
+			{ID: models.StatusDone, Name: "Done", Terminal: true},
+		},
+	}
+	appErr = wr.SaveWorkflow(want)
+	if appErr != nil {
+		t.Fatalf("SaveWorkflow() failed: %v", appErr)
+	}
+
+	workflow, _ = NewWorkflowRepo(wr.fp, wr.tasks).GetWorkflow(1234)
+	if !reflect.DeepEqual(workflow, want) {
+		t.Errorf("GetWorkflow() after save = %v, want %v", workflow, want)
+	}
+	workflow, _ = wr.GetWorkflow(99)
+	if !reflect.DeepEqual(workflow, models.DefaultWorkflow(99)) {
+		t.Errorf("GetWorkflow() of another user = %v, want the default workflow", workflow)
+	}
+}
+
+func Test_workflowRepo_SaveWorkflow_statuses_in_use(t *testing.T) {
+	review := models.Workflow{
+		UserID: 1234,
+		Statuses: []models.WorkflowStatus{
+			{ID: 3, Name: "Review", Next: []int{models.StatusDone}},
+			{ID: models.StatusDone, Name: "Done", Terminal: true},
+		},
+	}
+	tests := []struct {
+		name      string
+		tasksjson string
+		wantCode  int
+	}{
+		{
+			name:      "statuses of other users don't count",
+			tasksjson: `[{"id":1,"title":"a","user_id":99,"status":0}]`,
+		},
+		{
+			name:      "status still used by a task",
+			tasksjson: `[{"id":1,"title":"a","user_id":1234,"status":0}]`,
+			wantCode:  http.StatusConflict,
+		},
+		{
+			name:      "status still used by a task in the trash",
+			tasksjson: `[{"id":1,"title":"a","user_id":1234,"status":0,"deleted_at":"2020-01-05T10:00:00Z"}]`,
+			wantCode:  http.StatusConflict,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wr := newTestWorkflowRepo(t, tt.tasksjson, `[]`)
+
+			appErr := wr.SaveWorkflow(review)
+			if tt.wantCode == 0 && appErr != nil {
+				t.Fatalf("SaveWorkflow() failed: %v", appErr)
+			}
+			if tt.wantCode != 0 && (appErr == nil || appErr.Code != tt.wantCode) {
+				t.Fatalf("SaveWorkflow() = %v, want %d", appErr, tt.wantCode)
+			}
+			if tt.wantCode != 0 && appErr.Message != "Status Pending is still used by tasks" {
+				t.Errorf("SaveWorkflow() message = %q, want the status named", appErr.Message)
+			}
+		})
+	}
+}
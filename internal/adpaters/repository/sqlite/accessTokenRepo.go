@@ -0,0 +1,160 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/internal/ports"
+)
+
+func NewAccessTokenRepo(db *sql.DB, idGen ports.IDGenerator) *accessTokenRepo {
+	return &accessTokenRepo{
+		db:    db,
+		idGen: idGen,
+	}
+}
+
+type accessTokenRepo struct {
+	db    *sql.DB
+	idGen ports.IDGenerator
+}
+
+const accessTokenColumns = `id, user_id, name, hash, scopes, created_at, expires_at, last_used_at`
+
+// scanAccessToken reads a token, its scopes are stored separated by spaces.
+func scanAccessToken(row rowScanner) (models.AccessToken, error) {
+	var token models.AccessToken
+	var scopes string
+	var createdAt, expiresAt, lastUsedAt int64
+	err := row.Scan(
+		&token.ID, &token.UserID, &token.Name, &token.Hash, &scopes,
+		&createdAt, &expiresAt, &lastUsedAt,
+	)
+	token.Scopes = strings.Fields(scopes)
+	token.CreatedAt = parseUnixNano(createdAt)
+	token.ExpiresAt = parseUnixNano(expiresAt)
+	token.LastUsedAt = parseUnixNano(lastUsedAt)
+	return token, err
+}
+
+func (ar *accessTokenRepo) SaveAccessToken(token models.AccessToken) (models.AccessToken, *errr.AppError) {
+	token.ID = ar.idGen.NextID()
+	_, err := ar.db.Exec(
+		`INSERT INTO access_tokens (`+accessTokenColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		token.ID, token.UserID, token.Name, token.Hash, strings.Join(token.Scopes, " "),
+		unixNano(token.CreatedAt), unixNano(token.ExpiresAt), unixNano(token.LastUsedAt),
+	)
+	if err != nil {
+		return models.AccessToken{}, errr.NewUnexpectedError(
+			"Unable to save access token due to internal server error",
+		)
+	}
+
+	return token, nil
+}
+
+func (ar *accessTokenRepo) GetAccessTokens(userID int64) ([]models.AccessToken, *errr.AppError) {
+	rows, err := ar.db.Query(
+		`SELECT `+accessTokenColumns+` FROM access_tokens WHERE user_id = ? ORDER BY id`,
+		userID,
+	)
+	if err != nil {
+		return nil, errr.NewUnexpectedError("Unable to get access tokens due to internal server error")
+	}
+	defer rows.Close()
+
+	tokens := []models.AccessToken{}
+	for rows.Next() {
+		token, err := scanAccessToken(rows)
+		if err != nil {
+			return nil, errr.NewUnexpectedError("Unable to get access tokens due to internal server error")
+		}
+		tokens = append(tokens, token)
+	}
+	if rows.Err() != nil {
+		return nil, errr.NewUnexpectedError("Unable to get access tokens due to internal server error")
+	}
+
+	return tokens, nil
+}
+
+func (ar *accessTokenRepo) GetAccessTokenByHash(hash string) (models.AccessToken, *errr.AppError) {
+	token, err := scanAccessToken(ar.db.QueryRow(
+		`SELECT `+accessTokenColumns+` FROM access_tokens WHERE hash = ?`,
+		hash,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.AccessToken{}, errr.NewNotFoundError("Access token not found")
+	}
+	if err != nil {
+		return models.AccessToken{}, errr.NewUnexpectedError(
+			"Unable to get access token due to internal server error",
+		)
+	}
+
+	return token, nil
+}
+
+func (ar *accessTokenRepo) TouchAccessToken(id int64, usedAt time.Time) *errr.AppError {
+	result, err := ar.db.Exec(
+		`UPDATE access_tokens SET last_used_at = ? WHERE id = ?`,
+		unixNano(usedAt), id,
+	)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to update access token due to internal server error")
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to update access token due to internal server error")
+	}
+	if updated == 0 {
+		return errr.NewNotFoundError("Access token not found")
+	}
+
+	return nil
+}
+
+func (ar *accessTokenRepo) DeleteAccessToken(id int64, userID int64) *errr.AppError {
+	tx, err := ar.db.Begin()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to delete access token due to internal server error")
+	}
+	defer tx.Rollback()
+
+	var ownerID int64
+	err = tx.QueryRow(`SELECT user_id FROM access_tokens WHERE id = ?`, id).Scan(&ownerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errr.NewNotFoundError("Access token not found")
+	}
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to delete access token due to internal server error")
+	}
+	if ownerID != userID {
+		return errr.NewUnauthorizedError("Unauthorized to delete access token")
+	}
+
+	_, err = tx.Exec(`DELETE FROM access_tokens WHERE id = ?`, id)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to delete access token due to internal server error")
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to delete access token due to internal server error")
+	}
+
+	return nil
+}
+
+func (ar *accessTokenRepo) RevokeUserAccessTokens(userID int64) *errr.AppError {
+	_, err := ar.db.Exec(`DELETE FROM access_tokens WHERE user_id = ?`, userID)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to revoke access tokens due to internal server error")
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package sqlite
+
+import (
+	"database/sql"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/internal/ports"
+)
+
+func NewAuditRepo(db *sql.DB, idGen ports.IDGenerator) *auditRepo {
+	return &auditRepo{
+		db:    db,
+		idGen: idGen,
+	}
+}
+
+type auditRepo struct {
+	db    *sql.DB
+	idGen ports.IDGenerator
+}
+
+func (ar *auditRepo) SaveAuditEntry(entry models.AuditEntry) *errr.AppError {
+	entry.ID = ar.idGen.NextID()
+	_, err := ar.db.Exec(
+		`INSERT INTO audit_log (id, actor_id, action, target_id, outcome, reason, at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		entry.ID, entry.ActorID, entry.Action, entry.TargetID, entry.Outcome, entry.Reason, unixNano(entry.At),
+	)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to save audit entry due to internal server error")
+	}
+
+	return nil
+}
+
+func (ar *auditRepo) GetAuditEntries() ([]models.AuditEntry, *errr.AppError) {
+	rows, err := ar.db.Query(
+		`SELECT id, actor_id, action, target_id, outcome, reason, at FROM audit_log
+		ORDER BY at DESC, id DESC`,
+	)
+	if err != nil {
+		return nil, errr.NewUnexpectedError("Unable to get audit log due to internal server error")
+	}
+	defer rows.Close()
+
+	entries := make([]models.AuditEntry, 0)
+	for rows.Next() {
+		var entry models.AuditEntry
+		var at int64
+		err = rows.Scan(
+			&entry.ID, &entry.ActorID, &entry.Action, &entry.TargetID, &entry.Outcome, &entry.Reason, &at,
+		)
+		if err != nil {
+			return nil, errr.NewUnexpectedError("Unable to get audit log due to internal server error")
+		}
+		entry.At = parseUnixNano(at)
+		entries = append(entries, entry)
+	}
+	if rows.Err() != nil {
+		return nil, errr.NewUnexpectedError("Unable to get audit log due to internal server error")
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,40 @@
+package sqlite
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/adpaters/idgen"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+)
+
+func Test_auditRepo(t *testing.T) {
+	ar := NewAuditRepo(getTempDB(t), idgen.NewSequenceGenerator(0))
+
+	at := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	for _, entry := range []models.AuditEntry{
+		{ActorID: 1, Action: models.AuditListUsers, Outcome: models.AuditSucceeded, At: at},
+		{
+			ActorID: 2, Action: models.AuditDisableUser, TargetID: 77,
+			Outcome: models.AuditDenied, Reason: "insufficient_role", At: at.Add(time.Minute),
+		},
+	} {
+		appErr := ar.SaveAuditEntry(entry)
+		if appErr != nil {
+			t.Fatalf("SaveAuditEntry() failed: %v", appErr)
+		}
+	}
+
+	got, appErr := ar.GetAuditEntries()
+	want := []models.AuditEntry{
+		{
+			ID: 2, ActorID: 2, Action: models.AuditDisableUser, TargetID: 77,
+			Outcome: models.AuditDenied, Reason: "insufficient_role", At: at.Add(time.Minute),
+		},
+		{ID: 1, ActorID: 1, Action: models.AuditListUsers, Outcome: models.AuditSucceeded, At: at},
+	}
+	if appErr != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAuditEntries() = %v, %v, want %v", got, appErr, want)
+	}
+}
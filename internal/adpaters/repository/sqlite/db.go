@@ -0,0 +1,224 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+
+	_ "modernc.org/sqlite"
+)
+
+// migrations upgrade the schema one version at a time, the version reached is
+// kept in PRAGMA user_version. Only ever append to this list.
+var migrations = []string{
+	`
+	CREATE TABLE IF NOT EXISTS users (
+		id       INTEGER PRIMARY KEY,
+		username TEXT    NOT NULL,
+		password TEXT    NOT NULL
+	);
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username ON users (username);
+
+	CREATE TABLE IF NOT EXISTS tasks (
+		id          INTEGER PRIMARY KEY,
+		title       TEXT    NOT NULL,
+		description TEXT    NOT NULL,
+		status      INTEGER NOT NULL,
+		user_id     INTEGER NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_tasks_user_id ON tasks (user_id);
+	`,
+	`
+	ALTER TABLE users ADD COLUMN time_zone TEXT NOT NULL DEFAULT '';
+	ALTER TABLE tasks ADD COLUMN due_at TEXT;
+	ALTER TABLE tasks ADD COLUMN start_at TEXT;
+	CREATE INDEX idx_tasks_user_id_due_at ON tasks (user_id, due_at);
+	`,
+	`
+	ALTER TABLE tasks ADD COLUMN priority INTEGER NOT NULL DEFAULT 0;
+	`,
+	`
+	CREATE TABLE labels (
+		id      INTEGER PRIMARY KEY,
+		user_id INTEGER NOT NULL,
+		name    TEXT    NOT NULL,
+		color   TEXT    NOT NULL
+	);
+	CREATE UNIQUE INDEX idx_labels_user_id_name ON labels (user_id, name COLLATE NOCASE);
+
+	CREATE TABLE task_labels (
+		task_id  INTEGER NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+		label_id INTEGER NOT NULL REFERENCES labels (id) ON DELETE CASCADE,
+		PRIMARY KEY (task_id, label_id)
+	);
+	CREATE INDEX idx_task_labels_label_id ON task_labels (label_id);
+	`,
+	`
+	ALTER TABLE tasks ADD COLUMN parent_id INTEGER REFERENCES tasks (id) ON DELETE CASCADE;
+	CREATE INDEX idx_tasks_parent_id ON tasks (parent_id);
+
+	CREATE TABLE checklist_items (
+		task_id  INTEGER NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+		position INTEGER NOT NULL,
+		text     TEXT    NOT NULL,
+		done     INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY (task_id, position)
+	);
+	`,
+	`
+	ALTER TABLE tasks ADD COLUMN recurrence TEXT;
+	ALTER TABLE tasks ADD COLUMN series_id INTEGER;
+	CREATE INDEX idx_tasks_series_id ON tasks (series_id);
+	`,
+	`
+	CREATE TABLE projects (
+		id       INTEGER PRIMARY KEY,
+		user_id  INTEGER NOT NULL,
+		name     TEXT    NOT NULL,
+		color    TEXT    NOT NULL,
+		archived INTEGER NOT NULL DEFAULT 0,
+		position INTEGER NOT NULL
+	);
+	CREATE UNIQUE INDEX idx_projects_user_id_name ON projects (user_id, name COLLATE NOCASE);
+
+	ALTER TABLE tasks ADD COLUMN project_id INTEGER REFERENCES projects (id) ON DELETE SET NULL;
+	CREATE INDEX idx_tasks_user_id_project_id ON tasks (user_id, project_id);
+	`,
+	`
+	ALTER TABLE tasks ADD COLUMN done INTEGER NOT NULL DEFAULT 0;
+	UPDATE tasks SET done = 1 WHERE status = 1;
+
+	CREATE TABLE workflow_statuses (
+		user_id  INTEGER NOT NULL,
+		id       INTEGER NOT NULL,
+		position INTEGER NOT NULL,
+		name     TEXT    NOT NULL,
+		terminal INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY (user_id, id)
+	);
+
+	CREATE TABLE workflow_transitions (
+		user_id INTEGER NOT NULL,
+		from_id INTEGER NOT NULL,
+		to_id   INTEGER NOT NULL,
+		PRIMARY KEY (user_id, from_id, to_id),
+		FOREIGN KEY (user_id, from_id) REFERENCES workflow_statuses (user_id, id) ON DELETE CASCADE,
+		FOREIGN KEY (user_id, to_id) REFERENCES workflow_statuses (user_id, id) ON DELETE CASCADE
+	);
+	`,
+	`
+	ALTER TABLE tasks ADD COLUMN created_at INTEGER NOT NULL DEFAULT 0;
+	ALTER TABLE tasks ADD COLUMN updated_at INTEGER NOT NULL DEFAULT 0;
+	CREATE INDEX idx_tasks_user_id_created_at ON tasks (user_id, created_at, id);
+	CREATE INDEX idx_tasks_user_id_updated_at ON tasks (user_id, updated_at, id);
+	`,
+	`
+	ALTER TABLE tasks ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+	`,
+	`
+	ALTER TABLE tasks ADD COLUMN deleted_at INTEGER NOT NULL DEFAULT 0;
+	CREATE INDEX idx_tasks_deleted_at ON tasks (deleted_at);
+	`,
+	`
+	CREATE TABLE refresh_tokens (
+		hash       TEXT    PRIMARY KEY,
+		family_id  TEXT    NOT NULL,
+		user_id    INTEGER NOT NULL,
+		expires_at INTEGER NOT NULL,
+		used_at    INTEGER NOT NULL DEFAULT 0
+	);
+	CREATE INDEX idx_refresh_tokens_family_id ON refresh_tokens (family_id);
+	`,
+	`
+	CREATE INDEX idx_refresh_tokens_user_id ON refresh_tokens (user_id);
+	CREATE TABLE revocations (
+		id           INTEGER PRIMARY KEY,
+		token_id     TEXT    NOT NULL DEFAULT '',
+		user_id      INTEGER NOT NULL,
+		issued_until INTEGER NOT NULL DEFAULT 0,
+		expires_at   INTEGER NOT NULL
+	);
+	CREATE INDEX idx_revocations_token_id ON revocations (token_id);
+	CREATE INDEX idx_revocations_user_id ON revocations (user_id);
+	CREATE INDEX idx_revocations_expires_at ON revocations (expires_at);
+	`,
+	`
+	ALTER TABLE users ADD COLUMN role TEXT NOT NULL DEFAULT 'user';
+	ALTER TABLE users ADD COLUMN disabled INTEGER NOT NULL DEFAULT 0;
+	CREATE TABLE audit_log (
+		id        INTEGER PRIMARY KEY,
+		actor_id  INTEGER NOT NULL,
+		action    TEXT    NOT NULL,
+		target_id INTEGER NOT NULL DEFAULT 0,
+		at        INTEGER NOT NULL
+	);
+	CREATE INDEX idx_audit_log_at ON audit_log (at);
+	`,
+	`
+	CREATE TABLE access_tokens (
+		id           INTEGER PRIMARY KEY,
+		user_id      INTEGER NOT NULL,
+		name         TEXT    NOT NULL,
+		hash         TEXT    NOT NULL UNIQUE,
+		scopes       TEXT    NOT NULL,
+		created_at   INTEGER NOT NULL,
+		expires_at   INTEGER NOT NULL DEFAULT 0,
+		last_used_at INTEGER NOT NULL DEFAULT 0
+	);
+	CREATE INDEX idx_access_tokens_user_id ON access_tokens (user_id);
+	`,
+	// Only successful actions were recorded before outcomes were.
+	`
+	ALTER TABLE audit_log ADD COLUMN outcome TEXT NOT NULL DEFAULT 'succeeded';
+	ALTER TABLE audit_log ADD COLUMN reason TEXT NOT NULL DEFAULT '';
+	`,
+}
+
+// NewDB opens the sqlite database at fp, creating the file and bringing the
+// schema up to date.
+func NewDB(fp string) (*sql.DB, error) {
+	dsn := fmt.Sprintf(
+		"file:%s?_txlock=immediate&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)",
+		fp,
+	)
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open database.\n%s", err.Error())
+	}
+
+	err = migrate(db)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("unable to create database schema.\n%s", err.Error())
+	}
+
+	return db, nil
+}
+
+func migrate(db *sql.DB) error {
+	var version int
+	err := db.QueryRow(`PRAGMA user_version`).Scan(&version)
+	if err != nil {
+		return err
+	}
+
+	for ; version < len(migrations); version++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(migrations[version])
+		if err == nil {
+			_, err = tx.Exec(fmt.Sprintf(`PRAGMA user_version = %d`, version+1))
+		}
+		if err == nil {
+			err = tx.Commit()
+		}
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d failed: %w", version+1, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,196 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/internal/ports"
+)
+
+func NewLabelRepo(db *sql.DB, idGen ports.IDGenerator) *labelRepo {
+	return &labelRepo{
+		db:    db,
+		idGen: idGen,
+	}
+}
+
+type labelRepo struct {
+	db    *sql.DB
+	idGen ports.IDGenerator
+}
+
+const labelColumns = `id, user_id, name, color`
+
+func scanLabel(row rowScanner) (models.Label, error) {
+	var label models.Label
+	err := row.Scan(&label.ID, &label.UserID, &label.Name, &label.Color)
+	return label, err
+}
+
+// hasLabelNamed reports whether the user has a label called name, other than
+// the label with exceptID.
+func hasLabelNamed(tx *sql.Tx, userID int64, name string, exceptID int64) (bool, error) {
+	var exists bool
+	err := tx.QueryRow(
+		`SELECT EXISTS (
+			SELECT 1 FROM labels WHERE user_id = ? AND name = ? COLLATE NOCASE AND id != ?
+		)`,
+		userID, name, exceptID,
+	).Scan(&exists)
+	return exists, err
+}
+
+func (lr *labelRepo) SaveLabel(label models.Label) (models.Label, *errr.AppError) {
+	label.ID = lr.idGen.NextID()
+
+	tx, err := lr.db.Begin()
+	if err != nil {
+		return models.Label{}, errr.NewUnexpectedError("Unable to save label due to internal server error")
+	}
+	defer tx.Rollback()
+
+	exists, err := hasLabelNamed(tx, label.UserID, label.Name, 0)
+	if err != nil {
+		return models.Label{}, errr.NewUnexpectedError("Unable to save label due to internal server error")
+	}
+	if exists {
+		return models.Label{}, errr.NewDuplicateError("label already exists")
+	}
+
+	_, err = tx.Exec(
+		`INSERT INTO labels (`+labelColumns+`) VALUES (?, ?, ?, ?)`,
+		label.ID, label.UserID, label.Name, label.Color,
+	)
+	if err != nil {
+		return models.Label{}, errr.NewUnexpectedError("Unable to save label due to internal server error")
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return models.Label{}, errr.NewUnexpectedError("Unable to save label due to internal server error")
+	}
+
+	return label, nil
+}
+
+func (lr *labelRepo) UpdateLabel(id int64, label models.Label) *errr.AppError {
+	tx, err := lr.db.Begin()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to update label due to internal server error")
+	}
+	defer tx.Rollback()
+
+	stored, err := scanLabel(tx.QueryRow(`SELECT `+labelColumns+` FROM labels WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return errr.NewNotFoundError("no label found with id")
+	}
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to update label due to internal server error")
+	}
+	if stored.UserID != label.UserID {
+		return errr.NewUnauthorizedError("Unauthorized to update label")
+	}
+
+	if len(label.Name) != 0 {
+		exists, err := hasLabelNamed(tx, label.UserID, label.Name, id)
+		if err != nil {
+			return errr.NewUnexpectedError("Unable to update label due to internal server error")
+		}
+		if exists {
+			return errr.NewDuplicateError("label already exists")
+		}
+		stored.Name = label.Name
+	}
+	if len(label.Color) != 0 {
+		stored.Color = label.Color
+	}
+
+	_, err = tx.Exec(
+		`UPDATE labels SET name = ?, color = ? WHERE id = ?`,
+		stored.Name, stored.Color, id,
+	)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to update label due to internal server error")
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to update label due to internal server error")
+	}
+
+	return nil
+}
+
+// DeleteLabel removes the label and detaches it from every task in one
+// transaction.
+func (lr *labelRepo) DeleteLabel(id int64, userID int64) *errr.AppError {
+	tx, err := lr.db.Begin()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to delete label due to internal server error")
+	}
+	defer tx.Rollback()
+
+	stored, err := scanLabel(tx.QueryRow(`SELECT `+labelColumns+` FROM labels WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return errr.NewNotFoundError("no label found with id")
+	}
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to delete label due to internal server error")
+	}
+	if stored.UserID != userID {
+		return errr.NewUnauthorizedError("Unauthorized to delete label")
+	}
+
+	_, err = tx.Exec(
+		`UPDATE tasks SET version = version + 1
+		WHERE id IN (SELECT task_id FROM task_labels WHERE label_id = ?)`,
+		id,
+	)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to delete label due to internal server error")
+	}
+
+	_, err = tx.Exec(`DELETE FROM task_labels WHERE label_id = ?`, id)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to delete label due to internal server error")
+	}
+
+	_, err = tx.Exec(`DELETE FROM labels WHERE id = ?`, id)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to delete label due to internal server error")
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to delete label due to internal server error")
+	}
+
+	return nil
+}
+
+func (lr *labelRepo) GetLabels(userID int64) ([]models.Label, *errr.AppError) {
+	rows, err := lr.db.Query(
+		`SELECT `+labelColumns+` FROM labels WHERE user_id = ? ORDER BY name COLLATE NOCASE, id`,
+		userID,
+	)
+	if err != nil {
+		return nil, errr.NewUnexpectedError("Unable to get labels due to internal server error")
+	}
+	defer rows.Close()
+
+	labels := []models.Label{}
+	for rows.Next() {
+		label, err := scanLabel(rows)
+		if err != nil {
+			return nil, errr.NewUnexpectedError("Unable to get labels due to internal server error")
+		}
+		labels = append(labels, label)
+	}
+	if rows.Err() != nil {
+		return nil, errr.NewUnexpectedError("Unable to get labels due to internal server error")
+	}
+
+	return labels, nil
+}
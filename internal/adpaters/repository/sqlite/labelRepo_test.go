@@ -0,0 +1,88 @@
+package sqlite
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/adpaters/idgen"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+)
+
+func Test_labelRepo_SaveLabel(t *testing.T) {
+	db := getTempDB(t)
+	lr := NewLabelRepo(db, idgen.NewSequenceGenerator(0))
+
+	label, appErr := lr.SaveLabel(models.Label{UserID: 1234, Name: "Work", Color: "#ffffff"})
+	if appErr != nil {
+		t.Fatalf("SaveLabel() failed: %v", appErr)
+	}
+	if label.ID != 1 {
+		t.Errorf("SaveLabel() id = %d, want 1", label.ID)
+	}
+
+	_, appErr = lr.SaveLabel(models.Label{UserID: 1234, Name: "work", Color: "#000000"})
+	if appErr == nil || appErr.Code != http.StatusConflict {
+		t.Errorf("SaveLabel() with a taken name = %v, want conflict", appErr)
+	}
+
+	_, appErr = lr.SaveLabel(models.Label{UserID: 99, Name: "work", Color: "#000000"})
+	if appErr != nil {
+		t.Errorf("SaveLabel() with a name taken by another user failed: %v", appErr)
+	}
+}
+
+func Test_labelRepo_UpdateLabel(t *testing.T) {
+	db := getTempDB(t)
+	lr := NewLabelRepo(db, idgen.NewSequenceGenerator(0))
+	lr.SaveLabel(models.Label{UserID: 1234, Name: "work", Color: "#ffffff"})
+
+	appErr := lr.UpdateLabel(1, models.Label{UserID: 99, Name: "home"})
+	if appErr == nil || appErr.Code != http.StatusForbidden {
+		t.Errorf("UpdateLabel() by another user = %v, want forbidden", appErr)
+	}
+
+	appErr = lr.UpdateLabel(1, models.Label{UserID: 1234, Color: "#000000"})
+	if appErr != nil {
+		t.Fatalf("UpdateLabel() failed: %v", appErr)
+	}
+
+	labels, _ := lr.GetLabels(1234)
+	want := []models.Label{{ID: 1, UserID: 1234, Name: "work", Color: "#000000"}}
+	if len(labels) != 1 || labels[0] != want[0] {
+		t.Errorf("labels after update = %v, want %v", labels, want)
+	}
+}
+
+func Test_labelRepo_DeleteLabel(t *testing.T) {
+	db := getTempDB(t)
+	tr := NewTaskRepo(db, idgen.NewSequenceGenerator(100))
+	lr := NewLabelRepo(db, idgen.NewSequenceGenerator(0))
+	lr.SaveLabel(models.Label{UserID: 1234, Name: "work", Color: "#ffffff"})
+	lr.SaveLabel(models.Label{UserID: 1234, Name: "home", Color: "#000000"})
+	tr.SaveTask(models.Task{Title: "a", UserID: 1234, LabelIDs: []int64{1, 2}})
+	tr.SaveTask(models.Task{Title: "b", UserID: 1234, LabelIDs: []int64{1}})
+
+	appErr := lr.DeleteLabel(1, 99)
+	if appErr == nil || appErr.Code != http.StatusForbidden {
+		t.Errorf("DeleteLabel() by another user = %v, want forbidden", appErr)
+	}
+
+	appErr = lr.DeleteLabel(1, 1234)
+	if appErr != nil {
+		t.Fatalf("DeleteLabel() failed: %v", appErr)
+	}
+
+	tasks, _ := tr.GetTasks(models.NewTaskQuery(1234))
+	want := []models.Task{
+		{ID: 101, Title: "a", UserID: 1234, LabelIDs: []int64{2}, Version: 2},
+		{ID: 102, Title: "b", UserID: 1234, Version: 2},
+	}
+	if !equalTasks(tasks, want) {
+		t.Errorf("tasks after delete = %v, want %v", tasks, want)
+	}
+
+	appErr = lr.DeleteLabel(1, 1234)
+	if appErr == nil || appErr.Code != http.StatusNotFound {
+		t.Errorf("DeleteLabel() twice = %v, want not found", appErr)
+	}
+}
@@ -0,0 +1,204 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/internal/ports"
+)
+
+func NewProjectRepo(db *sql.DB, idGen ports.IDGenerator) *projectRepo {
+	return &projectRepo{
+		db:    db,
+		idGen: idGen,
+	}
+}
+
+type projectRepo struct {
+	db    *sql.DB
+	idGen ports.IDGenerator
+}
+
+const projectColumns = `id, user_id, name, color, archived, position`
+
+func scanProject(row rowScanner) (models.Project, error) {
+	var project models.Project
+	err := row.Scan(
+		&project.ID, &project.UserID, &project.Name, &project.Color,
+		&project.Archived, &project.Position,
+	)
+	return project, err
+}
+
+// hasProjectNamed reports whether the user has a project called name, other
+// than the project with exceptID.
+func hasProjectNamed(tx *sql.Tx, userID int64, name string, exceptID int64) (bool, error) {
+	var exists bool
+	err := tx.QueryRow(
+		`SELECT EXISTS (
+			SELECT 1 FROM projects WHERE user_id = ? AND name = ? COLLATE NOCASE AND id != ?
+		)`,
+		userID, name, exceptID,
+	).Scan(&exists)
+	return exists, err
+}
+
+// getOwnedProject reads the project with id, which userID must own to action
+// it.
+func getOwnedProject(q queryer, id int64, userID int64, action string) (models.Project, *errr.AppError) {
+	project, err := scanProject(q.QueryRow(`SELECT `+projectColumns+` FROM projects WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Project{}, errr.NewNotFoundError("no project found with id")
+	}
+	if err != nil {
+		return models.Project{}, errr.NewUnexpectedError("Unable to " + action + " project due to internal server error")
+	}
+	if project.UserID != userID {
+		return models.Project{}, errr.NewUnauthorizedError("Unauthorized to " + action + " project")
+	}
+	return project, nil
+}
+
+// SaveProject adds the project after the other projects of its user.
+func (pr *projectRepo) SaveProject(project models.Project) (models.Project, *errr.AppError) {
+	project.ID = pr.idGen.NextID()
+
+	tx, err := pr.db.Begin()
+	if err != nil {
+		return models.Project{}, errr.NewUnexpectedError("Unable to save project due to internal server error")
+	}
+	defer tx.Rollback()
+
+	exists, err := hasProjectNamed(tx, project.UserID, project.Name, 0)
+	if err != nil {
+		return models.Project{}, errr.NewUnexpectedError("Unable to save project due to internal server error")
+	}
+	if exists {
+		return models.Project{}, errr.NewDuplicateError("project already exists")
+	}
+
+	err = tx.QueryRow(
+		`SELECT COALESCE(MAX(position) + 1, 0) FROM projects WHERE user_id = ?`,
+		project.UserID,
+	).Scan(&project.Position)
+	if err != nil {
+		return models.Project{}, errr.NewUnexpectedError("Unable to save project due to internal server error")
+	}
+
+	_, err = tx.Exec(
+		`INSERT INTO projects (`+projectColumns+`) VALUES (?, ?, ?, ?, ?, ?)`,
+		project.ID, project.UserID, project.Name, project.Color, project.Archived, project.Position,
+	)
+	if err != nil {
+		return models.Project{}, errr.NewUnexpectedError("Unable to save project due to internal server error")
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return models.Project{}, errr.NewUnexpectedError("Unable to save project due to internal server error")
+	}
+
+	return project, nil
+}
+
+func (pr *projectRepo) GetProject(id int64, userID int64) (models.Project, *errr.AppError) {
+	return getOwnedProject(pr.db, id, userID, "view")
+}
+
+func (pr *projectRepo) UpdateProject(id int64, project models.Project) *errr.AppError {
+	tx, err := pr.db.Begin()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to update project due to internal server error")
+	}
+	defer tx.Rollback()
+
+	_, appErr := getOwnedProject(tx, id, project.UserID, "update")
+	if appErr != nil {
+		return appErr
+	}
+
+	exists, err := hasProjectNamed(tx, project.UserID, project.Name, id)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to update project due to internal server error")
+	}
+	if exists {
+		return errr.NewDuplicateError("project already exists")
+	}
+
+	_, err = tx.Exec(
+		`UPDATE projects SET name = ?, color = ?, archived = ?, position = ? WHERE id = ?`,
+		project.Name, project.Color, project.Archived, project.Position, id,
+	)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to update project due to internal server error")
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to update project due to internal server error")
+	}
+
+	return nil
+}
+
+// DeleteProject removes the project, its tasks are kept outside of any
+// project.
+func (pr *projectRepo) DeleteProject(id int64, userID int64) *errr.AppError {
+	tx, err := pr.db.Begin()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to delete project due to internal server error")
+	}
+	defer tx.Rollback()
+
+	_, appErr := getOwnedProject(tx, id, userID, "delete")
+	if appErr != nil {
+		return appErr
+	}
+
+	// The tasks leave the project through ON DELETE SET NULL, which changes
+	// them as well.
+	_, err = tx.Exec(`UPDATE tasks SET version = version + 1 WHERE project_id = ?`, id)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to delete project due to internal server error")
+	}
+
+	_, err = tx.Exec(`DELETE FROM projects WHERE id = ?`, id)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to delete project due to internal server error")
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to delete project due to internal server error")
+	}
+
+	return nil
+}
+
+// GetProjects returns the projects of the user in order of their position.
+func (pr *projectRepo) GetProjects(userID int64) ([]models.Project, *errr.AppError) {
+	rows, err := pr.db.Query(
+		`SELECT `+projectColumns+` FROM projects WHERE user_id = ? ORDER BY position, id`,
+		userID,
+	)
+	if err != nil {
+		return nil, errr.NewUnexpectedError("Unable to get projects due to internal server error")
+	}
+	defer rows.Close()
+
+	projects := []models.Project{}
+	for rows.Next() {
+		project, err := scanProject(rows)
+		if err != nil {
+			return nil, errr.NewUnexpectedError("Unable to get projects due to internal server error")
+		}
+		projects = append(projects, project)
+	}
+	if rows.Err() != nil {
+		return nil, errr.NewUnexpectedError("Unable to get projects due to internal server error")
+	}
+
+	return projects, nil
+}
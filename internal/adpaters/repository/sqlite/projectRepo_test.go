@@ -0,0 +1,91 @@
+package sqlite
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/adpaters/idgen"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+)
+
+func Test_projectRepo_SaveProject(t *testing.T) {
+	db := getTempDB(t)
+	pr := NewProjectRepo(db, idgen.NewSequenceGenerator(0))
+
+	project, appErr := pr.SaveProject(models.Project{UserID: 1234, Name: "Work", Color: "#ffffff"})
+	if appErr != nil {
+		t.Fatalf("SaveProject() failed: %v", appErr)
+	}
+	if project.ID != 1 || project.Position != 0 {
+		t.Errorf("SaveProject() = %+v, want id 1 at position 0", project)
+	}
+
+	project, appErr = pr.SaveProject(models.Project{UserID: 1234, Name: "Home", Color: "#ffffff"})
+	if appErr != nil || project.Position != 1 {
+		t.Errorf("SaveProject() = %+v, %v, want position 1", project, appErr)
+	}
+
+	_, appErr = pr.SaveProject(models.Project{UserID: 1234, Name: "work", Color: "#000000"})
+	if appErr == nil || appErr.Code != http.StatusConflict {
+		t.Errorf("SaveProject() with a taken name = %v, want conflict", appErr)
+	}
+}
+
+func Test_projectRepo_UpdateProject(t *testing.T) {
+	db := getTempDB(t)
+	pr := NewProjectRepo(db, idgen.NewSequenceGenerator(0))
+	pr.SaveProject(models.Project{UserID: 1234, Name: "work", Color: "#ffffff"})
+	pr.SaveProject(models.Project{UserID: 1234, Name: "home", Color: "#ffffff"})
+
+	appErr := pr.UpdateProject(1, models.Project{UserID: 99, Name: "work", Color: "#ffffff"})
+	if appErr == nil || appErr.Code != http.StatusForbidden {
+		t.Errorf("UpdateProject() by another user = %v, want forbidden", appErr)
+	}
+
+	appErr = pr.UpdateProject(1, models.Project{UserID: 1234, Name: "Home", Color: "#ffffff"})
+	if appErr == nil || appErr.Code != http.StatusConflict {
+		t.Errorf("UpdateProject() to a taken name = %v, want conflict", appErr)
+	}
+
+	want := models.Project{
+		ID: 1, UserID: 1234, Name: "work", Color: "#000000", Archived: true, Position: 5,
+	}
+	appErr = pr.UpdateProject(1, want)
+	if appErr != nil {
+		t.Fatalf("UpdateProject() failed: %v", appErr)
+	}
+
+	projects, _ := pr.GetProjects(1234)
+	if len(projects) != 2 || projects[1] != want {
+		t.Errorf("projects after update = %v, want %v last", projects, want)
+	}
+}
+
+func Test_projectRepo_DeleteProject(t *testing.T) {
+	db := getTempDB(t)
+	tr := NewTaskRepo(db, idgen.NewSequenceGenerator(100))
+	pr := NewProjectRepo(db, idgen.NewSequenceGenerator(0))
+	pr.SaveProject(models.Project{UserID: 1234, Name: "work", Color: "#ffffff"})
+	tr.SaveTask(models.Task{Title: "a", UserID: 1234, ProjectID: 1})
+
+	appErr := pr.DeleteProject(1, 99)
+	if appErr == nil || appErr.Code != http.StatusForbidden {
+		t.Errorf("DeleteProject() by another user = %v, want forbidden", appErr)
+	}
+
+	appErr = pr.DeleteProject(1, 1234)
+	if appErr != nil {
+		t.Fatalf("DeleteProject() failed: %v", appErr)
+	}
+
+	tasks, _ := tr.GetTasks(models.NewTaskQuery(1234))
+	want := []models.Task{{ID: 101, Title: "a", UserID: 1234, Version: 2}}
+	if !equalTasks(tasks, want) {
+		t.Errorf("tasks after delete = %v, want %v", tasks, want)
+	}
+
+	_, appErr = pr.GetProject(1, 1234)
+	if appErr == nil || appErr.Code != http.StatusNotFound {
+		t.Errorf("GetProject() after delete = %v, want not found", appErr)
+	}
+}
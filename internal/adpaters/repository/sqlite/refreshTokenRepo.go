@@ -0,0 +1,90 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+)
+
+func NewRefreshTokenRepo(db *sql.DB) *refreshTokenRepo {
+	return &refreshTokenRepo{
+		db: db,
+	}
+}
+
+type refreshTokenRepo struct {
+	db *sql.DB
+}
+
+func (rr *refreshTokenRepo) SaveRefreshToken(token models.RefreshToken) *errr.AppError {
+	_, err := rr.db.Exec(
+		`INSERT INTO refresh_tokens (hash, family_id, user_id, expires_at, used_at) VALUES (?, ?, ?, ?, ?)`,
+		token.Hash, token.FamilyID, token.UserID, unixNano(token.ExpiresAt), unixNano(token.UsedAt),
+	)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to save refresh token due to internal server error")
+	}
+
+	return nil
+}
+
+func (rr *refreshTokenRepo) GetRefreshToken(hash string) (models.RefreshToken, *errr.AppError) {
+	var token models.RefreshToken
+	var expiresAt, usedAt int64
+	err := rr.db.QueryRow(
+		`SELECT hash, family_id, user_id, expires_at, used_at FROM refresh_tokens WHERE hash = ?`,
+		hash,
+	).Scan(&token.Hash, &token.FamilyID, &token.UserID, &expiresAt, &usedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.RefreshToken{}, errr.NewNotFoundError("Refresh token not found")
+	}
+	if err != nil {
+		return models.RefreshToken{}, errr.NewUnexpectedError(
+			"Unable to get refresh token due to internal server error",
+		)
+	}
+	token.ExpiresAt = parseUnixNano(expiresAt)
+	token.UsedAt = parseUnixNano(usedAt)
+
+	return token, nil
+}
+
+func (rr *refreshTokenRepo) UseRefreshToken(hash string, usedAt time.Time) *errr.AppError {
+	result, err := rr.db.Exec(
+		`UPDATE refresh_tokens SET used_at = ? WHERE hash = ? AND used_at = 0`,
+		unixNano(usedAt), hash,
+	)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to use refresh token due to internal server error")
+	}
+	used, err := result.RowsAffected()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to use refresh token due to internal server error")
+	}
+	if used == 0 {
+		return errr.NewDuplicateError("Refresh token was already used")
+	}
+
+	return nil
+}
+
+func (rr *refreshTokenRepo) RevokeTokenFamily(familyID string) *errr.AppError {
+	_, err := rr.db.Exec(`DELETE FROM refresh_tokens WHERE family_id = ?`, familyID)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to revoke refresh tokens due to internal server error")
+	}
+
+	return nil
+}
+
+func (rr *refreshTokenRepo) RevokeUserTokens(userID int64) *errr.AppError {
+	_, err := rr.db.Exec(`DELETE FROM refresh_tokens WHERE user_id = ?`, userID)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to revoke refresh tokens due to internal server error")
+	}
+
+	return nil
+}
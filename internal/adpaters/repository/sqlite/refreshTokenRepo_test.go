@@ -0,0 +1,103 @@
+package sqlite
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+)
+
+func Test_refreshTokenRepo(t *testing.T) {
+	db := getTempDB(t)
+	rr := NewRefreshTokenRepo(db)
+
+	first := models.RefreshToken{
+		Hash:      "first",
+		FamilyID:  "first",
+		UserID:    1234,
+		ExpiresAt: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+	rotated := models.RefreshToken{
+		Hash:      "rotated",
+		FamilyID:  "first",
+		UserID:    1234,
+		ExpiresAt: time.Date(2025, 3, 2, 12, 0, 0, 0, time.UTC),
+	}
+	other := models.RefreshToken{
+		Hash:      "other",
+		FamilyID:  "other",
+		UserID:    1234,
+		ExpiresAt: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+	for _, token := range []models.RefreshToken{first, rotated, other} {
+		appErr := rr.SaveRefreshToken(token)
+		if appErr != nil {
+			t.Fatalf("SaveRefreshToken() failed: %v", appErr)
+		}
+	}
+
+	usedAt := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	appErr := rr.UseRefreshToken("first", usedAt)
+	if appErr != nil {
+		t.Fatalf("UseRefreshToken() failed: %v", appErr)
+	}
+	appErr = rr.UseRefreshToken("first", usedAt)
+	if appErr == nil || appErr.Code != http.StatusConflict {
+		t.Errorf("UseRefreshToken() of a used token = %v, want a conflict", appErr)
+	}
+
+	got, appErr := rr.GetRefreshToken("first")
+	first.UsedAt = usedAt
+	if appErr != nil || !reflect.DeepEqual(got, first) {
+		t.Errorf("GetRefreshToken() = %v, %v, want %v", got, appErr, first)
+	}
+
+	appErr = rr.RevokeTokenFamily("first")
+	if appErr != nil {
+		t.Fatalf("RevokeTokenFamily() failed: %v", appErr)
+	}
+	for _, hash := range []string{"first", "rotated"} {
+		_, appErr = rr.GetRefreshToken(hash)
+		if appErr == nil || appErr.Code != http.StatusNotFound {
+			t.Errorf("GetRefreshToken(%q) after revoking its family = %v, want not found", hash, appErr)
+		}
+	}
+	got, appErr = rr.GetRefreshToken("other")
+	if appErr != nil || !reflect.DeepEqual(got, other) {
+		t.Errorf("GetRefreshToken() of another family = %v, %v, want %v", got, appErr, other)
+	}
+}
+
+func Test_refreshTokenRepo_RevokeUserTokens(t *testing.T) {
+	db := getTempDB(t)
+	rr := NewRefreshTokenRepo(db)
+
+	expiresAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, token := range []models.RefreshToken{
+		{Hash: "a", FamilyID: "a", UserID: 1234, ExpiresAt: expiresAt},
+		{Hash: "b", FamilyID: "b", UserID: 1234, ExpiresAt: expiresAt},
+		{Hash: "c", FamilyID: "c", UserID: 4321, ExpiresAt: expiresAt},
+	} {
+		appErr := rr.SaveRefreshToken(token)
+		if appErr != nil {
+			t.Fatalf("SaveRefreshToken() failed: %v", appErr)
+		}
+	}
+
+	appErr := rr.RevokeUserTokens(1234)
+	if appErr != nil {
+		t.Fatalf("RevokeUserTokens() failed: %v", appErr)
+	}
+	for _, hash := range []string{"a", "b"} {
+		_, appErr = rr.GetRefreshToken(hash)
+		if appErr == nil || appErr.Code != http.StatusNotFound {
+			t.Errorf("GetRefreshToken(%q) after revoking its user's tokens = %v, want not found", hash, appErr)
+		}
+	}
+	_, appErr = rr.GetRefreshToken("c")
+	if appErr != nil {
+		t.Errorf("GetRefreshToken() of another user's token failed: %v", appErr)
+	}
+}
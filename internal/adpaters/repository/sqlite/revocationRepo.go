@@ -0,0 +1,61 @@
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+)
+
+func NewRevocationRepo(db *sql.DB) *revocationRepo {
+	return &revocationRepo{
+		db: db,
+	}
+}
+
+type revocationRepo struct {
+	db *sql.DB
+}
+
+func (vr *revocationRepo) SaveRevocation(revocation models.Revocation) *errr.AppError {
+	_, err := vr.db.Exec(
+		`INSERT INTO revocations (token_id, user_id, issued_until, expires_at) VALUES (?, ?, ?, ?)`,
+		revocation.TokenID, revocation.UserID, unixNano(revocation.IssuedUntil), unixNano(revocation.ExpiresAt),
+	)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to revoke token due to internal server error")
+	}
+
+	return nil
+}
+
+func (vr *revocationRepo) IsRevoked(claims models.Claims) (bool, *errr.AppError) {
+	var revoked bool
+	err := vr.db.QueryRow(
+		`SELECT EXISTS (
+			SELECT 1 FROM revocations
+			WHERE (token_id <> '' AND token_id = ?)
+				OR (token_id = '' AND user_id = ? AND issued_until >= ?)
+		)`,
+		claims.TokenID, claims.ID, unixNano(claims.IssuedAt),
+	).Scan(&revoked)
+	if err != nil {
+		return false, errr.NewUnexpectedError("Unable to check token due to internal server error")
+	}
+
+	return revoked, nil
+}
+
+func (vr *revocationRepo) PruneRevocations(now time.Time) (int, *errr.AppError) {
+	result, err := vr.db.Exec(`DELETE FROM revocations WHERE expires_at <= ?`, unixNano(now))
+	if err != nil {
+		return 0, errr.NewUnexpectedError("Unable to prune revocations due to internal server error")
+	}
+	pruned, err := result.RowsAffected()
+	if err != nil {
+		return 0, errr.NewUnexpectedError("Unable to prune revocations due to internal server error")
+	}
+
+	return int(pruned), nil
+}
@@ -0,0 +1,906 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/internal/ports"
+)
+
+func NewTaskRepo(db *sql.DB, idGen ports.IDGenerator) *taskRepo {
+	return &taskRepo{
+		db:    db,
+		idGen: idGen,
+	}
+}
+
+type taskRepo struct {
+	db    *sql.DB
+	idGen ports.IDGenerator
+	// tx is the transaction of a repo handed out by Transaction.
+	tx *sql.Tx
+}
+
+// step is the transaction a single call of the repo runs in. Within
+// Transaction it is a savepoint of the enclosing transaction, so a call that
+// fails leaves the others in place.
+type step struct {
+	*sql.Tx
+	savepoint bool
+	done      bool
+}
+
+func (tr *taskRepo) begin() (*step, error) {
+	if tr.tx == nil {
+		tx, err := tr.db.Begin()
+		return &step{Tx: tx}, err
+	}
+
+	_, err := tr.tx.Exec(`SAVEPOINT step`)
+	return &step{Tx: tr.tx, savepoint: true}, err
+}
+
+func (s *step) commit() error {
+	if !s.savepoint {
+		return s.Tx.Commit()
+	}
+	s.done = true
+	_, err := s.Exec(`RELEASE step`)
+	return err
+}
+
+// rollback undoes the step unless it was committed.
+func (s *step) rollback() {
+	if !s.savepoint {
+		s.Tx.Rollback()
+		return
+	}
+	if !s.done {
+		s.done = true
+		s.Exec(`ROLLBACK TO step`)
+		s.Exec(`RELEASE step`)
+	}
+}
+
+// reader is what the repo reads through, its transaction when it has one.
+func (tr *taskRepo) reader() queryer {
+	if tr.tx != nil {
+		return tr.tx
+	}
+	return tr.db
+}
+
+func (tr *taskRepo) Transaction(fn func(repo ports.TaskRepo) *errr.AppError) *errr.AppError {
+	if tr.tx != nil {
+		return fn(tr)
+	}
+
+	tx, err := tr.db.Begin()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to change tasks due to internal server error")
+	}
+	defer tx.Rollback()
+
+	appErr := fn(&taskRepo{db: tr.db, idGen: tr.idGen, tx: tx})
+	if appErr != nil {
+		return appErr
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to change tasks due to internal server error")
+	}
+
+	return nil
+}
+
+const (
+	taskColumns = `id, title, description, status, priority, user_id, due_at, start_at, parent_id,
+		recurrence, series_id, project_id, done, created_at, updated_at, version, deleted_at`
+	taskPlaceholders = `?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?`
+)
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// queryer is satisfied by both *sql.DB and *sql.Tx.
+type queryer interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// nullTime stores t as RFC 3339 text, the zero time as NULL.
+func nullTime(t time.Time) sql.NullString {
+	if t.IsZero() {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: t.UTC().Format(time.RFC3339Nano), Valid: true}
+}
+
+func parseNullTime(ns sql.NullString) (time.Time, error) {
+	if !ns.Valid {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339Nano, ns.String)
+}
+
+// unixNano stores t as nanoseconds since the epoch so it sorts as a number,
+// the zero time as 0.
+func unixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+func parseUnixNano(ns int64) time.Time {
+	if ns == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, ns).UTC()
+}
+
+// nullID stores an optional id, 0 being none, as NULL.
+func nullID(id int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: id, Valid: id != 0}
+}
+
+// nullRecurrence stores the rule of a recurring task as text, no rule as NULL.
+func nullRecurrence(r *models.Recurrence) sql.NullString {
+	if r == nil || r.Freq == "" {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: r.String(), Valid: true}
+}
+
+func scanTask(row rowScanner) (models.Task, error) {
+	var task models.Task
+	var dueAt, startAt, recurrence sql.NullString
+	var parentID, seriesID, projectID sql.NullInt64
+	var createdAt, updatedAt, deletedAt int64
+	err := row.Scan(
+		&task.ID, &task.Title, &task.Desc, &task.Status, &task.Priority, &task.UserID,
+		&dueAt, &startAt, &parentID, &recurrence, &seriesID, &projectID, &task.Done,
+		&createdAt, &updatedAt, &task.Version, &deletedAt,
+	)
+	if err != nil {
+		return models.Task{}, err
+	}
+	task.CreatedAt = parseUnixNano(createdAt)
+	task.UpdatedAt = parseUnixNano(updatedAt)
+	task.DeletedAt = parseUnixNano(deletedAt)
+	task.ParentID = parentID.Int64
+	task.SeriesID = seriesID.Int64
+	task.ProjectID = projectID.Int64
+
+	if recurrence.Valid {
+		task.Recurrence = &models.Recurrence{}
+		err = task.Recurrence.UnmarshalText([]byte(recurrence.String))
+		if err != nil {
+			return models.Task{}, err
+		}
+	}
+
+	task.DueAt, err = parseNullTime(dueAt)
+	if err != nil {
+		return models.Task{}, err
+	}
+	task.StartAt, err = parseNullTime(startAt)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	return task, nil
+}
+
+// getTask returns the task with the given id as q sees it, in the trash or
+// not.
+func getTask(q queryer, id int64) (models.Task, error) {
+	task, err := scanTask(q.QueryRow(`SELECT `+taskColumns+` FROM tasks WHERE id = ?`, id))
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	labelIDs, err := getLabelIDs(q, `WHERE task_id = ?`, id)
+	if err != nil {
+		return models.Task{}, err
+	}
+	task.LabelIDs = labelIDs[id]
+
+	checklists, err := getChecklists(q, `WHERE task_id = ?`, id)
+	if err != nil {
+		return models.Task{}, err
+	}
+	task.Checklist = checklists[id]
+
+	return task, nil
+}
+
+// getLabelIDs returns the label ids of the task_labels rows matched by where,
+// keyed by task id.
+func getLabelIDs(q queryer, where string, args ...any) (map[int64][]int64, error) {
+	rows, err := q.Query(
+		`SELECT task_id, label_id FROM task_labels `+where+` ORDER BY task_id, label_id`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	labelIDs := map[int64][]int64{}
+	for rows.Next() {
+		var taskID, labelID int64
+		err = rows.Scan(&taskID, &labelID)
+		if err != nil {
+			return nil, err
+		}
+		labelIDs[taskID] = append(labelIDs[taskID], labelID)
+	}
+
+	return labelIDs, rows.Err()
+}
+
+// getChecklists returns the checklist_items rows matched by where in order,
+// keyed by task id.
+func getChecklists(q queryer, where string, args ...any) (map[int64][]models.ChecklistItem, error) {
+	rows, err := q.Query(
+		`SELECT task_id, text, done FROM checklist_items `+where+` ORDER BY task_id, position`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	checklists := map[int64][]models.ChecklistItem{}
+	for rows.Next() {
+		var taskID int64
+		var item models.ChecklistItem
+		err = rows.Scan(&taskID, &item.Text, &item.Done)
+		if err != nil {
+			return nil, err
+		}
+		checklists[taskID] = append(checklists[taskID], item)
+	}
+
+	return checklists, rows.Err()
+}
+
+// setChecklist replaces the checklist of the task with the given id.
+func setChecklist(tx *sql.Tx, id int64, items []models.ChecklistItem) error {
+	_, err := tx.Exec(`DELETE FROM checklist_items WHERE task_id = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	for i, item := range items {
+		_, err = tx.Exec(
+			`INSERT INTO checklist_items (task_id, position, text, done) VALUES (?, ?, ?, ?)`,
+			id, i, item.Text, item.Done,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkParent makes sure the parent of the new subtask task exists, belongs
+// to the same user and leaves room for another level of nesting.
+func checkParent(tx *sql.Tx, task models.Task) *errr.AppError {
+	var userID int64
+	err := tx.QueryRow(
+		`SELECT user_id FROM tasks WHERE id = ? AND deleted_at = 0`, task.ParentID,
+	).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errr.NewNotFoundError("no parent task found with id")
+	}
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to save task due to internal server error")
+	}
+	if userID != task.UserID {
+		return errr.NewUnauthorizedError("Unauthorized to add subtask")
+	}
+
+	var depth int
+	err = tx.QueryRow(
+		`WITH RECURSIVE ancestors (id, parent_id) AS (
+			SELECT id, parent_id FROM tasks WHERE id = ?
+			UNION ALL
+			SELECT tasks.id, tasks.parent_id FROM tasks JOIN ancestors ON tasks.id = ancestors.parent_id
+		)
+		SELECT COUNT(*) FROM ancestors`,
+		task.ParentID,
+	).Scan(&depth)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to save task due to internal server error")
+	}
+	if depth >= models.MaxTaskDepth {
+		return errr.NewBadRequestError(
+			fmt.Sprintf("Subtasks can't be nested more than %d deep", models.MaxTaskDepth),
+		)
+	}
+
+	return nil
+}
+
+// hasUnfinishedSubtasks reports whether any subtask below the task with id
+// is not done yet, which keeps the task from being completed.
+func hasUnfinishedSubtasks(tx *sql.Tx, id int64) (bool, error) {
+	var unfinished bool
+	err := tx.QueryRow(
+		`WITH RECURSIVE descendants (id) AS (
+			SELECT id FROM tasks WHERE parent_id = ? AND deleted_at = 0
+			UNION ALL
+			SELECT tasks.id FROM tasks JOIN descendants ON tasks.parent_id = descendants.id
+			WHERE tasks.deleted_at = 0
+		)
+		SELECT EXISTS (SELECT 1 FROM tasks WHERE id IN descendants AND NOT done)`,
+		id,
+	).Scan(&unfinished)
+	return unfinished, err
+}
+
+// setLabelIDs replaces the labels of the task with the given id.
+func setLabelIDs(tx *sql.Tx, id int64, labelIDs []int64) error {
+	_, err := tx.Exec(`DELETE FROM task_labels WHERE task_id = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	for _, labelID := range labelIDs {
+		_, err = tx.Exec(
+			`INSERT OR IGNORE INTO task_labels (task_id, label_id) VALUES (?, ?)`,
+			id, labelID,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (tr *taskRepo) SaveTask(task models.Task) (models.Task, *errr.AppError) {
+	task.ID = tr.idGen.NextID()
+	task.Version = 1
+
+	st, err := tr.begin()
+	if err != nil {
+		return models.Task{}, errr.NewUnexpectedError("Unable to save task due to internal server error")
+	}
+	defer st.rollback()
+	tx := st.Tx
+
+	if task.ParentID != 0 {
+		appErr := checkParent(tx, task)
+		if appErr != nil {
+			return models.Task{}, appErr
+		}
+	}
+
+	_, err = tx.Exec(
+		`INSERT INTO tasks (`+taskColumns+`) VALUES (`+taskPlaceholders+`)`,
+		task.ID, task.Title, task.Desc, task.Status, task.Priority, task.UserID,
+		nullTime(task.DueAt), nullTime(task.StartAt), nullID(task.ParentID),
+		nullRecurrence(task.Recurrence), nullID(task.SeriesID), nullID(task.ProjectID), task.Done,
+		unixNano(task.CreatedAt), unixNano(task.UpdatedAt), task.Version, unixNano(task.DeletedAt),
+	)
+	if err != nil {
+		return models.Task{}, errr.NewUnexpectedError("Unable to save task due to internal server error")
+	}
+
+	err = setLabelIDs(tx, task.ID, task.LabelIDs)
+	if err != nil {
+		return models.Task{}, errr.NewUnexpectedError("Unable to save task due to internal server error")
+	}
+
+	err = setChecklist(tx, task.ID, task.Checklist)
+	if err != nil {
+		return models.Task{}, errr.NewUnexpectedError("Unable to save task due to internal server error")
+	}
+
+	err = st.commit()
+	if err != nil {
+		return models.Task{}, errr.NewUnexpectedError("Unable to save task due to internal server error")
+	}
+
+	return task, nil
+}
+
+func (tr *taskRepo) UpdateTask(id int64, task models.Task) *errr.AppError {
+	st, err := tr.begin()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to update task due to internal server error")
+	}
+	defer st.rollback()
+	tx := st.Tx
+
+	stored, err := getTask(tx, id)
+	if errors.Is(err, sql.ErrNoRows) || stored.InTrash() {
+		return errr.NewNotFoundError("no task found with id")
+	}
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to update task due to internal server error")
+	}
+	if stored.UserID != task.UserID {
+		return errr.NewUnauthorizedError("Unauthorized to update task")
+	}
+	if task.Version != 0 && task.Version != stored.Version {
+		return errr.NewPreconditionFailedError("Task has changed since it was read")
+	}
+	if task.Recurrence != nil && !task.IsRecurring() {
+		task.Recurrence = nil
+	}
+	if !task.HasValidDates() {
+		return errr.NewBadRequestError("Start date must not be after due date")
+	}
+	if !task.HasValidRecurrence() {
+		return errr.NewBadRequestError("Recurring tasks need a due date")
+	}
+	if task.Done && !stored.Done {
+		unfinished, err := hasUnfinishedSubtasks(tx, id)
+		if err != nil {
+			return errr.NewUnexpectedError("Unable to update task due to internal server error")
+		}
+		if unfinished {
+			return errr.NewDuplicateError("Task has unfinished subtasks")
+		}
+	}
+
+	_, err = tx.Exec(
+		`UPDATE tasks SET
+			title = ?, description = ?, status = ?, priority = ?, due_at = ?, start_at = ?,
+			recurrence = ?, project_id = ?, done = ?, updated_at = ?, version = ?
+		WHERE id = ?`,
+		task.Title, task.Desc, task.Status, task.Priority,
+		nullTime(task.DueAt), nullTime(task.StartAt), nullRecurrence(task.Recurrence),
+		nullID(task.ProjectID), task.Done, unixNano(task.UpdatedAt), stored.Version+1, id,
+	)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to update task due to internal server error")
+	}
+
+	err = setLabelIDs(tx, id, task.LabelIDs)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to update task due to internal server error")
+	}
+	err = setChecklist(tx, id, task.Checklist)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to update task due to internal server error")
+	}
+
+	err = st.commit()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to update task due to internal server error")
+	}
+
+	return nil
+}
+
+func (tr *taskRepo) DeleteTask(id int64, userID int64, version int64, deletedAt time.Time) *errr.AppError {
+	st, err := tr.begin()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to delete task due to internal server error")
+	}
+	defer st.rollback()
+	tx := st.Tx
+
+	stored, err := getTask(tx, id)
+	if errors.Is(err, sql.ErrNoRows) || stored.InTrash() {
+		return errr.NewNotFoundError("no task found with id")
+	}
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to delete task due to internal server error")
+	}
+	if stored.UserID != userID {
+		return errr.NewUnauthorizedError("Unauthorized to delete task")
+	}
+	if version != 0 && version != stored.Version {
+		return errr.NewPreconditionFailedError("Task has changed since it was read")
+	}
+
+	// Subtasks already in the trash keep the time they were deleted at, so
+	// restoring the task leaves them there.
+	_, err = tx.Exec(
+		`WITH RECURSIVE subtree (id) AS (
+			SELECT ?
+			UNION ALL
+			SELECT tasks.id FROM tasks JOIN subtree ON tasks.parent_id = subtree.id
+			WHERE tasks.deleted_at = 0
+		)
+		UPDATE tasks SET deleted_at = ?, version = version + 1 WHERE id IN subtree`,
+		id, unixNano(deletedAt),
+	)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to delete task due to internal server error")
+	}
+
+	err = st.commit()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to delete task due to internal server error")
+	}
+
+	return nil
+}
+
+func (tr *taskRepo) GetTask(id int64, userID int64) (models.Task, *errr.AppError) {
+	task, err := getTask(tr.reader(), id)
+	if errors.Is(err, sql.ErrNoRows) || task.InTrash() {
+		return models.Task{}, errr.NewNotFoundError("no task found with id")
+	}
+	if err != nil {
+		return models.Task{}, errr.NewUnexpectedError("Unable to get task due to internal server error")
+	}
+	if task.UserID != userID {
+		return models.Task{}, errr.NewUnauthorizedError("Unauthorized to get task")
+	}
+
+	return task, nil
+}
+
+// orderKey is a column tasks are sorted by, with the expression and argument
+// giving its value for the task a cursor points at.
+type orderKey struct {
+	column string
+	param  string
+	arg    func(models.TaskCursor) any
+}
+
+// taskOrder returns the columns the tasks of query are sorted by, ending
+// with their id. The keys all run the same way so a row value comparison
+// finds the tasks after a cursor.
+func taskOrder(query models.TaskQuery) []orderKey {
+	// Undated tasks sort last, julianday makes due dates with and without
+	// fractional seconds compare right.
+	due := []orderKey{
+		{`due_at IS NULL`, `?`, func(c models.TaskCursor) any { return c.DueAt.IsZero() }},
+		{`coalesce(julianday(due_at), 0)`, `coalesce(julianday(?), 0)`, func(c models.TaskCursor) any {
+			return nullTime(c.DueAt)
+		}},
+	}
+	id := orderKey{`id`, `?`, func(c models.TaskCursor) any { return c.ID }}
+
+	switch query.Sort {
+	case models.TaskSortUpdated:
+		return []orderKey{
+			{`updated_at`, `?`, func(c models.TaskCursor) any { return unixNano(c.UpdatedAt) }},
+			id,
+		}
+	case models.TaskSortTitle:
+		return []orderKey{
+			{`lower(title)`, `lower(?)`, func(c models.TaskCursor) any { return c.Title }},
+			id,
+		}
+	case models.TaskSortDue:
+		return append(due, id)
+	case models.TaskSortPriority:
+		priority := orderKey{`-priority`, `?`, func(c models.TaskCursor) any { return -c.Priority }}
+		return append(append([]orderKey{priority}, due...), id)
+	default:
+		return []orderKey{
+			{`created_at`, `?`, func(c models.TaskCursor) any { return unixNano(c.CreatedAt) }},
+			id,
+		}
+	}
+}
+
+// placeholders returns n comma separated placeholders.
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+func (tr *taskRepo) GetTasks(query models.TaskQuery) ([]models.Task, *errr.AppError) {
+	where := []string{`user_id = ?`}
+	args := []any{query.UserID}
+	if !query.Trashed {
+		where = append(where, `deleted_at = 0`)
+	}
+	if query.Status >= 0 {
+		where = append(where, `status = ?`)
+		args = append(args, query.Status)
+	}
+	if !query.DueAfter.IsZero() || !query.DueBefore.IsZero() {
+		where = append(where, `due_at IS NOT NULL`)
+	}
+	if !query.DueAfter.IsZero() {
+		where = append(where, `julianday(due_at) > julianday(?)`)
+		args = append(args, nullTime(query.DueAfter))
+	}
+	if !query.DueBefore.IsZero() {
+		where = append(where, `julianday(due_at) < julianday(?)`)
+		args = append(args, nullTime(query.DueBefore))
+	}
+	if query.LabelID != 0 {
+		where = append(where, `id IN (SELECT task_id FROM task_labels WHERE label_id = ?)`)
+		args = append(args, query.LabelID)
+	}
+	if query.ProjectID != 0 {
+		where = append(where, `project_id = ?`)
+		args = append(args, query.ProjectID)
+	}
+	if query.ParentIDs != nil {
+		if len(query.ParentIDs) == 0 {
+			return []models.Task{}, nil
+		}
+		where = append(where, `parent_id IN (`+placeholders(len(query.ParentIDs))+`)`)
+		for _, id := range query.ParentIDs {
+			args = append(args, id)
+		}
+	}
+	if query.IDs != nil {
+		if len(query.IDs) == 0 {
+			return []models.Task{}, nil
+		}
+		where = append(where, `id IN (`+placeholders(len(query.IDs))+`)`)
+		for _, id := range query.IDs {
+			args = append(args, id)
+		}
+	}
+
+	keys := taskOrder(query)
+	columns := make([]string, len(keys))
+	for i, key := range keys {
+		columns[i] = key.column
+	}
+	if query.After != nil {
+		op := `>`
+		if query.Reverse {
+			op = `<`
+		}
+		params := make([]string, len(keys))
+		for i, key := range keys {
+			params[i] = key.param
+			args = append(args, key.arg(*query.After))
+		}
+		where = append(where, fmt.Sprintf(
+			`(%s) %s (%s)`, strings.Join(columns, `, `), op, strings.Join(params, `, `),
+		))
+	}
+
+	order := strings.Join(columns, `, `)
+	if query.Reverse {
+		order = strings.Join(columns, ` DESC, `) + ` DESC`
+	}
+	stmt := `SELECT ` + taskColumns + ` FROM tasks WHERE ` + strings.Join(where, ` AND `) +
+		` ORDER BY ` + order
+	if query.Limit > 0 {
+		stmt += ` LIMIT ?`
+		args = append(args, query.Limit)
+	}
+
+	tasks, err := queryTasks(tr.reader(), stmt, args...)
+	if err != nil {
+		return nil, errr.NewUnexpectedError("Unable to get tasks due to internal server error")
+	}
+	if len(tasks) == 0 {
+		return tasks, nil
+	}
+
+	ids := make([]any, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	err = attachDetails(tr.reader(), tasks, `WHERE task_id IN (`+placeholders(len(ids))+`)`, ids...)
+	if err != nil {
+		return nil, errr.NewUnexpectedError("Unable to get tasks due to internal server error")
+	}
+
+	return tasks, nil
+}
+
+func (tr *taskRepo) GetAllTasks() ([]models.Task, *errr.AppError) {
+	tasks, err := queryTasks(
+		tr.reader(), `SELECT `+taskColumns+` FROM tasks WHERE deleted_at = 0 ORDER BY id`,
+	)
+	if err != nil {
+		return nil, errr.NewUnexpectedError("Unable to get tasks due to internal server error")
+	}
+
+	err = attachDetails(
+		tr.reader(), tasks, `WHERE task_id IN (SELECT id FROM tasks WHERE deleted_at = 0)`,
+	)
+	if err != nil {
+		return nil, errr.NewUnexpectedError("Unable to get tasks due to internal server error")
+	}
+
+	return tasks, nil
+}
+
+// queryTasks returns the tasks selected by stmt, which has to select
+// taskColumns.
+func queryTasks(q queryer, stmt string, args ...any) ([]models.Task, error) {
+	rows, err := q.Query(stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := []models.Task{}
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, rows.Err()
+}
+
+// attachDetails fills in the labels and checklists of tasks, reading the
+// rows of task_labels and checklist_items matched by where.
+func attachDetails(q queryer, tasks []models.Task, where string, args ...any) error {
+	labelIDs, err := getLabelIDs(q, where, args...)
+	if err != nil {
+		return err
+	}
+	checklists, err := getChecklists(q, where, args...)
+	if err != nil {
+		return err
+	}
+	for i := range tasks {
+		tasks[i].LabelIDs = labelIDs[tasks[i].ID]
+		tasks[i].Checklist = checklists[tasks[i].ID]
+	}
+
+	return nil
+}
+
+func (tr *taskRepo) GetTrash(userID int64) ([]models.Task, *errr.AppError) {
+	tasks, err := queryTasks(
+		tr.reader(),
+		`SELECT `+taskColumns+` FROM tasks AS t
+		WHERE user_id = ? AND deleted_at != 0 AND NOT EXISTS (
+			SELECT 1 FROM tasks WHERE id = t.parent_id AND deleted_at = t.deleted_at
+		)
+		ORDER BY deleted_at DESC, id`,
+		userID,
+	)
+	if err != nil {
+		return nil, errr.NewUnexpectedError("Unable to get trash due to internal server error")
+	}
+	if len(tasks) == 0 {
+		return tasks, nil
+	}
+
+	ids := make([]any, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	err = attachDetails(tr.reader(), tasks, `WHERE task_id IN (`+placeholders(len(ids))+`)`, ids...)
+	if err != nil {
+		return nil, errr.NewUnexpectedError("Unable to get trash due to internal server error")
+	}
+
+	return tasks, nil
+}
+
+// getTrashed returns the task with id, which has to be in the trash of the
+// user.
+func getTrashed(tx *sql.Tx, id int64, userID int64) (models.Task, *errr.AppError) {
+	task, err := getTask(tx, id)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !task.InTrash()) {
+		return models.Task{}, errr.NewNotFoundError("no task found in the trash with id")
+	}
+	if err != nil {
+		return models.Task{}, errr.NewUnexpectedError("Unable to change trash due to internal server error")
+	}
+	if task.UserID != userID {
+		return models.Task{}, errr.NewUnauthorizedError("Unauthorized to change task")
+	}
+
+	return task, nil
+}
+
+func (tr *taskRepo) RestoreTask(id int64, userID int64) *errr.AppError {
+	st, err := tr.begin()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to restore task due to internal server error")
+	}
+	defer st.rollback()
+	tx := st.Tx
+
+	task, appErr := getTrashed(tx, id, userID)
+	if appErr != nil {
+		return appErr
+	}
+	if task.ParentID != 0 {
+		var parentDeletedAt int64
+		err = tx.QueryRow(`SELECT deleted_at FROM tasks WHERE id = ?`, task.ParentID).Scan(&parentDeletedAt)
+		if err != nil {
+			return errr.NewUnexpectedError("Unable to restore task due to internal server error")
+		}
+		if parentDeletedAt != 0 {
+			return errr.NewDuplicateError("Parent task is in the trash, restore it first")
+		}
+	}
+
+	_, err = tx.Exec(
+		`WITH RECURSIVE subtree (id) AS (
+			SELECT ?
+			UNION ALL
+			SELECT tasks.id FROM tasks JOIN subtree ON tasks.parent_id = subtree.id
+			WHERE tasks.deleted_at = ?
+		)
+		UPDATE tasks SET deleted_at = 0, version = version + 1 WHERE id IN subtree`,
+		id, unixNano(task.DeletedAt),
+	)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to restore task due to internal server error")
+	}
+
+	err = st.commit()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to restore task due to internal server error")
+	}
+
+	return nil
+}
+
+func (tr *taskRepo) PurgeTask(id int64, userID int64) *errr.AppError {
+	st, err := tr.begin()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to purge task due to internal server error")
+	}
+	defer st.rollback()
+	tx := st.Tx
+
+	_, appErr := getTrashed(tx, id, userID)
+	if appErr != nil {
+		return appErr
+	}
+
+	// Subtasks go with their parent, all of them are in the trash too.
+	_, err = tx.Exec(`DELETE FROM tasks WHERE id = ?`, id)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to purge task due to internal server error")
+	}
+
+	err = st.commit()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to purge task due to internal server error")
+	}
+
+	return nil
+}
+
+func (tr *taskRepo) PurgeTrash(deletedBefore time.Time) (int, *errr.AppError) {
+	st, err := tr.begin()
+	if err != nil {
+		return 0, errr.NewUnexpectedError("Unable to purge trash due to internal server error")
+	}
+	defer st.rollback()
+	tx := st.Tx
+
+	var purged int
+	err = tx.QueryRow(
+		`SELECT COUNT(*) FROM tasks WHERE deleted_at != 0 AND deleted_at < ?`, unixNano(deletedBefore),
+	).Scan(&purged)
+	if err != nil {
+		return 0, errr.NewUnexpectedError("Unable to purge trash due to internal server error")
+	}
+
+	// Subtasks are never deleted after their parent, so none outlives it.
+	_, err = tx.Exec(
+		`DELETE FROM tasks WHERE deleted_at != 0 AND deleted_at < ?`, unixNano(deletedBefore),
+	)
+	if err != nil {
+		return 0, errr.NewUnexpectedError("Unable to purge trash due to internal server error")
+	}
+
+	err = st.commit()
+	if err != nil {
+		return 0, errr.NewUnexpectedError("Unable to purge trash due to internal server error")
+	}
+
+	return purged, nil
+}
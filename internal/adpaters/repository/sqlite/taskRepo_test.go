@@ -0,0 +1,864 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"path"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/adpaters/idgen"
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/internal/ports"
+)
+
+// trashedAt is when the tests move tasks to the trash.
+var trashedAt = time.Date(2020, time.January, 5, 10, 0, 0, 0, time.UTC)
+
+func getTempDB(t *testing.T) *sql.DB {
+	db, err := NewDB(path.Join(t.TempDir(), "todo.db"))
+	if err != nil {
+		t.Fatalf("NewDB() failed: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func equalTasks(a, b []models.Task) bool {
+	return slices.EqualFunc(a, b, func(x, y models.Task) bool {
+		return reflect.DeepEqual(x, y)
+	})
+}
+
+func insertTask(t *testing.T, db *sql.DB, task models.Task) {
+	_, err := db.Exec(
+		`INSERT INTO tasks (`+taskColumns+`) VALUES (`+taskPlaceholders+`)`,
+		task.ID, task.Title, task.Desc, task.Status, task.Priority, task.UserID,
+		nullTime(task.DueAt), nullTime(task.StartAt), nullID(task.ParentID),
+		nullRecurrence(task.Recurrence), nullID(task.SeriesID), nullID(task.ProjectID), task.Done,
+		unixNano(task.CreatedAt), unixNano(task.UpdatedAt), task.Version, unixNano(task.DeletedAt),
+	)
+	if err != nil {
+		t.Fatalf("failed to insert task: %v", err)
+	}
+}
+
+func TestNewDB_CreatesSchemaTwice(t *testing.T) {
+	fp := path.Join(t.TempDir(), "todo.db")
+	for range 2 {
+		db, err := NewDB(fp)
+		if err != nil {
+			t.Fatalf("NewDB() failed: %v", err)
+		}
+		db.Close()
+	}
+}
+
+func Test_taskRepo_SaveTask(t *testing.T) {
+	tests := []struct {
+		name    string
+		setupDB func(t *testing.T, db *sql.DB)
+		task    models.Task
+		wantErr bool
+	}{
+		{
+			name:    "writing first task",
+			setupDB: func(t *testing.T, db *sql.DB) {},
+			task: models.Task{
+				Title:  "some Title",
+				Desc:   "some desc",
+				Status: 0,
+				UserID: 1234,
+			},
+			wantErr: false,
+		},
+		{
+			name: "task written successfully",
+			setupDB: func(t *testing.T, db *sql.DB) {
+				insertTask(t, db, models.Task{
+					ID: 12234, Title: "any title", Desc: "any desc", UserID: 1234,
+				})
+			},
+			task: models.Task{
+				Title:  "some Title",
+				Desc:   "some desc",
+				Status: 0,
+				UserID: 1234,
+			},
+			wantErr: false,
+		},
+		{
+			name: "task write failure",
+			setupDB: func(t *testing.T, db *sql.DB) {
+				db.Close()
+			},
+			task: models.Task{
+				Title:  "some Title",
+				Desc:   "some desc",
+				Status: 0,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := getTempDB(t)
+			tt.setupDB(t, db)
+			tr := NewTaskRepo(db, idgen.NewSequenceGenerator(100000))
+			got, gotErr := tr.SaveTask(tt.task)
+			if tt.wantErr && gotErr == nil {
+				t.Errorf("SaveTask() successed unexpectedly")
+			}
+			if !tt.wantErr && gotErr != nil {
+				t.Errorf("SaveTask failed. got %v", gotErr)
+			}
+			if tt.wantErr || gotErr != nil {
+				return
+			}
+			stored, appErr := tr.GetTask(got.ID, tt.task.UserID)
+			if got.ID == 0 || appErr != nil || !reflect.DeepEqual(got, stored) {
+				t.Errorf("SaveTask() = %v, stored %v, %v", got, stored, appErr)
+			}
+		})
+	}
+}
+
+func Test_taskRepo_UpdateTask(t *testing.T) {
+	existing := models.Task{
+		ID: 12234, Title: "any title", Desc: "any desc", Status: 0, UserID: 1234, Version: 1,
+	}
+	tests := []struct {
+		name       string
+		setupDB    func(t *testing.T, db *sql.DB)
+		id         int64
+		task       models.Task
+		want       models.Task
+		wantErr    bool
+		errMessage string
+	}{
+		{
+			name:       "task not found",
+			setupDB:    func(t *testing.T, db *sql.DB) {},
+			id:         0,
+			task:       models.Task{},
+			wantErr:    true,
+			errMessage: "no task found with id",
+		},
+		{
+			name: "fields left out are cleared",
+			setupDB: func(t *testing.T, db *sql.DB) {
+				insertTask(t, db, models.Task{
+					ID: 12234, Title: "any title", Desc: "any desc", Priority: 2, UserID: 1234,
+					DueAt: time.Date(2020, time.January, 2, 10, 0, 0, 0, time.UTC), Version: 1,
+				})
+			},
+			id:   12234,
+			task: models.Task{Title: "any title", UserID: 1234},
+			want: models.Task{
+				ID: 12234, Title: "any title", Status: 0, UserID: 1234, Version: 2,
+			},
+			wantErr: false,
+		},
+		{
+			name: "version the update is based on is current",
+			setupDB: func(t *testing.T, db *sql.DB) {
+				insertTask(t, db, existing)
+			},
+			id:   12234,
+			task: models.Task{Title: "title", Desc: "any desc", UserID: 1234, Version: 1},
+			want: models.Task{
+				ID: 12234, Title: "title", Desc: "any desc", Status: 0, UserID: 1234, Version: 2,
+			},
+			wantErr: false,
+		},
+		{
+			name: "task changed since the version the update is based on",
+			setupDB: func(t *testing.T, db *sql.DB) {
+				insertTask(t, db, models.Task{ID: 12234, Title: "any title", UserID: 1234, Version: 3})
+			},
+			id:         12234,
+			task:       models.Task{Title: "title", UserID: 1234, Version: 2},
+			wantErr:    true,
+			errMessage: "Task has changed since it was read",
+		},
+		{
+			name: "task updated successfully",
+			setupDB: func(t *testing.T, db *sql.DB) {
+				insertTask(t, db, existing)
+			},
+			id:   12234,
+			task: models.Task{Title: "title", Desc: "desc", Status: 2, Priority: 3, UserID: 1234},
+			want: models.Task{
+				ID: 12234, Title: "title", Desc: "desc", Status: 2, Priority: 3, UserID: 1234,
+				Version: 2,
+			},
+			wantErr: false,
+		},
+		{
+			name: "dates are updated",
+			setupDB: func(t *testing.T, db *sql.DB) {
+				insertTask(t, db, existing)
+			},
+			id: 12234,
+			task: models.Task{
+				Title:   "any title",
+				Desc:    "any desc",
+				UserID:  1234,
+				DueAt:   time.Date(2020, time.January, 2, 10, 0, 0, 0, time.UTC),
+				StartAt: time.Date(2020, time.January, 1, 10, 0, 0, 0, time.UTC),
+			},
+			want: models.Task{
+				ID: 12234, Title: "any title", Desc: "any desc", Status: 0, UserID: 1234,
+				DueAt:   time.Date(2020, time.January, 2, 10, 0, 0, 0, time.UTC),
+				StartAt: time.Date(2020, time.January, 1, 10, 0, 0, 0, time.UTC),
+				Version: 2,
+			},
+			wantErr: false,
+		},
+		{
+			name: "start after the due date",
+			setupDB: func(t *testing.T, db *sql.DB) {
+				insertTask(t, db, existing)
+			},
+			id: 12234,
+			task: models.Task{
+				Title:   "any title",
+				UserID:  1234,
+				DueAt:   time.Date(2020, time.January, 2, 10, 0, 0, 0, time.UTC),
+				StartAt: time.Date(2020, time.January, 3, 10, 0, 0, 0, time.UTC),
+			},
+			wantErr:    true,
+			errMessage: "Start date must not be after due date",
+		},
+		{
+			name: "task belongs to another user",
+			setupDB: func(t *testing.T, db *sql.DB) {
+				insertTask(t, db, existing)
+			},
+			id:         12234,
+			task:       models.Task{Title: "title", UserID: 4321},
+			wantErr:    true,
+			errMessage: "Unauthorized to update task",
+		},
+		{
+			name: "unable to read tasks",
+			setupDB: func(t *testing.T, db *sql.DB) {
+				db.Close()
+			},
+			id:         12234,
+			task:       models.Task{},
+			wantErr:    true,
+			errMessage: "Unable to update task due to internal server error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := getTempDB(t)
+			tt.setupDB(t, db)
+			tr := NewTaskRepo(db, idgen.NewSequenceGenerator(100000))
+			gotErr := tr.UpdateTask(tt.id, tt.task)
+			if tt.wantErr && gotErr == nil {
+				t.Errorf("UpdateTask() successed unexpectedly")
+				return
+			}
+			if !tt.wantErr && gotErr != nil {
+				t.Errorf("UpdateTask() failed, got err %v", gotErr.Message)
+				return
+			}
+			if tt.wantErr {
+				if gotErr.Message != tt.errMessage {
+					t.Errorf("wanted error %v, got err %v", tt.errMessage, gotErr.Message)
+				}
+				return
+			}
+			got, _ := tr.GetTasks(models.NewTaskQuery(tt.want.UserID))
+			if !equalTasks(got, []models.Task{tt.want}) {
+				t.Errorf("wanted %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_taskRepo_DeleteTask(t *testing.T) {
+	tests := []struct {
+		name       string
+		setupDB    func(t *testing.T, db *sql.DB)
+		id         int64
+		userID     int64
+		version    int64
+		wantErr    bool
+		errMessage string
+	}{
+		{
+			name:       "task not found",
+			setupDB:    func(t *testing.T, db *sql.DB) {},
+			id:         0,
+			wantErr:    true,
+			errMessage: "no task found with id",
+		},
+		{
+			name: "task belongs to another user",
+			setupDB: func(t *testing.T, db *sql.DB) {
+				insertTask(t, db, models.Task{
+					ID: 12234, Title: "any title", Desc: "any desc", UserID: 1234,
+				})
+			},
+			id:         12234,
+			userID:     4321,
+			wantErr:    true,
+			errMessage: "Unauthorized to delete task",
+		},
+		{
+			name: "unable to read tasks",
+			setupDB: func(t *testing.T, db *sql.DB) {
+				db.Close()
+			},
+			id:         12234,
+			wantErr:    true,
+			errMessage: "Unable to delete task due to internal server error",
+		},
+		{
+			name: "task changed since it was read",
+			setupDB: func(t *testing.T, db *sql.DB) {
+				insertTask(t, db, models.Task{ID: 12234, Title: "any title", UserID: 1234, Version: 2})
+			},
+			id:         12234,
+			userID:     1234,
+			version:    1,
+			wantErr:    true,
+			errMessage: "Task has changed since it was read",
+		},
+		{
+			name: "task deleted successfully",
+			setupDB: func(t *testing.T, db *sql.DB) {
+				insertTask(t, db, models.Task{
+					ID: 12234, Title: "any title", Desc: "any desc", UserID: 1234,
+				})
+			},
+			id:      12234,
+			userID:  1234,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := getTempDB(t)
+			tt.setupDB(t, db)
+			tr := NewTaskRepo(db, idgen.NewSequenceGenerator(100000))
+			gotErr := tr.DeleteTask(tt.id, tt.userID, tt.version, trashedAt)
+			if tt.wantErr && gotErr == nil {
+				t.Errorf("DeleteTask() successed unexpectedly")
+				return
+			}
+			if !tt.wantErr && gotErr != nil {
+				t.Errorf("DeleteTask() failed, got err %v", gotErr.Message)
+				return
+			}
+			if tt.wantErr && gotErr != nil {
+				if gotErr.Message != tt.errMessage {
+					t.Errorf("wanted error %v, got err %v", tt.errMessage, gotErr.Message)
+				}
+			}
+		})
+	}
+}
+
+func Test_taskRepo_GetTasks(t *testing.T) {
+	tests := []struct {
+		name    string
+		setupDB func(t *testing.T, db *sql.DB)
+		userID  int64
+		want    []models.Task
+		err     *errr.AppError
+		wantErr bool
+	}{
+		{
+			name: "tasks read failure",
+			setupDB: func(t *testing.T, db *sql.DB) {
+				db.Close()
+			},
+			wantErr: true,
+			err: &errr.AppError{
+				Code:    http.StatusInternalServerError,
+				Message: "Unable to get tasks due to internal server error",
+			},
+		},
+		{
+			name: "tasks read successfully",
+			setupDB: func(t *testing.T, db *sql.DB) {
+				insertTask(t, db, models.Task{
+					ID: 12234, Title: "any title", Desc: "any desc", UserID: 1234,
+				})
+				insertTask(t, db, models.Task{
+					ID: 12235, Title: "other title", Desc: "other desc", UserID: 4321,
+				})
+			},
+			userID: 1234,
+			want: []models.Task{
+				{
+					ID:     12234,
+					Status: 0,
+					Desc:   "any desc",
+					Title:  "any title",
+					UserID: 1234,
+				},
+			},
+		},
+		{
+			name:    "no tasks",
+			setupDB: func(t *testing.T, db *sql.DB) {},
+			userID:  1234,
+			want:    []models.Task{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := getTempDB(t)
+			tt.setupDB(t, db)
+			tr := NewTaskRepo(db, idgen.NewSequenceGenerator(100000))
+			got, err := tr.GetTasks(models.NewTaskQuery(tt.userID))
+			if tt.wantErr && err == nil {
+				t.Errorf("GetTasks successed unexpectedly")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("GetTasks() Failed, got err %v", err)
+			}
+			if tt.wantErr && err != nil && !reflect.DeepEqual(err, tt.err) {
+				t.Errorf("wanted err %v, got %v", tt.err, err)
+			}
+			if !tt.wantErr && err == nil {
+				if !equalTasks(got, tt.want) {
+					t.Errorf("Wanted %v, got %v", tt.want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestNewDB_MigratesExistingDatabase(t *testing.T) {
+	fp := path.Join(t.TempDir(), "todo.db")
+	db, err := sql.Open("sqlite", fp)
+	if err != nil {
+		t.Fatalf("sql.Open() failed: %v", err)
+	}
+	_, err = db.Exec(migrations[0] + `PRAGMA user_version = 1;`)
+	if err != nil {
+		t.Fatalf("failed to create first schema version: %v", err)
+	}
+	db.Close()
+
+	db, err = NewDB(fp)
+	if err != nil {
+		t.Fatalf("NewDB() failed: %v", err)
+	}
+	defer db.Close()
+
+	var version int
+	db.QueryRow(`PRAGMA user_version`).Scan(&version)
+	if version != len(migrations) {
+		t.Errorf("wanted schema version %d, got %d", len(migrations), version)
+	}
+}
+
+func Test_taskRepo_subtasks(t *testing.T) {
+	newRepo := func(t *testing.T) *taskRepo {
+		db := getTempDB(t)
+		insertTask(t, db, models.Task{ID: 1, Title: "project", UserID: 1234})
+		insertTask(t, db, models.Task{ID: 2, Title: "step", UserID: 1234, ParentID: 1})
+		insertTask(t, db, models.Task{ID: 3, Title: "detail", UserID: 1234, ParentID: 2})
+		insertTask(t, db, models.Task{ID: 4, Title: "other", UserID: 1234})
+		return NewTaskRepo(db, idgen.NewSequenceGenerator(100))
+	}
+
+	t.Run("subtask of another user's task", func(t *testing.T) {
+		tr := newRepo(t)
+		_, appErr := tr.SaveTask(models.Task{Title: "t", UserID: 99, ParentID: 1})
+		if appErr == nil || appErr.Code != http.StatusForbidden {
+			t.Errorf("SaveTask() = %v, want forbidden", appErr)
+		}
+	})
+
+	t.Run("nested too deep", func(t *testing.T) {
+		tr := newRepo(t)
+		_, appErr := tr.SaveTask(models.Task{Title: "t", UserID: 1234, ParentID: 3})
+		if appErr == nil || appErr.Code != http.StatusBadRequest {
+			t.Errorf("SaveTask() = %v, want bad request", appErr)
+		}
+	})
+
+	t.Run("completing with unfinished subtasks is blocked", func(t *testing.T) {
+		tr := newRepo(t)
+		appErr := tr.UpdateTask(1, models.Task{UserID: 1234, Status: 1, Done: true})
+		if appErr == nil || appErr.Code != http.StatusConflict {
+			t.Errorf("UpdateTask() = %v, want conflict", appErr)
+		}
+
+		tr.UpdateTask(3, models.Task{UserID: 1234, Status: 1, Done: true})
+		tr.UpdateTask(2, models.Task{UserID: 1234, Status: 1, Done: true})
+		appErr = tr.UpdateTask(1, models.Task{UserID: 1234, Status: 1, Done: true})
+		if appErr != nil {
+			t.Errorf("UpdateTask() with finished subtasks failed: %v", appErr)
+		}
+	})
+
+	t.Run("checklist is stored in order", func(t *testing.T) {
+		tr := newRepo(t)
+		checklist := []models.ChecklistItem{{Text: "b", Done: true}, {Text: "a"}}
+		appErr := tr.UpdateTask(4, models.Task{Title: "other", UserID: 1234, Checklist: checklist})
+		if appErr != nil {
+			t.Fatalf("UpdateTask() failed: %v", appErr)
+		}
+
+		got, _ := tr.GetTasks(models.NewTaskQuery(1234))
+		if !reflect.DeepEqual(got[3].Checklist, checklist) {
+			t.Errorf("wanted checklist %v, got %v", checklist, got[3].Checklist)
+		}
+	})
+
+	t.Run("deleting cascades to subtasks", func(t *testing.T) {
+		tr := newRepo(t)
+		appErr := tr.DeleteTask(1, 1234, 0, trashedAt)
+		if appErr != nil {
+			t.Fatalf("DeleteTask() failed: %v", appErr)
+		}
+
+		got, _ := tr.GetTasks(models.NewTaskQuery(1234))
+		want := []models.Task{{ID: 4, Title: "other", UserID: 1234}}
+		if !equalTasks(got, want) {
+			t.Errorf("wanted %v, got %v", want, got)
+		}
+	})
+}
+
+func Test_taskRepo_recurrence(t *testing.T) {
+	db := getTempDB(t)
+	tr := NewTaskRepo(db, idgen.NewSequenceGenerator(0))
+	weekly, _ := models.ParseRecurrence("FREQ=WEEKLY;BYDAY=MO")
+	due := time.Date(2025, time.March, 3, 9, 0, 0, 0, time.UTC)
+
+	_, appErr := tr.SaveTask(models.Task{
+		Title: "report", UserID: 1234, DueAt: due, Recurrence: &weekly, SeriesID: 5,
+	})
+	if appErr != nil {
+		t.Fatalf("SaveTask() failed: %v", appErr)
+	}
+
+	got, _ := tr.GetTasks(models.NewTaskQuery(1234))
+	want := []models.Task{
+		{ID: 1, Title: "report", UserID: 1234, DueAt: due, Recurrence: &weekly, SeriesID: 5, Version: 1},
+	}
+	if !equalTasks(got, want) {
+		t.Errorf("wanted %v, got %v", want, got)
+	}
+
+	appErr = tr.UpdateTask(1, models.Task{
+		Title: "report", UserID: 1234, DueAt: due, Recurrence: &models.Recurrence{},
+	})
+	if appErr != nil {
+		t.Fatalf("UpdateTask() failed: %v", appErr)
+	}
+	got, _ = tr.GetTasks(models.NewTaskQuery(1234))
+	if got[0].Recurrence != nil {
+		t.Errorf("wanted recurrence to be cleared, got %v", got[0].Recurrence)
+	}
+}
+
+func Test_taskRepo_GetTasks_query(t *testing.T) {
+	db := getTempDB(t)
+	label, appErr := NewLabelRepo(db, idgen.NewSequenceGenerator(100)).SaveLabel(
+		models.Label{UserID: 1234, Name: "work", Color: "#ffffff"},
+	)
+	if appErr != nil {
+		t.Fatalf("SaveLabel() failed: %v", appErr)
+	}
+	project, appErr := NewProjectRepo(db, idgen.NewSequenceGenerator(200)).SaveProject(
+		models.Project{UserID: 1234, Name: "home", Color: "#000000"},
+	)
+	if appErr != nil {
+		t.Fatalf("SaveProject() failed: %v", appErr)
+	}
+
+	day := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	tasks := []models.Task{
+		{ID: 1, Title: "banana", Priority: 2, DueAt: day.AddDate(0, 0, 2), CreatedAt: day},
+		{ID: 2, Title: "Apple", Priority: 4, CreatedAt: day, UpdatedAt: day.Add(time.Hour), LabelIDs: []int64{label.ID}},
+		{ID: 3, Title: "cherry", Priority: 2, DueAt: day.AddDate(0, 0, 1).Add(time.Millisecond), CreatedAt: day.Add(time.Hour)},
+		{ID: 4, Title: "apple pie", Status: 1, Done: true, DueAt: day.AddDate(0, 0, 1), ProjectID: project.ID},
+		{ID: 5, Title: "date", Priority: 4, DueAt: day.AddDate(0, 0, 3), ParentID: 1, UpdatedAt: day},
+		{ID: 6, Title: "cherry", Priority: 2, DueAt: day.AddDate(0, 0, 1).Add(time.Millisecond), CreatedAt: day.Add(time.Hour)},
+		{ID: 7, Title: "fig", Status: 1, CreatedAt: day.Add(2 * time.Hour), DeletedAt: day.Add(3 * time.Hour)},
+	}
+	tr := NewTaskRepo(db, idgen.NewSequenceGenerator(0))
+	for _, task := range tasks {
+		task.UserID = 1234
+		if _, appErr := tr.SaveTask(task); appErr != nil {
+			t.Fatalf("SaveTask() failed: %v", appErr)
+		}
+	}
+	tr.SaveTask(models.Task{Title: "elsewhere", UserID: 4321})
+	for i := range tasks {
+		tasks[i].UserID = 1234
+	}
+
+	queries := map[string]func(q *models.TaskQuery){
+		"status":  func(q *models.TaskQuery) { q.Status = 1 },
+		"due":     func(q *models.TaskQuery) { q.DueAfter, q.DueBefore = day.AddDate(0, 0, 1), day.AddDate(0, 0, 3) },
+		"label":   func(q *models.TaskQuery) { q.LabelID = label.ID },
+		"project": func(q *models.TaskQuery) { q.ProjectID = project.ID },
+		"parents": func(q *models.TaskQuery) { q.ParentIDs = []int64{1, 2} },
+		"none":    func(q *models.TaskQuery) { q.ParentIDs = []int64{} },
+		"trashed": func(q *models.TaskQuery) { q.Trashed, q.Status = true, 1 },
+		"ids":     func(q *models.TaskQuery) { q.IDs = []int64{2, 5, 7, 9} },
+	}
+	for _, sort := range []string{
+		models.TaskSortCreated, models.TaskSortUpdated, models.TaskSortTitle,
+		models.TaskSortDue, models.TaskSortPriority,
+	} {
+		for _, reverse := range []bool{false, true} {
+			queries[fmt.Sprintf("%s reversed %t", sort, reverse)] = func(q *models.TaskQuery) {
+				q.Sort, q.Reverse = sort, reverse
+			}
+		}
+	}
+
+	// Paging through two tasks at a time lists the same tasks in the same
+	// order as the file repo does in one go.
+	for name, edit := range queries {
+		t.Run(name, func(t *testing.T) {
+			query := models.NewTaskQuery(1234)
+			edit(&query)
+			want := []int64{}
+			for _, task := range query.Apply(tasks) {
+				want = append(want, task.ID)
+			}
+
+			got := []int64{}
+			query.Limit = 2
+			for {
+				page, appErr := tr.GetTasks(query)
+				if appErr != nil {
+					t.Fatalf("GetTasks() failed: %v", appErr)
+				}
+				for _, task := range page {
+					got = append(got, task.ID)
+				}
+				if len(page) < query.Limit || len(got) > len(tasks) {
+					break
+				}
+				cursor := query.CursorAt(page[len(page)-1])
+				query.After = &cursor
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("GetTasks() pages = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func Test_taskRepo_GetTask(t *testing.T) {
+	tests := []struct {
+		name       string
+		setupDB    func(t *testing.T, db *sql.DB)
+		id         int64
+		userID     int64
+		want       models.Task
+		errMessage string
+	}{
+		{
+			name:   "task found with its details",
+			id:     12234,
+			userID: 1234,
+			want: models.Task{
+				ID: 12234, Title: "any title", UserID: 1234,
+				Checklist: []models.ChecklistItem{{Text: "step", Done: true}},
+			},
+		},
+		{
+			name:       "task not found",
+			id:         99,
+			userID:     1234,
+			errMessage: "no task found with id",
+		},
+		{
+			name:       "task belongs to another user",
+			id:         12234,
+			userID:     4321,
+			errMessage: "Unauthorized to get task",
+		},
+		{
+			name: "unable to read tasks",
+			setupDB: func(t *testing.T, db *sql.DB) {
+				db.Close()
+			},
+			id:         12234,
+			userID:     1234,
+			errMessage: "Unable to get task due to internal server error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := getTempDB(t)
+			insertTask(t, db, models.Task{ID: 12234, Title: "any title", UserID: 1234})
+			_, err := db.Exec(
+				`INSERT INTO checklist_items (task_id, position, text, done) VALUES (12234, 0, 'step', 1)`,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.setupDB != nil {
+				tt.setupDB(t, db)
+			}
+			tr := NewTaskRepo(db, idgen.NewSequenceGenerator(100000))
+
+			got, gotErr := tr.GetTask(tt.id, tt.userID)
+			if tt.errMessage != "" {
+				if gotErr == nil || gotErr.Message != tt.errMessage {
+					t.Errorf("GetTask() err = %v, want %v", gotErr, tt.errMessage)
+				}
+				return
+			}
+			if gotErr != nil {
+				t.Fatalf("GetTask() failed, got err %v", gotErr.Message)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetTask() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_taskRepo_Transaction(t *testing.T) {
+	t.Run("changes are committed together", func(t *testing.T) {
+		db := getTempDB(t)
+		insertTask(t, db, models.Task{ID: 1, Title: "old", UserID: 1234})
+		tr := NewTaskRepo(db, idgen.NewSequenceGenerator(100))
+
+		appErr := tr.Transaction(func(tx ports.TaskRepo) *errr.AppError {
+			if _, appErr := tx.SaveTask(models.Task{Title: "new", UserID: 1234}); appErr != nil {
+				return appErr
+			}
+			return tx.DeleteTask(1, 1234, 0, trashedAt)
+		})
+		if appErr != nil {
+			t.Fatalf("Transaction() failed: %v", appErr)
+		}
+		got, _ := tr.GetTasks(models.NewTaskQuery(1234))
+		if len(got) != 1 || got[0].Title != "new" {
+			t.Errorf("GetTasks() = %v, want only the new task", got)
+		}
+	})
+
+	t.Run("failure undoes every change", func(t *testing.T) {
+		db := getTempDB(t)
+		insertTask(t, db, models.Task{ID: 1, Title: "old", UserID: 1234})
+		tr := NewTaskRepo(db, idgen.NewSequenceGenerator(100))
+
+		appErr := tr.Transaction(func(tx ports.TaskRepo) *errr.AppError {
+			tx.SaveTask(models.Task{Title: "new", UserID: 1234})
+			tx.DeleteTask(1, 1234, 0, trashedAt)
+			return errr.NewNotFoundError("stop")
+		})
+		if appErr == nil || appErr.Message != "stop" {
+			t.Fatalf("Transaction() err = %v, want stop", appErr)
+		}
+		got, _ := tr.GetTasks(models.NewTaskQuery(1234))
+		if len(got) != 1 || got[0].Title != "old" {
+			t.Errorf("GetTasks() = %v, want only the old task", got)
+		}
+	})
+
+	t.Run("failed change is undone alone", func(t *testing.T) {
+		db := getTempDB(t)
+		insertTask(t, db, models.Task{ID: 1, Title: "parent", UserID: 1234})
+		insertTask(t, db, models.Task{ID: 2, Title: "subtask", UserID: 1234, ParentID: 1})
+		tr := NewTaskRepo(db, idgen.NewSequenceGenerator(100))
+
+		appErr := tr.Transaction(func(tx ports.TaskRepo) *errr.AppError {
+			tx.SaveTask(models.Task{Title: "new", UserID: 1234})
+			// Closing the parent fails on its open subtask.
+			if appErr := tx.UpdateTask(1, models.Task{Title: "closed", UserID: 1234, Done: true}); appErr == nil {
+				t.Errorf("UpdateTask() closed a task with an open subtask")
+			}
+			return nil
+		})
+		if appErr != nil {
+			t.Fatalf("Transaction() failed: %v", appErr)
+		}
+		parent, _ := tr.GetTask(1, 1234)
+		got, _ := tr.GetTasks(models.NewTaskQuery(1234))
+		if parent.Title != "parent" || parent.Done || len(got) != 3 {
+			t.Errorf("GetTasks() = %v, want the parent unchanged and the new task", got)
+		}
+	})
+}
+
+func Test_taskRepo_trash(t *testing.T) {
+	db := getTempDB(t)
+	insertTask(t, db, models.Task{ID: 1, Title: "parent", UserID: 1234, Version: 1})
+	insertTask(t, db, models.Task{ID: 2, Title: "subtask", UserID: 1234, ParentID: 1, Version: 1})
+	insertTask(t, db, models.Task{ID: 3, Title: "deleted before", UserID: 1234, ParentID: 1, Version: 1})
+	tr := NewTaskRepo(db, idgen.NewSequenceGenerator(100))
+
+	earlier := trashedAt.Add(-time.Hour)
+	if appErr := tr.DeleteTask(3, 1234, 0, earlier); appErr != nil {
+		t.Fatalf("DeleteTask() failed: %v", appErr)
+	}
+	if appErr := tr.DeleteTask(1, 1234, 0, trashedAt); appErr != nil {
+		t.Fatalf("DeleteTask() failed: %v", appErr)
+	}
+
+	if _, appErr := tr.GetTask(2, 1234); appErr == nil || appErr.Code != http.StatusNotFound {
+		t.Errorf("GetTask() of a subtask in the trash = %v, want not found", appErr)
+	}
+	if appErr := tr.DeleteTask(1, 1234, 0, trashedAt); appErr == nil || appErr.Code != http.StatusNotFound {
+		t.Errorf("DeleteTask() of a task in the trash = %v, want not found", appErr)
+	}
+	if tasks, _ := tr.GetTasks(models.NewTaskQuery(1234)); len(tasks) != 0 {
+		t.Errorf("GetTasks() = %v, want none", tasks)
+	}
+	trash, appErr := tr.GetTrash(1234)
+	if appErr != nil || len(trash) != 2 || trash[0].ID != 1 || !trash[0].DeletedAt.Equal(trashedAt) ||
+		trash[1].ID != 3 {
+		t.Fatalf("GetTrash() = %v, %v, want the parent and the subtask deleted before it", trash, appErr)
+	}
+
+	appErr = tr.RestoreTask(3, 1234)
+	if appErr == nil || appErr.Message != "Parent task is in the trash, restore it first" {
+		t.Errorf("RestoreTask() of a subtask = %v, want its parent in the trash", appErr)
+	}
+	if appErr = tr.RestoreTask(1, 1234); appErr != nil {
+		t.Fatalf("RestoreTask() failed: %v", appErr)
+	}
+	tasks, _ := tr.GetTasks(models.NewTaskQuery(1234))
+	if len(tasks) != 2 || tasks[0].ID != 1 || tasks[0].Version != 3 || tasks[1].ID != 2 {
+		t.Errorf("GetTasks() after restore = %v, want the parent and the subtask deleted with it", tasks)
+	}
+
+	purged, appErr := tr.PurgeTrash(trashedAt)
+	if appErr != nil || purged != 1 {
+		t.Errorf("PurgeTrash() = %v, %v, want 1 task purged", purged, appErr)
+	}
+	if trash, _ := tr.GetTrash(1234); len(trash) != 0 {
+		t.Errorf("GetTrash() after purge = %v, want none", trash)
+	}
+
+	tr.DeleteTask(1, 1234, 0, trashedAt)
+	if appErr = tr.PurgeTask(1, 4321); appErr == nil || appErr.Code != http.StatusForbidden {
+		t.Errorf("PurgeTask() of another user = %v, want forbidden", appErr)
+	}
+	if appErr = tr.PurgeTask(1, 1234); appErr != nil {
+		t.Fatalf("PurgeTask() failed: %v", appErr)
+	}
+	var left int
+	db.QueryRow(`SELECT COUNT(*) FROM tasks`).Scan(&left)
+	if left != 0 {
+		t.Errorf("%d tasks left after purging the parent, want none", left)
+	}
+	if appErr = tr.RestoreTask(1, 1234); appErr == nil || appErr.Code != http.StatusNotFound {
+		t.Errorf("RestoreTask() of a purged task = %v, want not found", appErr)
+	}
+}
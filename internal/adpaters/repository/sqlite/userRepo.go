@@ -0,0 +1,136 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/internal/ports"
+)
+
+func NewUserRepo(db *sql.DB, idGen ports.IDGenerator) *userRepo {
+	return &userRepo{
+		db:    db,
+		idGen: idGen,
+	}
+}
+
+type userRepo struct {
+	db    *sql.DB
+	idGen ports.IDGenerator
+}
+
+const userColumns = `id, username, password, time_zone, role, disabled`
+
+func scanUser(row rowScanner) (models.User, error) {
+	var user models.User
+	err := row.Scan(&user.ID, &user.Username, &user.Password, &user.TimeZone, &user.Role, &user.Disabled)
+	return user, err
+}
+
+func (ur *userRepo) GetUser(id int64) (models.User, *errr.AppError) {
+	user, err := scanUser(ur.db.QueryRow(`SELECT `+userColumns+` FROM users WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.User{}, errr.NewNotFoundError("User not Found")
+	}
+	if err != nil {
+		return models.User{}, errr.NewUnexpectedError(
+			"Unable to get user due to internal server error",
+		)
+	}
+
+	return user, nil
+}
+
+func (ur *userRepo) GetUserByUsername(username string) (models.User, *errr.AppError) {
+	user, err := scanUser(ur.db.QueryRow(`SELECT `+userColumns+` FROM users WHERE username = ?`, username))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.User{}, errr.NewNotFoundError("User not Found")
+	}
+	if err != nil {
+		return models.User{}, errr.NewUnexpectedError(
+			"Unable to get user due to internal server error",
+		)
+	}
+
+	return user, nil
+}
+
+func (ur *userRepo) CreateUser(user models.User) (models.User, *errr.AppError) {
+	tx, err := ur.db.Begin()
+	if err != nil {
+		return models.User{}, errr.NewUnexpectedError("Unable to save user due to internal server error")
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	err = tx.QueryRow(
+		`SELECT EXISTS (SELECT 1 FROM users WHERE username = ?)`,
+		user.Username,
+	).Scan(&exists)
+	if err != nil {
+		return models.User{}, errr.NewUnexpectedError("Unable to save user due to internal server error")
+	}
+	if exists {
+		return models.User{}, errr.NewDuplicateError("user already exists")
+	}
+
+	user.ID = ur.idGen.NextID()
+	user.Role = user.EffectiveRole()
+	_, err = tx.Exec(
+		`INSERT INTO users (`+userColumns+`) VALUES (?, ?, ?, ?, ?, ?)`,
+		user.ID, user.Username, user.Password, user.TimeZone, user.Role, user.Disabled,
+	)
+	if err != nil {
+		return models.User{}, errr.NewUnexpectedError("Unable to save user due to internal server error")
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return models.User{}, errr.NewUnexpectedError("Unable to save user due to internal server error")
+	}
+
+	return user, nil
+}
+
+func (ur *userRepo) GetUsers() ([]models.User, *errr.AppError) {
+	rows, err := ur.db.Query(`SELECT ` + userColumns + ` FROM users ORDER BY username`)
+	if err != nil {
+		return nil, errr.NewUnexpectedError("Unable to get users due to internal server error")
+	}
+	defer rows.Close()
+
+	users := make([]models.User, 0)
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, errr.NewUnexpectedError("Unable to get users due to internal server error")
+		}
+		users = append(users, user)
+	}
+	if rows.Err() != nil {
+		return nil, errr.NewUnexpectedError("Unable to get users due to internal server error")
+	}
+
+	return users, nil
+}
+
+func (ur *userRepo) UpdateUser(user models.User) *errr.AppError {
+	result, err := ur.db.Exec(
+		`UPDATE users SET username = ?, password = ?, time_zone = ?, role = ?, disabled = ? WHERE id = ?`,
+		user.Username, user.Password, user.TimeZone, user.EffectiveRole(), user.Disabled, user.ID,
+	)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to update user due to internal server error")
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to update user due to internal server error")
+	}
+	if updated == 0 {
+		return errr.NewNotFoundError("User not Found")
+	}
+
+	return nil
+}
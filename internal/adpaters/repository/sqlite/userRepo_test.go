@@ -0,0 +1,221 @@
+package sqlite
+
+import (
+	"database/sql"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/adpaters/idgen"
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+)
+
+func insertUser(t *testing.T, db *sql.DB, user models.User) {
+	_, err := db.Exec(
+		`INSERT INTO users (`+userColumns+`) VALUES (?, ?, ?, ?, ?, ?)`,
+		user.ID, user.Username, user.Password, user.TimeZone, user.Role, user.Disabled,
+	)
+	if err != nil {
+		t.Fatalf("failed to insert user: %v", err)
+	}
+}
+
+func Test_userRepo_GetUserByUsername(t *testing.T) {
+	tests := []struct {
+		name       string
+		setupDB    func(t *testing.T, db *sql.DB)
+		username   string
+		want       models.User
+		wantAppErr *errr.AppError
+	}{
+		{
+			name: "read from database failed",
+			setupDB: func(t *testing.T, db *sql.DB) {
+				db.Close()
+			},
+			username: "user",
+			wantAppErr: &errr.AppError{
+				Code:    http.StatusInternalServerError,
+				Message: "Unable to get user due to internal server error",
+			},
+		},
+		{
+			name: "user not found",
+			setupDB: func(t *testing.T, db *sql.DB) {
+				insertUser(t, db, models.User{ID: 1234, Username: "user", Password: "password"})
+			},
+			username: "otheruser",
+			wantAppErr: &errr.AppError{
+				Code:    http.StatusNotFound,
+				Message: "User not Found",
+			},
+		},
+		{
+			name: "successfully got user",
+			setupDB: func(t *testing.T, db *sql.DB) {
+				insertUser(t, db, models.User{ID: 1234, Username: "user", Password: "password"})
+			},
+			username: "user",
+			want: models.User{
+				ID:       1234,
+				Username: "user",
+				Password: "password",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := getTempDB(t)
+			tt.setupDB(t, db)
+			ur := NewUserRepo(db, idgen.NewSequenceGenerator(100000))
+			got, gotAppErr := ur.GetUserByUsername(tt.username)
+			if tt.wantAppErr == nil && gotAppErr != nil {
+				t.Errorf("GetUserByUsername() failed: %v", gotAppErr)
+				return
+			}
+			if tt.wantAppErr != nil {
+				if gotAppErr == nil {
+					t.Errorf("GetUserByUsername() succeeded unexpectedly")
+				} else if !reflect.DeepEqual(gotAppErr, tt.wantAppErr) {
+					t.Errorf("wanted err %v, got %v", tt.wantAppErr, gotAppErr)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetUserByUsername() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_userRepo_CreateUser(t *testing.T) {
+	tests := []struct {
+		name       string
+		setupDB    func(t *testing.T, db *sql.DB)
+		user       models.User
+		wantAppErr *errr.AppError
+	}{
+		{
+			name: "write to database failed",
+			setupDB: func(t *testing.T, db *sql.DB) {
+				db.Close()
+			},
+			user: models.User{Username: "user", Password: "password"},
+			wantAppErr: &errr.AppError{
+				Code:    http.StatusInternalServerError,
+				Message: "Unable to save user due to internal server error",
+			},
+		},
+		{
+			name: "user already exists",
+			setupDB: func(t *testing.T, db *sql.DB) {
+				insertUser(t, db, models.User{ID: 1234, Username: "user", Password: "password"})
+			},
+			user: models.User{Username: "user", Password: "password"},
+			wantAppErr: &errr.AppError{
+				Code:    http.StatusConflict,
+				Message: "user already exists",
+			},
+		},
+		{
+			name: "successfully created user",
+			setupDB: func(t *testing.T, db *sql.DB) {
+				insertUser(t, db, models.User{ID: 1234, Username: "user", Password: "password"})
+			},
+			user: models.User{
+				Username: "other user",
+				Password: "password",
+				TimeZone: "Asia/Tokyo",
+			},
+			wantAppErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := getTempDB(t)
+			tt.setupDB(t, db)
+			ur := NewUserRepo(db, idgen.NewSequenceGenerator(100000))
+			created, gotAppErr := ur.CreateUser(tt.user)
+			if tt.wantAppErr == nil && gotAppErr != nil {
+				t.Errorf("CreateUser() failed: %v", gotAppErr)
+				return
+			}
+			if tt.wantAppErr != nil {
+				if gotAppErr == nil {
+					t.Errorf("CreateUser() succeeded unexpectedly")
+				} else if !reflect.DeepEqual(gotAppErr, tt.wantAppErr) {
+					t.Errorf("wanted err %v, got %v", tt.wantAppErr, gotAppErr)
+				}
+				return
+			}
+			got, appErr := ur.GetUserByUsername(tt.user.Username)
+			if appErr != nil {
+				t.Errorf("created user not found: %v", appErr)
+				return
+			}
+			if got.ID == 0 || got.Password != tt.user.Password || got.TimeZone != tt.user.TimeZone {
+				t.Errorf("stored user = %v, want %v with an id", got, tt.user)
+			}
+			if created != got {
+				t.Errorf("CreateUser() = %v, want the stored %v", created, got)
+			}
+		})
+	}
+}
+
+func Test_userRepo_GetUser(t *testing.T) {
+	db := getTempDB(t)
+	ur := NewUserRepo(db, nil)
+	want := models.User{ID: 1234, Username: "jass", Password: "hash", TimeZone: "Asia/Kolkata"}
+	insertUser(t, db, want)
+
+	got, appErr := ur.GetUser(1234)
+	if appErr != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("GetUser() = %v, %v, want %v", got, appErr, want)
+	}
+
+	_, appErr = ur.GetUser(99)
+	if appErr == nil || appErr.Code != http.StatusNotFound {
+		t.Errorf("GetUser() of a missing user = %v, want not found", appErr)
+	}
+}
+
+func Test_userRepo_UpdateUser(t *testing.T) {
+	db := getTempDB(t)
+	ur := NewUserRepo(db, nil)
+	insertUser(t, db, models.User{ID: 2, Username: "zed", Password: "hash", Role: models.RoleUser})
+	insertUser(t, db, models.User{ID: 1, Username: "amy", Password: "hash", Role: models.RoleAdmin})
+
+	want := models.User{ID: 2, Username: "zed", Password: "new hash", Role: models.RoleUser, Disabled: true}
+	appErr := ur.UpdateUser(want)
+	if appErr != nil {
+		t.Fatalf("UpdateUser() failed: %v", appErr)
+	}
+	appErr = ur.UpdateUser(models.User{ID: 3, Username: "nobody"})
+	if appErr == nil || appErr.Code != http.StatusNotFound {
+		t.Errorf("UpdateUser() of a missing user = %v, want not found", appErr)
+	}
+
+	users, appErr := ur.GetUsers()
+	wantUsers := []models.User{
+		{ID: 1, Username: "amy", Password: "hash", Role: models.RoleAdmin},
+		want,
+	}
+	if appErr != nil || !reflect.DeepEqual(users, wantUsers) {
+		t.Errorf("GetUsers() = %v, %v, want %v", users, appErr, wantUsers)
+	}
+}
+
+func TestNewDB_UsersAreUsersByDefault(t *testing.T) {
+	db := getTempDB(t)
+	_, err := db.Exec(`INSERT INTO users (id, username, password) VALUES (1, 'amy', 'hash')`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user, appErr := NewUserRepo(db, nil).GetUser(1)
+	if appErr != nil || user.Role != models.RoleUser || user.Disabled {
+		t.Errorf("GetUser() = %v, %v, want an enabled user", user, appErr)
+	}
+}
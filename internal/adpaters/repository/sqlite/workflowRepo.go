@@ -0,0 +1,161 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+)
+
+func NewWorkflowRepo(db *sql.DB) *workflowRepo {
+	return &workflowRepo{
+		db: db,
+	}
+}
+
+type workflowRepo struct {
+	db *sql.DB
+}
+
+// GetWorkflow reads the statuses of the user in order along with the
+// transitions between them.
+func (wr *workflowRepo) GetWorkflow(userID int64) (models.Workflow, *errr.AppError) {
+	workflow, err := getWorkflow(wr.db, userID)
+	if err != nil {
+		return models.Workflow{}, errr.NewUnexpectedError("Unable to get workflow due to internal server error")
+	}
+	if len(workflow.Statuses) == 0 {
+		return models.DefaultWorkflow(userID), nil
+	}
+
+	return workflow, nil
+}
+
+func getWorkflow(q queryer, userID int64) (models.Workflow, error) {
+	workflow := models.Workflow{UserID: userID}
+
+	rows, err := q.Query(
+		`SELECT id, name, terminal FROM workflow_statuses WHERE user_id = ? ORDER BY position`,
+		userID,
+	)
+	if err != nil {
+		return models.Workflow{}, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var status models.WorkflowStatus
+		err = rows.Scan(&status.ID, &status.Name, &status.Terminal)
+		if err != nil {
+			return models.Workflow{}, err
+		}
+		workflow.Statuses = append(workflow.Statuses, status)
+	}
+	if rows.Err() != nil {
+		return models.Workflow{}, rows.Err()
+	}
+
+	transitions, err := q.Query(
+		`SELECT from_id, to_id FROM workflow_transitions WHERE user_id = ? ORDER BY rowid`,
+		userID,
+	)
+	if err != nil {
+		return models.Workflow{}, err
+	}
+	defer transitions.Close()
+	for transitions.Next() {
+		var from, to int
+		err = transitions.Scan(&from, &to)
+		if err != nil {
+			return models.Workflow{}, err
+		}
+		for i := range workflow.Statuses {
+			if workflow.Statuses[i].ID == from {
+				workflow.Statuses[i].Next = append(workflow.Statuses[i].Next, to)
+			}
+		}
+	}
+
+	return workflow, transitions.Err()
+}
+
+// SaveWorkflow replaces the statuses and transitions of the user in one
+// transaction, which holds the write lock of the database from its start, so
+// no task changes between the check of the tasks and the save.
+func (wr *workflowRepo) SaveWorkflow(workflow models.Workflow) *errr.AppError {
+	tx, err := wr.db.Begin()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to save workflow due to internal server error")
+	}
+	defer tx.Rollback()
+
+	stored, err := getWorkflow(tx, workflow.UserID)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to save workflow due to internal server error")
+	}
+	if len(stored.Statuses) == 0 {
+		stored = models.DefaultWorkflow(workflow.UserID)
+	}
+	statuses, err := taskStatuses(tx, workflow.UserID)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to save workflow due to internal server error")
+	}
+	if id, ok := workflow.MissingStatus(statuses); ok {
+		return errr.NewDuplicateError(fmt.Sprintf("Status %s is still used by tasks", stored.StatusName(id)))
+	}
+
+	_, err = tx.Exec(`DELETE FROM workflow_statuses WHERE user_id = ?`, workflow.UserID)
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to save workflow due to internal server error")
+	}
+
+	for position, status := range workflow.Statuses {
+		_, err = tx.Exec(
+			`INSERT INTO workflow_statuses (user_id, id, position, name, terminal) VALUES (?, ?, ?, ?, ?)`,
+			workflow.UserID, status.ID, position, status.Name, status.Terminal,
+		)
+		if err != nil {
+			return errr.NewUnexpectedError("Unable to save workflow due to internal server error")
+		}
+	}
+	for _, status := range workflow.Statuses {
+		for _, next := range status.Next {
+			_, err = tx.Exec(
+				`INSERT OR IGNORE INTO workflow_transitions (user_id, from_id, to_id) VALUES (?, ?, ?)`,
+				workflow.UserID, status.ID, next,
+			)
+			if err != nil {
+				return errr.NewUnexpectedError("Unable to save workflow due to internal server error")
+			}
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errr.NewUnexpectedError("Unable to save workflow due to internal server error")
+	}
+
+	return nil
+}
+
+// taskStatuses returns the statuses the tasks of the user are in, those in
+// the trash included.
+func taskStatuses(q queryer, userID int64) ([]int, error) {
+	rows, err := q.Query(`SELECT DISTINCT status FROM tasks WHERE user_id = ?`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	statuses := []int{}
+	for rows.Next() {
+		var status int
+		err = rows.Scan(&status)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, rows.Err()
+}
@@ -0,0 +1,108 @@
+package sqlite
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/adpaters/idgen"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+)
+
+func Test_workflowRepo_SaveWorkflow(t *testing.T) {
+	db := getTempDB(t)
+	wr := NewWorkflowRepo(db)
+
+	workflow, appErr := wr.GetWorkflow(1234)
+	if appErr != nil || !reflect.DeepEqual(workflow, models.DefaultWorkflow(1234)) {
+		t.Errorf("GetWorkflow() = %v, %v, want the default workflow", workflow, appErr)
+	}
+
+	want := models.Workflow{
+		UserID: 1234,
+		Statuses: []models.WorkflowStatus{
+			{ID: 4, Name: "Backlog", Next: []int{3}},
+			{ID: 3, Name: "Review", Next: []int{4, models.StatusDone}},
+			{ID: models.StatusDone, Name: "Done", Terminal: true},
+		},
+	}
+	for range 2 {
+		appErr = wr.SaveWorkflow(want)
+		if appErr != nil {
+			t.Fatalf("SaveWorkflow() failed: %v", appErr)
+		}
+	}
+
+	workflow, appErr = wr.GetWorkflow(1234)
+	if appErr != nil || !reflect.DeepEqual(workflow, want) {
+		t.Errorf("GetWorkflow() after save = %v, %v, want %v", workflow, appErr, want)
+	}
+}
+
+func TestNewDB_MarksDoneTasks(t *testing.T) {
+	db := getTempDB(t)
+	_, err := db.Exec(`PRAGMA user_version = 7`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec(`
+		DROP TABLE workflow_transitions;
+		DROP TABLE workflow_statuses;
+		DROP INDEX idx_tasks_user_id_created_at;
+		DROP INDEX idx_tasks_user_id_updated_at;
+		DROP TABLE access_tokens;
+		DROP TABLE audit_log;
+		ALTER TABLE users DROP COLUMN role;
+		ALTER TABLE users DROP COLUMN disabled;
+		DROP TABLE revocations;
+		DROP TABLE refresh_tokens;
+		DROP INDEX idx_tasks_deleted_at;
+		ALTER TABLE tasks DROP COLUMN deleted_at;
+		ALTER TABLE tasks DROP COLUMN created_at;
+		ALTER TABLE tasks DROP COLUMN updated_at;
+		ALTER TABLE tasks DROP COLUMN done;
+		ALTER TABLE tasks DROP COLUMN version;
+		INSERT INTO tasks (id, title, description, status, user_id) VALUES
+			(1, 'a', 'd', 1, 1234), (2, 'b', 'd', 2, 1234);
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = migrate(db)
+	if err != nil {
+		t.Fatalf("migrate() failed: %v", err)
+	}
+
+	tasks, _ := NewTaskRepo(db, nil).GetTasks(models.NewTaskQuery(1234))
+	if len(tasks) != 2 || !tasks[0].Done || tasks[1].Done {
+		t.Errorf("tasks after migration = %v, want only task 1 done", tasks)
+	}
+}
+
+func Test_workflowRepo_SaveWorkflow_statuses_in_use(t *testing.T) {
+	db := getTempDB(t)
+	wr := NewWorkflowRepo(db)
+	tr := NewTaskRepo(db, idgen.NewSequenceGenerator(100))
+	task, _ := tr.SaveTask(models.Task{Title: "a", Desc: "a", UserID: 1234, Status: models.StatusPending})
+	tr.DeleteTask(task.ID, 1234, 0, time.Date(2020, time.January, 5, 10, 0, 0, 0, time.UTC))
+
+	review := models.Workflow{
+		UserID: 1234,
+		Statuses: []models.WorkflowStatus{
+			{ID: 3, Name: "Review", Next: []int{models.StatusDone}},
+			{ID: models.StatusDone, Name: "Done", Terminal: true},
+		},
+	}
+	appErr := wr.SaveWorkflow(review)
+	if appErr == nil || appErr.Code != http.StatusConflict || appErr.Message != "Status Pending is still used by tasks" {
+		t.Errorf("SaveWorkflow() with a task in the trash = %v, want a conflict naming Pending", appErr)
+	}
+
+	review.UserID = 99
+	appErr = wr.SaveWorkflow(review)
+	if appErr != nil {
+		t.Errorf("SaveWorkflow() of another user failed: %v", appErr)
+	}
+}
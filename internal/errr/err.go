@@ -1,10 +1,43 @@
 package errr
 
-import "net/http"
+import (
+	"net/http"
+	"strings"
+)
 
+// AppError is a failure to report to the client. Code is the HTTP status and
+// Message the human-readable detail.
 type AppError struct {
 	Code    int
 	Message string
+	// Reason is a machine-readable code like "not_found" clients can switch
+	// on. Left empty, it follows from Code.
+	Reason string
+	// Fields tells which fields of the request were invalid and why.
+	Fields []FieldError
+	// TraceID identifies the request that failed, to find it in the logs.
+	TraceID string
+}
+
+// FieldError is a problem with one field of a request. Field is its path,
+// like "checklist[2].text".
+type FieldError struct {
+	Field   string `json:"field"`
+	Reason  string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ReasonCode returns Reason or, when it is empty, the snake cased status text
+// of Code, such as "bad_request".
+func (e *AppError) ReasonCode() string {
+	if e.Reason != "" {
+		return e.Reason
+	}
+	text := http.StatusText(e.Code)
+	if text == "" {
+		return "error"
+	}
+	return strings.ReplaceAll(strings.ToLower(text), " ", "_")
 }
 
 func NewUnexpectedError(message string) *AppError {
@@ -48,3 +81,20 @@ func NewUnauthorizedError(message string) *AppError {
 		Message: message,
 	}
 }
+
+func NewPreconditionFailedError(message string) *AppError {
+	return &AppError{
+		Code:    http.StatusPreconditionFailed,
+		Message: message,
+	}
+}
+
+// NewValidationError reports the fields of a request that break its rules.
+func NewValidationError(message string, fields []FieldError) *AppError {
+	return &AppError{
+		Code:    http.StatusBadRequest,
+		Message: message,
+		Reason:  "validation_failed",
+		Fields:  fields,
+	}
+}
@@ -2,6 +2,7 @@ package errr
 
 import (
 	"net/http"
+	"reflect"
 	"testing"
 )
 
@@ -12,7 +13,7 @@ func TestNewUnexpectedError(t *testing.T) {
 		Code:    http.StatusInternalServerError,
 		Message: message,
 	}
-	if *got != *want {
+	if !reflect.DeepEqual(got, want) {
 		t.Errorf("NewUnexpectedError() = %v, want %v", got, want)
 	}
 }
@@ -24,7 +25,7 @@ func TestNewNotFoundError(t *testing.T) {
 		Code:    http.StatusNotFound,
 		Message: message,
 	}
-	if *got != *want {
+	if !reflect.DeepEqual(got, want) {
 		t.Errorf("NewNotFoundError() = %v, want %v", got, want)
 	}
 }
@@ -36,7 +37,7 @@ func TestNewUnauthenticatedError(t *testing.T) {
 		Code:    http.StatusUnauthorized,
 		Message: message,
 	}
-	if *got != *want {
+	if !reflect.DeepEqual(got, want) {
 		t.Errorf("NewUnexpectedError() = %v, want %v", got, want)
 	}
 }
@@ -48,7 +49,7 @@ func TestNewBadRequestError(t *testing.T) {
 		Code:    http.StatusBadRequest,
 		Message: message,
 	}
-	if *got != *want {
+	if !reflect.DeepEqual(got, want) {
 		t.Errorf("NewUnexpectedError() = %v, want %v", got, want)
 	}
 }
@@ -60,7 +61,7 @@ func TestNewDuplicateError(t *testing.T) {
 		Code:    http.StatusConflict,
 		Message: message,
 	}
-	if *got != *want {
+	if !reflect.DeepEqual(got, want) {
 		t.Errorf("NewUnexpectedError() = %v, want %v", got, want)
 	}
 }
@@ -72,7 +73,43 @@ func TestNewUnauthorizedError(t *testing.T) {
 		Code:    http.StatusForbidden,
 		Message: message,
 	}
-	if *got != *want {
+	if !reflect.DeepEqual(got, want) {
 		t.Errorf("NewUnexpectedError() = %v, want %v", got, want)
 	}
 }
+
+func TestAppError_ReasonCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		appErr AppError
+		want   string
+	}{
+		{
+			name:   "explicit reason",
+			appErr: AppError{Code: http.StatusBadRequest, Reason: "invalid_body"},
+			want:   "invalid_body",
+		},
+		{
+			name:   "from the status",
+			appErr: AppError{Code: http.StatusNotFound},
+			want:   "not_found",
+		},
+		{
+			name:   "from a status of several words",
+			appErr: AppError{Code: http.StatusInternalServerError},
+			want:   "internal_server_error",
+		},
+		{
+			name:   "unknown status",
+			appErr: AppError{},
+			want:   "error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.appErr.ReasonCode(); got != tt.want {
+				t.Errorf("ReasonCode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
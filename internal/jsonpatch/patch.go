@@ -0,0 +1,334 @@
+// Package jsonpatch changes JSON documents by JSON Merge Patch (RFC 7396) and
+// JSON Patch (RFC 6902) documents.
+package jsonpatch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ErrTestFailed is returned when a test operation of a JSON Patch finds a
+// different value. Every other error means the patch can't be applied.
+var ErrTestFailed = errors.New("test failed")
+
+// MergePatch applies the merge patch to doc. Members of the patch that are
+// null remove the member from doc, objects are merged into the object they
+// replace and anything else replaces it.
+func MergePatch(doc, patch []byte) ([]byte, error) {
+	var target, changes any
+	if err := json.Unmarshal(doc, &target); err != nil {
+		return nil, fmt.Errorf("document is not JSON: %w", err)
+	}
+	if err := json.Unmarshal(patch, &changes); err != nil {
+		return nil, errors.New("patch is not JSON")
+	}
+
+	return json.Marshal(mergePatch(target, changes))
+}
+
+func mergePatch(target, patch any) any {
+	changes, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	object, ok := target.(map[string]any)
+	if !ok {
+		object = map[string]any{}
+	}
+	for name, value := range changes {
+		if value == nil {
+			delete(object, name)
+			continue
+		}
+		object[name] = mergePatch(object[name], value)
+	}
+	return object
+}
+
+// operation is one step of a JSON Patch. Value stays nil when the member is
+// left out, which tells it apart from a null value.
+type operation struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// Apply applies the operations of the JSON Patch patch to doc in order. It
+// stops at the first operation that fails, leaving doc as it was.
+func Apply(doc, patch []byte) ([]byte, error) {
+	var target any
+	if err := json.Unmarshal(doc, &target); err != nil {
+		return nil, fmt.Errorf("document is not JSON: %w", err)
+	}
+	var operations []operation
+	if err := json.Unmarshal(patch, &operations); err != nil {
+		return nil, errors.New("patch is not an array of operations")
+	}
+
+	for i, op := range operations {
+		var err error
+		target, err = op.apply(target)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i, err)
+		}
+	}
+
+	return json.Marshal(target)
+}
+
+func (op operation) apply(doc any) (any, error) {
+	if op.Path == nil {
+		return nil, errors.New("path is missing")
+	}
+	path, err := parsePointer(*op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return nil, errors.New("value is missing")
+		}
+		var value any
+		if err := json.Unmarshal(op.Value, &value); err != nil {
+			return nil, errors.New("value is not JSON")
+		}
+		switch op.Op {
+		case "add":
+			return add(doc, path, value)
+		case "replace":
+			return replace(doc, path, value)
+		}
+		current, err := get(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(current, value) {
+			return nil, fmt.Errorf("%w at %s", ErrTestFailed, *op.Path)
+		}
+		return doc, nil
+	case "remove":
+		doc, _, err := remove(doc, path)
+		return doc, err
+	case "move", "copy":
+		if op.From == nil {
+			return nil, errors.New("from is missing")
+		}
+		from, err := parsePointer(*op.From)
+		if err != nil {
+			return nil, err
+		}
+		if op.Op == "copy" {
+			value, err := get(doc, from)
+			if err != nil {
+				return nil, err
+			}
+			return add(doc, path, deepCopy(value))
+		}
+		if len(from) < len(path) && reflect.DeepEqual(from, path[:len(from)]) {
+			return nil, errors.New("can't move a value into itself")
+		}
+		doc, value, err := remove(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	}
+	return nil, fmt.Errorf("unknown op %q", op.Op)
+}
+
+// parsePointer splits a JSON Pointer (RFC 6901) into its reference tokens.
+// The empty pointer is the whole document.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("path %q must start with /", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func pointerString(path []string) string {
+	var b strings.Builder
+	for _, token := range path {
+		b.WriteString("/")
+		b.WriteString(strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1"))
+	}
+	return b.String()
+}
+
+// arrayIndex parses the token of an element of an array of length n. Adding
+// may name the end of the array, by n or "-".
+func arrayIndex(token string, n int, adding bool) (int, error) {
+	if adding && token == "-" {
+		return n, nil
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || (token != "0" && strings.HasPrefix(token, "0")) {
+		return 0, fmt.Errorf("%q is not an array index", token)
+	}
+	if i > n || (i == n && !adding) {
+		return 0, fmt.Errorf("index %d is out of range", i)
+	}
+	return i, nil
+}
+
+func get(doc any, path []string) (any, error) {
+	for i, token := range path {
+		switch node := doc.(type) {
+		case map[string]any:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("path %s does not exist", pointerString(path[:i+1]))
+			}
+			doc = value
+		case []any:
+			index, err := arrayIndex(token, len(node), false)
+			if err != nil {
+				return nil, err
+			}
+			doc = node[index]
+		default:
+			return nil, fmt.Errorf("path %s does not exist", pointerString(path[:i+1]))
+		}
+	}
+	return doc, nil
+}
+
+// change replaces the container holding the last token of path by what
+// edit makes of it, returning the changed document. The container has to
+// exist.
+func change(doc any, path []string, edit func(container any, token string) (any, error)) (any, error) {
+	if len(path) == 1 {
+		return edit(doc, path[0])
+	}
+
+	switch node := doc.(type) {
+	case map[string]any:
+		child, ok := node[path[0]]
+		if !ok {
+			return nil, fmt.Errorf("path /%s does not exist", path[0])
+		}
+		child, err := change(child, path[1:], edit)
+		if err != nil {
+			return nil, err
+		}
+		node[path[0]] = child
+		return node, nil
+	case []any:
+		index, err := arrayIndex(path[0], len(node), false)
+		if err != nil {
+			return nil, err
+		}
+		node[index], err = change(node[index], path[1:], edit)
+		if err != nil {
+			return nil, err
+		}
+		return node, nil
+	}
+	return nil, fmt.Errorf("path /%s is not an object or array", path[0])
+}
+
+func add(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	if _, err := get(doc, path[:len(path)-1]); err != nil {
+		return nil, err
+	}
+	return change(doc, path, func(container any, token string) (any, error) {
+		switch node := container.(type) {
+		case map[string]any:
+			node[token] = value
+			return node, nil
+		case []any:
+			index, err := arrayIndex(token, len(node), true)
+			if err != nil {
+				return nil, err
+			}
+			node = append(node, nil)
+			copy(node[index+1:], node[index:])
+			node[index] = value
+			return node, nil
+		}
+		return nil, fmt.Errorf("can't add to %s", pointerString(path[:len(path)-1]))
+	})
+}
+
+func replace(doc any, path []string, value any) (any, error) {
+	if _, err := get(doc, path); err != nil {
+		return nil, err
+	}
+	if len(path) == 0 {
+		return value, nil
+	}
+	return change(doc, path, func(container any, token string) (any, error) {
+		switch node := container.(type) {
+		case map[string]any:
+			node[token] = value
+			return node, nil
+		case []any:
+			index, _ := arrayIndex(token, len(node), false)
+			node[index] = value
+			return node, nil
+		}
+		return container, nil
+	})
+}
+
+// remove removes the value at path and returns it next to the changed
+// document.
+func remove(doc any, path []string) (any, any, error) {
+	removed, err := get(doc, path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(path) == 0 {
+		return nil, nil, errors.New("can't remove the whole document")
+	}
+	doc, err = change(doc, path, func(container any, token string) (any, error) {
+		switch node := container.(type) {
+		case map[string]any:
+			delete(node, token)
+			return node, nil
+		case []any:
+			index, _ := arrayIndex(token, len(node), false)
+			return append(node[:index], node[index+1:]...), nil
+		}
+		return container, nil
+	})
+	return doc, removed, err
+}
+
+// deepCopy copies a decoded JSON value so a copy and its source can change
+// independently.
+func deepCopy(value any) any {
+	switch node := value.(type) {
+	case map[string]any:
+		object := make(map[string]any, len(node))
+		for name, child := range node {
+			object[name] = deepCopy(child)
+		}
+		return object
+	case []any:
+		array := make([]any, len(node))
+		for i, child := range node {
+			array[i] = deepCopy(child)
+		}
+		return array
+	}
+	return value
+}
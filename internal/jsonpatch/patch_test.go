@@ -0,0 +1,200 @@
+package jsonpatch
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+	}{
+		{name: "replace member", doc: `{"a":"b"}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{name: "add member", doc: `{"a":"b"}`, patch: `{"b":"c"}`, want: `{"a":"b","b":"c"}`},
+		{name: "null removes member", doc: `{"a":"b","b":"c"}`, patch: `{"a":null}`, want: `{"b":"c"}`},
+		{name: "arrays are replaced", doc: `{"a":["b"]}`, patch: `{"a":["c","d"]}`, want: `{"a":["c","d"]}`},
+		{
+			name:  "objects are merged",
+			doc:   `{"a":{"b":"c","d":"e"}}`,
+			patch: `{"a":{"d":null,"f":"g"}}`,
+			want:  `{"a":{"b":"c","f":"g"}}`,
+		},
+		{name: "object replaces value", doc: `{"a":"b"}`, patch: `{"a":{"c":null}}`, want: `{"a":{}}`},
+		{name: "non-object replaces document", doc: `{"a":"b"}`, patch: `["c"]`, want: `["c"]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MergePatch([]byte(tt.doc), []byte(tt.patch))
+			if err != nil || string(got) != tt.want {
+				t.Errorf("MergePatch() = %s, %v, want %s", got, err, tt.want)
+			}
+		})
+	}
+
+	if _, err := MergePatch([]byte(`{}`), []byte(`{`)); err == nil {
+		t.Errorf("MergePatch() of a broken patch did not fail")
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		patch   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "add member",
+			doc:   `{"foo":"bar"}`,
+			patch: `[{"op":"add","path":"/baz","value":"qux"}]`,
+			want:  `{"baz":"qux","foo":"bar"}`,
+		},
+		{
+			name:  "add array element",
+			doc:   `{"foo":["bar","baz"]}`,
+			patch: `[{"op":"add","path":"/foo/1","value":"qux"}]`,
+			want:  `{"foo":["bar","qux","baz"]}`,
+		},
+		{
+			name:  "append to array",
+			doc:   `{"foo":["bar"]}`,
+			patch: `[{"op":"add","path":"/foo/-","value":{"a":1}}]`,
+			want:  `{"foo":["bar",{"a":1}]}`,
+		},
+		{
+			name:  "add null",
+			doc:   `{"foo":"bar"}`,
+			patch: `[{"op":"add","path":"/foo","value":null}]`,
+			want:  `{"foo":null}`,
+		},
+		{
+			name:  "remove array element",
+			doc:   `{"foo":["bar","qux","baz"]}`,
+			patch: `[{"op":"remove","path":"/foo/1"}]`,
+			want:  `{"foo":["bar","baz"]}`,
+		},
+		{
+			name:  "replace value",
+			doc:   `{"baz":"qux","foo":"bar"}`,
+			patch: `[{"op":"replace","path":"/baz","value":"boo"}]`,
+			want:  `{"baz":"boo","foo":"bar"}`,
+		},
+		{
+			name:  "move value",
+			doc:   `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+			patch: `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			want:  `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`,
+		},
+		{
+			name:  "move array element",
+			doc:   `{"foo":["all","grass","cows","eat"]}`,
+			patch: `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`,
+			want:  `{"foo":["all","cows","eat","grass"]}`,
+		},
+		{
+			name:  "copy is independent",
+			doc:   `{"a":{"b":1}}`,
+			patch: `[{"op":"copy","from":"/a","path":"/c"},{"op":"replace","path":"/c/b","value":2}]`,
+			want:  `{"a":{"b":1},"c":{"b":2}}`,
+		},
+		{
+			name:  "escaped pointer",
+			doc:   `{"a/b":1,"m~n":2}`,
+			patch: `[{"op":"remove","path":"/a~1b"},{"op":"test","path":"/m~0n","value":2}]`,
+			want:  `{"m~n":2}`,
+		},
+		{
+			name:  "test passes",
+			doc:   `{"baz":"qux","foo":["a",2,"c"]}`,
+			patch: `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`,
+			want:  `{"baz":"qux","foo":["a",2,"c"]}`,
+		},
+		{
+			name:    "add to missing parent",
+			doc:     `{"foo":"bar"}`,
+			patch:   `[{"op":"add","path":"/baz/bat","value":"qux"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "remove missing member",
+			doc:     `{"foo":"bar"}`,
+			patch:   `[{"op":"remove","path":"/baz"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "replace missing member",
+			doc:     `{"foo":"bar"}`,
+			patch:   `[{"op":"replace","path":"/baz","value":1}]`,
+			wantErr: true,
+		},
+		{
+			name:    "array index out of range",
+			doc:     `{"foo":["bar"]}`,
+			patch:   `[{"op":"add","path":"/foo/2","value":"baz"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "array index with leading zero",
+			doc:     `{"foo":["bar","baz"]}`,
+			patch:   `[{"op":"remove","path":"/foo/01"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "missing value",
+			doc:     `{"foo":"bar"}`,
+			patch:   `[{"op":"add","path":"/baz"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown op",
+			doc:     `{"foo":"bar"}`,
+			patch:   `[{"op":"frobnicate","path":"/foo"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "move into itself",
+			doc:     `{"a":{"b":{}}}`,
+			patch:   `[{"op":"move","from":"/a","path":"/a/b/c"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "not an array of operations",
+			doc:     `{"foo":"bar"}`,
+			patch:   `{"op":"remove","path":"/foo"}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply([]byte(tt.doc), []byte(tt.patch))
+			if tt.wantErr {
+				if err == nil || errors.Is(err, ErrTestFailed) {
+					t.Errorf("Apply() = %s, %v, want an invalid patch", got, err)
+				}
+				return
+			}
+			if err != nil || string(got) != tt.want {
+				t.Errorf("Apply() = %s, %v, want %s", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestApply_testFails(t *testing.T) {
+	_, err := Apply(
+		[]byte(`{"baz":"qux"}`),
+		[]byte(`[{"op":"remove","path":"/baz"},{"op":"test","path":"/baz","value":"qux"}]`),
+	)
+	if err == nil || errors.Is(err, ErrTestFailed) {
+		t.Errorf("Apply() = %v, want a missing path", err)
+	}
+
+	_, err = Apply([]byte(`{"baz":"qux"}`), []byte(`[{"op":"test","path":"/baz","value":"bar"}]`))
+	if !errors.Is(err, ErrTestFailed) {
+		t.Errorf("Apply() = %v, want %v", err, ErrTestFailed)
+	}
+}
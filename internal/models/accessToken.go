@@ -0,0 +1,111 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Scopes of personal access tokens. Reading and writing tasks covers the
+// labels, projects and workflow the tasks are organised with.
+const (
+	ScopeTasksRead  = "tasks:read"
+	ScopeTasksWrite = "tasks:write"
+)
+
+// AccessTokenPrefix starts every personal access token, so it can't be
+// mistaken for a JWT.
+const AccessTokenPrefix = "todo_pat_"
+
+const maxAccessTokenNameLength = 100
+
+// AccessToken is a personal access token a user created for a script or an
+// integration. Only a hash of the token is kept.
+type AccessToken struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	Name      string    `json:"name"`
+	Hash      string    `json:"hash"`
+	Scopes    []string  `json:"scopes"`
+	CreatedAt time.Time `json:"created_at"`
+	// ExpiresAt is zero for tokens that never expire.
+	ExpiresAt  time.Time `json:"expires_at,omitzero"`
+	LastUsedAt time.Time `json:"last_used_at,omitzero"`
+}
+
+// IsExpired reports whether the token can no longer be used at now.
+func (t AccessToken) IsExpired(now time.Time) bool {
+	return !t.ExpiresAt.IsZero() && !now.Before(t.ExpiresAt)
+}
+
+// Validate checks the name and scopes of the token and that it expires after
+// now.
+func (t AccessToken) Validate(now time.Time) Violations {
+	var v Violations
+	if v.Required("name", t.Name, "Name") {
+		v.MaxLength("name", t.Name, "Name", maxAccessTokenNameLength)
+	}
+
+	if len(t.Scopes) == 0 {
+		v.Add("scopes", RuleRequired, "At least one scope is required")
+	}
+	for i, scope := range t.Scopes {
+		if scope != ScopeTasksRead && scope != ScopeTasksWrite {
+			v.Add(fmt.Sprintf("scopes[%d]", i), RuleUnknownValue, fmt.Sprintf(
+				"Unknown scope, use %s or %s", ScopeTasksRead, ScopeTasksWrite,
+			))
+		}
+	}
+
+	if !t.ExpiresAt.IsZero() && !t.ExpiresAt.After(now) {
+		v.Add("expires_at", RuleDateOrder, "Expiry must be in the future")
+	}
+	return v
+}
+
+// ToDto renders the token without its hash.
+func (t AccessToken) ToDto() AccessTokenResponseDto {
+	dto := AccessTokenResponseDto{
+		ID:        strconv.FormatInt(t.ID, 10),
+		Name:      t.Name,
+		Scopes:    t.Scopes,
+		CreatedAt: t.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if !t.ExpiresAt.IsZero() {
+		dto.ExpiresAt = t.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+	if !t.LastUsedAt.IsZero() {
+		dto.LastUsedAt = t.LastUsedAt.UTC().Format(time.RFC3339)
+	}
+	return dto
+}
+
+// Claims are the claims of the token's user, limited to the token's scopes.
+// The scopes are never nil, nil scopes would allow everything.
+func (t AccessToken) Claims(user User) Claims {
+	return Claims{
+		ID:       user.ID,
+		Role:     user.EffectiveRole(),
+		TimeZone: user.TimeZone,
+		Scopes:   append([]string{}, t.Scopes...),
+	}
+}
+
+// AccessTokenRequestDto creates an access token. ExpiresAt is an RFC 3339
+// time, the token never expires when it is empty.
+type AccessTokenRequestDto struct {
+	Name      string   `json:"name"`
+	Scopes    []string `json:"scopes"`
+	ExpiresAt string   `json:"expires_at,omitempty"`
+}
+
+type AccessTokenResponseDto struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Scopes     []string `json:"scopes"`
+	CreatedAt  string   `json:"created_at"`
+	ExpiresAt  string   `json:"expires_at,omitempty"`
+	LastUsedAt string   `json:"last_used_at,omitempty"`
+	// Token is only set when the token is created, it can't be read again.
+	Token string `json:"token,omitempty"`
+}
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAccessToken_IsExpired(t *testing.T) {
+	now := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		want      bool
+	}{
+		{"never expires", time.Time{}, false},
+		{"expires later", now.Add(time.Second), false},
+		{"expires now", now, true},
+		{"expired", now.Add(-time.Hour), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (AccessToken{ExpiresAt: tt.expiresAt}).IsExpired(now); got != tt.want {
+				t.Errorf("IsExpired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAccessToken_Validate(t *testing.T) {
+	now := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		token AccessToken
+		want  Violations
+	}{
+		{
+			name:  "valid token",
+			token: AccessToken{Name: "ci", Scopes: []string{ScopeTasksRead, ScopeTasksWrite}},
+			want:  nil,
+		},
+		{
+			name: "valid token with expiry",
+			token: AccessToken{
+				Name: "ci", Scopes: []string{ScopeTasksRead}, ExpiresAt: now.Add(time.Hour),
+			},
+			want: nil,
+		},
+		{
+			name:  "missing name and scopes",
+			token: AccessToken{},
+			want: Violations{
+				{Field: "name", Rule: RuleRequired, Message: "Name is required"},
+				{Field: "scopes", Rule: RuleRequired, Message: "At least one scope is required"},
+			},
+		},
+		{
+			name:  "unknown scope",
+			token: AccessToken{Name: "ci", Scopes: []string{ScopeTasksRead, "users:write"}},
+			want: Violations{
+				{
+					Field:   "scopes[1]",
+					Rule:    RuleUnknownValue,
+					Message: "Unknown scope, use tasks:read or tasks:write",
+				},
+			},
+		},
+		{
+			name:  "expired already",
+			token: AccessToken{Name: "ci", Scopes: []string{ScopeTasksRead}, ExpiresAt: now},
+			want: Violations{
+				{Field: "expires_at", Rule: RuleDateOrder, Message: "Expiry must be in the future"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.token.Validate(now); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAccessToken_Claims(t *testing.T) {
+	user := User{ID: 4321, Role: RoleAdmin, TimeZone: "Asia/Tokyo"}
+
+	got := AccessToken{Scopes: []string{}}.Claims(user)
+	want := Claims{ID: 4321, Role: RoleAdmin, TimeZone: "Asia/Tokyo", Scopes: []string{}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Claims() = %v, want %v", got, want)
+	}
+	// nil scopes would allow everything.
+	if got.HasScope(ScopeTasksRead) {
+		t.Errorf("HasScope(%q) = true for a token without scopes", ScopeTasksRead)
+	}
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"strconv"
+	"time"
+)
+
+// Actions of admins recorded in the audit log.
+const (
+	AuditListUsers     = "list_users"
+	AuditDisableUser   = "disable_user"
+	AuditEnableUser    = "enable_user"
+	AuditResetPassword = "reset_password"
+	AuditViewTasks     = "view_tasks"
+	AuditViewAuditLog  = "view_audit_log"
+)
+
+// Outcomes of the attempts recorded in the audit log.
+const (
+	AuditSucceeded = "succeeded"
+	// AuditDenied is an attempt the user lacked the rights for.
+	AuditDenied = "denied"
+	AuditFailed = "failed"
+)
+
+// AuditEntry records an attempt at an admin action, on the user with
+// TargetID when the action concerns one user. Reason is the error code of an
+// attempt that didn't succeed.
+type AuditEntry struct {
+	ID       int64     `json:"id"`
+	ActorID  int64     `json:"actor_id"`
+	Action   string    `json:"action"`
+	TargetID int64     `json:"target_id,omitempty"`
+	Outcome  string    `json:"outcome"`
+	Reason   string    `json:"reason,omitempty"`
+	At       time.Time `json:"at"`
+}
+
+type AuditEntryDto struct {
+	ID       string `json:"id"`
+	ActorID  string `json:"actor_id"`
+	Action   string `json:"action"`
+	TargetID string `json:"target_id,omitempty"`
+	Outcome  string `json:"outcome"`
+	Reason   string `json:"reason,omitempty"`
+	At       string `json:"at"`
+}
+
+func (e AuditEntry) ToDto() AuditEntryDto {
+	dto := AuditEntryDto{
+		ID:      strconv.FormatInt(e.ID, 10),
+		ActorID: strconv.FormatInt(e.ActorID, 10),
+		Action:  e.Action,
+		Outcome: e.Outcome,
+		Reason:  e.Reason,
+		At:      e.At.UTC().Format(time.RFC3339),
+	}
+	if e.TargetID != 0 {
+		dto.TargetID = strconv.FormatInt(e.TargetID, 10)
+	}
+	return dto
+}
@@ -0,0 +1,37 @@
+package models
+
+import "fmt"
+
+const (
+	maxChecklistItems    = 100
+	maxChecklistItemText = 200
+)
+
+// ChecklistItem is a lightweight step inside a task, too small to be a
+// subtask of its own.
+type ChecklistItem struct {
+	Text string `json:"text"`
+	Done bool   `json:"done"`
+}
+
+// Progress counts the finished parts of a task.
+type Progress struct {
+	Done  int
+	Total int
+}
+
+func (p Progress) Add(other Progress) Progress {
+	return Progress{
+		Done:  p.Done + other.Done,
+		Total: p.Total + other.Total,
+	}
+}
+
+// String renders the progress as "3/5 done", or nothing for a task without
+// parts.
+func (p Progress) String() string {
+	if p.Total == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d done", p.Done, p.Total)
+}
@@ -1,6 +1,35 @@
 package models
 
+import (
+	"slices"
+	"time"
+)
+
 type Claims struct {
-	ID   int64
-	Role string
+	ID       int64
+	Role     string
+	TimeZone string
+	// TokenID, IssuedAt and ExpiresAt describe the token the claims were
+	// read from. The token provider sets them, they are ignored when
+	// generating a token.
+	TokenID   string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+	// Scopes limit what a personal access token may do. They are nil for a
+	// signed in user, who may do everything.
+	Scopes []string
+}
+
+// HasScope reports whether the claims allow what scope stands for.
+func (c Claims) HasScope(scope string) bool {
+	return c.Scopes == nil || slices.Contains(c.Scopes, scope)
+}
+
+// Location returns the user's time zone, UTC when it is unset or unknown.
+func (c Claims) Location() *time.Location {
+	loc, err := time.LoadLocation(c.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
 }
@@ -0,0 +1,55 @@
+package models
+
+import "testing"
+
+func TestClaims_Location(t *testing.T) {
+	tests := []struct {
+		name     string
+		timeZone string
+		want     string
+	}{
+		{
+			name:     "no time zone",
+			timeZone: "",
+			want:     "UTC",
+		},
+		{
+			name:     "unknown time zone",
+			timeZone: "Nowhere/Town",
+			want:     "UTC",
+		},
+		{
+			name:     "known time zone",
+			timeZone: "Asia/Tokyo",
+			want:     "Asia/Tokyo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Claims{TimeZone: tt.timeZone}.Location()
+			if got.String() != tt.want {
+				t.Errorf("Location() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClaims_HasScope(t *testing.T) {
+	tests := []struct {
+		name   string
+		scopes []string
+		want   bool
+	}{
+		{"signed in user", nil, true},
+		{"token with the scope", []string{ScopeTasksRead, ScopeTasksWrite}, true},
+		{"token without the scope", []string{ScopeTasksWrite}, false},
+		{"token without scopes", []string{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (Claims{Scopes: tt.scopes}).HasScope(ScopeTasksRead); got != tt.want {
+				t.Errorf("HasScope() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package models
+
+// JSONWebKey is the public part of a key tokens are signed with, as a JSON
+// Web Key (RFC 7517). Which members are set depends on the key type: n and e
+// for RSA, crv, x and y for EC and crv and x for OKP keys.
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JSONWebKeySet is what other services verify tokens with, served at
+// /.well-known/jwks.json.
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
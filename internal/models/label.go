@@ -0,0 +1,38 @@
+package models
+
+import (
+	"regexp"
+	"strconv"
+)
+
+const maxLabelNameLength = 50
+
+var labelColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+type Label struct {
+	ID     int64  `json:"id"`
+	UserID int64  `json:"user_id"`
+	Name   string `json:"name"`
+	Color  string `json:"color"`
+}
+
+func (l Label) IsValidLabel() bool {
+	return l.IsValidName() && l.IsValidColor()
+}
+
+func (l Label) IsValidName() bool {
+	return l.Name != "" && len(l.Name) <= maxLabelNameLength
+}
+
+// IsValidColor reports whether the label colour is a hex colour like #1e90ff.
+func (l Label) IsValidColor() bool {
+	return labelColorPattern.MatchString(l.Color)
+}
+
+func (l Label) ToDto() LabelResponseDto {
+	return LabelResponseDto{
+		ID:    strconv.FormatInt(l.ID, 10),
+		Name:  l.Name,
+		Color: l.Color,
+	}
+}
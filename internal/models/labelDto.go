@@ -0,0 +1,19 @@
+package models
+
+type LabelRequestDto struct {
+	Name  string `json:"name,omitempty"`
+	Color string `json:"color,omitempty"`
+}
+
+func (lrd LabelRequestDto) ToLabel() Label {
+	return Label{
+		Name:  lrd.Name,
+		Color: lrd.Color,
+	}
+}
+
+type LabelResponseDto struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
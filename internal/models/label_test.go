@@ -0,0 +1,50 @@
+package models
+
+import "testing"
+
+func TestLabel_IsValidLabel(t *testing.T) {
+	tests := []struct {
+		name  string
+		label Label
+		want  bool
+	}{
+		{
+			name:  "valid label",
+			label: Label{Name: "work", Color: "#1e90ff"},
+			want:  true,
+		},
+		{
+			name:  "empty name",
+			label: Label{Name: "", Color: "#1e90ff"},
+			want:  false,
+		},
+		{
+			name:  "colour is not hex",
+			label: Label{Name: "work", Color: "blue"},
+			want:  false,
+		},
+		{
+			name:  "short hex colour",
+			label: Label{Name: "work", Color: "#fff"},
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.label.IsValidLabel()
+			if got != tt.want {
+				t.Errorf("IsValidLabel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLabel_ToDto(t *testing.T) {
+	label := Label{ID: 12, UserID: 34, Name: "work", Color: "#1e90ff"}
+	want := LabelResponseDto{ID: "12", Name: "work", Color: "#1e90ff"}
+
+	got := label.ToDto()
+	if got != want {
+		t.Errorf("ToDto() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,39 @@
+package models
+
+import "strconv"
+
+const maxProjectNameLength = 100
+
+type Project struct {
+	ID       int64  `json:"id"`
+	UserID   int64  `json:"user_id"`
+	Name     string `json:"name"`
+	Color    string `json:"color"`
+	Archived bool   `json:"archived,omitempty"`
+	// Position orders the projects of a user, lowest first.
+	Position int `json:"position"`
+}
+
+func (p Project) IsValidProject() bool {
+	return p.IsValidName() && p.IsValidColor() && p.Position >= 0
+}
+
+func (p Project) IsValidName() bool {
+	return p.Name != "" && len(p.Name) <= maxProjectNameLength
+}
+
+// IsValidColor reports whether the project colour is a hex colour like
+// #1e90ff.
+func (p Project) IsValidColor() bool {
+	return labelColorPattern.MatchString(p.Color)
+}
+
+func (p Project) ToDto() ProjectResponseDto {
+	return ProjectResponseDto{
+		ID:       strconv.FormatInt(p.ID, 10),
+		Name:     p.Name,
+		Color:    p.Color,
+		Archived: p.Archived,
+		Position: p.Position,
+	}
+}
@@ -0,0 +1,33 @@
+package models
+
+type ProjectRequestDto struct {
+	Name     string `json:"name,omitempty"`
+	Color    string `json:"color,omitempty"`
+	Archived *bool  `json:"archived,omitempty"`
+	Position *int   `json:"position,omitempty"`
+}
+
+// ApplyTo returns p with the fields given in the request replaced.
+func (prd ProjectRequestDto) ApplyTo(p Project) Project {
+	if prd.Name != "" {
+		p.Name = prd.Name
+	}
+	if prd.Color != "" {
+		p.Color = prd.Color
+	}
+	if prd.Archived != nil {
+		p.Archived = *prd.Archived
+	}
+	if prd.Position != nil {
+		p.Position = *prd.Position
+	}
+	return p
+}
+
+type ProjectResponseDto struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Color    string `json:"color"`
+	Archived bool   `json:"archived"`
+	Position int    `json:"position"`
+}
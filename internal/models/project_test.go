@@ -0,0 +1,51 @@
+package models
+
+import "testing"
+
+func TestProject_IsValidProject(t *testing.T) {
+	tests := []struct {
+		name    string
+		project Project
+		want    bool
+	}{
+		{
+			name:    "valid project",
+			project: Project{Name: "work", Color: "#1e90ff"},
+			want:    true,
+		},
+		{
+			name:    "empty name",
+			project: Project{Name: "", Color: "#1e90ff"},
+			want:    false,
+		},
+		{
+			name:    "colour is not hex",
+			project: Project{Name: "work", Color: "blue"},
+			want:    false,
+		},
+		{
+			name:    "negative position",
+			project: Project{Name: "work", Color: "#1e90ff", Position: -1},
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.project.IsValidProject()
+			if got != tt.want {
+				t.Errorf("IsValidProject() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProjectRequestDto_ApplyTo(t *testing.T) {
+	archived := true
+	project := Project{ID: 3, UserID: 34, Name: "work", Color: "#1e90ff", Position: 2}
+	want := Project{ID: 3, UserID: 34, Name: "work", Color: "#000000", Archived: true, Position: 2}
+
+	got := ProjectRequestDto{Color: "#000000", Archived: &archived}.ApplyTo(project)
+	if got != want {
+		t.Errorf("ApplyTo() = %v, want %v", got, want)
+	}
+}
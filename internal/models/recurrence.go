@@ -0,0 +1,256 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FreqDaily   = "DAILY"
+	FreqWeekly  = "WEEKLY"
+	FreqMonthly = "MONTHLY"
+
+	maxRecurrenceInterval = 366
+)
+
+var ErrInvalidRecurrence = errors.New("invalid recurrence rule")
+
+var weekdayCodes = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// Recurrence is a subset of an iCalendar RRULE, written like
+// "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE". Supported are FREQ=DAILY, WEEKLY with
+// BYDAY and MONTHLY with BYMONTHDAY, plus the non-standard FROM=COMPLETION
+// for daily rules counting from when the task was done instead of when it
+// was due.
+type Recurrence struct {
+	Freq     string
+	Interval int
+	// Weekdays of a weekly rule, the weekday of the due date when empty.
+	Weekdays []time.Weekday
+	// MonthDay of a monthly rule, the day of the due date when 0. Months
+	// shorter than that use their last day.
+	MonthDay        int
+	AfterCompletion bool
+}
+
+func ParseRecurrence(rule string) (Recurrence, error) {
+	r := Recurrence{Interval: 1}
+	seen := map[string]bool{}
+
+	for part := range strings.SplitSeq(strings.ToUpper(strings.TrimSpace(rule)), ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" || seen[key] {
+			return Recurrence{}, ErrInvalidRecurrence
+		}
+		seen[key] = true
+
+		var err error
+		switch key {
+		case "FREQ":
+			r.Freq = value
+		case "INTERVAL":
+			r.Interval, err = strconv.Atoi(value)
+		case "BYDAY":
+			for code := range strings.SplitSeq(value, ",") {
+				day := slices.Index(weekdayCodes, code)
+				if day == -1 {
+					return Recurrence{}, ErrInvalidRecurrence
+				}
+				r.Weekdays = append(r.Weekdays, time.Weekday(day))
+			}
+		case "BYMONTHDAY":
+			r.MonthDay, err = strconv.Atoi(value)
+			if err == nil && r.MonthDay == 0 {
+				err = ErrInvalidRecurrence
+			}
+		case "FROM":
+			if value != "COMPLETION" {
+				err = ErrInvalidRecurrence
+			}
+			r.AfterCompletion = true
+		default:
+			err = ErrInvalidRecurrence
+		}
+		if err != nil {
+			return Recurrence{}, ErrInvalidRecurrence
+		}
+	}
+
+	slices.Sort(r.Weekdays)
+	r.Weekdays = slices.Compact(r.Weekdays)
+	if !r.IsValid() {
+		return Recurrence{}, ErrInvalidRecurrence
+	}
+
+	return r, nil
+}
+
+func (r Recurrence) IsValid() bool {
+	if r.Interval < 1 || r.Interval > maxRecurrenceInterval {
+		return false
+	}
+
+	switch r.Freq {
+	case FreqDaily:
+		return len(r.Weekdays) == 0 && r.MonthDay == 0
+	case FreqWeekly:
+		return r.MonthDay == 0 && !r.AfterCompletion
+	case FreqMonthly:
+		return len(r.Weekdays) == 0 && r.MonthDay >= 0 && r.MonthDay <= 31 &&
+			!r.AfterCompletion
+	default:
+		return false
+	}
+}
+
+// String renders the rule in its canonical form.
+func (r Recurrence) String() string {
+	if r.Freq == "" {
+		return ""
+	}
+
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.Weekdays) > 0 {
+		codes := make([]string, len(r.Weekdays))
+		for i, day := range r.Weekdays {
+			codes[i] = weekdayCodes[day]
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if r.MonthDay != 0 {
+		parts = append(parts, "BYMONTHDAY="+strconv.Itoa(r.MonthDay))
+	}
+	if r.AfterCompletion {
+		parts = append(parts, "FROM=COMPLETION")
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// AnchoredAt fills in the weekday or day of month left out of the rule from
+// due, read in the time zone loc, so later occurrences don't drift.
+func (r Recurrence) AnchoredAt(due time.Time, loc *time.Location) Recurrence {
+	if loc == nil {
+		loc = time.UTC
+	}
+	due = due.In(loc)
+
+	switch r.Freq {
+	case FreqWeekly:
+		if len(r.Weekdays) == 0 {
+			r.Weekdays = []time.Weekday{due.Weekday()}
+		}
+	case FreqMonthly:
+		if r.MonthDay == 0 {
+			r.MonthDay = due.Day()
+		}
+	}
+	return r
+}
+
+func (r Recurrence) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText reads a rule, an empty one gives the zero Recurrence.
+func (r *Recurrence) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*r = Recurrence{}
+		return nil
+	}
+
+	parsed, err := ParseRecurrence(string(text))
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, text)
+	}
+	*r = parsed
+	return nil
+}
+
+// Next returns the occurrence following the one due at due, reading the rule
+// in the time zone loc. Rules counting from completion start at completedAt
+// and keep the time of day of due.
+func (r Recurrence) Next(due, completedAt time.Time, loc *time.Location) time.Time {
+	if loc == nil {
+		loc = time.UTC
+	}
+	due = due.In(loc)
+
+	var next time.Time
+	switch {
+	case r.AfterCompletion:
+		done := completedAt.In(loc)
+		next = time.Date(
+			done.Year(), done.Month(), done.Day()+r.Interval,
+			due.Hour(), due.Minute(), due.Second(), 0, loc,
+		)
+	case r.Freq == FreqDaily:
+		next = due.AddDate(0, 0, r.Interval)
+	case r.Freq == FreqWeekly:
+		next = r.nextWeekly(due)
+	case r.Freq == FreqMonthly:
+		next = r.nextMonthly(due)
+	}
+
+	return next.UTC()
+}
+
+// Occurrences returns the next n occurrences after the one due at due. Rules
+// counting from completion assume every occurrence is done when due.
+func (r Recurrence) Occurrences(due time.Time, n int, loc *time.Location) []time.Time {
+	occurrences := make([]time.Time, 0, n)
+	for range n {
+		due = r.Next(due, due, loc)
+		occurrences = append(occurrences, due)
+	}
+	return occurrences
+}
+
+func (r Recurrence) nextWeekly(due time.Time) time.Time {
+	weekdays := r.Weekdays
+	if len(weekdays) == 0 {
+		weekdays = []time.Weekday{due.Weekday()}
+	}
+
+	week := startOfWeek(due)
+	for day := 1; ; day++ {
+		next := due.AddDate(0, 0, day)
+		weeks := int(startOfWeek(next).Sub(week).Hours()) / (24 * 7)
+		if weeks%r.Interval == 0 && slices.Contains(weekdays, next.Weekday()) {
+			return next
+		}
+	}
+}
+
+func (r Recurrence) nextMonthly(due time.Time) time.Time {
+	day := r.MonthDay
+	if day == 0 {
+		day = due.Day()
+	}
+
+	for months := 0; ; months += r.Interval {
+		first := time.Date(due.Year(), due.Month()+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+		lastDay := first.AddDate(0, 1, -1).Day()
+		next := time.Date(
+			first.Year(), first.Month(), min(day, lastDay),
+			due.Hour(), due.Minute(), due.Second(), 0, due.Location(),
+		)
+		if next.After(due) {
+			return next
+		}
+	}
+}
+
+// startOfWeek returns the Monday starting the calendar week of t, as a UTC
+// date so weeks can be counted without daylight saving shifts.
+func startOfWeek(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,147 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRecurrence(t *testing.T) {
+	tests := []struct {
+		rule    string
+		want    string
+		wantErr bool
+	}{
+		{rule: "FREQ=DAILY", want: "FREQ=DAILY"},
+		{rule: "freq=weekly;byday=we,mo,we", want: "FREQ=WEEKLY;BYDAY=MO,WE"},
+		{rule: "FREQ=MONTHLY;BYMONTHDAY=31;INTERVAL=2", want: "FREQ=MONTHLY;INTERVAL=2;BYMONTHDAY=31"},
+		{rule: "FREQ=DAILY;INTERVAL=3;FROM=COMPLETION", want: "FREQ=DAILY;INTERVAL=3;FROM=COMPLETION"},
+		{rule: "", wantErr: true},
+		{rule: "FREQ=YEARLY", wantErr: true},
+		{rule: "FREQ=DAILY;INTERVAL=0", wantErr: true},
+		{rule: "FREQ=DAILY;FREQ=WEEKLY", wantErr: true},
+		{rule: "FREQ=WEEKLY;BYDAY=XX", wantErr: true},
+		{rule: "FREQ=WEEKLY;FROM=COMPLETION", wantErr: true},
+		{rule: "FREQ=MONTHLY;BYMONTHDAY=32", wantErr: true},
+		{rule: "FREQ=DAILY;COUNT=3", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			got, err := ParseRecurrence(tt.rule)
+			if err != nil {
+				if !tt.wantErr {
+					t.Errorf("ParseRecurrence() failed: %v", err)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Fatal("ParseRecurrence() succeeded unexpectedly")
+			}
+			if got.String() != tt.want {
+				t.Errorf("ParseRecurrence().String() = %q, want %q", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRecurrence_Next(t *testing.T) {
+	paris, _ := time.LoadLocation("Europe/Paris")
+	tests := []struct {
+		name        string
+		rule        string
+		due         time.Time
+		completedAt time.Time
+		loc         *time.Location
+		want        time.Time
+	}{
+		{
+			name: "every other day",
+			rule: "FREQ=DAILY;INTERVAL=2",
+			due:  time.Date(2025, time.March, 1, 9, 0, 0, 0, time.UTC),
+			want: time.Date(2025, time.March, 3, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "next weekday in the same week",
+			rule: "FREQ=WEEKLY;BYDAY=MO,TH",
+			due:  time.Date(2025, time.March, 3, 9, 0, 0, 0, time.UTC), // Monday
+			want: time.Date(2025, time.March, 6, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "every other week skips a week",
+			rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH",
+			due:  time.Date(2025, time.March, 6, 9, 0, 0, 0, time.UTC), // Thursday
+			want: time.Date(2025, time.March, 17, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "weekly keeps local time over daylight saving",
+			rule: "FREQ=WEEKLY;BYDAY=SA",
+			due:  time.Date(2025, time.March, 29, 9, 0, 0, 0, paris),
+			loc:  paris,
+			want: time.Date(2025, time.April, 5, 9, 0, 0, 0, paris),
+		},
+		{
+			name: "monthly uses the last day of short months",
+			rule: "FREQ=MONTHLY;BYMONTHDAY=31",
+			due:  time.Date(2025, time.January, 31, 9, 0, 0, 0, time.UTC),
+			want: time.Date(2025, time.February, 28, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "monthly goes back to its day after a short month",
+			rule: "FREQ=MONTHLY;BYMONTHDAY=31",
+			due:  time.Date(2025, time.February, 28, 9, 0, 0, 0, time.UTC),
+			want: time.Date(2025, time.March, 31, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:        "days after completion",
+			rule:        "FREQ=DAILY;INTERVAL=3;FROM=COMPLETION",
+			due:         time.Date(2025, time.March, 1, 9, 0, 0, 0, time.UTC),
+			completedAt: time.Date(2025, time.March, 5, 18, 30, 0, 0, time.UTC),
+			want:        time.Date(2025, time.March, 8, 9, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := ParseRecurrence(tt.rule)
+			if err != nil {
+				t.Fatalf("ParseRecurrence() failed: %v", err)
+			}
+			got := r.Next(tt.due, tt.completedAt, tt.loc)
+			if !got.Equal(tt.want) {
+				t.Errorf("Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecurrence_AnchoredAt(t *testing.T) {
+	due := time.Date(2025, time.March, 6, 9, 0, 0, 0, time.UTC) // Thursday
+
+	weekly, _ := ParseRecurrence("FREQ=WEEKLY")
+	if got := weekly.AnchoredAt(due, nil).String(); got != "FREQ=WEEKLY;BYDAY=TH" {
+		t.Errorf("AnchoredAt() = %q, want %q", got, "FREQ=WEEKLY;BYDAY=TH")
+	}
+
+	monthly, _ := ParseRecurrence("FREQ=MONTHLY")
+	if got := monthly.AnchoredAt(due, nil).String(); got != "FREQ=MONTHLY;BYMONTHDAY=6" {
+		t.Errorf("AnchoredAt() = %q, want %q", got, "FREQ=MONTHLY;BYMONTHDAY=6")
+	}
+}
+
+func TestRecurrence_Occurrences(t *testing.T) {
+	r, _ := ParseRecurrence("FREQ=WEEKLY;BYDAY=MO,FR")
+	due := time.Date(2025, time.March, 3, 9, 0, 0, 0, time.UTC) // Monday
+
+	got := r.Occurrences(due, 3, nil)
+	want := []time.Time{
+		time.Date(2025, time.March, 7, 9, 0, 0, 0, time.UTC),
+		time.Date(2025, time.March, 10, 9, 0, 0, 0, time.UTC),
+		time.Date(2025, time.March, 14, 9, 0, 0, 0, time.UTC),
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Occurrences() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("Occurrences()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// RefreshToken renews the access token of a user, once. Only a hash of the
+// token is kept. Using it issues the next token of its family, the tokens
+// descending from one sign in, so a token showing up a second time means it
+// was stolen.
+type RefreshToken struct {
+	Hash string `json:"hash"`
+	// FamilyID is the hash of the first token of the family.
+	FamilyID  string    `json:"family_id"`
+	UserID    int64     `json:"user_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// UsedAt is when the token was exchanged, zero while it is unused.
+	UsedAt time.Time `json:"used_at,omitzero"`
+}
+
+// IsExpired reports whether the token can no longer be used at now.
+func (t RefreshToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+// TokenPairDto is what signing in and refreshing answer with. The access
+// token goes into the Authorization header, the refresh token is exchanged
+// for the next pair once the access token expires.
+type TokenPairDto struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+}
+
+// RefreshRequestDto exchanges a refresh token for a new token pair.
+type RefreshRequestDto struct {
+	RefreshToken string `json:"refresh_token"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Revocation makes access tokens invalid before they expire: the token with
+// TokenID or, when TokenID is empty, every token of UserID issued up to
+// IssuedUntil.
+type Revocation struct {
+	TokenID     string    `json:"token_id,omitempty"`
+	UserID      int64     `json:"user_id"`
+	IssuedUntil time.Time `json:"issued_until,omitzero"`
+	// ExpiresAt is when every token the revocation revokes has expired, so
+	// it can be pruned.
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Revokes reports whether the revocation revokes the token the claims were
+// read from.
+func (r Revocation) Revokes(claims Claims) bool {
+	if r.TokenID != "" {
+		return r.TokenID == claims.TokenID
+	}
+	return r.UserID == claims.ID && !claims.IssuedAt.After(r.IssuedUntil)
+}
+
+// IsSame reports whether r and other are the same revocation: of the token
+// with the same jti or, without one, of the tokens of the same user issued
+// up to the same time.
+func (r Revocation) IsSame(other Revocation) bool {
+	if r.TokenID != "" || other.TokenID != "" {
+		return r.TokenID == other.TokenID
+	}
+	return r.UserID == other.UserID && r.IssuedUntil.Equal(other.IssuedUntil)
+}
+
+// IsExpired reports whether the revocation can be pruned at now.
+func (r Revocation) IsExpired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
+
+// LogoutRequestDto optionally names the refresh token to revoke with the
+// access token on logout.
+type LogoutRequestDto struct {
+	RefreshToken string `json:"refresh_token"`
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRevocation_Revokes(t *testing.T) {
+	issuedAt := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	claims := Claims{ID: 4321, TokenID: "jti", IssuedAt: issuedAt}
+
+	tests := []struct {
+		name       string
+		revocation Revocation
+		want       bool
+	}{
+		{"same token", Revocation{TokenID: "jti", UserID: 4321}, true},
+		{"another token", Revocation{TokenID: "other", UserID: 4321}, false},
+		{"user's tokens issued later", Revocation{UserID: 4321, IssuedUntil: issuedAt.Add(time.Second)}, true},
+		{"user's tokens issued in the same second", Revocation{UserID: 4321, IssuedUntil: issuedAt}, true},
+		{"user's tokens issued earlier", Revocation{UserID: 4321, IssuedUntil: issuedAt.Add(-time.Second)}, false},
+		{"another user's tokens", Revocation{UserID: 99, IssuedUntil: issuedAt.Add(time.Second)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.revocation.Revokes(claims); got != tt.want {
+				t.Errorf("Revokes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRevocation_IsSame(t *testing.T) {
+	issuedUntil := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	kolkata, _ := time.LoadLocation("Asia/Kolkata")
+	revocation := Revocation{TokenID: "jti", UserID: 4321, ExpiresAt: issuedUntil}
+	logout := Revocation{UserID: 4321, IssuedUntil: issuedUntil}
+
+	tests := []struct {
+		name  string
+		r     Revocation
+		other Revocation
+		want  bool
+	}{
+		{"same token", revocation, Revocation{TokenID: "jti", UserID: 4321, ExpiresAt: issuedUntil.In(kolkata)}, true},
+		{"another token", revocation, Revocation{TokenID: "other", UserID: 4321, ExpiresAt: issuedUntil}, false},
+		{"token and the user's tokens", revocation, logout, false},
+		{"user's tokens at the same time", logout, Revocation{UserID: 4321, IssuedUntil: issuedUntil.In(kolkata)}, true},
+		{"user's tokens at another time", logout, Revocation{UserID: 4321, IssuedUntil: issuedUntil.Add(time.Second)}, false},
+		{"another user's tokens", logout, Revocation{UserID: 99, IssuedUntil: issuedUntil}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.r.IsSame(tt.other); got != tt.want {
+				t.Errorf("IsSame() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
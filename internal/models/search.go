@@ -0,0 +1,24 @@
+package models
+
+// SearchHit is a task matching a search. Title and Snippet are HTML with the
+// matched words wrapped in <mark>.
+type SearchHit struct {
+	TaskID int64
+	Score  float64
+	Title  string
+	// Snippet shows the words of the description around its first match,
+	// it is empty when only the title matched.
+	Snippet string
+}
+
+// ToDto renders the hit for task, the task it points at.
+func (h SearchHit) ToDto(task TaskResponseDto) SearchResultDto {
+	return SearchResultDto{
+		Task:  task,
+		Score: h.Score,
+		Highlights: SearchHighlightsDto{
+			Title: h.Title,
+			Desc:  h.Snippet,
+		},
+	}
+}
@@ -0,0 +1,13 @@
+package models
+
+type SearchResultDto struct {
+	Task       TaskResponseDto     `json:"task"`
+	Score      float64             `json:"score"`
+	Highlights SearchHighlightsDto `json:"highlights"`
+}
+
+// SearchHighlightsDto holds HTML with the matched words wrapped in <mark>.
+type SearchHighlightsDto struct {
+	Title string `json:"title"`
+	Desc  string `json:"desc,omitempty"`
+}
@@ -0,0 +1,66 @@
+package models
+
+// MaxTaskDepth is how deeply subtasks nest, a top-level task has depth 1.
+const MaxTaskDepth = 3
+
+// SubtaskProgress tallies the direct subtasks of every task in tasks, keyed
+// by parent id.
+func SubtaskProgress(tasks []Task) map[int64]Progress {
+	progress := map[int64]Progress{}
+	for _, task := range tasks {
+		if task.ParentID == 0 {
+			continue
+		}
+		p := progress[task.ParentID]
+		p.Total++
+		if task.Done {
+			p.Done++
+		}
+		progress[task.ParentID] = p
+	}
+	return progress
+}
+
+// TaskDepth returns how deeply the task with id is nested in tasks, 1 for a
+// top-level task and 0 when there is no such task.
+func TaskDepth(tasks []Task, id int64) int {
+	parents := make(map[int64]int64, len(tasks))
+	for _, task := range tasks {
+		parents[task.ID] = task.ParentID
+	}
+
+	depth := 0
+	for id != 0 && depth <= len(tasks) {
+		parentID, ok := parents[id]
+		if !ok {
+			break
+		}
+		depth++
+		id = parentID
+	}
+	return depth
+}
+
+// Descendants returns the ids of all subtasks below the task with id, however
+// deeply nested.
+func Descendants(tasks []Task, id int64) map[int64]bool {
+	children := map[int64][]int64{}
+	for _, task := range tasks {
+		if task.ParentID != 0 {
+			children[task.ParentID] = append(children[task.ParentID], task.ID)
+		}
+	}
+
+	descendants := map[int64]bool{}
+	queue := children[id]
+	for len(queue) > 0 {
+		childID := queue[0]
+		queue = queue[1:]
+		if descendants[childID] || childID == id {
+			continue
+		}
+		descendants[childID] = true
+		queue = append(queue, children[childID]...)
+	}
+	return descendants
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"maps"
+	"testing"
+	"time"
+)
+
+var nestedTasks = []Task{
+	{ID: 1},
+	{ID: 2, ParentID: 1, Status: 1, Done: true},
+	{ID: 3, ParentID: 2},
+	{ID: 4, ParentID: 1},
+	{ID: 5},
+}
+
+func TestTaskDepth(t *testing.T) {
+	tests := []struct {
+		id   int64
+		want int
+	}{
+		{id: 1, want: 1},
+		{id: 2, want: 2},
+		{id: 3, want: 3},
+		{id: 6, want: 0},
+	}
+	for _, tt := range tests {
+		got := TaskDepth(nestedTasks, tt.id)
+		if got != tt.want {
+			t.Errorf("TaskDepth(%d) = %d, want %d", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestDescendants(t *testing.T) {
+	got := Descendants(nestedTasks, 1)
+	want := map[int64]bool{2: true, 3: true, 4: true}
+	if !maps.Equal(got, want) {
+		t.Errorf("Descendants() = %v, want %v", got, want)
+	}
+
+	got = Descendants(nestedTasks, 5)
+	if len(got) != 0 {
+		t.Errorf("Descendants() of a leaf = %v, want none", got)
+	}
+}
+
+func TestSubtaskProgress(t *testing.T) {
+	got := SubtaskProgress(nestedTasks)
+	want := map[int64]Progress{
+		1: {Done: 1, Total: 2},
+		2: {Done: 0, Total: 1},
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("SubtaskProgress() = %v, want %v", got, want)
+	}
+}
+
+func TestTask_Progress(t *testing.T) {
+	task := Task{
+		Checklist: []ChecklistItem{
+			{Text: "one", Done: true},
+			{Text: "two", Done: true},
+			{Text: "three"},
+		},
+		Subtasks: Progress{Done: 1, Total: 2},
+	}
+
+	got := task.ToDto(DefaultWorkflow(0), nil, time.Now()).Progress
+	if got != "3/5 done" {
+		t.Errorf("ToDto().Progress = %q, want %q", got, "3/5 done")
+	}
+
+	got = Task{}.ToDto(DefaultWorkflow(0), nil, time.Now()).Progress
+	if got != "" {
+		t.Errorf("ToDto().Progress without parts = %q, want none", got)
+	}
+}
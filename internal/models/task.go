@@ -1,45 +1,196 @@
 package models
 
-import "strconv"
+import (
+	"slices"
+	"strconv"
+	"time"
+)
 
 type Task struct {
-	ID     int64  `json:"id"`
-	Title  string `json:"title"`
-	Desc   string `json:"desc"`
-	Status int    `json:"status"`
-	UserID int64  `json:"user_id"`
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+	Desc  string `json:"desc"`
+	// Status is the id of a status of the user's workflow.
+	Status int `json:"status"`
+	// Done records whether Status is terminal, so the rules around subtasks
+	// don't need the workflow.
+	Done     bool  `json:"done,omitempty"`
+	Priority int   `json:"priority"`
+	UserID   int64 `json:"user_id"`
+	ParentID int64 `json:"parent_id,omitempty"`
+	// ProjectID groups the task into a project, 0 is no project.
+	ProjectID int64           `json:"project_id,omitempty"`
+	LabelIDs  []int64         `json:"label_ids,omitempty"`
+	DueAt     time.Time       `json:"due_at,omitzero"`
+	StartAt   time.Time       `json:"start_at,omitzero"`
+	Checklist []ChecklistItem `json:"checklist,omitempty"`
+	// Recurrence makes the task repeat, nil and a zero Recurrence don't.
+	Recurrence *Recurrence `json:"recurrence,omitempty"`
+	// SeriesID links an occurrence of a recurring task to the first task of
+	// its series.
+	SeriesID int64 `json:"series_id,omitempty"`
+	// CreatedAt and UpdatedAt are set by the service. An update replaces
+	// everything but the id, owner, parent, series and CreatedAt.
+	CreatedAt time.Time `json:"created_at,omitzero"`
+	UpdatedAt time.Time `json:"updated_at,omitzero"`
+	// Version counts the changes to the task, starting at 1. On update a
+	// Version other than 0 is the one the change is based on and has to
+	// still be current.
+	Version int64 `json:"version,omitempty"`
+	// DeletedAt is when the task was moved to the trash, zero while it is
+	// not in there.
+	DeletedAt time.Time `json:"deleted_at,omitzero"`
+	// Subtasks tallies the direct subtasks of the task. It is filled in when
+	// listing tasks and never stored.
+	Subtasks Progress `json:"-"`
 }
 
-func (t Task) IsValidTask() bool {
-	if t.Title == "" || t.Desc == "" || !t.IsValidStatus() {
-		return false
+func (t Task) PriorityAsText() string {
+	switch t.Priority {
+	case 0:
+		return "None"
+	case 1:
+		return "Low"
+	case 2:
+		return "Medium"
+	case 3:
+		return "High"
+	case 4:
+		return "Urgent"
+	default:
+		return "Invalid Priority"
 	}
+}
+
+func (t Task) IsValidPriority() bool {
+	return t.Priority >= 0 && t.Priority <= 4
+}
+
+// HasValidDates reports whether the task does not start after it is due.
+func (t Task) HasValidDates() bool {
+	return t.StartAt.IsZero() || t.DueAt.IsZero() || !t.StartAt.After(t.DueAt)
+}
 
+// HasValidRecurrence reports whether a recurring task has a valid rule and a
+// due date to count occurrences from.
+func (t Task) HasValidRecurrence() bool {
+	return t.Recurrence == nil || (t.Recurrence.IsValid() && !t.DueAt.IsZero())
+}
+
+// IsRecurring reports whether the task repeats.
+func (t Task) IsRecurring() bool {
+	return t.Recurrence != nil && t.Recurrence.Freq != ""
+}
+
+// HasLabel reports whether the task carries the label with the given id.
+func (t Task) HasLabel(labelID int64) bool {
+	return slices.Contains(t.LabelIDs, labelID)
+}
+
+// IsDueBetween reports whether the task is due after after and before
+// before, a zero bound is ignored. Tasks without a due date only pass when
+// both bounds are zero.
+func (t Task) IsDueBetween(after, before time.Time) bool {
+	if after.IsZero() && before.IsZero() {
+		return true
+	}
+	if t.DueAt.IsZero() {
+		return false
+	}
+	if !after.IsZero() && !t.DueAt.After(after) {
+		return false
+	}
+	if !before.IsZero() && !t.DueAt.Before(before) {
+		return false
+	}
 	return true
 }
 
-func (t Task) StatusAsText() string {
-	switch t.Status {
-	case 0:
-		return "Pending"
-	case 1:
-		return "Done"
-	case 2:
-		return "Waiting"
-	default:
-		return "Invalid Status"
+// IsOverdue reports whether the task has passed its due date at now without
+// being done.
+func (t Task) IsOverdue(now time.Time) bool {
+	return !t.DueAt.IsZero() && now.After(t.DueAt) && !t.Done
+}
+
+// Progress counts the done checklist items and direct subtasks of the task.
+func (t Task) Progress() Progress {
+	p := Progress{Total: len(t.Checklist)}
+	for _, item := range t.Checklist {
+		if item.Done {
+			p.Done++
+		}
+	}
+	return p.Add(t.Subtasks)
+}
+
+// ToDto renders the task for a user with the workflow in the time zone loc,
+// as it stands at now.
+func (t Task) ToDto(workflow Workflow, loc *time.Location, now time.Time) TaskResponseDto {
+	dto := TaskResponseDto{
+		ID:        strconv.FormatInt(t.ID, 10),
+		Title:     t.Title,
+		Desc:      t.Desc,
+		Status:    workflow.StatusName(t.Status),
+		Priority:  t.PriorityAsText(),
+		DueAt:     FormatTaskTime(t.DueAt, loc),
+		StartAt:   FormatTaskTime(t.StartAt, loc),
+		Overdue:   t.IsOverdue(now),
+		LabelIDs:  formatIDs(t.LabelIDs),
+		ParentID:  formatID(t.ParentID),
+		Checklist: t.Checklist,
+		Progress:  t.Progress().String(),
+		SeriesID:  formatID(t.SeriesID),
+		ProjectID: formatID(t.ProjectID),
+		CreatedAt: FormatTaskTime(t.CreatedAt, loc),
+		UpdatedAt: FormatTaskTime(t.UpdatedAt, loc),
+		Version:   t.Version,
+		DeletedAt: FormatTaskTime(t.DeletedAt, loc),
+	}
+	if t.IsRecurring() {
+		dto.Recurrence = t.Recurrence.String()
+	}
+	return dto
+}
+
+// ToRequestDto renders the task as the request that replaces it by itself,
+// with its status named status. Patches are applied to it.
+func (t Task) ToRequestDto(status string, loc *time.Location) TaskRequestDto {
+	dto := TaskRequestDto{
+		Title:     t.Title,
+		Desc:      t.Desc,
+		Status:    status,
+		Priority:  t.PriorityAsText(),
+		DueAt:     FormatTaskTime(t.DueAt, loc),
+		StartAt:   FormatTaskTime(t.StartAt, loc),
+		LabelIDs:  formatIDs(t.LabelIDs),
+		Checklist: t.Checklist,
+	}
+	if t.IsRecurring() {
+		recurrence := t.Recurrence.String()
+		dto.Recurrence = &recurrence
+	}
+	if t.ProjectID != 0 {
+		projectID := formatID(t.ProjectID)
+		dto.ProjectID = &projectID
 	}
+	return dto
 }
 
-func (t Task) IsValidStatus() bool {
-	return t.Status == 0 || t.Status == 1 || t.Status == 2
+// formatID renders an optional id, 0 being none.
+func formatID(id int64) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.FormatInt(id, 10)
 }
 
-func (t Task) ToDto() TaskResponseDto {
-	return TaskResponseDto{
-		ID:     strconv.FormatInt(t.ID, 10),
-		Title:  t.Title,
-		Desc:   t.Desc,
-		Status: t.StatusAsText(),
+func formatIDs(ids []int64) []string {
+	if ids == nil {
+		return nil
+	}
+	strs := make([]string, len(ids))
+	for i, id := range ids {
+		strs[i] = strconv.FormatInt(id, 10)
 	}
+	return strs
 }
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+)
+
+// MaxBatchOperations caps the operations of a single batch.
+const MaxBatchOperations = 100
+
+// Operations of a task batch.
+const (
+	BatchCreate = "create"
+	// BatchUpdate replaces the task as a whole, like PUT /tasks/{id}.
+	BatchUpdate = "update"
+	// BatchPatch changes the task by a JSON Merge Patch, like PATCH
+	// /tasks/{id}.
+	BatchPatch  = "patch"
+	BatchDelete = "delete"
+)
+
+// Modes of a task batch.
+const (
+	// BatchAtomic applies every operation or, as soon as one fails, none.
+	BatchAtomic = "atomic"
+	// BatchPerItem applies the operations that succeed and reports why the
+	// others failed.
+	BatchPerItem = "per_item"
+)
+
+// TaskBatchDto is a list of operations on the tasks of a user, run in order.
+// Mode defaults to BatchAtomic.
+type TaskBatchDto struct {
+	Mode       string             `json:"mode,omitempty"`
+	Operations []TaskOperationDto `json:"operations"`
+}
+
+// TaskOperationDto is one operation of a batch. Task is the task to create
+// or the replacement of the task with ID, Patch is a merge patch of it.
+// Version works like If-Match, 0 skips the check.
+type TaskOperationDto struct {
+	Op      string          `json:"op"`
+	ID      string          `json:"id,omitempty"`
+	Version int64           `json:"version,omitempty"`
+	Task    *TaskRequestDto `json:"task,omitempty"`
+	Patch   json.RawMessage `json:"patch,omitempty"`
+}
+
+// Validate checks that every operation of the batch is complete, before any
+// of them runs.
+func (b TaskBatchDto) Validate() Violations {
+	var v Violations
+	if b.Mode != "" && b.Mode != BatchAtomic && b.Mode != BatchPerItem {
+		v.Add("mode", RuleUnknownValue, "Mode must be atomic or per_item")
+	}
+	if len(b.Operations) == 0 {
+		v.Add("operations", RuleRequired, "Operations are required")
+	}
+	if len(b.Operations) > MaxBatchOperations {
+		v.Add("operations", RuleMaxItems, fmt.Sprintf(
+			"A batch can have at most %d operations", MaxBatchOperations,
+		))
+	}
+
+	for i, op := range b.Operations {
+		field := fmt.Sprintf("operations[%d].", i)
+		switch op.Op {
+		case BatchCreate:
+			if op.Task == nil {
+				v.Add(field+"task", RuleRequired, "Task is required")
+			}
+		case BatchUpdate, BatchPatch, BatchDelete:
+			v.Required(field+"id", op.ID, "Task id")
+			if op.Op == BatchUpdate && op.Task == nil {
+				v.Add(field+"task", RuleRequired, "Task is required")
+			}
+			if op.Op == BatchPatch && len(op.Patch) == 0 {
+				v.Add(field+"patch", RuleRequired, "Patch is required")
+			}
+		default:
+			v.Add(field+"op", RuleUnknownValue, "Op must be create, update, patch or delete")
+		}
+	}
+	return v
+}
+
+// TaskOperationResult is the outcome of an operation of a batch: the task it
+// created, or the error it failed with.
+type TaskOperationResult struct {
+	Op   string
+	Task *TaskResponseDto
+	Err  *errr.AppError
+}
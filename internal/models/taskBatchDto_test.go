@@ -0,0 +1,67 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestTaskBatchDto_Validate(t *testing.T) {
+	task := &TaskRequestDto{Title: "title", Desc: "desc"}
+	tooMany := make([]TaskOperationDto, MaxBatchOperations+1)
+	for i := range tooMany {
+		tooMany[i] = TaskOperationDto{Op: BatchDelete, ID: "1"}
+	}
+	tests := []struct {
+		name  string
+		batch TaskBatchDto
+		want  []string
+	}{
+		{
+			name: "valid batch",
+			batch: TaskBatchDto{Mode: BatchPerItem, Operations: []TaskOperationDto{
+				{Op: BatchCreate, Task: task},
+				{Op: BatchUpdate, ID: "1", Task: task},
+				{Op: BatchPatch, ID: "1", Patch: json.RawMessage(`{"title":"other"}`)},
+				{Op: BatchDelete, ID: "2"},
+			}},
+		},
+		{
+			name:  "operations required",
+			batch: TaskBatchDto{Mode: "all"},
+			want:  []string{"mode:unknown_value", "operations:required"},
+		},
+		{
+			name:  "too many operations",
+			batch: TaskBatchDto{Operations: tooMany},
+			want:  []string{"operations:max_items"},
+		},
+		{
+			name: "incomplete operations",
+			batch: TaskBatchDto{Operations: []TaskOperationDto{
+				{Op: BatchCreate},
+				{Op: BatchUpdate},
+				{Op: BatchPatch, ID: "1"},
+				{Op: "move", ID: "1"},
+			}},
+			want: []string{
+				"operations[0].task:required",
+				"operations[1].id:required",
+				"operations[1].task:required",
+				"operations[2].patch:required",
+				"operations[3].op:unknown_value",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, v := range tt.batch.Validate() {
+				got = append(got, v.Field+":"+v.Rule)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
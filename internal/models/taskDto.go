@@ -1,42 +1,269 @@
 package models
 
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const (
+	maxTaskTitleLength = 200
+	maxTaskDescLength  = 5000
+)
+
+// localTaskTimeLayout is accepted next to RFC 3339 for task dates given
+// without an offset, which are read in the user's time zone.
+const localTaskTimeLayout = "2006-01-02T15:04:05"
+
+var ErrInvalidTaskTime = errors.New("task time must be RFC 3339")
+
+// ParseTaskTime parses a task date in RFC 3339, reading it in loc when it has
+// no offset. The result is in UTC, an empty value gives the zero time.
+func ParseTaskTime(value string, loc *time.Location) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err == nil {
+		return t.UTC(), nil
+	}
+
+	t, err = time.ParseInLocation(localTaskTimeLayout, value, loc)
+	if err == nil {
+		return t.UTC(), nil
+	}
+
+	return time.Time{}, ErrInvalidTaskTime
+}
+
+// FormatTaskTime formats a task date in RFC 3339 in loc, UTC when loc is
+// nil. The zero time gives an empty value.
+func FormatTaskTime(t time.Time, loc *time.Location) string {
+	if t.IsZero() {
+		return ""
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	return t.In(loc).Format(time.RFC3339)
+}
+
+// TaskRequestDto is a new task, or the whole of a task on update, where a
+// field left out clears it.
 type TaskRequestDto struct {
-	Title  string `json:"title,omitempty"`
-	Desc   string `json:"desc,omitempty"`
-	Status string `json:"status,omitempty"`
+	Title string `json:"title,omitempty"`
+	Desc  string `json:"desc,omitempty"`
+	// Status is ignored on create, new tasks start in the first status of
+	// the workflow. An update has to give it.
+	Status   string   `json:"status,omitempty"`
+	Priority string   `json:"priority,omitempty"`
+	DueAt    string   `json:"due_at,omitempty"`
+	StartAt  string   `json:"start_at,omitempty"`
+	LabelIDs []string `json:"label_ids,omitempty"`
+	// ParentID makes the new task a subtask, it is ignored on update.
+	ParentID  string          `json:"parent_id,omitempty"`
+	Checklist []ChecklistItem `json:"checklist,omitempty"`
+	// Recurrence is a rule like "FREQ=WEEKLY;BYDAY=MO", an empty rule is the
+	// same as none.
+	Recurrence *string `json:"recurrence,omitempty"`
+	// ProjectID puts the task into a project, an empty id is the same as
+	// none.
+	ProjectID *string `json:"project_id,omitempty"`
 }
 
-func (trd TaskRequestDto) IsValidStatus() bool {
-	switch trd.Status {
+// Formats a TaskPatch can be in.
+const (
+	// MergePatch is a JSON Merge Patch (RFC 7396) of the task.
+	MergePatch = "merge"
+	// JSONPatch is a JSON Patch (RFC 6902) of the task.
+	JSONPatch = "json"
+)
+
+// TaskPatch changes some fields of a task. Doc is applied to the task as
+// rendered by Task.ToRequestDto, where null and removing a field clear it.
+type TaskPatch struct {
+	Format string
+	Doc    []byte
+}
+
+func (trd TaskRequestDto) IsValidPriority() bool {
+	switch trd.Priority {
 	default:
 		return false
-	case "Pending", "Done", "Waiting":
+	case "None", "Low", "Medium", "High", "Urgent":
 		return true
 	}
 }
 
+// Validate checks a new task or the whole of a task on update, which needs
+// a title and a description, and a due date when it recurs. The status is
+// left to the workflow of the user.
+func (trd TaskRequestDto) Validate(loc *time.Location) Violations {
+	var v Violations
+	if v.Required("title", trd.Title, "Title") {
+		v.MaxLength("title", trd.Title, "Title", maxTaskTitleLength)
+	}
+	if v.Required("desc", trd.Desc, "Description") {
+		v.MaxLength("desc", trd.Desc, "Description", maxTaskDescLength)
+	}
+	trd.validateFields(&v, loc)
+	if trd.ParentID != "" {
+		parentID, err := strconv.ParseInt(trd.ParentID, 10, 64)
+		if err != nil || parentID == 0 {
+			v.Add("parent_id", RuleInvalidFormat, "Invalid parent task id")
+		}
+	}
+	if trd.Recurrence != nil && *trd.Recurrence != "" && trd.DueAt == "" {
+		v.Add("due_at", RuleRequired, "Recurring tasks need a due date")
+	}
+	return v
+}
+
+// validateFields checks the fields that aren't required.
+func (trd TaskRequestDto) validateFields(v *Violations, loc *time.Location) {
+	if trd.Priority != "" && !trd.IsValidPriority() {
+		v.Add("priority", RuleUnknownValue, "Invalid priority")
+	}
+
+	dueAt, dueErr := ParseTaskTime(trd.DueAt, loc)
+	if dueErr != nil {
+		v.Add("due_at", RuleInvalidFormat, "Invalid due date, use RFC 3339")
+	}
+	startAt, startErr := ParseTaskTime(trd.StartAt, loc)
+	if startErr != nil {
+		v.Add("start_at", RuleInvalidFormat, "Invalid start date, use RFC 3339")
+	}
+	if dueErr == nil && startErr == nil && !(Task{DueAt: dueAt, StartAt: startAt}).HasValidDates() {
+		v.Add("start_at", RuleDateOrder, "Start date must not be after due date")
+	}
+
+	if len(trd.Checklist) > maxChecklistItems {
+		v.Add("checklist", RuleMaxItems, fmt.Sprintf(
+			"Checklist must have at most %d items", maxChecklistItems,
+		))
+	}
+	for i, item := range trd.Checklist {
+		field := fmt.Sprintf("checklist[%d].text", i)
+		if v.Required(field, item.Text, "Checklist item text") {
+			v.MaxLength(field, item.Text, "Checklist item text", maxChecklistItemText)
+		}
+	}
+
+	if trd.Recurrence != nil && *trd.Recurrence != "" {
+		if _, err := ParseRecurrence(*trd.Recurrence); err != nil {
+			v.Add("recurrence", RuleInvalidFormat, "Invalid recurrence, use a rule like FREQ=WEEKLY;BYDAY=MO")
+		}
+	}
+}
+
+// ToTask converts the request into a task without a status, which is named
+// in the workflow of the user and resolved through it. A priority left out is
+// None.
 func (trd TaskRequestDto) ToTask() Task {
-	var status int
-	switch trd.Status {
-	case "Pending":
-		status = 0
-	case "Done":
-		status = 1
-	case "Waiting":
-		status = 2
+	var priority int
+	switch trd.Priority {
+	case "", "None":
+		priority = 0
+	case "Low":
+		priority = 1
+	case "Medium":
+		priority = 2
+	case "High":
+		priority = 3
+	case "Urgent":
+		priority = 4
 	default:
-		status = -1
+		priority = -1
 	}
 	return Task{
-		Title:  trd.Title,
-		Desc:   trd.Desc,
-		Status: status,
+		Title:     trd.Title,
+		Desc:      trd.Desc,
+		Priority:  priority,
+		Checklist: trd.Checklist,
+	}
+}
+
+// ToTaskIn is ToTask with the due and start dates parsed in loc and the
+// recurrence rule parsed. It fails with ErrInvalidTaskTime or
+// ErrInvalidRecurrence.
+func (trd TaskRequestDto) ToTaskIn(loc *time.Location) (Task, error) {
+	task := trd.ToTask()
+
+	dueAt, err := ParseTaskTime(trd.DueAt, loc)
+	if err != nil {
+		return Task{}, err
 	}
+	startAt, err := ParseTaskTime(trd.StartAt, loc)
+	if err != nil {
+		return Task{}, err
+	}
+
+	task.DueAt = dueAt
+	task.StartAt = startAt
+
+	if trd.Recurrence != nil {
+		task.Recurrence = &Recurrence{}
+		if *trd.Recurrence != "" {
+			recurrence, err := ParseRecurrence(*trd.Recurrence)
+			if err != nil {
+				return Task{}, err
+			}
+			task.Recurrence = &recurrence
+		}
+	}
+
+	return task, nil
 }
 
 type TaskResponseDto struct {
-	ID     string `json:"id"`
-	Title  string `json:"title"`
-	Desc   string `json:"desc"`
-	Status string `json:"status"`
+	ID       string   `json:"id"`
+	Title    string   `json:"title"`
+	Desc     string   `json:"desc"`
+	Status   string   `json:"status"`
+	Priority string   `json:"priority,omitempty"`
+	DueAt    string   `json:"due_at,omitempty"`
+	StartAt  string   `json:"start_at,omitempty"`
+	Overdue  bool     `json:"overdue"`
+	LabelIDs []string `json:"label_ids,omitempty"`
+	ParentID string   `json:"parent_id,omitempty"`
+	// Progress is e.g. "3/5 done" over checklist items and direct subtasks.
+	Progress   string          `json:"progress,omitempty"`
+	Checklist  []ChecklistItem `json:"checklist,omitempty"`
+	Recurrence string          `json:"recurrence,omitempty"`
+	SeriesID   string          `json:"series_id,omitempty"`
+	ProjectID  string          `json:"project_id,omitempty"`
+	CreatedAt  string          `json:"created_at,omitempty"`
+	UpdatedAt  string          `json:"updated_at,omitempty"`
+	// Version is sent back as the ETag of the task.
+	Version   int64  `json:"version,omitempty"`
+	DeletedAt string `json:"deleted_at,omitempty"`
+}
+
+// TaskPageDto is a page of a task listing. NextCursor is passed as after to
+// get the next page and is empty on the last one.
+type TaskPageDto struct {
+	Tasks      []TaskResponseDto `json:"tasks"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+}
+
+// OccurrenceDto is an upcoming instance of a recurring task.
+type OccurrenceDto struct {
+	DueAt   string `json:"due_at"`
+	StartAt string `json:"start_at,omitempty"`
+}
+
+// TaskFilterDto holds the filters and ordering of a task listing as sent by
+// the client.
+type TaskFilterDto struct {
+	DueBefore string
+	DueAfter  string
+	Status    string
+	Sort      string
+	Order     string
+	Label     string
+	Project   string
+	Limit     string
+	After     string
 }
@@ -1,118 +1,207 @@
 package models
 
 import (
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
-func TestTaskRequestDto_IsValidStatus(t *testing.T) {
+func TestTaskRequestDto_ToTask(t *testing.T) {
+	taskreq := TaskRequestDto{
+		Title:    "title",
+		Desc:     "desc",
+		Status:   "Done",
+		Priority: "High",
+	}
+	// the status is resolved through the workflow of the user.
+	want := Task{
+		Title:    "title",
+		Desc:     "desc",
+		Priority: 3,
+	}
+	got := taskreq.ToTask()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ToTask() = %v, want %v", got, want)
+	}
+}
+
+func TestParseTaskTime(t *testing.T) {
+	newYork, _ := time.LoadLocation("America/New_York")
 	tests := []struct {
-		name    string // description of this test case
-		taskreq TaskRequestDto
-		want    bool
+		name    string
+		value   string
+		want    time.Time
+		wantErr bool
 	}{
 		{
-			name: "status is pending",
-			taskreq: TaskRequestDto{
-				Status: "Pending",
-			},
-			want: true,
+			name:  "empty value",
+			value: "",
+			want:  time.Time{},
 		},
 		{
-			name: "status is done",
-			taskreq: TaskRequestDto{
-				Status: "Done",
-			},
-			want: true,
+			name:  "rfc 3339 with offset",
+			value: "2020-01-02T10:00:00+05:30",
+			want:  time.Date(2020, time.January, 2, 4, 30, 0, 0, time.UTC),
 		},
 		{
-			name: "status is Waiting",
-			taskreq: TaskRequestDto{
-				Status: "Waiting",
-			},
-			want: true,
+			name:  "without offset is read in the user's time zone",
+			value: "2020-01-02T10:00:00",
+			want:  time.Date(2020, time.January, 2, 15, 0, 0, 0, time.UTC),
 		},
 		{
-			name: "status is invalid",
-			taskreq: TaskRequestDto{
-				Status: "Pend",
-			},
-			want: false,
+			name:    "invalid value",
+			value:   "tomorrow",
+			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := tt.taskreq.IsValidStatus()
+			got, gotErr := ParseTaskTime(tt.value, newYork)
+			if gotErr != nil {
+				if !tt.wantErr {
+					t.Errorf("ParseTaskTime() failed: %v", gotErr)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Fatal("ParseTaskTime() succeeded unexpectedly")
+			}
 			if got != tt.want {
-				t.Errorf("IsValidStatus() = %v, want %v", got, tt.want)
+				t.Errorf("ParseTaskTime() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-func TestTaskRequestDto_ToTask(t *testing.T) {
+func TestTaskRequestDto_ToTaskIn(t *testing.T) {
+	taskreq := TaskRequestDto{
+		Title:   "title",
+		Desc:    "desc",
+		Status:  "Done",
+		DueAt:   "2020-01-02T10:00:00Z",
+		StartAt: "2020-01-01T10:00:00",
+	}
+	want := Task{
+		Title:   "title",
+		Desc:    "desc",
+		DueAt:   time.Date(2020, time.January, 2, 10, 0, 0, 0, time.UTC),
+		StartAt: time.Date(2020, time.January, 1, 10, 0, 0, 0, time.UTC),
+	}
+	got, err := taskreq.ToTaskIn(time.UTC)
+	if err != nil {
+		t.Fatalf("ToTaskIn() failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ToTaskIn() = %v, want %v", got, want)
+	}
+
+	taskreq.DueAt = "soon"
+	_, err = taskreq.ToTaskIn(time.UTC)
+	if err == nil {
+		t.Errorf("ToTaskIn() succeeded unexpectedly with an invalid due date")
+	}
+}
+
+func TestTaskRequestDto_Priority(t *testing.T) {
 	tests := []struct {
-		name    string // description of this test case
+		name      string
+		priority  string
+		wantValid bool
+		want      int
+	}{
+		{name: "none", priority: "None", wantValid: true, want: 0},
+		{name: "low", priority: "Low", wantValid: true, want: 1},
+		{name: "medium", priority: "Medium", wantValid: true, want: 2},
+		{name: "high", priority: "High", wantValid: true, want: 3},
+		{name: "urgent", priority: "Urgent", wantValid: true, want: 4},
+		{name: "not given", priority: "", wantValid: false, want: 0},
+		{name: "invalid", priority: "asap", wantValid: false, want: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskreq := TaskRequestDto{Priority: tt.priority}
+			if got := taskreq.IsValidPriority(); got != tt.wantValid {
+				t.Errorf("IsValidPriority() = %v, want %v", got, tt.wantValid)
+			}
+			if got := taskreq.ToTask().Priority; got != tt.want {
+				t.Errorf("ToTask().Priority = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskRequestDto_Validate(t *testing.T) {
+	rule := "FREQ=WEEKLY"
+	badRule := "FREQ=HOURLY"
+	tests := []struct {
+		name    string
 		taskreq TaskRequestDto
-		want    Task
+		want    []string
 	}{
 		{
-			name: "task in pending",
-			taskreq: TaskRequestDto{
-				Title:  "title",
-				Desc:   "desc",
-				Status: "Pending",
-			},
-			want: Task{
-				Title:  "title",
-				Desc:   "desc",
-				Status: 0,
-			},
+			name:    "valid task",
+			taskreq: TaskRequestDto{Title: "title", Desc: "desc", Priority: "High"},
 		},
 		{
-			name: "task in done",
-			taskreq: TaskRequestDto{
-				Title:  "title",
-				Desc:   "desc",
-				Status: "Done",
-			},
-			want: Task{
-				Title:  "title",
-				Desc:   "desc",
-				Status: 1,
-			},
+			name:    "title and desc required",
+			taskreq: TaskRequestDto{},
+			want:    []string{"title:required", "desc:required"},
 		},
 		{
-			name: "task in Waiting",
+			name:    "title too long",
+			taskreq: TaskRequestDto{Title: strings.Repeat("é", 201), Desc: "desc"},
+			want:    []string{"title:max_length"},
+		},
+		{
+			name: "every field reported",
 			taskreq: TaskRequestDto{
-				Title:  "title",
-				Desc:   "desc",
-				Status: "Waiting",
+				Title:     "title",
+				Desc:      "desc",
+				Priority:  "asap",
+				DueAt:     "soon",
+				StartAt:   "later",
+				ParentID:  "abc",
+				Checklist: []ChecklistItem{{Text: "ok"}, {Text: ""}, {Text: strings.Repeat("a", 201)}},
 			},
-			want: Task{
-				Title:  "title",
-				Desc:   "desc",
-				Status: 2,
+			want: []string{
+				"priority:unknown_value",
+				"due_at:invalid_format",
+				"start_at:invalid_format",
+				"checklist[1].text:required",
+				"checklist[2].text:max_length",
+				"parent_id:invalid_format",
 			},
 		},
 		{
-			name: "task in invalid status",
+			name: "start after due",
 			taskreq: TaskRequestDto{
-				Title:  "title",
-				Desc:   "desc",
-				Status: "asdfj",
-			},
-			want: Task{
-				Title:  "title",
-				Desc:   "desc",
-				Status: -1,
+				Title:   "title",
+				Desc:    "desc",
+				DueAt:   "2020-01-01T10:00:00Z",
+				StartAt: "2020-01-02T10:00:00Z",
 			},
+			want: []string{"start_at:date_order"},
+		},
+		{
+			name:    "recurring task without a due date",
+			taskreq: TaskRequestDto{Title: "title", Desc: "desc", Recurrence: &rule},
+			want:    []string{"due_at:required"},
+		},
+		{
+			name:    "invalid recurrence",
+			taskreq: TaskRequestDto{Title: "title", Desc: "desc", DueAt: "2020-01-01T10:00:00Z", Recurrence: &badRule},
+			want:    []string{"recurrence:invalid_format"},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := tt.taskreq.ToTask()
-			if got != tt.want {
-				t.Errorf("ToTask() = %v, want %v", got, tt.want)
+			var got []string
+			for _, v := range tt.taskreq.Validate(time.UTC) {
+				got = append(got, v.Field+":"+v.Rule)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %v, want %v", got, tt.want)
 			}
 		})
 	}
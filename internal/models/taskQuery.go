@@ -0,0 +1,195 @@
+package models
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+// The orders a task listing can be sorted in. Every order breaks ties by task
+// id so pages never overlap.
+const (
+	TaskSortCreated = "created"
+	TaskSortUpdated = "updated"
+	TaskSortTitle   = "title"
+	// TaskSortDue lists the earliest due tasks first, undated ones last.
+	TaskSortDue = "due"
+	// TaskSortPriority lists the most urgent tasks first, tasks of the same
+	// priority by due date.
+	TaskSortPriority = "priority"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// TaskQuery selects tasks of a user, a page at a time when Limit is set.
+type TaskQuery struct {
+	UserID int64
+	// Status keeps the tasks in the status with this id, -1 keeps all.
+	Status int
+	// DueAfter and DueBefore keep the tasks due between them, a zero bound is
+	// ignored. Tasks without a due date only pass when both are zero.
+	DueAfter  time.Time
+	DueBefore time.Time
+	LabelID   int64
+	ProjectID int64
+	// ParentIDs keeps the direct subtasks of these tasks, nil keeps all.
+	ParentIDs []int64
+	// IDs keeps the tasks with these ids, nil keeps all.
+	IDs []int64
+	// Trashed also selects the tasks in the trash.
+	Trashed bool
+	Sort    string
+	// Reverse flips the order Sort lists tasks in.
+	Reverse bool
+	// After skips the tasks up to and including the one the cursor points at.
+	After *TaskCursor
+	// Limit caps the number of tasks, 0 returns all of them.
+	Limit int
+}
+
+// NewTaskQuery selects every task of the user, oldest first.
+func NewTaskQuery(userID int64) TaskQuery {
+	return TaskQuery{
+		UserID: userID,
+		Status: -1,
+		Sort:   TaskSortCreated,
+	}
+}
+
+// Matches reports whether task passes the filters of the query and comes
+// after its cursor. Tasks in the trash only match when the query is Trashed.
+func (q TaskQuery) Matches(task Task) bool {
+	return task.UserID == q.UserID && (q.Trashed || !task.InTrash()) &&
+		(q.Status < 0 || task.Status == q.Status) &&
+		task.IsDueBetween(q.DueAfter, q.DueBefore) &&
+		(q.LabelID == 0 || task.HasLabel(q.LabelID)) &&
+		(q.ProjectID == 0 || task.ProjectID == q.ProjectID) &&
+		(q.ParentIDs == nil || slices.Contains(q.ParentIDs, task.ParentID)) &&
+		(q.IDs == nil || slices.Contains(q.IDs, task.ID)) &&
+		(q.After == nil || q.Compare(task, q.After.task()) > 0)
+}
+
+// Compare orders a and b the way the query lists them.
+func (q TaskQuery) Compare(a, b Task) int {
+	c := compareTaskKeys(q.Sort, a, b)
+	if c == 0 {
+		c = cmp.Compare(a.ID, b.ID)
+	}
+	if q.Reverse {
+		return -c
+	}
+	return c
+}
+
+// Apply selects the tasks of the query from tasks, in order.
+func (q TaskQuery) Apply(tasks []Task) []Task {
+	selected := []Task{}
+	for _, task := range tasks {
+		if q.Matches(task) {
+			selected = append(selected, task)
+		}
+	}
+	slices.SortFunc(selected, q.Compare)
+	if q.Limit > 0 && len(selected) > q.Limit {
+		selected = selected[:q.Limit]
+	}
+	return selected
+}
+
+// CursorAt returns the cursor of the page ending with task.
+func (q TaskQuery) CursorAt(task Task) TaskCursor {
+	c := TaskCursor{Sort: q.Sort, Reverse: q.Reverse, ID: task.ID}
+	switch q.Sort {
+	case TaskSortUpdated:
+		c.UpdatedAt = task.UpdatedAt
+	case TaskSortTitle:
+		c.Title = task.Title
+	case TaskSortDue:
+		c.DueAt = task.DueAt
+	case TaskSortPriority:
+		c.Priority = task.Priority
+		c.DueAt = task.DueAt
+	default:
+		c.CreatedAt = task.CreatedAt
+	}
+	return c
+}
+
+func compareTaskKeys(sort string, a, b Task) int {
+	switch sort {
+	case TaskSortUpdated:
+		return a.UpdatedAt.Compare(b.UpdatedAt)
+	case TaskSortTitle:
+		return strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+	case TaskSortDue:
+		return compareDue(a, b)
+	case TaskSortPriority:
+		if a.Priority != b.Priority {
+			return cmp.Compare(b.Priority, a.Priority)
+		}
+		return compareDue(a, b)
+	default:
+		return a.CreatedAt.Compare(b.CreatedAt)
+	}
+}
+
+// compareDue orders tasks by due date with undated ones last.
+func compareDue(a, b Task) int {
+	if a.DueAt.IsZero() != b.DueAt.IsZero() {
+		if a.DueAt.IsZero() {
+			return 1
+		}
+		return -1
+	}
+	return a.DueAt.Compare(b.DueAt)
+}
+
+// TaskCursor points at the last task of a page. It carries the sort keys of
+// that task, so the next page starts right after it even when tasks were
+// added or removed in between.
+type TaskCursor struct {
+	Sort      string    `json:"sort"`
+	Reverse   bool      `json:"reverse,omitempty"`
+	ID        int64     `json:"id"`
+	Title     string    `json:"title,omitempty"`
+	Priority  int       `json:"priority,omitempty"`
+	DueAt     time.Time `json:"due_at,omitzero"`
+	CreatedAt time.Time `json:"created_at,omitzero"`
+	UpdatedAt time.Time `json:"updated_at,omitzero"`
+}
+
+// ParseTaskCursor reads a cursor written by TaskCursor.String.
+func ParseTaskCursor(s string) (TaskCursor, error) {
+	cursorjson, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return TaskCursor{}, ErrInvalidCursor
+	}
+
+	var c TaskCursor
+	err = json.Unmarshal(cursorjson, &c)
+	if err != nil || c.ID == 0 {
+		return TaskCursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// String encodes the cursor as an opaque token for clients.
+func (c TaskCursor) String() string {
+	cursorjson, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(cursorjson)
+}
+
+func (c TaskCursor) task() Task {
+	return Task{
+		ID:        c.ID,
+		Title:     c.Title,
+		Priority:  c.Priority,
+		DueAt:     c.DueAt,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+}
@@ -0,0 +1,131 @@
+package models
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func taskIDs(tasks []Task) []int64 {
+	ids := make([]int64, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	return ids
+}
+
+func TestTaskQuery_Apply(t *testing.T) {
+	day := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	tasks := []Task{
+		{ID: 1, UserID: 1, Title: "banana", Priority: 2, DueAt: day.AddDate(0, 0, 2), CreatedAt: day},
+		{ID: 2, UserID: 1, Title: "Apple", Priority: 4, CreatedAt: day, LabelIDs: []int64{7}},
+		{ID: 3, UserID: 1, Title: "cherry", Priority: 2, DueAt: day.AddDate(0, 0, 1), CreatedAt: day.Add(time.Hour)},
+		{ID: 4, UserID: 1, Title: "apple pie", Status: 1, DueAt: day.AddDate(0, 0, 1), ProjectID: 5},
+		{ID: 5, UserID: 1, Title: "date", Priority: 4, DueAt: day.AddDate(0, 0, 3), ParentID: 1},
+		{ID: 6, UserID: 2, Title: "elsewhere"},
+		{ID: 7, UserID: 1, Title: "fig", Status: 1, DeletedAt: day},
+	}
+
+	tests := []struct {
+		name string
+		edit func(q *TaskQuery)
+		want []int64
+	}{
+		{
+			name: "created keeps ties in id order",
+			want: []int64{4, 5, 1, 2, 3},
+		},
+		{
+			name: "reversed",
+			edit: func(q *TaskQuery) { q.Reverse = true },
+			want: []int64{3, 2, 1, 5, 4},
+		},
+		{
+			name: "title ignores case",
+			edit: func(q *TaskQuery) { q.Sort = TaskSortTitle },
+			want: []int64{2, 4, 1, 3, 5},
+		},
+		{
+			name: "due puts undated last",
+			edit: func(q *TaskQuery) { q.Sort = TaskSortDue },
+			want: []int64{3, 4, 1, 5, 2},
+		},
+		{
+			name: "priority puts the most urgent first",
+			edit: func(q *TaskQuery) { q.Sort = TaskSortPriority },
+			want: []int64{5, 2, 3, 1, 4},
+		},
+		{
+			name: "filtered",
+			edit: func(q *TaskQuery) {
+				q.DueAfter = day
+				q.DueBefore = day.AddDate(0, 0, 3)
+				q.Status = 0
+			},
+			want: []int64{1, 3},
+		},
+		{
+			name: "by label",
+			edit: func(q *TaskQuery) { q.LabelID = 7 },
+			want: []int64{2},
+		},
+		{
+			name: "with the trash",
+			edit: func(q *TaskQuery) { q.Trashed, q.Status = true, 1 },
+			want: []int64{4, 7},
+		},
+		{
+			name: "by id",
+			edit: func(q *TaskQuery) { q.IDs = []int64{3, 6, 7, 1} },
+			want: []int64{1, 3},
+		},
+		{
+			name: "subtasks",
+			edit: func(q *TaskQuery) { q.ParentIDs = []int64{1, 3} },
+			want: []int64{5},
+		},
+		{
+			name: "limited page after a cursor",
+			edit: func(q *TaskQuery) {
+				q.Sort = TaskSortTitle
+				c := q.CursorAt(tasks[1])
+				q.After = &c
+				q.Limit = 2
+			},
+			want: []int64{4, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewTaskQuery(1)
+			if tt.edit != nil {
+				tt.edit(&q)
+			}
+			if got := taskIDs(q.Apply(tasks)); !slices.Equal(got, tt.want) {
+				t.Errorf("Apply() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTaskCursor(t *testing.T) {
+	want := TaskCursor{
+		Sort:     TaskSortPriority,
+		Reverse:  true,
+		ID:       42,
+		Priority: 3,
+		DueAt:    time.Date(2025, time.January, 1, 9, 30, 0, 500, time.UTC),
+	}
+
+	got, err := ParseTaskCursor(want.String())
+	if err != nil || got != want {
+		t.Errorf("ParseTaskCursor() = %v, %v, want %v", got, err, want)
+	}
+
+	for _, s := range []string{"", "not base64!", "e30"} {
+		_, err = ParseTaskCursor(s)
+		if err != ErrInvalidCursor {
+			t.Errorf("ParseTaskCursor(%q) err = %v, want ErrInvalidCursor", s, err)
+		}
+	}
+}
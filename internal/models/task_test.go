@@ -1,169 +1,193 @@
 package models
 
 import (
+	"reflect"
 	"testing"
+	"time"
 )
 
-func TestTask_IsValidTask(t *testing.T) {
-	tests := []struct {
-		name string // description of this test case
-		want bool
-		task Task
-	}{
-		{
-			name: "task is valid",
-			want: true,
-			task: Task{
-				ID:     123,
-				Title:  "title",
-				Desc:   "desc",
-				Status: 1,
-			},
-		},
-		{
-			name: "invalid title",
-			want: false,
-			task: Task{
-				ID:     123,
-				Title:  "",
-				Desc:   "desc",
-				Status: 1,
-			},
-		},
-		{
-			name: "invalid desc",
-			want: false,
-			task: Task{
-				ID:     123,
-				Title:  "title",
-				Desc:   "",
-				Status: 1,
-			},
-		},
-		{
-			name: "invalid status`",
-			want: false,
-			task: Task{
-				ID:     123,
-				Title:  "title",
-				Desc:   "desc",
-				Status: -91,
-			},
-		},
+func TestTask_ToDto(t *testing.T) {
+	ta := Task{
+		Title:  "task title",
+		Desc:   "task desc",
+		ID:     12345,
+		Status: 1,
 	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := tt.task.IsValidTask()
-			if got != tt.want {
-				t.Errorf("IsValidTask() = %v, want %v", got, tt.want)
-			}
-		})
+	want := TaskResponseDto{
+		Title:    "task title",
+		Desc:     "task desc",
+		ID:       "12345",
+		Status:   "Done",
+		Priority: "None",
+	}
+	got := ta.ToDto(DefaultWorkflow(0), time.UTC, time.Now())
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ToDto() = %v, want %v", got, want)
 	}
 }
 
-func TestTask_StatusAsText(t *testing.T) {
+func TestTask_ToDto_Dates(t *testing.T) {
+	kolkata, _ := time.LoadLocation("Asia/Kolkata")
+	ta := Task{
+		ID:      12345,
+		Status:  0,
+		DueAt:   time.Date(2020, time.January, 2, 10, 0, 0, 0, time.UTC),
+		StartAt: time.Date(2020, time.January, 1, 10, 0, 0, 0, time.UTC),
+	}
+	want := TaskResponseDto{
+		ID:       "12345",
+		Status:   "Pending",
+		Priority: "None",
+		DueAt:    "2020-01-02T15:30:00+05:30",
+		StartAt:  "2020-01-01T15:30:00+05:30",
+		Overdue:  true,
+	}
+	got := ta.ToDto(DefaultWorkflow(0), kolkata, time.Date(2020, time.January, 3, 0, 0, 0, 0, time.UTC))
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ToDto() = %v, want %v", got, want)
+	}
+
+	got = ta.ToDto(DefaultWorkflow(0), kolkata, time.Date(2020, time.January, 2, 9, 0, 0, 0, time.UTC))
+	if got.Overdue {
+		t.Errorf("ToDto().Overdue before the due date = true, want false")
+	}
+}
+
+func TestTask_ToRequestDto(t *testing.T) {
+	kolkata, _ := time.LoadLocation("Asia/Kolkata")
+	recurrence, _ := ParseRecurrence("FREQ=WEEKLY;BYDAY=MO")
+	ta := Task{
+		ID:         12345,
+		Title:      "task title",
+		Desc:       "task desc",
+		Status:     7,
+		Priority:   3,
+		ParentID:   4,
+		ProjectID:  9,
+		LabelIDs:   []int64{2, 5},
+		DueAt:      time.Date(2020, time.January, 6, 10, 0, 0, 0, time.UTC),
+		Checklist:  []ChecklistItem{{Text: "item", Done: true}},
+		Recurrence: &recurrence,
+		Version:    3,
+	}
+	rule, projectID := "FREQ=WEEKLY;BYDAY=MO", "9"
+	want := TaskRequestDto{
+		Title:      "task title",
+		Desc:       "task desc",
+		Status:     "Review",
+		Priority:   "High",
+		DueAt:      "2020-01-06T15:30:00+05:30",
+		LabelIDs:   []string{"2", "5"},
+		Checklist:  []ChecklistItem{{Text: "item", Done: true}},
+		Recurrence: &rule,
+		ProjectID:  &projectID,
+	}
+	got := ta.ToRequestDto("Review", kolkata)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ToRequestDto() = %+v, want %+v", got, want)
+	}
+
+	got = Task{Title: "task title", Recurrence: &Recurrence{}}.ToRequestDto("Waiting", time.UTC)
+	want = TaskRequestDto{Title: "task title", Status: "Waiting", Priority: "None"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ToRequestDto() = %+v, want %+v", got, want)
+	}
+}
+
+func TestTask_HasValidDates(t *testing.T) {
+	day := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
 	tests := []struct {
-		name string // description of this test case
-		want string
+		name string
 		task Task
+		want bool
 	}{
 		{
-			name: "status Pending",
-			want: "Pending",
-			task: Task{
-				Status: 0,
-			},
+			name: "no dates",
+			task: Task{},
+			want: true,
 		},
 		{
-			name: "status Done",
-			want: "Done",
-			task: Task{
-				Status: 1,
-			},
+			name: "only due date",
+			task: Task{DueAt: day},
+			want: true,
 		},
 		{
-			name: "status Waiting",
-			want: "Waiting",
-			task: Task{
-				Status: 2,
-			},
+			name: "starts before due",
+			task: Task{StartAt: day, DueAt: day.Add(time.Hour)},
+			want: true,
 		},
 		{
-			name: "status invalid",
-			want: "Invalid Status",
-			task: Task{
-				Status: -1,
-			},
+			name: "starts after due",
+			task: Task{StartAt: day.Add(time.Hour), DueAt: day},
+			want: false,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := tt.task.StatusAsText()
-			if tt.want != got {
-				t.Errorf("StatusAsText() = %v, want %v.", got, tt.want)
+			got := tt.task.HasValidDates()
+			if got != tt.want {
+				t.Errorf("HasValidDates() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-func TestTask_IsValidStatus(t *testing.T) {
+func TestTask_IsOverdue(t *testing.T) {
+	now := time.Date(2020, time.January, 1, 12, 0, 0, 0, time.UTC)
 	tests := []struct {
-		name string // description of this test case
+		name string
 		task Task
 		want bool
 	}{
 		{
-			name: "status pending",
+			name: "no due date",
 			task: Task{},
-			want: true,
+			want: false,
 		},
 		{
-			name: "status is done",
-			task: Task{
-				Status: 1,
-			},
-			want: true,
+			name: "due in the future",
+			task: Task{DueAt: now.Add(time.Hour)},
+			want: false,
 		},
 		{
-			name: "status is waiting",
-			task: Task{
-				Status: 2,
-			},
+			name: "due in the past",
+			task: Task{DueAt: now.Add(-time.Hour)},
 			want: true,
 		},
 		{
-			name: "status is invalid",
-			task: Task{
-				Status: -1,
-			},
+			name: "due in the past but done",
+			task: Task{DueAt: now.Add(-time.Hour), Status: 1, Done: true},
 			want: false,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := tt.task.IsValidStatus()
-			if tt.want != got {
-				t.Errorf("IsValidStatus() = %v, want %v", got, tt.want)
+			got := tt.task.IsOverdue(now)
+			if got != tt.want {
+				t.Errorf("IsOverdue() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-func TestTask_ToDto(t *testing.T) {
-	ta := Task{
-		Title:  "task title",
-		Desc:   "task desc",
-		ID:     12345,
-		Status: 1,
-	}
-	want := TaskResponseDto{
-		Title:  "task title",
-		Desc:   "task desc",
-		ID:     "12345",
-		Status: "Done",
+func TestTask_PriorityAsText(t *testing.T) {
+	tests := []struct {
+		priority int
+		want     string
+	}{
+		{priority: 0, want: "None"},
+		{priority: 1, want: "Low"},
+		{priority: 2, want: "Medium"},
+		{priority: 3, want: "High"},
+		{priority: 4, want: "Urgent"},
+		{priority: -1, want: "Invalid Priority"},
 	}
-	got := ta.ToDto()
-	if got != want {
-		t.Errorf("ToDto() = %v, want %v", got, want)
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			got := Task{Priority: tt.priority}.PriorityAsText()
+			if got != tt.want {
+				t.Errorf("PriorityAsText() = %v, want %v", got, tt.want)
+			}
+		})
 	}
 }
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"cmp"
+	"slices"
+)
+
+// InTrash reports whether the task was deleted and can still be restored.
+func (t Task) InTrash() bool {
+	return !t.DeletedAt.IsZero()
+}
+
+// Trash returns the tasks the user deleted from tasks, most recently deleted
+// first. Subtasks deleted along with their parent are left out, they are
+// restored and purged with it.
+func Trash(tasks []Task, userID int64) []Task {
+	byID := make(map[int64]Task, len(tasks))
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+
+	trash := []Task{}
+	for _, task := range tasks {
+		if task.UserID != userID || !task.InTrash() {
+			continue
+		}
+		if parent, ok := byID[task.ParentID]; ok && parent.DeletedAt.Equal(task.DeletedAt) {
+			continue
+		}
+		trash = append(trash, task)
+	}
+	slices.SortFunc(trash, func(a, b Task) int {
+		return cmp.Or(b.DeletedAt.Compare(a.DeletedAt), cmp.Compare(a.ID, b.ID))
+	})
+	return trash
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTrash(t *testing.T) {
+	first := time.Date(2025, time.January, 1, 10, 0, 0, 0, time.UTC)
+	second := first.Add(time.Hour)
+	tasks := []Task{
+		{ID: 1, UserID: 1234, DeletedAt: first},
+		// Deleted with its parent.
+		{ID: 2, UserID: 1234, ParentID: 1, DeletedAt: first},
+		{ID: 3, UserID: 1234},
+		// Deleted on its own, before its parent.
+		{ID: 4, UserID: 1234, ParentID: 5, DeletedAt: first},
+		{ID: 5, UserID: 1234, DeletedAt: second},
+		{ID: 6, UserID: 4321, DeletedAt: second},
+	}
+
+	var got []int64
+	for _, task := range Trash(tasks, 1234) {
+		got = append(got, task.ID)
+	}
+	if want := []int64{5, 1, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Trash() = %v, want %v", got, want)
+	}
+
+	if NewTaskQuery(1234).Matches(tasks[0]) {
+		t.Errorf("Matches() selected a task in the trash")
+	}
+}
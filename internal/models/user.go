@@ -1,11 +1,116 @@
 package models
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	minUsernameLength = 3
+	maxUsernameLength = 32
+	minPasswordLength = 8
+	// maxPasswordLength is in bytes, bcrypt ignores whatever follows.
+	maxPasswordLength = 72
+)
+
+// Roles of users. Admins can manage the accounts of every user.
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 type User struct {
 	ID       int64  `json:"id"`
 	Username string `json:"username"`
 	Password string `json:"password"`
+	TimeZone string `json:"time_zone,omitempty"`
+	Role     string `json:"role,omitempty"`
+	// Disabled accounts can't sign in.
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// EffectiveRole is the role of the user, RoleUser for users stored before
+// roles existed.
+func (u User) EffectiveRole() string {
+	if u.Role == "" {
+		return RoleUser
+	}
+	return u.Role
+}
+
+// Validate checks a new user. Usernames are letters, digits, ".", "_" and
+// "-", and the password is 8 to 72 bytes long and differs from the username.
+func (u User) Validate() Violations {
+	var v Violations
+	if v.Required("username", u.Username, "Username") {
+		if len(u.Username) < minUsernameLength {
+			v.Add("username", RuleMinLength, fmt.Sprintf(
+				"Username must be at least %d characters", minUsernameLength,
+			))
+		}
+		v.MaxLength("username", u.Username, "Username", maxUsernameLength)
+		if !isUsernameCharset(u.Username) {
+			v.Add("username", RuleCharset, "Username may only contain letters, digits, '.', '_' and '-'")
+		}
+	}
+
+	v = append(v, u.ValidatePassword()...)
+
+	if !u.IsValidTimeZone() {
+		v.Add("time_zone", RuleUnknownValue, "Unknown time zone, use an IANA name like Europe/Berlin")
+	}
+	return v
+}
+
+// ValidatePassword checks the password of the user against the password
+// policy.
+func (u User) ValidatePassword() Violations {
+	var v Violations
+	if v.Required("password", u.Password, "Password") {
+		switch {
+		case len(u.Password) < minPasswordLength:
+			v.Add("password", RulePasswordPolicy, fmt.Sprintf(
+				"Password must be at least %d characters", minPasswordLength,
+			))
+		case len(u.Password) > maxPasswordLength:
+			v.Add("password", RulePasswordPolicy, fmt.Sprintf(
+				"Password must be at most %d bytes", maxPasswordLength,
+			))
+		case strings.EqualFold(u.Password, u.Username):
+			v.Add("password", RulePasswordPolicy, "Password must differ from the username")
+		}
+	}
+	return v
+}
+
+func isUsernameCharset(username string) bool {
+	for _, r := range username {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '.', r == '_', r == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// IsValidTimeZone reports whether the user's time zone is empty, meaning UTC,
+// or a known IANA time zone name.
+func (u User) IsValidTimeZone() bool {
+	_, err := time.LoadLocation(u.TimeZone)
+	return err == nil
 }
 
-func (u User) IsValidUser() bool {
-	return len(u.Username) > 0 && len(u.Password) > 7
+// ToDto renders the user without the password hash.
+func (u User) ToDto() UserResponseDto {
+	return UserResponseDto{
+		ID:       strconv.FormatInt(u.ID, 10),
+		Username: u.Username,
+		TimeZone: u.TimeZone,
+		Role:     u.EffectiveRole(),
+		Disabled: u.Disabled,
+	}
 }
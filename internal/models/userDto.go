@@ -3,11 +3,27 @@ package models
 type UserRequestDto struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
+	TimeZone string `json:"time_zone,omitempty"`
 }
 
 func (urd UserRequestDto) ToUser() User {
 	return User{
 		Username: urd.Username,
 		Password: urd.Password,
+		TimeZone: urd.TimeZone,
+		Role:     RoleUser,
 	}
 }
+
+type UserResponseDto struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	TimeZone string `json:"time_zone,omitempty"`
+	Role     string `json:"role"`
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+// PasswordResetDto sets a new password for a user.
+type PasswordResetDto struct {
+	Password string `json:"password"`
+}
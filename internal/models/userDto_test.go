@@ -11,9 +11,36 @@ func TestUserRequestDto_ToUser(t *testing.T) {
 		ID:       0,
 		Username: "my user",
 		Password: "my password",
+		Role:     RoleUser,
 	}
 	got := urd.ToUser()
 	if got != want {
 		t.Errorf("ToUser() = %v, want %v", got, want)
 	}
 }
+
+func TestUser_ToDto(t *testing.T) {
+	tests := []struct {
+		name string
+		user User
+		want UserResponseDto
+	}{
+		{
+			name: "user stored before roles",
+			user: User{ID: 77, Username: "jass", Password: "hash"},
+			want: UserResponseDto{ID: "77", Username: "jass", Role: RoleUser},
+		},
+		{
+			name: "disabled admin",
+			user: User{ID: 77, Username: "jass", Password: "hash", Role: RoleAdmin, Disabled: true},
+			want: UserResponseDto{ID: "77", Username: "jass", Role: RoleAdmin, Disabled: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.user.ToDto(); got != tt.want {
+				t.Errorf("ToDto() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,46 +1,69 @@
 package models
 
-import "testing"
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
 
-func TestUser_IsValidUser(t *testing.T) {
+func TestUser_Validate(t *testing.T) {
 	tests := []struct {
 		name string // description of this test case
 		user User
-		want bool
+		want []string
 	}{
 		{
 			name: "username empty",
-			user: User{
-				ID:       0,
-				Username: "",
-				Password: "qwerqteqetre",
-			},
-			want: false,
+			user: User{Username: "", Password: "qwerqteqetre"},
+			want: []string{"username:required"},
 		},
 		{
 			name: "short password",
-			user: User{
-				ID:       0,
-				Username: "user",
-				Password: "qwe",
-			},
-			want: false,
+			user: User{Username: "user", Password: "qwe"},
+			want: []string{"password:password_policy"},
+		},
+		{
+			name: "password too long for bcrypt",
+			user: User{Username: "user", Password: strings.Repeat("a", 73)},
+			want: []string{"password:password_policy"},
+		},
+		{
+			name: "password is the username",
+			user: User{Username: "username", Password: "UserName"},
+			want: []string{"password:password_policy"},
+		},
+		{
+			name: "username with a space",
+			user: User{Username: "a b", Password: "qwerqteqetre"},
+			want: []string{"username:charset"},
+		},
+		{
+			name: "short username and unknown time zone",
+			user: User{Username: "ab", Password: "qwerqteqetre", TimeZone: "Mars/Olympus_Mons"},
+			want: []string{"username:min_length", "time_zone:unknown_value"},
+		},
+		{
+			name: "everything missing",
+			user: User{},
+			want: []string{"username:required", "password:required"},
+		},
+		{
+			name: "valid user with time zone",
+			user: User{Username: "jane.doe-1", Password: "qwerqteqetre", TimeZone: "Europe/Berlin"},
 		},
 		{
 			name: "valid user",
-			user: User{
-				ID:       0,
-				Username: "user",
-				Password: "qwerqteqetre",
-			},
-			want: true,
+			user: User{Username: "user", Password: "qwerqteqetre"},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := tt.user.IsValidUser()
-			if got != tt.want {
-				t.Errorf("IsValidUser() = %v, want %v", got, tt.want)
+			var got []string
+			for _, v := range tt.user.Validate() {
+				got = append(got, v.Field+":"+v.Rule)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// Rules a field can break, reported to clients as the code of a field error.
+const (
+	RuleRequired       = "required"
+	RuleMaxLength      = "max_length"
+	RuleMinLength      = "min_length"
+	RuleMaxItems       = "max_items"
+	RuleCharset        = "charset"
+	RulePasswordPolicy = "password_policy"
+	RuleUnknownStatus  = "unknown_status"
+	RuleUnknownValue   = "unknown_value"
+	RuleInvalidFormat  = "invalid_format"
+	RuleDateOrder      = "date_order"
+)
+
+// Violation is a rule a field of a request breaks. Field is the path of the
+// field, like "checklist[2].text".
+type Violation struct {
+	Field   string
+	Rule    string
+	Message string
+}
+
+// Violations collects every rule a request breaks, in the order they were
+// checked.
+type Violations []Violation
+
+func (v *Violations) Add(field, rule, message string) {
+	*v = append(*v, Violation{Field: field, Rule: rule, Message: message})
+}
+
+// Required adds a violation when value is empty and reports whether it was
+// given.
+func (v *Violations) Required(field, value, name string) bool {
+	if value == "" {
+		v.Add(field, RuleRequired, name+" is required")
+		return false
+	}
+	return true
+}
+
+// MaxLength adds a violation when value has more than max characters.
+func (v *Violations) MaxLength(field, value, name string, max int) {
+	if utf8.RuneCountInString(value) > max {
+		v.Add(field, RuleMaxLength, fmt.Sprintf("%s must be at most %d characters", name, max))
+	}
+}
+
+// Message is the message of the only violation, or summary when there are
+// several.
+func (v Violations) Message(summary string) string {
+	if len(v) == 1 {
+		return v[0].Message
+	}
+	return summary
+}
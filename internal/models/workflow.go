@@ -0,0 +1,170 @@
+package models
+
+import (
+	"slices"
+	"strings"
+)
+
+// The statuses of the default workflow. Tasks stored before workflows could
+// be configured carry these values, so their ids are never given to other
+// statuses and StatusDone is always terminal.
+const (
+	StatusPending = 0
+	StatusDone    = 1
+	StatusWaiting = 2
+)
+
+const (
+	maxWorkflowStatuses     = 20
+	maxWorkflowStatusLength = 30
+)
+
+// WorkflowStatus is one step a task can be in.
+type WorkflowStatus struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	// Terminal statuses finish a task: it no longer counts as overdue, it
+	// counts as done for the progress of its parent and completing a
+	// recurring task starts its next occurrence.
+	Terminal bool `json:"terminal,omitempty"`
+	// Next lists the ids of the statuses a task may move to from this one,
+	// any status when empty.
+	Next []int `json:"next,omitempty"`
+}
+
+// Workflow is the ordered list of statuses of a user's tasks. New tasks start
+// in the first status.
+type Workflow struct {
+	UserID   int64            `json:"user_id"`
+	Statuses []WorkflowStatus `json:"statuses"`
+}
+
+// DefaultWorkflow is the workflow of a user who hasn't configured one.
+func DefaultWorkflow(userID int64) Workflow {
+	return Workflow{
+		UserID: userID,
+		Statuses: []WorkflowStatus{
+			{ID: StatusWaiting, Name: "Waiting"},
+			{ID: StatusPending, Name: "Pending"},
+			{ID: StatusDone, Name: "Done", Terminal: true},
+		},
+	}
+}
+
+func (w Workflow) IsValid() bool {
+	if len(w.Statuses) == 0 || len(w.Statuses) > maxWorkflowStatuses {
+		return false
+	}
+
+	terminal := false
+	for i, status := range w.Statuses {
+		if status.Name == "" || len(status.Name) > maxWorkflowStatusLength || status.ID < 0 {
+			return false
+		}
+		if status.ID == StatusDone && !status.Terminal {
+			return false
+		}
+		for _, other := range w.Statuses[:i] {
+			if other.ID == status.ID || strings.EqualFold(other.Name, status.Name) {
+				return false
+			}
+		}
+		for _, next := range status.Next {
+			if _, ok := w.Status(next); !ok {
+				return false
+			}
+		}
+		terminal = terminal || status.Terminal
+	}
+
+	return terminal
+}
+
+// Status returns the status with id.
+func (w Workflow) Status(id int) (WorkflowStatus, bool) {
+	i := slices.IndexFunc(w.Statuses, func(s WorkflowStatus) bool { return s.ID == id })
+	if i == -1 {
+		return WorkflowStatus{}, false
+	}
+	return w.Statuses[i], true
+}
+
+// StatusNamed returns the status called name, ignoring case.
+func (w Workflow) StatusNamed(name string) (WorkflowStatus, bool) {
+	i := slices.IndexFunc(w.Statuses, func(s WorkflowStatus) bool {
+		return strings.EqualFold(s.Name, name)
+	})
+	if i == -1 {
+		return WorkflowStatus{}, false
+	}
+	return w.Statuses[i], true
+}
+
+// StatusName returns the name of the status with id, "Invalid Status" when
+// the workflow has no such status.
+func (w Workflow) StatusName(id int) string {
+	status, ok := w.Status(id)
+	if !ok {
+		return "Invalid Status"
+	}
+	return status.Name
+}
+
+// MissingStatus returns the first of statuses the workflow has no status
+// with, if any.
+func (w Workflow) MissingStatus(statuses []int) (int, bool) {
+	for _, id := range statuses {
+		if _, ok := w.Status(id); !ok {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// Initial returns the status new tasks start in.
+func (w Workflow) Initial() WorkflowStatus {
+	return w.Statuses[0]
+}
+
+// CanMove reports whether a task may move from the status with id from to
+// the status with id to. Staying put is always allowed, and so is leaving a
+// status the workflow no longer has.
+func (w Workflow) CanMove(from, to int) bool {
+	if from == to {
+		return true
+	}
+	if _, ok := w.Status(to); !ok {
+		return false
+	}
+	status, ok := w.Status(from)
+	if !ok || len(status.Next) == 0 {
+		return true
+	}
+	return slices.Contains(status.Next, to)
+}
+
+// NextStatusID returns an id no status of the workflow has, above the ids of
+// the default workflow.
+func (w Workflow) NextStatusID() int {
+	id := StatusWaiting + 1
+	for _, status := range w.Statuses {
+		if status.ID >= id {
+			id = status.ID + 1
+		}
+	}
+	return id
+}
+
+func (w Workflow) ToDto() WorkflowDto {
+	dto := WorkflowDto{Statuses: make([]WorkflowStatusDto, len(w.Statuses))}
+	for i, status := range w.Statuses {
+		dto.Statuses[i] = WorkflowStatusDto{
+			Name:     status.Name,
+			Terminal: status.Terminal,
+		}
+		for _, next := range status.Next {
+			dto.Statuses[i].Next = append(dto.Statuses[i].Next, w.StatusName(next))
+		}
+	}
+	return dto
+}
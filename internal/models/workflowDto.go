@@ -0,0 +1,58 @@
+package models
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrUnknownStatus = errors.New("unknown status")
+
+// WorkflowDto lists the statuses of a workflow in order, transitions refer to
+// statuses by name.
+type WorkflowDto struct {
+	Statuses []WorkflowStatusDto `json:"statuses"`
+}
+
+type WorkflowStatusDto struct {
+	Name     string   `json:"name"`
+	Terminal bool     `json:"terminal"`
+	Next     []string `json:"next,omitempty"`
+}
+
+// ToWorkflow turns the request into a workflow replacing stored. A status
+// keeps its id when stored has one of the same name and terminal flag, so
+// tasks stay in it, others get new ids. It fails with ErrUnknownStatus when a
+// transition names a status the request doesn't have.
+func (wd WorkflowDto) ToWorkflow(stored Workflow) (Workflow, error) {
+	workflow := Workflow{
+		UserID:   stored.UserID,
+		Statuses: make([]WorkflowStatus, len(wd.Statuses)),
+	}
+
+	nextID := stored.NextStatusID()
+	for i, dto := range wd.Statuses {
+		name := strings.TrimSpace(dto.Name)
+		status, ok := stored.StatusNamed(name)
+		if !ok || status.Terminal != dto.Terminal {
+			status = WorkflowStatus{ID: nextID}
+			nextID++
+		}
+		workflow.Statuses[i] = WorkflowStatus{
+			ID:       status.ID,
+			Name:     name,
+			Terminal: dto.Terminal,
+		}
+	}
+
+	for i, dto := range wd.Statuses {
+		for _, name := range dto.Next {
+			next, ok := workflow.StatusNamed(strings.TrimSpace(name))
+			if !ok {
+				return Workflow{}, ErrUnknownStatus
+			}
+			workflow.Statuses[i].Next = append(workflow.Statuses[i].Next, next.ID)
+		}
+	}
+
+	return workflow, nil
+}
@@ -0,0 +1,169 @@
+package models
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+var reviewWorkflow = Workflow{
+	UserID: 1234,
+	Statuses: []WorkflowStatus{
+		{ID: 3, Name: "Backlog", Next: []int{4}},
+		{ID: 4, Name: "In progress", Next: []int{3, 5}},
+		{ID: 5, Name: "Review", Next: []int{4, StatusDone}},
+		{ID: StatusDone, Name: "Done", Terminal: true},
+	},
+}
+
+func TestWorkflow_IsValid(t *testing.T) {
+	tests := []struct {
+		name     string
+		workflow Workflow
+		want     bool
+	}{
+		{name: "default workflow", workflow: DefaultWorkflow(1), want: true},
+		{name: "custom workflow", workflow: reviewWorkflow, want: true},
+		{name: "no statuses", workflow: Workflow{}, want: false},
+		{
+			name: "no terminal status",
+			workflow: Workflow{Statuses: []WorkflowStatus{
+				{ID: 3, Name: "Open"},
+			}},
+			want: false,
+		},
+		{
+			name: "names differ only in case",
+			workflow: Workflow{Statuses: []WorkflowStatus{
+				{ID: 3, Name: "open"},
+				{ID: 4, Name: "Open", Terminal: true},
+			}},
+			want: false,
+		},
+		{
+			name: "transition to a missing status",
+			workflow: Workflow{Statuses: []WorkflowStatus{
+				{ID: 3, Name: "Open", Next: []int{9}},
+				{ID: 4, Name: "Closed", Terminal: true},
+			}},
+			want: false,
+		},
+		{
+			name: "built-in done is not terminal",
+			workflow: Workflow{Statuses: []WorkflowStatus{
+				{ID: StatusDone, Name: "Done"},
+				{ID: 4, Name: "Closed", Terminal: true},
+			}},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.workflow.IsValid()
+			if got != tt.want {
+				t.Errorf("IsValid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkflow_StatusName(t *testing.T) {
+	tests := []struct {
+		name string // description of this test case
+		id   int
+		want string
+	}{
+		{name: "status Pending", id: 0, want: "Pending"},
+		{name: "status Done", id: 1, want: "Done"},
+		{name: "status Waiting", id: 2, want: "Waiting"},
+		{name: "status invalid", id: -1, want: "Invalid Status"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DefaultWorkflow(1234).StatusName(tt.id)
+			if tt.want != got {
+				t.Errorf("StatusName() = %v, want %v.", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkflow_MissingStatus(t *testing.T) {
+	workflow := DefaultWorkflow(1234)
+	if id, ok := workflow.MissingStatus([]int{StatusDone, StatusPending}); ok {
+		t.Errorf("MissingStatus() = %d, want none", id)
+	}
+	if id, ok := workflow.MissingStatus([]int{StatusDone, 7, 8}); !ok || id != 7 {
+		t.Errorf("MissingStatus() = %d, %v, want 7", id, ok)
+	}
+}
+
+func TestWorkflow_CanMove(t *testing.T) {
+	tests := []struct {
+		from, to int
+		want     bool
+	}{
+		{from: 3, to: 4, want: true},
+		{from: 3, to: StatusDone, want: false},
+		{from: 5, to: StatusDone, want: true},
+		{from: 3, to: 3, want: true},
+		// Terminal statuses without transitions may go anywhere.
+		{from: StatusDone, to: 3, want: true},
+		// Tasks left in a status the workflow no longer has may leave it.
+		{from: StatusPending, to: 3, want: true},
+		{from: 3, to: 9, want: false},
+	}
+	for _, tt := range tests {
+		got := reviewWorkflow.CanMove(tt.from, tt.to)
+		if got != tt.want {
+			t.Errorf("CanMove(%d, %d) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestWorkflowDto_ToWorkflow(t *testing.T) {
+	req := WorkflowDto{Statuses: []WorkflowStatusDto{
+		{Name: "waiting"},
+		{Name: "Review", Next: []string{"Done"}},
+		{Name: "Done", Terminal: true},
+		// A status changing whether it is terminal is a new status.
+		{Name: "Pending", Terminal: true},
+	}}
+	want := Workflow{
+		UserID: 1234,
+		Statuses: []WorkflowStatus{
+			{ID: StatusWaiting, Name: "waiting"},
+			{ID: 3, Name: "Review", Next: []int{StatusDone}},
+			{ID: StatusDone, Name: "Done", Terminal: true},
+			{ID: 4, Name: "Pending", Terminal: true},
+		},
+	}
+
+	got, err := req.ToWorkflow(DefaultWorkflow(1234))
+	if err != nil {
+		t.Fatalf("ToWorkflow() failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ToWorkflow() = %v, want %v", got, want)
+	}
+
+	req.Statuses[1].Next = []string{"Closed"}
+	_, err = req.ToWorkflow(DefaultWorkflow(1234))
+	if !errors.Is(err, ErrUnknownStatus) {
+		t.Errorf("ToWorkflow() with an unknown transition = %v, want %v", err, ErrUnknownStatus)
+	}
+}
+
+func TestWorkflow_ToDto(t *testing.T) {
+	want := WorkflowDto{Statuses: []WorkflowStatusDto{
+		{Name: "Backlog", Next: []string{"In progress"}},
+		{Name: "In progress", Next: []string{"Backlog", "Review"}},
+		{Name: "Review", Next: []string{"In progress", "Done"}},
+		{Name: "Done", Terminal: true},
+	}}
+
+	got := reviewWorkflow.ToDto()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ToDto() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,5 @@
+package ports
+
+type IDGenerator interface {
+	NextID() int64
+}
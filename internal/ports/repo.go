@@ -1,20 +1,139 @@
 package ports
 
 import (
+	"time"
+
 	"github.com/Jashanveer-Singh/todo-go/internal/errr"
 	"github.com/Jashanveer-Singh/todo-go/internal/models"
 )
 
+// TaskRepo stores tasks and enforces how subtasks hang together: a subtask
+// joins a parent of the same user at most models.MaxTaskDepth deep, deleting
+// a task deletes its subtasks and a task can't be marked done while any of
+// its subtasks is unfinished.
+//
+// Deleted tasks go to the trash, where every method but the trash ones
+// treats them as gone.
 type TaskRepo interface {
-	SaveTask(task models.Task) *errr.AppError
+	// SaveTask stores task under a new id and returns it as stored.
+	SaveTask(task models.Task) (models.Task, *errr.AppError)
+	// UpdateTask replaces the stored task by task, keeping its id, owner,
+	// parent, series and creation time, and bumps its version. A
+	// task.Version other than 0 has to match the stored one.
 	UpdateTask(id int64, task models.Task) *errr.AppError
-	DeleteTask(id int64, userID int64) *errr.AppError
-	GetTasks(userId int64) ([]models.Task, *errr.AppError)
+	// DeleteTask moves the task and its subtasks to the trash at deletedAt,
+	// a version other than 0 has to match the one of the task.
+	DeleteTask(id int64, userID int64, version int64, deletedAt time.Time) *errr.AppError
+	// GetTask returns the task with id, which has to belong to the user.
+	GetTask(id int64, userID int64) (models.Task, *errr.AppError)
+	// GetTasks returns the tasks selected by the query in its order.
+	GetTasks(query models.TaskQuery) ([]models.Task, *errr.AppError)
+	// GetAllTasks returns the tasks of every user, to rebuild what is derived
+	// from them.
+	GetAllTasks() ([]models.Task, *errr.AppError)
+	// GetTrash returns the tasks the user deleted as models.Trash does.
+	GetTrash(userID int64) ([]models.Task, *errr.AppError)
+	// RestoreTask takes the task out of the trash along with the subtasks
+	// deleted with it. A subtask can't be restored while its parent is in
+	// the trash.
+	RestoreTask(id int64, userID int64) *errr.AppError
+	// PurgeTask deletes the task in the trash and its subtasks for good.
+	PurgeTask(id int64, userID int64) *errr.AppError
+	// PurgeTrash deletes every task of any user put in the trash before
+	// deletedBefore for good, returning how many it deleted.
+	PurgeTrash(deletedBefore time.Time) (int, *errr.AppError)
+	// Transaction runs fn with a TaskRepo whose changes are kept together
+	// when fn returns nil and dropped otherwise. Each of its calls is atomic
+	// on its own, so one that fails leaves the changes of the others.
+	Transaction(fn func(repo TaskRepo) *errr.AppError) *errr.AppError
+}
+
+type LabelRepo interface {
+	SaveLabel(label models.Label) (models.Label, *errr.AppError)
+	UpdateLabel(id int64, label models.Label) *errr.AppError
+	// DeleteLabel also detaches the label from every task, including a task
+	// saved by a Transaction that checked the label before it was deleted.
+	DeleteLabel(id int64, userID int64) *errr.AppError
+	GetLabels(userID int64) ([]models.Label, *errr.AppError)
+}
+
+// ProjectRepo stores projects. Deleting a project moves its tasks out of it.
+type ProjectRepo interface {
+	// SaveProject places the project after the other projects of its user.
+	SaveProject(project models.Project) (models.Project, *errr.AppError)
+	GetProject(id int64, userID int64) (models.Project, *errr.AppError)
+	UpdateProject(id int64, project models.Project) *errr.AppError
+	// DeleteProject also moves a task saved by a Transaction that checked the
+	// project before it was deleted out of it.
+	DeleteProject(id int64, userID int64) *errr.AppError
+	GetProjects(userID int64) ([]models.Project, *errr.AppError)
+}
+
+type WorkflowRepo interface {
+	// GetWorkflow returns models.DefaultWorkflow for a user who hasn't saved
+	// a workflow.
+	GetWorkflow(userID int64) (models.Workflow, *errr.AppError)
+	// SaveWorkflow replaces the workflow of the user. It fails with a
+	// conflict while a task of the user, in the trash too, is in a status the
+	// workflow lacks. The tasks are checked along with the save, so none can
+	// move into such a status meanwhile.
+	SaveWorkflow(workflow models.Workflow) *errr.AppError
 }
 
 type UserRepo interface {
+	GetUser(id int64) (models.User, *errr.AppError)
 	GetUserByUsername(username string) (models.User, *errr.AppError)
-	CreateUser(user models.User) *errr.AppError
+	// CreateUser stores user under a new id and returns it as stored.
+	CreateUser(user models.User) (models.User, *errr.AppError)
+	GetUsers() ([]models.User, *errr.AppError)
+	// UpdateUser replaces the stored user with the same id.
+	UpdateUser(user models.User) *errr.AppError
+}
+
+// AuditRepo keeps the audit log of admin actions.
+type AuditRepo interface {
+	// SaveAuditEntry gives the entry an id and appends it to the log.
+	SaveAuditEntry(entry models.AuditEntry) *errr.AppError
+	// GetAuditEntries returns the log, newest entry first.
+	GetAuditEntries() ([]models.AuditEntry, *errr.AppError)
+}
+
+// RefreshTokenRepo stores refresh tokens by their hash.
+type RefreshTokenRepo interface {
+	SaveRefreshToken(token models.RefreshToken) *errr.AppError
+	GetRefreshToken(hash string) (models.RefreshToken, *errr.AppError)
+	// UseRefreshToken marks the token used at usedAt. Of two calls for the
+	// same token only the first succeeds, the other fails with a conflict.
+	UseRefreshToken(hash string, usedAt time.Time) *errr.AppError
+	// RevokeTokenFamily deletes every token of the family.
+	RevokeTokenFamily(familyID string) *errr.AppError
+	// RevokeUserTokens deletes every token of the user.
+	RevokeUserTokens(userID int64) *errr.AppError
+}
+
+// RevocationRepo stores the revocations of access tokens until the tokens
+// they revoke have expired.
+type RevocationRepo interface {
+	SaveRevocation(revocation models.Revocation) *errr.AppError
+	// IsRevoked reports whether a saved revocation revokes the token the
+	// claims were read from.
+	IsRevoked(claims models.Claims) (bool, *errr.AppError)
+	// PruneRevocations deletes the revocations that expired before now and
+	// returns how many it deleted.
+	PruneRevocations(now time.Time) (int, *errr.AppError)
+}
+
+// AccessTokenRepo stores the personal access tokens of users.
+type AccessTokenRepo interface {
+	// SaveAccessToken gives the token an id and stores it.
+	SaveAccessToken(token models.AccessToken) (models.AccessToken, *errr.AppError)
+	GetAccessTokens(userID int64) ([]models.AccessToken, *errr.AppError)
+	GetAccessTokenByHash(hash string) (models.AccessToken, *errr.AppError)
+	// TouchAccessToken records that the token was used at usedAt.
+	TouchAccessToken(id int64, usedAt time.Time) *errr.AppError
+	DeleteAccessToken(id int64, userID int64) *errr.AppError
+	// RevokeUserAccessTokens deletes every token of the user.
+	RevokeUserAccessTokens(userID int64) *errr.AppError
 }
 
 // type AuthRepo interface
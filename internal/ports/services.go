@@ -1,21 +1,144 @@
 package ports
 
 import (
+	"time"
+
 	"github.com/Jashanveer-Singh/todo-go/internal/errr"
 	"github.com/Jashanveer-Singh/todo-go/internal/models"
 )
 
 type TaskService interface {
-	CreateTask(taskReq models.TaskRequestDto, claims models.Claims) *errr.AppError
-	UpdateTask(id string, task models.TaskRequestDto, claims models.Claims) *errr.AppError
-	DeleteTask(id string, claims models.Claims) *errr.AppError
-	GetTasks(claims models.Claims) ([]models.TaskResponseDto, *errr.AppError)
+	CreateTask(
+		taskReq models.TaskRequestDto,
+		claims models.Claims,
+	) (models.TaskResponseDto, *errr.AppError)
+	// UpdateTask, PatchTask and DeleteTask only change the task while it is
+	// at version, 0 changes it whatever its version. UpdateTask and PatchTask
+	// return the version the task is at after the change.
+	// UpdateTask replaces the task as a whole, fields left out are cleared.
+	UpdateTask(
+		id string,
+		version int64,
+		task models.TaskRequestDto,
+		claims models.Claims,
+	) (int64, *errr.AppError)
+	// PatchTask changes the fields of the task the patch names.
+	PatchTask(id string, version int64, patch models.TaskPatch, claims models.Claims) (int64, *errr.AppError)
+	// DeleteTask moves the task and its subtasks to the trash.
+	DeleteTask(id string, version int64, claims models.Claims) *errr.AppError
+	GetTrash(claims models.Claims) ([]models.TaskResponseDto, *errr.AppError)
+	RestoreTask(id string, claims models.Claims) *errr.AppError
+	// PurgeTask deletes a task in the trash for good.
+	PurgeTask(id string, claims models.Claims) *errr.AppError
+	// PurgeTrash deletes the tasks of every user that have been in the trash
+	// for longer than retention, returning how many it deleted.
+	PurgeTrash(retention time.Duration) (int, *errr.AppError)
+	// Batch runs the operations of batch in order in one transaction and
+	// returns the outcome of each.
+	Batch(batch models.TaskBatchDto, claims models.Claims) ([]models.TaskOperationResult, *errr.AppError)
+	GetTask(id string, claims models.Claims) (models.TaskResponseDto, *errr.AppError)
+	GetTasks(
+		claims models.Claims,
+		filter models.TaskFilterDto,
+	) (models.TaskPageDto, *errr.AppError)
+	// SearchTasks ranks the tasks of the user matching query, at most limit
+	// of them.
+	SearchTasks(
+		query string,
+		limit string,
+		claims models.Claims,
+	) ([]models.SearchResultDto, *errr.AppError)
+	GetOccurrences(
+		id string,
+		count string,
+		claims models.Claims,
+	) ([]models.OccurrenceDto, *errr.AppError)
+	GetProjectTasks(
+		id string,
+		claims models.Claims,
+		filter models.TaskFilterDto,
+	) (models.TaskPageDto, *errr.AppError)
+}
+
+type LabelService interface {
+	CreateLabel(
+		labelReq models.LabelRequestDto,
+		claims models.Claims,
+	) (models.LabelResponseDto, *errr.AppError)
+	UpdateLabel(id string, label models.LabelRequestDto, claims models.Claims) *errr.AppError
+	DeleteLabel(id string, claims models.Claims) *errr.AppError
+	GetLabels(claims models.Claims) ([]models.LabelResponseDto, *errr.AppError)
+}
+
+type ProjectService interface {
+	CreateProject(
+		projectReq models.ProjectRequestDto,
+		claims models.Claims,
+	) (models.ProjectResponseDto, *errr.AppError)
+	GetProject(id string, claims models.Claims) (models.ProjectResponseDto, *errr.AppError)
+	UpdateProject(id string, project models.ProjectRequestDto, claims models.Claims) *errr.AppError
+	DeleteProject(id string, claims models.Claims) *errr.AppError
+	GetProjects(claims models.Claims) ([]models.ProjectResponseDto, *errr.AppError)
+}
+
+type WorkflowService interface {
+	GetWorkflow(claims models.Claims) (models.WorkflowDto, *errr.AppError)
+	UpdateWorkflow(workflow models.WorkflowDto, claims models.Claims) *errr.AppError
 }
 
 type UserService interface {
-	CreateUser(models.UserRequestDto) *errr.AppError
+	CreateUser(models.UserRequestDto) (models.UserResponseDto, *errr.AppError)
+}
+
+// AccessTokenService manages the personal access tokens of the user of the
+// claims.
+type AccessTokenService interface {
+	// CreateAccessToken answers with the token itself, which can't be read
+	// again later.
+	CreateAccessToken(
+		token models.AccessTokenRequestDto,
+		claims models.Claims,
+	) (models.AccessTokenResponseDto, *errr.AppError)
+	GetAccessTokens(claims models.Claims) ([]models.AccessTokenResponseDto, *errr.AppError)
+	DeleteAccessToken(id string, claims models.Claims) *errr.AppError
+	// Authenticate returns the claims of a personal access token, limited to
+	// its scopes, and records that it was used.
+	Authenticate(token string) (models.Claims, *errr.AppError)
+}
+
+// AdminService manages the accounts of every user. The claims are those of
+// the admin, every call is recorded in the audit log along with whether it
+// succeeded.
+type AdminService interface {
+	GetUsers(claims models.Claims) ([]models.UserResponseDto, *errr.AppError)
+	// SetUserDisabled disables or enables the account of the user. Disabling
+	// it revokes every token of the user.
+	SetUserDisabled(id string, disabled bool, claims models.Claims) *errr.AppError
+	// ResetPassword sets a new password for the user and revokes every token
+	// of the user.
+	ResetPassword(id string, reset models.PasswordResetDto, claims models.Claims) *errr.AppError
+	GetUserTasks(
+		id string,
+		filter models.TaskFilterDto,
+		claims models.Claims,
+	) (models.TaskPageDto, *errr.AppError)
+	GetAuditLog(claims models.Claims) ([]models.AuditEntryDto, *errr.AppError)
+	// AuditDenied records that a user who isn't an admin attempted action on
+	// the user with id.
+	AuditDenied(action string, id string, claims models.Claims)
 }
 
 type AuthService interface {
-	Login(username, password string) (token string, appErr *errr.AppError)
+	Login(username, password string) (models.TokenPairDto, *errr.AppError)
+	// Refresh exchanges an unused refresh token for a new token pair. Using
+	// a token a second time revokes every token of its family.
+	Refresh(refreshToken string) (models.TokenPairDto, *errr.AppError)
+	// Logout revokes the access token the claims were read from and, when
+	// it is not empty, the family of the user's refresh token.
+	Logout(claims models.Claims, refreshToken string) *errr.AppError
+	// LogoutAll revokes every access and refresh token of the user.
+	LogoutAll(claims models.Claims) *errr.AppError
+	// PruneRevocations deletes the revocations of tokens that have all
+	// expired and returns how many it deleted.
+	PruneRevocations() (int, *errr.AppError)
 }
@@ -0,0 +1,22 @@
+package ports
+
+import "github.com/Jashanveer-Singh/todo-go/internal/models"
+
+// TaskIndex finds the tasks of a user by the words in their title and
+// description. It only holds what it derives from the tasks, so the tasks of
+// a user can be dropped and indexed again at any time.
+type TaskIndex interface {
+	// Generation counts the changes to the tasks of the user.
+	Generation(userID int64) uint64
+	// Replace indexes tasks as all the tasks of the user, unless they changed
+	// since Generation returned gen. It reports whether it did.
+	Replace(userID int64, tasks []models.Task, gen uint64) bool
+	// Put indexes a new or changed task of the user, dropping it when it is
+	// in the trash.
+	Put(task models.Task)
+	// Remove drops a task of the user along with its subtasks.
+	Remove(userID int64, id int64)
+	Forget(userID int64)
+	Indexed(userID int64) bool
+	Search(userID int64, query string, limit int) []models.SearchHit
+}
@@ -5,4 +5,7 @@ import "github.com/Jashanveer-Singh/todo-go/internal/models"
 type TokenProvider interface {
 	GenerateToken(claims models.Claims) (token string, err error)
 	ValidateToken(token string) (claims models.Claims, err error)
+	// PublicKeys returns the keys tokens can be verified with, including
+	// retired keys until the tokens they signed have expired.
+	PublicKeys() models.JSONWebKeySet
 }
@@ -0,0 +1,78 @@
+package search
+
+import (
+	"cmp"
+	"html"
+	"slices"
+	"strings"
+)
+
+const (
+	// snippetWords is how many words of a description a snippet shows.
+	snippetWords = 12
+	// snippetLead is how many of them come before the first match.
+	snippetLead = 4
+)
+
+// highlight renders text as HTML with the words in spans, token index ranges
+// into tokens, wrapped in <mark>. A snippet only shows the words around the
+// first match and is empty when nothing matched.
+func highlight(text string, tokens []token, spans [][2]int, snippet bool) string {
+	spans = mergeSpans(spans)
+	if len(spans) == 0 {
+		if snippet {
+			return ""
+		}
+		return html.EscapeString(text)
+	}
+
+	from, to := 0, len(text)
+	first, last := 0, len(tokens)
+	if snippet {
+		first = max(0, spans[0][0]-snippetLead)
+		last = min(len(tokens), max(first+snippetWords, spans[0][1]))
+		if first > 0 {
+			from = tokens[first].start
+		}
+		if last < len(tokens) {
+			to = tokens[last-1].end
+		}
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	at := from
+	for _, span := range spans {
+		if span[0] < first || span[1] > last {
+			continue
+		}
+		start, end := tokens[span[0]].start, tokens[span[1]-1].end
+		b.WriteString(html.EscapeString(text[at:start]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[start:end]))
+		b.WriteString("</mark>")
+		at = end
+	}
+	b.WriteString(html.EscapeString(text[at:to]))
+	if to < len(text) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+// mergeSpans sorts spans and joins the overlapping ones.
+func mergeSpans(spans [][2]int) [][2]int {
+	slices.SortFunc(spans, func(a, b [2]int) int { return cmp.Compare(a[0], b[0]) })
+	merged := [][2]int{}
+	for _, span := range spans {
+		n := len(merged)
+		if n > 0 && span[0] < merged[n-1][1] {
+			merged[n-1][1] = max(merged[n-1][1], span[1])
+			continue
+		}
+		merged = append(merged, span)
+	}
+	return merged
+}
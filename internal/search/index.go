@@ -0,0 +1,293 @@
+// Package search keeps an inverted index of the titles and descriptions of
+// tasks, separately for every user.
+package search
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+)
+
+const (
+	// titleWeight makes a match in the title count as much as this many
+	// matches in the description.
+	titleWeight = 3
+	// k1 limits how much repeating a word raises the score, as in BM25.
+	k1 = 1.2
+)
+
+func NewIndex() *Index {
+	return &Index{
+		users:       map[int64]*userIndex{},
+		generations: map[int64]uint64{},
+	}
+}
+
+// Index finds tasks by the words in their title and description. A search
+// only ever sees the tasks of one user.
+type Index struct {
+	mu    sync.RWMutex
+	users map[int64]*userIndex
+	// generations counts the changes to the tasks of every user, so Replace
+	// can tell the tasks it got are outdated.
+	generations map[int64]uint64
+}
+
+type userIndex struct {
+	docs map[int64]document
+	// postings maps every word to the tasks it occurs in.
+	postings map[string]map[int64]struct{}
+}
+
+// document is an indexed task.
+type document struct {
+	id       int64
+	parentID int64
+	version  int64
+	title    string
+	desc     string
+	// fields holds the words of the title and of the description.
+	fields [2][]token
+}
+
+const (
+	titleField = 0
+	descField  = 1
+)
+
+// Generation counts the changes to the tasks of the user, pass it to Replace.
+func (ix *Index) Generation(userID int64) uint64 {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return ix.generations[userID]
+}
+
+// Replace indexes tasks as all the tasks of the user. It keeps the index as
+// it is and returns false when the tasks of the user changed since
+// Generation returned gen, as tasks may miss the change.
+func (ix *Index) Replace(userID int64, tasks []models.Task, gen uint64) bool {
+	ui := &userIndex{
+		docs:     make(map[int64]document, len(tasks)),
+		postings: map[string]map[int64]struct{}{},
+	}
+	for _, task := range tasks {
+		ui.add(task)
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	if ix.generations[userID] != gen {
+		return false
+	}
+	ix.users[userID] = ui
+	return true
+}
+
+// Put indexes task in place of the version the index holds, unless that is
+// newer. Tasks in the trash are dropped. Nothing is indexed for a user who
+// isn't, the next Replace picks the task up.
+func (ix *Index) Put(task models.Task) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	ix.generations[task.UserID]++
+	ui, ok := ix.users[task.UserID]
+	if !ok {
+		return
+	}
+	if doc, ok := ui.docs[task.ID]; ok {
+		if doc.version > task.Version {
+			return
+		}
+		ui.remove(doc)
+	}
+	if !task.InTrash() {
+		ui.add(task)
+	}
+}
+
+// Remove drops the task with id of the user along with its subtasks.
+func (ix *Index) Remove(userID int64, id int64) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	ix.generations[userID]++
+	ui, ok := ix.users[userID]
+	if !ok {
+		return
+	}
+	removed := map[int64]bool{id: true}
+	for len(removed) > 0 {
+		next := map[int64]bool{}
+		for _, doc := range ui.docs {
+			if removed[doc.id] {
+				ui.remove(doc)
+			} else if removed[doc.parentID] {
+				next[doc.id] = true
+			}
+		}
+		removed = next
+	}
+}
+
+// Forget drops the tasks of the user, Indexed reports false until the next
+// Replace.
+func (ix *Index) Forget(userID int64) {
+	ix.mu.Lock()
+	ix.generations[userID]++
+	delete(ix.users, userID)
+	ix.mu.Unlock()
+}
+
+// Indexed reports whether the tasks of the user are in the index.
+func (ix *Index) Indexed(userID int64) bool {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	_, ok := ix.users[userID]
+	return ok
+}
+
+// add indexes task, which must not be indexed yet.
+func (ui *userIndex) add(task models.Task) {
+	doc := document{
+		id:       task.ID,
+		parentID: task.ParentID,
+		version:  task.Version,
+		title:    task.Title,
+		desc:     task.Desc,
+		fields:   [2][]token{tokenize(task.Title), tokenize(task.Desc)},
+	}
+	ui.docs[task.ID] = doc
+	for _, field := range doc.fields {
+		for _, t := range field {
+			if ui.postings[t.term] == nil {
+				ui.postings[t.term] = map[int64]struct{}{}
+			}
+			ui.postings[t.term][task.ID] = struct{}{}
+		}
+	}
+}
+
+// remove drops doc and the postings of its words.
+func (ui *userIndex) remove(doc document) {
+	delete(ui.docs, doc.id)
+	for _, field := range doc.fields {
+		for _, t := range field {
+			delete(ui.postings[t.term], doc.id)
+			if len(ui.postings[t.term]) == 0 {
+				delete(ui.postings, t.term)
+			}
+		}
+	}
+}
+
+// Search returns up to limit tasks of the user matching every word, phrase
+// and prefix of query, best first.
+func (ix *Index) Search(userID int64, query string, limit int) []models.SearchHit {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	ui, ok := ix.users[userID]
+	clauses := parseQuery(query)
+	if !ok || len(clauses) == 0 {
+		return []models.SearchHit{}
+	}
+
+	candidates := make([]map[int64]struct{}, len(clauses))
+	for i, c := range clauses {
+		candidates[i] = ui.candidates(c)
+	}
+
+	hits := []models.SearchHit{}
+	for id := range candidates[0] {
+		hit, ok := ui.score(ui.docs[id], clauses, candidates)
+		if ok {
+			hits = append(hits, hit)
+		}
+	}
+
+	// Newer tasks, which have larger ids, win ties.
+	slices.SortFunc(hits, func(a, b models.SearchHit) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.TaskID, a.TaskID)
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// candidates returns the tasks holding every word of the clause. They still
+// need checking that the words follow each other.
+func (ui *userIndex) candidates(c clause) map[int64]struct{} {
+	var found map[int64]struct{}
+	for i, term := range c.terms {
+		docs := ui.postings[term]
+		if i == len(c.terms)-1 && c.prefix {
+			docs = map[int64]struct{}{}
+			for word, ids := range ui.postings {
+				if strings.HasPrefix(word, term) {
+					for id := range ids {
+						docs[id] = struct{}{}
+					}
+				}
+			}
+		}
+
+		if found == nil {
+			found = docs
+			continue
+		}
+		both := map[int64]struct{}{}
+		for id := range docs {
+			if _, ok := found[id]; ok {
+				both[id] = struct{}{}
+			}
+		}
+		found = both
+	}
+	return found
+}
+
+// score ranks doc by how often and where it matches clauses, ok is false
+// when it misses any of them. Rarer clauses weigh more, as in BM25.
+func (ui *userIndex) score(
+	doc document,
+	clauses []clause,
+	candidates []map[int64]struct{},
+) (models.SearchHit, bool) {
+	var spans [2][][2]int
+	score := 0.0
+	for i, c := range clauses {
+		if _, ok := candidates[i][doc.id]; !ok {
+			return models.SearchHit{}, false
+		}
+
+		titleSpans := c.matches(doc.fields[titleField])
+		descSpans := c.matches(doc.fields[descField])
+		tf := float64(titleWeight*len(titleSpans) + len(descSpans))
+		if tf == 0 {
+			return models.SearchHit{}, false
+		}
+		spans[titleField] = append(spans[titleField], titleSpans...)
+		spans[descField] = append(spans[descField], descSpans...)
+
+		n := float64(len(ui.docs))
+		df := float64(len(candidates[i]))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		score += idf * tf * (k1 + 1) / (tf + k1)
+	}
+
+	return models.SearchHit{
+		TaskID:  doc.id,
+		Score:   math.Round(score*1000) / 1000,
+		Title:   highlight(doc.title, doc.fields[titleField], spans[titleField], false),
+		Snippet: highlight(doc.desc, doc.fields[descField], spans[descField], true),
+	}, true
+}
@@ -0,0 +1,214 @@
+package search
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+)
+
+func newTestIndex() *Index {
+	ix := NewIndex()
+	ix.Replace(1, []models.Task{
+		{ID: 1, Title: "Buy milk", Desc: "fresh milk and bread from the corner shop"},
+		{ID: 2, Title: "Write report", Desc: "quarterly report about milk prices"},
+		{ID: 3, Title: "Call the bakery", Desc: "ask about bread for the party"},
+		{ID: 4, Title: "E-mail <Bob>", Desc: "send the milk report to bob"},
+	}, 0)
+	ix.Replace(2, []models.Task{
+		{ID: 5, Title: "Buy milk", Desc: "someone else's milk"},
+	}, 0)
+	return ix
+}
+
+func hitIDs(hits []models.SearchHit) []int64 {
+	ids := []int64{}
+	for _, hit := range hits {
+		ids = append(ids, hit.TaskID)
+	}
+	return ids
+}
+
+func TestIndex_Search(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []int64
+	}{
+		{
+			name:  "title matches rank first",
+			query: "milk",
+			want:  []int64{1, 4, 2},
+		},
+		{
+			name:  "every word has to match",
+			query: "milk report",
+			want:  []int64{2, 4},
+		},
+		{
+			name:  "phrase",
+			query: `"milk report"`,
+			want:  []int64{4},
+		},
+		{
+			name:  "prefix",
+			query: "bak*",
+			want:  []int64{3},
+		},
+		{
+			name:  "prefix in a phrase",
+			query: `"fresh mi*"`,
+			want:  []int64{1},
+		},
+		{
+			name:  "word with punctuation is a phrase",
+			query: "e-mail",
+			want:  []int64{4},
+		},
+		{
+			name:  "case is ignored",
+			query: "BREAD",
+			want:  []int64{3, 1},
+		},
+		{
+			name:  "no match",
+			query: "cheese",
+			want:  []int64{},
+		},
+		{
+			name:  "no words",
+			query: `"" *`,
+			want:  []int64{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := hitIDs(newTestIndex().Search(1, tt.query, 10))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIndex_Search_scopedByUser(t *testing.T) {
+	ix := newTestIndex()
+
+	if got := hitIDs(ix.Search(2, "milk", 10)); !slices.Equal(got, []int64{5}) {
+		t.Errorf("Search() of user 2 = %v, want [5]", got)
+	}
+	if got := hitIDs(ix.Search(3, "milk", 10)); len(got) != 0 {
+		t.Errorf("Search() of user 3 = %v, want none", got)
+	}
+}
+
+func TestIndex_Search_limit(t *testing.T) {
+	got := hitIDs(newTestIndex().Search(1, "milk", 2))
+	if !slices.Equal(got, []int64{1, 4}) {
+		t.Errorf("Search() = %v, want [1 4]", got)
+	}
+}
+
+func TestIndex_Search_highlights(t *testing.T) {
+	ix := newTestIndex()
+	ix.Replace(3, []models.Task{{
+		ID:    6,
+		Title: "Plan <the> trip",
+		Desc:  "one two three four five six seven eight nine ten trip eleven twelve thirteen fourteen fifteen sixteen",
+	}}, 0)
+
+	hits := ix.Search(3, "trip", 10)
+	if len(hits) != 1 {
+		t.Fatalf("Search() = %v, want one hit", hits)
+	}
+	if want := "Plan &lt;the&gt; <mark>trip</mark>"; hits[0].Title != want {
+		t.Errorf("Title = %q, want %q", hits[0].Title, want)
+	}
+	if want := "…seven eight nine ten <mark>trip</mark> eleven twelve thirteen fourteen fifteen sixteen"; hits[0].Snippet != want {
+		t.Errorf("Snippet = %q, want %q", hits[0].Snippet, want)
+	}
+
+	hits = ix.Search(3, `"one two"`, 10)
+	if want := "<mark>one two</mark> three four five six seven eight nine ten trip eleven…"; len(hits) != 1 || hits[0].Snippet != want {
+		t.Errorf("Search() = %v, want snippet %q", hits, want)
+	}
+	if want := "Plan &lt;the&gt; trip"; hits[0].Title != want {
+		t.Errorf("Title = %q, want %q", hits[0].Title, want)
+	}
+}
+
+func TestIndex_Forget(t *testing.T) {
+	ix := newTestIndex()
+	ix.Forget(1)
+
+	if ix.Indexed(1) || !ix.Indexed(2) {
+		t.Errorf("Indexed() after Forget(1) = %t, %t, want false, true", ix.Indexed(1), ix.Indexed(2))
+	}
+	if got := ix.Search(1, "milk", 10); len(got) != 0 {
+		t.Errorf("Search() after Forget() = %v, want none", got)
+	}
+}
+
+func TestIndex_Put(t *testing.T) {
+	ix := newTestIndex()
+
+	ix.Put(models.Task{ID: 1, UserID: 1, Title: "Buy oat drink", Version: 2})
+	ix.Put(models.Task{ID: 6, UserID: 1, Title: "Milk the cow", Version: 1})
+	if got := hitIDs(ix.Search(1, "milk", 10)); !slices.Equal(got, []int64{6, 4, 2}) {
+		t.Errorf("Search() after Put() = %v, want [6 4 2]", got)
+	}
+	if got := hitIDs(ix.Search(1, "oat", 10)); !slices.Equal(got, []int64{1}) {
+		t.Errorf("Search() for the new title = %v, want [1]", got)
+	}
+
+	// an older version arriving late doesn't undo the change.
+	ix.Put(models.Task{ID: 1, UserID: 1, Title: "Buy milk", Version: 1})
+	if got := hitIDs(ix.Search(1, "oat", 10)); !slices.Equal(got, []int64{1}) {
+		t.Errorf("Search() after an outdated Put() = %v, want [1]", got)
+	}
+
+	ix.Put(models.Task{ID: 6, UserID: 1, Title: "Milk the cow", Version: 2, DeletedAt: time.Now()})
+	if got := hitIDs(ix.Search(1, "cow", 10)); len(got) != 0 {
+		t.Errorf("Search() after putting a task in the trash = %v, want none", got)
+	}
+
+	ix.Put(models.Task{ID: 7, UserID: 3, Title: "Buy milk", Version: 1})
+	if ix.Indexed(3) {
+		t.Errorf("Put() indexed a user who wasn't")
+	}
+}
+
+func TestIndex_Remove(t *testing.T) {
+	ix := newTestIndex()
+	ix.Put(models.Task{ID: 6, UserID: 1, ParentID: 1, Title: "Find milk", Version: 1})
+	ix.Put(models.Task{ID: 7, UserID: 1, ParentID: 6, Title: "Milk aisle", Version: 1})
+
+	ix.Remove(1, 1)
+	if got := hitIDs(ix.Search(1, "milk", 10)); !slices.Equal(got, []int64{4, 2}) {
+		t.Errorf("Search() after Remove() = %v, want [4 2]", got)
+	}
+	if got := hitIDs(ix.Search(2, "milk", 10)); !slices.Equal(got, []int64{5}) {
+		t.Errorf("Search() of another user after Remove() = %v, want [5]", got)
+	}
+}
+
+func TestIndex_Replace_after_a_change(t *testing.T) {
+	ix := NewIndex()
+	gen := ix.Generation(1)
+	tasks := []models.Task{{ID: 1, UserID: 1, Title: "Buy milk", Version: 1}}
+
+	// the task changes after it was read for indexing.
+	ix.Put(models.Task{ID: 1, UserID: 1, Title: "Buy oat drink", Version: 2})
+	if ix.Replace(1, tasks, gen) || ix.Indexed(1) {
+		t.Errorf("Replace() indexed tasks read before a change")
+	}
+
+	gen = ix.Generation(1)
+	if !ix.Replace(1, []models.Task{{ID: 1, UserID: 1, Title: "Buy oat drink", Version: 2}}, gen) {
+		t.Errorf("Replace() refused tasks read after the change")
+	}
+	if got := hitIDs(ix.Search(1, "oat", 10)); !slices.Equal(got, []int64{1}) {
+		t.Errorf("Search() = %v, want [1]", got)
+	}
+}
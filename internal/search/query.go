@@ -0,0 +1,114 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// token is a word of a text, lower cased, along with where it sits in the
+// text.
+type token struct {
+	term string
+	// start and end are the byte offsets of the word in the text.
+	start int
+	end   int
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// tokenize splits text into words made of letters and digits.
+func tokenize(text string) []token {
+	tokens := []token{}
+	start := -1
+	for i, r := range text {
+		if isWordRune(r) {
+			if start == -1 {
+				start = i
+			}
+			continue
+		}
+		if start != -1 {
+			tokens = append(tokens, token{strings.ToLower(text[start:i]), start, i})
+			start = -1
+		}
+	}
+	if start != -1 {
+		tokens = append(tokens, token{strings.ToLower(text[start:]), start, len(text)})
+	}
+	return tokens
+}
+
+// clause is a part of a query every result has to match: a single word or a
+// phrase of words that have to follow each other.
+type clause struct {
+	terms []string
+	// prefix lets the last term match any word starting with it.
+	prefix bool
+}
+
+// parseQuery splits a query into clauses. Words in double quotes form a
+// phrase, a word ending in * matches as a prefix and a word joined by
+// punctuation like e-mail is a phrase of its parts.
+func parseQuery(query string) []clause {
+	clauses := []clause{}
+	for i, part := range strings.Split(query, `"`) {
+		if i%2 == 1 {
+			clauses = appendClause(clauses, part)
+			continue
+		}
+		for _, word := range strings.Fields(part) {
+			clauses = appendClause(clauses, word)
+		}
+	}
+	return clauses
+}
+
+// appendClause adds the clause of text to clauses unless text has no words.
+func appendClause(clauses []clause, text string) []clause {
+	text = strings.TrimSpace(text)
+	tokens := tokenize(text)
+	if len(tokens) == 0 {
+		return clauses
+	}
+
+	c := clause{
+		terms:  make([]string, len(tokens)),
+		prefix: strings.HasSuffix(text, "*"),
+	}
+	for i, t := range tokens {
+		c.terms[i] = t.term
+	}
+	return append(clauses, c)
+}
+
+// matches returns the spans of tokens, as token index ranges, where the
+// clause occurs.
+func (c clause) matches(tokens []token) [][2]int {
+	spans := [][2]int{}
+	n := len(c.terms)
+	for i := 0; i+n <= len(tokens); i++ {
+		if c.matchesAt(tokens, i) {
+			spans = append(spans, [2]int{i, i + n})
+		}
+	}
+	return spans
+}
+
+func (c clause) matchesAt(tokens []token, i int) bool {
+	last := len(c.terms) - 1
+	for k, term := range c.terms {
+		word := tokens[i+k].term
+		if k == last && c.prefix {
+			if !strings.HasPrefix(word, term) {
+				return false
+			}
+			continue
+		}
+		if word != term {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/internal/ports"
+)
+
+// lastUsedPrecision is how often the last use of an access token is
+// recorded, so a script doesn't cause a write with every request.
+const lastUsedPrecision = time.Minute
+
+func NewAccessTokenService(
+	accessTokenRepo ports.AccessTokenRepo,
+	userRepo ports.UserRepo,
+) *accessTokenService {
+	return &accessTokenService{
+		accessTokenRepo: accessTokenRepo,
+		userRepo:        userRepo,
+		now:             time.Now,
+		newSecret:       newSecret,
+	}
+}
+
+type accessTokenService struct {
+	accessTokenRepo ports.AccessTokenRepo
+	userRepo        ports.UserRepo
+	now             func() time.Time
+	newSecret       func() (string, error)
+}
+
+func (as *accessTokenService) CreateAccessToken(
+	tokenReq models.AccessTokenRequestDto,
+	claims models.Claims,
+) (models.AccessTokenResponseDto, *errr.AppError) {
+	now := as.now()
+	expiresAt, err := models.ParseTaskTime(tokenReq.ExpiresAt, claims.Location())
+	token := models.AccessToken{
+		UserID:    claims.ID,
+		Name:      tokenReq.Name,
+		Scopes:    tokenReq.Scopes,
+		CreatedAt: now,
+		ExpiresAt: expiresAt,
+	}
+	violations := token.Validate(now)
+	if err != nil {
+		violations.Add("expires_at", models.RuleInvalidFormat, "Invalid expiry, use RFC 3339")
+	}
+	if len(violations) > 0 {
+		return models.AccessTokenResponseDto{}, invalidRequest("Invalid access token", violations)
+	}
+
+	secret, err := as.newSecret()
+	if err != nil {
+		return models.AccessTokenResponseDto{}, errr.NewUnexpectedError("Failed to create token")
+	}
+	secret = models.AccessTokenPrefix + secret
+	token.Hash = hashSecret(secret)
+
+	token, appErr := as.accessTokenRepo.SaveAccessToken(token)
+	if appErr != nil {
+		return models.AccessTokenResponseDto{}, appErr
+	}
+
+	tokenDto := token.ToDto()
+	tokenDto.Token = secret
+	return tokenDto, nil
+}
+
+func (as *accessTokenService) GetAccessTokens(
+	claims models.Claims,
+) ([]models.AccessTokenResponseDto, *errr.AppError) {
+	tokens, appErr := as.accessTokenRepo.GetAccessTokens(claims.ID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	tokenDtos := make([]models.AccessTokenResponseDto, 0, len(tokens))
+	for _, token := range tokens {
+		tokenDtos = append(tokenDtos, token.ToDto())
+	}
+	return tokenDtos, nil
+}
+
+func (as *accessTokenService) DeleteAccessToken(idString string, claims models.Claims) *errr.AppError {
+	id, err := strconv.ParseInt(idString, 10, 64)
+	if err != nil {
+		return errr.NewBadRequestError("Invalid access token id")
+	}
+
+	return as.accessTokenRepo.DeleteAccessToken(id, claims.ID)
+}
+
+func (as *accessTokenService) Authenticate(secret string) (models.Claims, *errr.AppError) {
+	if !strings.HasPrefix(secret, models.AccessTokenPrefix) {
+		return models.Claims{}, errr.NewUnauthenticatedError("Invalid access token")
+	}
+
+	token, appErr := as.accessTokenRepo.GetAccessTokenByHash(hashSecret(secret))
+	if appErr != nil {
+		if appErr.Code == http.StatusNotFound {
+			return models.Claims{}, errr.NewUnauthenticatedError("Invalid access token")
+		}
+		return models.Claims{}, appErr
+	}
+
+	now := as.now()
+	if token.IsExpired(now) {
+		return models.Claims{}, errr.NewUnauthenticatedError("Access token has expired")
+	}
+
+	user, appErr := as.userRepo.GetUser(token.UserID)
+	if appErr != nil {
+		if appErr.Code == http.StatusNotFound {
+			return models.Claims{}, errr.NewUnauthenticatedError("Invalid access token")
+		}
+		return models.Claims{}, appErr
+	}
+	if user.Disabled {
+		return models.Claims{}, errr.NewUnauthorizedError("Account is disabled")
+	}
+
+	if now.Sub(token.LastUsedAt) >= lastUsedPrecision {
+		appErr = as.accessTokenRepo.TouchAccessToken(token.ID, now)
+		if appErr != nil {
+			return models.Claims{}, appErr
+		}
+	}
+
+	return token.Claims(user), nil
+}
@@ -0,0 +1,242 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/test/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+func newTestAccessTokenService(
+	t *testing.T,
+) (*accessTokenService, *mocks.MockAccessTokenRepo, *mocks.MockUserRepo) {
+	ctrl := gomock.NewController(t)
+	mar := mocks.NewMockAccessTokenRepo(ctrl)
+	mur := mocks.NewMockUserRepo(ctrl)
+	as := NewAccessTokenService(mar, mur)
+	as.now = func() time.Time { return testNow }
+	as.newSecret = func() (string, error) { return "secret", nil }
+	return as, mar, mur
+}
+
+func Test_accessTokenService_CreateAccessToken(t *testing.T) {
+	claims := models.Claims{ID: 4321, TimeZone: "Asia/Kolkata"}
+
+	tests := []struct {
+		name       string
+		tokenReq   models.AccessTokenRequestDto
+		setupMAR   func(mar *mocks.MockAccessTokenRepo)
+		want       models.AccessTokenResponseDto
+		wantAppErr *errr.AppError
+	}{
+		{
+			name:     "creates a token",
+			tokenReq: models.AccessTokenRequestDto{Name: "ci", Scopes: []string{models.ScopeTasksRead}},
+			setupMAR: func(mar *mocks.MockAccessTokenRepo) {
+				mar.EXPECT().SaveAccessToken(models.AccessToken{
+					UserID:    4321,
+					Name:      "ci",
+					Hash:      hashSecret("todo_pat_secret"),
+					Scopes:    []string{models.ScopeTasksRead},
+					CreatedAt: testNow,
+				}).DoAndReturn(func(token models.AccessToken) (models.AccessToken, *errr.AppError) {
+					token.ID = 9
+					return token, nil
+				})
+			},
+			want: models.AccessTokenResponseDto{
+				ID:        "9",
+				Name:      "ci",
+				Scopes:    []string{models.ScopeTasksRead},
+				CreatedAt: "2025-02-01T12:00:00Z",
+				Token:     "todo_pat_secret",
+			},
+		},
+		{
+			name: "reads the expiry in the user's time zone",
+			tokenReq: models.AccessTokenRequestDto{
+				Name:      "ci",
+				Scopes:    []string{models.ScopeTasksWrite},
+				ExpiresAt: "2025-03-01T05:30:00",
+			},
+			setupMAR: func(mar *mocks.MockAccessTokenRepo) {
+				mar.EXPECT().SaveAccessToken(models.AccessToken{
+					UserID:    4321,
+					Name:      "ci",
+					Hash:      hashSecret("todo_pat_secret"),
+					Scopes:    []string{models.ScopeTasksWrite},
+					CreatedAt: testNow,
+					ExpiresAt: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+				}).DoAndReturn(func(token models.AccessToken) (models.AccessToken, *errr.AppError) {
+					token.ID = 9
+					return token, nil
+				})
+			},
+			want: models.AccessTokenResponseDto{
+				ID:        "9",
+				Name:      "ci",
+				Scopes:    []string{models.ScopeTasksWrite},
+				CreatedAt: "2025-02-01T12:00:00Z",
+				ExpiresAt: "2025-03-01T00:00:00Z",
+				Token:     "todo_pat_secret",
+			},
+		},
+		{
+			name:     "unknown scope",
+			tokenReq: models.AccessTokenRequestDto{Name: "ci", Scopes: []string{"admin"}},
+			setupMAR: func(mar *mocks.MockAccessTokenRepo) {},
+			wantAppErr: violation(
+				"scopes[0]", models.RuleUnknownValue, "Unknown scope, use tasks:read or tasks:write",
+			),
+		},
+		{
+			name: "invalid expiry",
+			tokenReq: models.AccessTokenRequestDto{
+				Name: "ci", Scopes: []string{models.ScopeTasksRead}, ExpiresAt: "tomorrow",
+			},
+			setupMAR:   func(mar *mocks.MockAccessTokenRepo) {},
+			wantAppErr: violation("expires_at", models.RuleInvalidFormat, "Invalid expiry, use RFC 3339"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			as, mar, _ := newTestAccessTokenService(t)
+			tt.setupMAR(mar)
+
+			got, gotAppErr := as.CreateAccessToken(tt.tokenReq, claims)
+			if !reflect.DeepEqual(tt.wantAppErr, gotAppErr) {
+				t.Errorf("CreateAccessToken() err = %v, wanted %v", gotAppErr, tt.wantAppErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CreateAccessToken() = %v, wanted %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_accessTokenService_DeleteAccessToken(t *testing.T) {
+	as, mar, _ := newTestAccessTokenService(t)
+	mar.EXPECT().DeleteAccessToken(int64(9), int64(4321)).Return(nil)
+
+	appErr := as.DeleteAccessToken("9", models.Claims{ID: 4321})
+	if appErr != nil {
+		t.Errorf("DeleteAccessToken() err = %v", appErr)
+	}
+
+	appErr = as.DeleteAccessToken("nine", models.Claims{ID: 4321})
+	want := errr.NewBadRequestError("Invalid access token id")
+	if !reflect.DeepEqual(appErr, want) {
+		t.Errorf("DeleteAccessToken() err = %v, wanted %v", appErr, want)
+	}
+}
+
+func Test_accessTokenService_Authenticate(t *testing.T) {
+	stored := models.AccessToken{
+		ID:         9,
+		UserID:     4321,
+		Hash:       hashSecret("todo_pat_secret"),
+		Scopes:     []string{models.ScopeTasksRead},
+		ExpiresAt:  testNow.Add(time.Hour),
+		LastUsedAt: testNow.Add(-time.Hour),
+	}
+	recentlyUsed := stored
+	recentlyUsed.LastUsedAt = testNow.Add(-time.Second)
+	expired := stored
+	expired.ExpiresAt = testNow
+	user := models.User{ID: 4321, TimeZone: "Asia/Kolkata"}
+	claims := models.Claims{
+		ID:       4321,
+		Role:     models.RoleUser,
+		TimeZone: "Asia/Kolkata",
+		Scopes:   []string{models.ScopeTasksRead},
+	}
+
+	tests := []struct {
+		name       string
+		token      string
+		setupMAR   func(mar *mocks.MockAccessTokenRepo)
+		setupMUR   func(mur *mocks.MockUserRepo)
+		want       models.Claims
+		wantAppErr *errr.AppError
+	}{
+		{
+			name:       "not an access token",
+			token:      "secret",
+			wantAppErr: errr.NewUnauthenticatedError("Invalid access token"),
+		},
+		{
+			name:  "unknown token",
+			token: "todo_pat_secret",
+			setupMAR: func(mar *mocks.MockAccessTokenRepo) {
+				mar.EXPECT().GetAccessTokenByHash(hashSecret("todo_pat_secret")).
+					Return(models.AccessToken{}, errr.NewNotFoundError("Access token not found"))
+			},
+			wantAppErr: errr.NewUnauthenticatedError("Invalid access token"),
+		},
+		{
+			name:  "expired token",
+			token: "todo_pat_secret",
+			setupMAR: func(mar *mocks.MockAccessTokenRepo) {
+				mar.EXPECT().GetAccessTokenByHash(hashSecret("todo_pat_secret")).Return(expired, nil)
+			},
+			wantAppErr: errr.NewUnauthenticatedError("Access token has expired"),
+		},
+		{
+			name:  "disabled account",
+			token: "todo_pat_secret",
+			setupMAR: func(mar *mocks.MockAccessTokenRepo) {
+				mar.EXPECT().GetAccessTokenByHash(hashSecret("todo_pat_secret")).Return(stored, nil)
+			},
+			setupMUR: func(mur *mocks.MockUserRepo) {
+				mur.EXPECT().GetUser(int64(4321)).Return(models.User{ID: 4321, Disabled: true}, nil)
+			},
+			wantAppErr: errr.NewUnauthorizedError("Account is disabled"),
+		},
+		{
+			name:  "records the use",
+			token: "todo_pat_secret",
+			setupMAR: func(mar *mocks.MockAccessTokenRepo) {
+				mar.EXPECT().GetAccessTokenByHash(hashSecret("todo_pat_secret")).Return(stored, nil)
+				mar.EXPECT().TouchAccessToken(int64(9), testNow).Return(nil)
+			},
+			setupMUR: func(mur *mocks.MockUserRepo) {
+				mur.EXPECT().GetUser(int64(4321)).Return(user, nil)
+			},
+			want: claims,
+		},
+		{
+			name:  "used within the minute",
+			token: "todo_pat_secret",
+			setupMAR: func(mar *mocks.MockAccessTokenRepo) {
+				mar.EXPECT().GetAccessTokenByHash(hashSecret("todo_pat_secret")).Return(recentlyUsed, nil)
+			},
+			setupMUR: func(mur *mocks.MockUserRepo) {
+				mur.EXPECT().GetUser(int64(4321)).Return(user, nil)
+			},
+			want: claims,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			as, mar, mur := newTestAccessTokenService(t)
+			if tt.setupMAR != nil {
+				tt.setupMAR(mar)
+			}
+			if tt.setupMUR != nil {
+				tt.setupMUR(mur)
+			}
+
+			got, gotAppErr := as.Authenticate(tt.token)
+			if !reflect.DeepEqual(tt.wantAppErr, gotAppErr) {
+				t.Errorf("Authenticate() err = %v, wanted %v", gotAppErr, tt.wantAppErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Authenticate() = %v, wanted %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/internal/ports"
+)
+
+func NewAdminService(
+	userRepo ports.UserRepo,
+	auditRepo ports.AuditRepo,
+	accessTokenRepo ports.AccessTokenRepo,
+	passwordHasher ports.PasswordHasher,
+	authService ports.AuthService,
+	taskService ports.TaskService,
+) *adminService {
+	return &adminService{
+		userRepo:        userRepo,
+		auditRepo:       auditRepo,
+		accessTokenRepo: accessTokenRepo,
+		passwordHasher:  passwordHasher,
+		authService:     authService,
+		taskService:     taskService,
+		now:             time.Now,
+	}
+}
+
+type adminService struct {
+	userRepo  ports.UserRepo
+	auditRepo ports.AuditRepo
+	// accessTokenRepo and authService revoke the tokens of users whose
+	// account changed.
+	accessTokenRepo ports.AccessTokenRepo
+	passwordHasher  ports.PasswordHasher
+	authService     ports.AuthService
+	// taskService lists the tasks of users on behalf of admins.
+	taskService ports.TaskService
+	now         func() time.Time
+}
+
+func (as *adminService) GetUsers(claims models.Claims) ([]models.UserResponseDto, *errr.AppError) {
+	users, appErr := as.userRepo.GetUsers()
+	appErr = as.auditRead(claims, models.AuditListUsers, 0, appErr)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	userDtos := make([]models.UserResponseDto, 0, len(users))
+	for _, user := range users {
+		userDtos = append(userDtos, user.ToDto())
+	}
+	return userDtos, nil
+}
+
+func (as *adminService) SetUserDisabled(id string, disabled bool, claims models.Claims) *errr.AppError {
+	action := models.AuditEnableUser
+	if disabled {
+		action = models.AuditDisableUser
+	}
+
+	appErr := as.setUserDisabled(id, disabled, claims)
+	return as.auditChange(claims, action, targetID(id), appErr)
+}
+
+func (as *adminService) setUserDisabled(id string, disabled bool, claims models.Claims) *errr.AppError {
+	user, appErr := as.getUser(id)
+	if appErr != nil {
+		return appErr
+	}
+	if disabled && user.ID == claims.ID {
+		return errr.NewBadRequestError("You can't disable your own account")
+	}
+
+	user.Disabled = disabled
+	appErr = as.userRepo.UpdateUser(user)
+	if appErr != nil {
+		return appErr
+	}
+
+	if disabled {
+		return as.revokeTokens(user.ID)
+	}
+	return nil
+}
+
+func (as *adminService) ResetPassword(
+	id string,
+	reset models.PasswordResetDto,
+	claims models.Claims,
+) *errr.AppError {
+	appErr := as.resetPassword(id, reset)
+	return as.auditChange(claims, models.AuditResetPassword, targetID(id), appErr)
+}
+
+func (as *adminService) resetPassword(id string, reset models.PasswordResetDto) *errr.AppError {
+	user, appErr := as.getUser(id)
+	if appErr != nil {
+		return appErr
+	}
+
+	user.Password = reset.Password
+	if violations := user.ValidatePassword(); len(violations) > 0 {
+		return invalidRequest("Invalid password", violations)
+	}
+	hash, err := as.passwordHasher.Hash(user.Password)
+	if err != nil {
+		return errr.NewUnexpectedError(err.Error())
+	}
+	user.Password = hash
+
+	appErr = as.userRepo.UpdateUser(user)
+	if appErr != nil {
+		return appErr
+	}
+
+	return as.revokeTokens(user.ID)
+}
+
+// revokeTokens signs the user out everywhere and revokes their personal
+// access tokens, which would otherwise outlive a password reset.
+func (as *adminService) revokeTokens(userID int64) *errr.AppError {
+	appErr := as.authService.LogoutAll(models.Claims{ID: userID})
+	if appErr != nil {
+		return appErr
+	}
+
+	return as.accessTokenRepo.RevokeUserAccessTokens(userID)
+}
+
+func (as *adminService) GetUserTasks(
+	id string,
+	filter models.TaskFilterDto,
+	claims models.Claims,
+) (models.TaskPageDto, *errr.AppError) {
+	page, appErr := as.getUserTasks(id, filter)
+	appErr = as.auditRead(claims, models.AuditViewTasks, targetID(id), appErr)
+	if appErr != nil {
+		return models.TaskPageDto{}, appErr
+	}
+
+	return page, nil
+}
+
+func (as *adminService) getUserTasks(id string, filter models.TaskFilterDto) (models.TaskPageDto, *errr.AppError) {
+	user, appErr := as.getUser(id)
+	if appErr != nil {
+		return models.TaskPageDto{}, appErr
+	}
+
+	return as.taskService.GetTasks(models.Claims{
+		ID:       user.ID,
+		Role:     user.EffectiveRole(),
+		TimeZone: user.TimeZone,
+	}, filter)
+}
+
+func (as *adminService) GetAuditLog(claims models.Claims) ([]models.AuditEntryDto, *errr.AppError) {
+	entries, appErr := as.auditRepo.GetAuditEntries()
+	appErr = as.auditRead(claims, models.AuditViewAuditLog, 0, appErr)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	entryDtos := make([]models.AuditEntryDto, 0, len(entries))
+	for _, entry := range entries {
+		entryDtos = append(entryDtos, entry.ToDto())
+	}
+	return entryDtos, nil
+}
+
+// AuditDenied records that the user with claims, who isn't an admin, tried
+// to take action on the user with id.
+func (as *adminService) AuditDenied(action string, id string, claims models.Claims) {
+	denied := &errr.AppError{Code: http.StatusForbidden, Reason: "insufficient_role"}
+	as.auditChange(claims, action, targetID(id), denied)
+}
+
+func (as *adminService) getUser(idString string) (models.User, *errr.AppError) {
+	id, err := strconv.ParseInt(idString, 10, 64)
+	if err != nil {
+		return models.User{}, errr.NewBadRequestError("Invalid user id")
+	}
+
+	return as.userRepo.GetUser(id)
+}
+
+// targetID is the id of the user an action is on, 0 when id is invalid.
+func targetID(id string) int64 {
+	targetID, _ := strconv.ParseInt(id, 10, 64)
+	return targetID
+}
+
+// auditRead records the attempt to read what an admin may see, failing the
+// read when the record can't be saved so nothing is seen unrecorded. It
+// returns the error of the read otherwise.
+func (as *adminService) auditRead(
+	claims models.Claims,
+	action string,
+	targetID int64,
+	appErr *errr.AppError,
+) *errr.AppError {
+	auditErr := as.audit(claims, action, targetID, appErr)
+	if appErr == nil {
+		return auditErr
+	}
+	return appErr
+}
+
+// auditChange records the attempt to change an account and returns the error
+// of the change. The change is done when the record can't be saved, so that
+// is only logged.
+func (as *adminService) auditChange(
+	claims models.Claims,
+	action string,
+	targetID int64,
+	appErr *errr.AppError,
+) *errr.AppError {
+	auditErr := as.audit(claims, action, targetID, appErr)
+	if auditErr != nil {
+		log.Printf("Can't record %s by user %d in the audit log: %s", action, claims.ID, auditErr.Message)
+	}
+	return appErr
+}
+
+// audit records that the user with claims attempted action, on the user with
+// targetID unless it is 0, and how appErr says it ended.
+func (as *adminService) audit(
+	claims models.Claims,
+	action string,
+	targetID int64,
+	appErr *errr.AppError,
+) *errr.AppError {
+	entry := models.AuditEntry{
+		ActorID:  claims.ID,
+		Action:   action,
+		TargetID: targetID,
+		Outcome:  models.AuditSucceeded,
+		At:       as.now(),
+	}
+	if appErr != nil {
+		entry.Outcome = models.AuditFailed
+		if appErr.Code == http.StatusForbidden {
+			entry.Outcome = models.AuditDenied
+		}
+		entry.Reason = appErr.ReasonCode()
+	}
+
+	return as.auditRepo.SaveAuditEntry(entry)
+}
@@ -0,0 +1,303 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/test/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+var adminClaims = models.Claims{ID: 1, Role: models.RoleAdmin}
+
+// audits expects the admin to take action on the user with targetID.
+func audits(mar *mocks.MockAuditRepo, action string, targetID int64) *gomock.Call {
+	return mar.EXPECT().SaveAuditEntry(models.AuditEntry{
+		ActorID:  adminClaims.ID,
+		Action:   action,
+		TargetID: targetID,
+		Outcome:  models.AuditSucceeded,
+		At:       testNow,
+	}).Return(nil)
+}
+
+// auditsFailure expects the admin's attempt to take action on the user with
+// targetID to fail for reason.
+func auditsFailure(mar *mocks.MockAuditRepo, action string, targetID int64, reason string) {
+	mar.EXPECT().SaveAuditEntry(models.AuditEntry{
+		ActorID:  adminClaims.ID,
+		Action:   action,
+		TargetID: targetID,
+		Outcome:  models.AuditFailed,
+		Reason:   reason,
+		At:       testNow,
+	}).Return(nil)
+}
+
+type adminServiceMocks struct {
+	userRepo        *mocks.MockUserRepo
+	auditRepo       *mocks.MockAuditRepo
+	accessTokenRepo *mocks.MockAccessTokenRepo
+	passwordHasher  *mocks.MockPasswordHasher
+	authService     *mocks.MockAuthService
+	taskService     *mocks.MockTaskService
+}
+
+func newTestAdminService(t *testing.T) (*adminService, adminServiceMocks) {
+	ctrl := gomock.NewController(t)
+	m := adminServiceMocks{
+		userRepo:        mocks.NewMockUserRepo(ctrl),
+		auditRepo:       mocks.NewMockAuditRepo(ctrl),
+		accessTokenRepo: mocks.NewMockAccessTokenRepo(ctrl),
+		passwordHasher:  mocks.NewMockPasswordHasher(ctrl),
+		authService:     mocks.NewMockAuthService(ctrl),
+		taskService:     mocks.NewMockTaskService(ctrl),
+	}
+	as := NewAdminService(
+		m.userRepo, m.auditRepo, m.accessTokenRepo, m.passwordHasher, m.authService, m.taskService,
+	)
+	as.now = func() time.Time { return testNow }
+	return as, m
+}
+
+func Test_adminService_GetUsers(t *testing.T) {
+	as, m := newTestAdminService(t)
+	m.userRepo.EXPECT().GetUsers().Return([]models.User{
+		{ID: 1, Username: "admin", Password: "hash", Role: models.RoleAdmin},
+		{ID: 77, Username: "jass", Password: "hash", Disabled: true},
+	}, nil)
+	audits(m.auditRepo, models.AuditListUsers, 0)
+
+	got, appErr := as.GetUsers(adminClaims)
+	want := []models.UserResponseDto{
+		{ID: "1", Username: "admin", Role: models.RoleAdmin},
+		{ID: "77", Username: "jass", Role: models.RoleUser, Disabled: true},
+	}
+	if appErr != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("GetUsers() = %v, %v, want %v", got, appErr, want)
+	}
+}
+
+func Test_adminService_SetUserDisabled(t *testing.T) {
+	user := models.User{ID: 77, Username: "jass", Password: "hash", Role: models.RoleUser}
+	disabled := user
+	disabled.Disabled = true
+
+	tests := []struct {
+		name       string
+		id         string
+		disabled   bool
+		setup      func(m adminServiceMocks)
+		wantAppErr *errr.AppError
+	}{
+		{
+			name:     "invalid id",
+			id:       "abc",
+			disabled: true,
+			setup: func(m adminServiceMocks) {
+				auditsFailure(m.auditRepo, models.AuditDisableUser, 0, "bad_request")
+			},
+			wantAppErr: errr.NewBadRequestError("Invalid user id"),
+		},
+		{
+			name:     "unknown user",
+			id:       "77",
+			disabled: true,
+			setup: func(m adminServiceMocks) {
+				m.userRepo.EXPECT().GetUser(int64(77)).Return(models.User{}, errr.NewNotFoundError("User not Found"))
+				auditsFailure(m.auditRepo, models.AuditDisableUser, 77, "not_found")
+			},
+			wantAppErr: errr.NewNotFoundError("User not Found"),
+		},
+		{
+			name:     "own account",
+			id:       "1",
+			disabled: true,
+			setup: func(m adminServiceMocks) {
+				m.userRepo.EXPECT().GetUser(int64(1)).
+					Return(models.User{ID: 1, Username: "admin", Role: models.RoleAdmin}, nil)
+				auditsFailure(m.auditRepo, models.AuditDisableUser, 1, "bad_request")
+			},
+			wantAppErr: errr.NewBadRequestError("You can't disable your own account"),
+		},
+		{
+			name:     "disable revokes the user's tokens",
+			id:       "77",
+			disabled: true,
+			setup: func(m adminServiceMocks) {
+				m.userRepo.EXPECT().GetUser(int64(77)).Return(user, nil)
+				m.userRepo.EXPECT().UpdateUser(disabled).Return(nil)
+				m.authService.EXPECT().LogoutAll(models.Claims{ID: 77}).Return(nil)
+				m.accessTokenRepo.EXPECT().RevokeUserAccessTokens(int64(77)).Return(nil)
+				audits(m.auditRepo, models.AuditDisableUser, 77)
+			},
+		},
+		{
+			name:     "disable is kept when it can't be audited",
+			id:       "77",
+			disabled: true,
+			setup: func(m adminServiceMocks) {
+				m.userRepo.EXPECT().GetUser(int64(77)).Return(user, nil)
+				m.userRepo.EXPECT().UpdateUser(disabled).Return(nil)
+				m.authService.EXPECT().LogoutAll(models.Claims{ID: 77}).Return(nil)
+				m.accessTokenRepo.EXPECT().RevokeUserAccessTokens(int64(77)).Return(nil)
+				audits(m.auditRepo, models.AuditDisableUser, 77).Return(errr.NewUnexpectedError("disk full"))
+			},
+		},
+		{
+			name:     "enable",
+			id:       "77",
+			disabled: false,
+			setup: func(m adminServiceMocks) {
+				m.userRepo.EXPECT().GetUser(int64(77)).Return(disabled, nil)
+				m.userRepo.EXPECT().UpdateUser(user).Return(nil)
+				audits(m.auditRepo, models.AuditEnableUser, 77)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			as, m := newTestAdminService(t)
+			tt.setup(m)
+
+			appErr := as.SetUserDisabled(tt.id, tt.disabled, adminClaims)
+			if !reflect.DeepEqual(appErr, tt.wantAppErr) {
+				t.Errorf("SetUserDisabled() err = %v, want %v", appErr, tt.wantAppErr)
+			}
+		})
+	}
+}
+
+func Test_adminService_ResetPassword(t *testing.T) {
+	user := models.User{ID: 77, Username: "jass", Password: "old hash", Role: models.RoleUser}
+
+	tests := []struct {
+		name       string
+		password   string
+		setup      func(m adminServiceMocks)
+		wantAppErr *errr.AppError
+	}{
+		{
+			name:     "password breaking the policy",
+			password: "short",
+			setup: func(m adminServiceMocks) {
+				m.userRepo.EXPECT().GetUser(int64(77)).Return(user, nil)
+				auditsFailure(m.auditRepo, models.AuditResetPassword, 77, "validation_failed")
+			},
+			wantAppErr: violation("password", models.RulePasswordPolicy, "Password must be at least 8 characters"),
+		},
+		{
+			name:     "new password revokes the user's tokens",
+			password: "new password",
+			setup: func(m adminServiceMocks) {
+				m.userRepo.EXPECT().GetUser(int64(77)).Return(user, nil)
+				m.passwordHasher.EXPECT().Hash("new password").Return("new hash", nil)
+				reset := user
+				reset.Password = "new hash"
+				m.userRepo.EXPECT().UpdateUser(reset).Return(nil)
+				m.authService.EXPECT().LogoutAll(models.Claims{ID: 77}).Return(nil)
+				m.accessTokenRepo.EXPECT().RevokeUserAccessTokens(int64(77)).Return(nil)
+				audits(m.auditRepo, models.AuditResetPassword, 77)
+			},
+		},
+		{
+			name:     "access tokens that can't be revoked",
+			password: "new password",
+			setup: func(m adminServiceMocks) {
+				m.userRepo.EXPECT().GetUser(int64(77)).Return(user, nil)
+				m.passwordHasher.EXPECT().Hash("new password").Return("new hash", nil)
+				reset := user
+				reset.Password = "new hash"
+				m.userRepo.EXPECT().UpdateUser(reset).Return(nil)
+				m.authService.EXPECT().LogoutAll(models.Claims{ID: 77}).Return(nil)
+				m.accessTokenRepo.EXPECT().RevokeUserAccessTokens(int64(77)).
+					Return(errr.NewUnexpectedError("Unable to revoke access tokens due to internal server error"))
+				auditsFailure(m.auditRepo, models.AuditResetPassword, 77, "internal_server_error")
+			},
+			wantAppErr: errr.NewUnexpectedError("Unable to revoke access tokens due to internal server error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			as, m := newTestAdminService(t)
+			tt.setup(m)
+
+			appErr := as.ResetPassword("77", models.PasswordResetDto{Password: tt.password}, adminClaims)
+			if !reflect.DeepEqual(appErr, tt.wantAppErr) {
+				t.Errorf("ResetPassword() err = %v, want %v", appErr, tt.wantAppErr)
+			}
+		})
+	}
+}
+
+func Test_adminService_GetUserTasks(t *testing.T) {
+	as, m := newTestAdminService(t)
+	filter := models.TaskFilterDto{Status: "Done"}
+	page := models.TaskPageDto{Tasks: []models.TaskResponseDto{{ID: "5", Title: "a"}}}
+	m.userRepo.EXPECT().GetUser(int64(77)).
+		Return(models.User{ID: 77, Username: "jass", TimeZone: "Asia/Kolkata"}, nil)
+	m.taskService.EXPECT().
+		GetTasks(models.Claims{ID: 77, Role: models.RoleUser, TimeZone: "Asia/Kolkata"}, filter).
+		Return(page, nil)
+	audits(m.auditRepo, models.AuditViewTasks, 77)
+
+	got, appErr := as.GetUserTasks("77", filter, adminClaims)
+	if appErr != nil || !reflect.DeepEqual(got, page) {
+		t.Errorf("GetUserTasks() = %v, %v, want %v", got, appErr, page)
+	}
+}
+
+func Test_adminService_GetUserTasks_when_it_cant_be_audited(t *testing.T) {
+	as, m := newTestAdminService(t)
+	m.userRepo.EXPECT().GetUser(int64(77)).Return(models.User{ID: 77, Username: "jass"}, nil)
+	m.taskService.EXPECT().GetTasks(models.Claims{ID: 77, Role: models.RoleUser}, models.TaskFilterDto{}).
+		Return(models.TaskPageDto{Tasks: []models.TaskResponseDto{{ID: "5", Title: "a"}}}, nil)
+	audits(m.auditRepo, models.AuditViewTasks, 77).Return(errr.NewUnexpectedError("disk full"))
+
+	got, appErr := as.GetUserTasks("77", models.TaskFilterDto{}, adminClaims)
+	want := errr.NewUnexpectedError("disk full")
+	if !reflect.DeepEqual(appErr, want) || !reflect.DeepEqual(got, models.TaskPageDto{}) {
+		t.Errorf("GetUserTasks() = %v, %v, want no tasks and %v", got, appErr, want)
+	}
+}
+
+func Test_adminService_AuditDenied(t *testing.T) {
+	as, m := newTestAdminService(t)
+	m.auditRepo.EXPECT().SaveAuditEntry(models.AuditEntry{
+		ActorID:  77,
+		Action:   models.AuditResetPassword,
+		TargetID: 1,
+		Outcome:  models.AuditDenied,
+		Reason:   "insufficient_role",
+		At:       testNow,
+	}).Return(nil)
+
+	as.AuditDenied(models.AuditResetPassword, "1", models.Claims{ID: 77, Role: models.RoleUser})
+}
+
+func Test_adminService_GetAuditLog(t *testing.T) {
+	as, m := newTestAdminService(t)
+	m.auditRepo.EXPECT().GetAuditEntries().Return([]models.AuditEntry{
+		{ID: 9, ActorID: 1, Action: models.AuditDisableUser, TargetID: 77, Outcome: models.AuditSucceeded, At: testNow},
+		{
+			ID: 8, ActorID: 1, Action: models.AuditListUsers, Outcome: models.AuditFailed, Reason: "not_found",
+			At: testNow.Add(-time.Hour),
+		},
+	}, nil)
+	audits(m.auditRepo, models.AuditViewAuditLog, 0)
+
+	got, appErr := as.GetAuditLog(adminClaims)
+	want := []models.AuditEntryDto{
+		{ID: "9", ActorID: "1", Action: "disable_user", TargetID: "77", Outcome: "succeeded", At: "2025-02-01T12:00:00Z"},
+		{
+			ID: "8", ActorID: "1", Action: "list_users", Outcome: "failed", Reason: "not_found",
+			At: "2025-02-01T11:00:00Z",
+		},
+	}
+	if appErr != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAuditLog() = %v, %v, want %v", got, appErr, want)
+	}
+}
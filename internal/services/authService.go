@@ -1,6 +1,13 @@
 package services
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"time"
+
 	"github.com/Jashanveer-Singh/todo-go/internal/errr"
 	"github.com/Jashanveer-Singh/todo-go/internal/models"
 	"github.com/Jashanveer-Singh/todo-go/internal/ports"
@@ -8,44 +15,209 @@ import (
 
 func NewAuthService(
 	userRepo ports.UserRepo,
+	refreshTokenRepo ports.RefreshTokenRepo,
+	revocationRepo ports.RevocationRepo,
 	tokenProvider ports.TokenProvider,
 	passwordHasher ports.PasswordHasher,
+	accessTTL time.Duration,
+	refreshTTL time.Duration,
 ) *authService {
 	return &authService{
-		userRepo:       userRepo,
-		tokenProvider:  tokenProvider,
-		passwordHasher: passwordHasher,
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		revocationRepo:   revocationRepo,
+		tokenProvider:    tokenProvider,
+		passwordHasher:   passwordHasher,
+		accessTTL:        accessTTL,
+		refreshTTL:       refreshTTL,
+		now:              time.Now,
+		newSecret:        newSecret,
 	}
 }
 
 type authService struct {
-	userRepo       ports.UserRepo
-	tokenProvider  ports.TokenProvider
-	passwordHasher ports.PasswordHasher
+	userRepo         ports.UserRepo
+	refreshTokenRepo ports.RefreshTokenRepo
+	revocationRepo   ports.RevocationRepo
+	tokenProvider    ports.TokenProvider
+	passwordHasher   ports.PasswordHasher
+	// accessTTL is how long the token provider's access tokens are valid.
+	accessTTL time.Duration
+	// refreshTTL is how long a refresh token can be exchanged.
+	refreshTTL time.Duration
+	now        func() time.Time
+	newSecret  func() (string, error)
 }
 
-func (as *authService) Login(username, password string) (string, *errr.AppError) {
+func (as *authService) Login(username, password string) (models.TokenPairDto, *errr.AppError) {
 	user, appErr := as.userRepo.GetUserByUsername(username)
 	if appErr != nil {
-		return "", appErr
+		return models.TokenPairDto{}, appErr
 	}
 
 	match, err := as.passwordHasher.CompareHash(user.Password, password)
 	if err != nil {
-		return "", errr.NewUnexpectedError(err.Error())
+		return models.TokenPairDto{}, errr.NewUnexpectedError(err.Error())
 	}
 	if !match {
-		return "", errr.NewUnauthenticatedError("Invalid Username or password")
+		return models.TokenPairDto{}, errr.NewUnauthenticatedError("Invalid Username or password")
+	}
+	if user.Disabled {
+		return models.TokenPairDto{}, errr.NewUnauthorizedError("Account is disabled")
+	}
+
+	return as.issueTokens(user, "")
+}
+
+// Refresh exchanges a refresh token for a new token pair. Every refresh token
+// can be exchanged once: presenting one again means it leaked, so the whole
+// family issued since the sign-in is revoked.
+func (as *authService) Refresh(refreshToken string) (models.TokenPairDto, *errr.AppError) {
+	token, appErr := as.refreshTokenRepo.GetRefreshToken(hashSecret(refreshToken))
+	if appErr != nil {
+		if appErr.Code == http.StatusNotFound {
+			return models.TokenPairDto{}, errr.NewUnauthenticatedError("Invalid refresh token")
+		}
+		return models.TokenPairDto{}, appErr
+	}
+
+	if !token.UsedAt.IsZero() {
+		return models.TokenPairDto{}, as.revokeFamily(token.FamilyID)
+	}
+
+	now := as.now()
+	if token.IsExpired(now) {
+		return models.TokenPairDto{}, errr.NewUnauthenticatedError("Refresh token has expired")
+	}
+
+	appErr = as.refreshTokenRepo.UseRefreshToken(token.Hash, now)
+	if appErr != nil {
+		// Another request exchanged the token first.
+		if appErr.Code == http.StatusConflict {
+			return models.TokenPairDto{}, as.revokeFamily(token.FamilyID)
+		}
+		return models.TokenPairDto{}, appErr
+	}
+
+	user, appErr := as.userRepo.GetUser(token.UserID)
+	if appErr != nil {
+		if appErr.Code == http.StatusNotFound {
+			return models.TokenPairDto{}, errr.NewUnauthenticatedError("Invalid refresh token")
+		}
+		return models.TokenPairDto{}, appErr
 	}
+	if user.Disabled {
+		return models.TokenPairDto{}, errr.NewUnauthorizedError("Account is disabled")
+	}
+
+	return as.issueTokens(user, token.FamilyID)
+}
 
+func (as *authService) Logout(claims models.Claims, refreshToken string) *errr.AppError {
+	if refreshToken != "" {
+		token, appErr := as.refreshTokenRepo.GetRefreshToken(hashSecret(refreshToken))
+		// an unknown refresh token is already unusable.
+		if appErr != nil && appErr.Code != http.StatusNotFound {
+			return appErr
+		}
+		if appErr == nil && token.UserID == claims.ID {
+			appErr = as.refreshTokenRepo.RevokeTokenFamily(token.FamilyID)
+			if appErr != nil {
+				return appErr
+			}
+		}
+	}
+
+	return as.revocationRepo.SaveRevocation(models.Revocation{
+		TokenID:   claims.TokenID,
+		UserID:    claims.ID,
+		ExpiresAt: claims.ExpiresAt,
+	})
+}
+
+func (as *authService) LogoutAll(claims models.Claims) *errr.AppError {
+	appErr := as.refreshTokenRepo.RevokeUserTokens(claims.ID)
+	if appErr != nil {
+		return appErr
+	}
+
+	// issued at times are whole seconds, so a token issued later in the
+	// same second is revoked too.
+	now := as.now()
+	return as.revocationRepo.SaveRevocation(models.Revocation{
+		UserID:      claims.ID,
+		IssuedUntil: now,
+		ExpiresAt:   now.Add(as.accessTTL),
+	})
+}
+
+func (as *authService) PruneRevocations() (int, *errr.AppError) {
+	return as.revocationRepo.PruneRevocations(as.now())
+}
+
+func (as *authService) revokeFamily(familyID string) *errr.AppError {
+	appErr := as.refreshTokenRepo.RevokeTokenFamily(familyID)
+	if appErr != nil {
+		return appErr
+	}
+
+	return errr.NewUnauthenticatedError("Refresh token was already used, sign in again")
+}
+
+// issueTokens creates an access token and a refresh token for the user. The
+// refresh token joins familyID, or starts a new family when it is empty.
+func (as *authService) issueTokens(user models.User, familyID string) (models.TokenPairDto, *errr.AppError) {
 	claims := models.Claims{
-		ID:   user.ID,
-		Role: "",
+		ID:       user.ID,
+		Role:     user.EffectiveRole(),
+		TimeZone: user.TimeZone,
+	}
+
+	accessToken, err := as.tokenProvider.GenerateToken(claims)
+	if err != nil {
+		return models.TokenPairDto{}, errr.NewUnexpectedError("Failed to create token")
 	}
 
-	token, err := as.tokenProvider.GenerateToken(claims)
+	refreshToken, err := as.newSecret()
 	if err != nil {
-		return "", errr.NewUnexpectedError("Failed to create token")
+		return models.TokenPairDto{}, errr.NewUnexpectedError("Failed to create token")
 	}
-	return token, nil
+	hash := hashSecret(refreshToken)
+	if familyID == "" {
+		familyID = hash
+	}
+
+	appErr := as.refreshTokenRepo.SaveRefreshToken(models.RefreshToken{
+		Hash:      hash,
+		FamilyID:  familyID,
+		UserID:    user.ID,
+		ExpiresAt: as.now().Add(as.refreshTTL),
+	})
+	if appErr != nil {
+		return models.TokenPairDto{}, appErr
+	}
+
+	return models.TokenPairDto{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+	}, nil
+}
+
+// newSecret returns 32 random bytes, base64url encoded.
+func newSecret() (string, error) {
+	secret := make([]byte, 32)
+	_, err := rand.Read(secret)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// hashSecret is what is stored of a secret, so a leaked store can't be used
+// to sign in.
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
 }
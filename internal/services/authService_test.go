@@ -3,7 +3,9 @@ package services
 import (
 	"errors"
 	"net/http"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/Jashanveer-Singh/todo-go/internal/errr"
 	"github.com/Jashanveer-Singh/todo-go/internal/models"
@@ -15,13 +17,14 @@ func Test_authService_Login(t *testing.T) {
 	tests := []struct {
 		name string // description of this test case
 		// Named input parameters for receiver constructor.
-		setupUserRepo       func(mur *mocks.MockUserRepo)
-		setupTokenProvider  func(mtp *mocks.MockTokenProvider)
-		setupPasswordHasher func(mph *mocks.MockPasswordHasher)
+		setupUserRepo         func(mur *mocks.MockUserRepo)
+		setupRefreshTokenRepo func(mrr *mocks.MockRefreshTokenRepo)
+		setupTokenProvider    func(mtp *mocks.MockTokenProvider)
+		setupPasswordHasher   func(mph *mocks.MockPasswordHasher)
 		// Named input parameters for target function.
 		username   string
 		password   string
-		want       string
+		want       models.TokenPairDto
 		wantAppErr *errr.AppError
 	}{
 		{
@@ -35,7 +38,6 @@ func Test_authService_Login(t *testing.T) {
 			},
 			setupTokenProvider:  func(mtp *mocks.MockTokenProvider) {},
 			setupPasswordHasher: func(mph *mocks.MockPasswordHasher) {},
-			want:                "",
 			wantAppErr: &errr.AppError{
 				Code:    0,
 				Message: "error message from user repo",
@@ -58,7 +60,6 @@ func Test_authService_Login(t *testing.T) {
 					Return(false, errors.New("error message from password hasher"))
 			},
 			setupTokenProvider: func(mtp *mocks.MockTokenProvider) {},
-			want:               "",
 			wantAppErr: &errr.AppError{
 				Code:    http.StatusInternalServerError,
 				Message: "error message from password hasher",
@@ -75,7 +76,6 @@ func Test_authService_Login(t *testing.T) {
 			},
 			setupPasswordHasher: func(mph *mocks.MockPasswordHasher) {},
 			setupTokenProvider:  func(mtp *mocks.MockTokenProvider) {},
-			want:                "",
 			wantAppErr: &errr.AppError{
 				Code:    0,
 				Message: "error message from user repo, user not found",
@@ -98,12 +98,34 @@ func Test_authService_Login(t *testing.T) {
 					Return(false, nil)
 			},
 			setupTokenProvider: func(mtp *mocks.MockTokenProvider) {},
-			want:               "",
 			wantAppErr: &errr.AppError{
 				Code:    http.StatusUnauthorized,
 				Message: "Invalid Username or password",
 			},
 		},
+		{
+			name:     "disabled account",
+			username: "user",
+			password: "password",
+			setupUserRepo: func(mur *mocks.MockUserRepo) {
+				mur.EXPECT().GetUserByUsername("user").Return(models.User{
+					ID:       0,
+					Username: "user",
+					Password: "password",
+					Disabled: true,
+				}, nil)
+			},
+			setupPasswordHasher: func(mph *mocks.MockPasswordHasher) {
+				mph.EXPECT().
+					CompareHash("password", "password").
+					Return(true, nil)
+			},
+			setupTokenProvider: func(mtp *mocks.MockTokenProvider) {},
+			wantAppErr: &errr.AppError{
+				Code:    http.StatusForbidden,
+				Message: "Account is disabled",
+			},
+		},
 		{
 			name:     "failed to generate token",
 			username: "user",
@@ -121,10 +143,9 @@ func Test_authService_Login(t *testing.T) {
 					Return(true, nil)
 			},
 			setupTokenProvider: func(mtp *mocks.MockTokenProvider) {
-				mtp.EXPECT().GenerateToken(models.Claims{ID: 0, Role: ""}).
+				mtp.EXPECT().GenerateToken(models.Claims{ID: 0, Role: models.RoleUser}).
 					Return("", errors.New("error message from token provider"))
 			},
-			want: "",
 			wantAppErr: &errr.AppError{
 				Code:    http.StatusInternalServerError,
 				Message: "Failed to create token",
@@ -147,10 +168,22 @@ func Test_authService_Login(t *testing.T) {
 					Return(true, nil)
 			},
 			setupTokenProvider: func(mtp *mocks.MockTokenProvider) {
-				mtp.EXPECT().GenerateToken(models.Claims{ID: 0, Role: ""}).
+				mtp.EXPECT().GenerateToken(models.Claims{ID: 0, Role: models.RoleUser}).
 					Return("token", nil)
 			},
-			want:       "token",
+			setupRefreshTokenRepo: func(mrr *mocks.MockRefreshTokenRepo) {
+				mrr.EXPECT().SaveRefreshToken(models.RefreshToken{
+					Hash:      hashSecret("refresh"),
+					FamilyID:  hashSecret("refresh"),
+					UserID:    0,
+					ExpiresAt: testNow.Add(time.Hour),
+				}).Return(nil)
+			},
+			want: models.TokenPairDto{
+				AccessToken:  "token",
+				RefreshToken: "refresh",
+				TokenType:    "Bearer",
+			},
 			wantAppErr: nil,
 		},
 	}
@@ -171,7 +204,14 @@ func Test_authService_Login(t *testing.T) {
 			passwordHasher := mocks.NewMockPasswordHasher(passwordHasherCtrl)
 			tt.setupPasswordHasher(passwordHasher)
 
-			as := NewAuthService(userRepo, tokenProvider, passwordHasher)
+			refreshTokenRepo := mocks.NewMockRefreshTokenRepo(userRepoCtrl)
+			if tt.setupRefreshTokenRepo != nil {
+				tt.setupRefreshTokenRepo(refreshTokenRepo)
+			}
+
+			as := NewAuthService(userRepo, refreshTokenRepo, nil, tokenProvider, passwordHasher, time.Minute, time.Hour)
+			as.now = func() time.Time { return testNow }
+			as.newSecret = func() (string, error) { return "refresh", nil }
 			got, gotAppErr := as.Login(tt.username, tt.password)
 			if tt.wantAppErr == nil && gotAppErr != nil {
 				t.Errorf("Login() failed, got err: %v", gotAppErr)
@@ -179,7 +219,7 @@ func Test_authService_Login(t *testing.T) {
 			}
 			if tt.wantAppErr == nil && gotAppErr == nil {
 				if tt.want != got {
-					t.Errorf("Login = %s, wanted: %s", got, tt.want)
+					t.Errorf("Login = %v, wanted: %v", got, tt.want)
 				}
 				return
 			}
@@ -187,10 +227,246 @@ func Test_authService_Login(t *testing.T) {
 				t.Errorf("Login() successed unexpectedly, wanted err: %v", tt.wantAppErr)
 				return
 			}
-			if tt.wantAppErr != nil && *tt.wantAppErr != *gotAppErr {
+			if tt.wantAppErr != nil && !reflect.DeepEqual(tt.wantAppErr, gotAppErr) {
 				t.Errorf("wanted err: %v, got %v", tt.wantAppErr, gotAppErr)
 				return
 			}
 		})
 	}
 }
+
+func Test_authService_Refresh(t *testing.T) {
+	stored := models.RefreshToken{
+		Hash:      hashSecret("old"),
+		FamilyID:  "family",
+		UserID:    4321,
+		ExpiresAt: testNow.Add(time.Minute),
+	}
+	used := stored
+	used.UsedAt = testNow.Add(-time.Minute)
+	expired := stored
+	expired.ExpiresAt = testNow
+
+	tests := []struct {
+		name                  string
+		setupRefreshTokenRepo func(mrr *mocks.MockRefreshTokenRepo)
+		setupUserRepo         func(mur *mocks.MockUserRepo)
+		setupTokenProvider    func(mtp *mocks.MockTokenProvider)
+		want                  models.TokenPairDto
+		wantAppErr            *errr.AppError
+	}{
+		{
+			name: "unknown token",
+			setupRefreshTokenRepo: func(mrr *mocks.MockRefreshTokenRepo) {
+				mrr.EXPECT().GetRefreshToken(hashSecret("old")).
+					Return(models.RefreshToken{}, errr.NewNotFoundError("Refresh token not found"))
+			},
+			wantAppErr: errr.NewUnauthenticatedError("Invalid refresh token"),
+		},
+		{
+			name: "reused token revokes its family",
+			setupRefreshTokenRepo: func(mrr *mocks.MockRefreshTokenRepo) {
+				mrr.EXPECT().GetRefreshToken(hashSecret("old")).Return(used, nil)
+				mrr.EXPECT().RevokeTokenFamily("family").Return(nil)
+			},
+			wantAppErr: errr.NewUnauthenticatedError("Refresh token was already used, sign in again"),
+		},
+		{
+			name: "expired token",
+			setupRefreshTokenRepo: func(mrr *mocks.MockRefreshTokenRepo) {
+				mrr.EXPECT().GetRefreshToken(hashSecret("old")).Return(expired, nil)
+			},
+			wantAppErr: errr.NewUnauthenticatedError("Refresh token has expired"),
+		},
+		{
+			name: "token exchanged concurrently revokes its family",
+			setupRefreshTokenRepo: func(mrr *mocks.MockRefreshTokenRepo) {
+				mrr.EXPECT().GetRefreshToken(hashSecret("old")).Return(stored, nil)
+				mrr.EXPECT().UseRefreshToken(hashSecret("old"), testNow).
+					Return(errr.NewDuplicateError("Refresh token was already used"))
+				mrr.EXPECT().RevokeTokenFamily("family").Return(nil)
+			},
+			wantAppErr: errr.NewUnauthenticatedError("Refresh token was already used, sign in again"),
+		},
+		{
+			name: "deleted user",
+			setupRefreshTokenRepo: func(mrr *mocks.MockRefreshTokenRepo) {
+				mrr.EXPECT().GetRefreshToken(hashSecret("old")).Return(stored, nil)
+				mrr.EXPECT().UseRefreshToken(hashSecret("old"), testNow).Return(nil)
+			},
+			setupUserRepo: func(mur *mocks.MockUserRepo) {
+				mur.EXPECT().GetUser(int64(4321)).
+					Return(models.User{}, errr.NewNotFoundError("User not Found"))
+			},
+			wantAppErr: errr.NewUnauthenticatedError("Invalid refresh token"),
+		},
+		{
+			name: "disabled account",
+			setupRefreshTokenRepo: func(mrr *mocks.MockRefreshTokenRepo) {
+				mrr.EXPECT().GetRefreshToken(hashSecret("old")).Return(stored, nil)
+				mrr.EXPECT().UseRefreshToken(hashSecret("old"), testNow).Return(nil)
+			},
+			setupUserRepo: func(mur *mocks.MockUserRepo) {
+				mur.EXPECT().GetUser(int64(4321)).Return(models.User{ID: 4321, Disabled: true}, nil)
+			},
+			wantAppErr: errr.NewUnauthorizedError("Account is disabled"),
+		},
+		{
+			name: "rotates the token within its family",
+			setupRefreshTokenRepo: func(mrr *mocks.MockRefreshTokenRepo) {
+				mrr.EXPECT().GetRefreshToken(hashSecret("old")).Return(stored, nil)
+				mrr.EXPECT().UseRefreshToken(hashSecret("old"), testNow).Return(nil)
+				mrr.EXPECT().SaveRefreshToken(models.RefreshToken{
+					Hash:      hashSecret("refresh"),
+					FamilyID:  "family",
+					UserID:    4321,
+					ExpiresAt: testNow.Add(time.Hour),
+				}).Return(nil)
+			},
+			setupUserRepo: func(mur *mocks.MockUserRepo) {
+				mur.EXPECT().GetUser(int64(4321)).
+					Return(models.User{ID: 4321, TimeZone: "Asia/Kolkata"}, nil)
+			},
+			setupTokenProvider: func(mtp *mocks.MockTokenProvider) {
+				mtp.EXPECT().GenerateToken(models.Claims{ID: 4321, Role: models.RoleUser, TimeZone: "Asia/Kolkata"}).
+					Return("token", nil)
+			},
+			want: models.TokenPairDto{
+				AccessToken:  "token",
+				RefreshToken: "refresh",
+				TokenType:    "Bearer",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			refreshTokenRepo := mocks.NewMockRefreshTokenRepo(ctrl)
+			tt.setupRefreshTokenRepo(refreshTokenRepo)
+			userRepo := mocks.NewMockUserRepo(ctrl)
+			if tt.setupUserRepo != nil {
+				tt.setupUserRepo(userRepo)
+			}
+			tokenProvider := mocks.NewMockTokenProvider(ctrl)
+			if tt.setupTokenProvider != nil {
+				tt.setupTokenProvider(tokenProvider)
+			}
+
+			as := NewAuthService(userRepo, refreshTokenRepo, nil, tokenProvider, nil, time.Minute, time.Hour)
+			as.now = func() time.Time { return testNow }
+			as.newSecret = func() (string, error) { return "refresh", nil }
+
+			got, gotAppErr := as.Refresh("old")
+			if !reflect.DeepEqual(tt.wantAppErr, gotAppErr) {
+				t.Errorf("Refresh() err = %v, wanted %v", gotAppErr, tt.wantAppErr)
+			}
+			if got != tt.want {
+				t.Errorf("Refresh() = %v, wanted %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_authService_Logout(t *testing.T) {
+	claims := models.Claims{ID: 4321, TokenID: "jti", ExpiresAt: testNow.Add(time.Minute)}
+	revocation := models.Revocation{TokenID: "jti", UserID: 4321, ExpiresAt: testNow.Add(time.Minute)}
+
+	tests := []struct {
+		name                  string
+		refreshToken          string
+		setupRefreshTokenRepo func(mrr *mocks.MockRefreshTokenRepo)
+		setupRevocationRepo   func(mvr *mocks.MockRevocationRepo)
+		wantAppErr            *errr.AppError
+	}{
+		{
+			name:                  "access token only",
+			setupRefreshTokenRepo: func(mrr *mocks.MockRefreshTokenRepo) {},
+			setupRevocationRepo: func(mvr *mocks.MockRevocationRepo) {
+				mvr.EXPECT().SaveRevocation(revocation).Return(nil)
+			},
+		},
+		{
+			name:         "with the refresh token",
+			refreshToken: "refresh",
+			setupRefreshTokenRepo: func(mrr *mocks.MockRefreshTokenRepo) {
+				mrr.EXPECT().GetRefreshToken(hashSecret("refresh")).
+					Return(models.RefreshToken{FamilyID: "family", UserID: 4321}, nil)
+				mrr.EXPECT().RevokeTokenFamily("family").Return(nil)
+			},
+			setupRevocationRepo: func(mvr *mocks.MockRevocationRepo) {
+				mvr.EXPECT().SaveRevocation(revocation).Return(nil)
+			},
+		},
+		{
+			name:         "with another user's refresh token",
+			refreshToken: "refresh",
+			setupRefreshTokenRepo: func(mrr *mocks.MockRefreshTokenRepo) {
+				mrr.EXPECT().GetRefreshToken(hashSecret("refresh")).
+					Return(models.RefreshToken{FamilyID: "family", UserID: 99}, nil)
+			},
+			setupRevocationRepo: func(mvr *mocks.MockRevocationRepo) {
+				mvr.EXPECT().SaveRevocation(revocation).Return(nil)
+			},
+		},
+		{
+			name:         "with an unknown refresh token",
+			refreshToken: "refresh",
+			setupRefreshTokenRepo: func(mrr *mocks.MockRefreshTokenRepo) {
+				mrr.EXPECT().GetRefreshToken(hashSecret("refresh")).
+					Return(models.RefreshToken{}, errr.NewNotFoundError("Refresh token not found"))
+			},
+			setupRevocationRepo: func(mvr *mocks.MockRevocationRepo) {
+				mvr.EXPECT().SaveRevocation(revocation).Return(nil)
+			},
+		},
+		{
+			name:                  "revocation fails",
+			setupRefreshTokenRepo: func(mrr *mocks.MockRefreshTokenRepo) {},
+			setupRevocationRepo: func(mvr *mocks.MockRevocationRepo) {
+				mvr.EXPECT().SaveRevocation(revocation).
+					Return(errr.NewUnexpectedError("Unable to revoke token due to internal server error"))
+			},
+			wantAppErr: errr.NewUnexpectedError("Unable to revoke token due to internal server error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			refreshTokenRepo := mocks.NewMockRefreshTokenRepo(ctrl)
+			tt.setupRefreshTokenRepo(refreshTokenRepo)
+			revocationRepo := mocks.NewMockRevocationRepo(ctrl)
+			tt.setupRevocationRepo(revocationRepo)
+
+			as := NewAuthService(nil, refreshTokenRepo, revocationRepo, nil, nil, time.Minute, time.Hour)
+			as.now = func() time.Time { return testNow }
+
+			gotAppErr := as.Logout(claims, tt.refreshToken)
+			if !reflect.DeepEqual(tt.wantAppErr, gotAppErr) {
+				t.Errorf("Logout() err = %v, wanted %v", gotAppErr, tt.wantAppErr)
+			}
+		})
+	}
+}
+
+func Test_authService_LogoutAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	refreshTokenRepo := mocks.NewMockRefreshTokenRepo(ctrl)
+	refreshTokenRepo.EXPECT().RevokeUserTokens(int64(4321)).Return(nil)
+	revocationRepo := mocks.NewMockRevocationRepo(ctrl)
+	revocationRepo.EXPECT().SaveRevocation(models.Revocation{
+		UserID:      4321,
+		IssuedUntil: testNow,
+		ExpiresAt:   testNow.Add(time.Minute),
+	}).Return(nil)
+
+	as := NewAuthService(nil, refreshTokenRepo, revocationRepo, nil, nil, time.Minute, time.Hour)
+	as.now = func() time.Time { return testNow }
+
+	appErr := as.LogoutAll(models.Claims{ID: 4321, TokenID: "jti"})
+	if appErr != nil {
+		t.Errorf("LogoutAll() failed: %v", appErr)
+	}
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"strconv"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/internal/ports"
+)
+
+type labelService struct {
+	labelRepo ports.LabelRepo
+}
+
+func NewLabelService(labelRepo ports.LabelRepo) *labelService {
+	return &labelService{
+		labelRepo: labelRepo,
+	}
+}
+
+func (ls *labelService) CreateLabel(
+	labelReq models.LabelRequestDto,
+	claims models.Claims,
+) (models.LabelResponseDto, *errr.AppError) {
+	label := labelReq.ToLabel()
+	label.UserID = claims.ID
+	if !label.IsValidLabel() {
+		return models.LabelResponseDto{}, errr.NewBadRequestError(
+			"Invalid label, name and a #rrggbb color are required",
+		)
+	}
+
+	label, appErr := ls.labelRepo.SaveLabel(label)
+	if appErr != nil {
+		return models.LabelResponseDto{}, appErr
+	}
+
+	return label.ToDto(), nil
+}
+
+func (ls *labelService) UpdateLabel(
+	labelIDStr string,
+	labelReq models.LabelRequestDto,
+	claims models.Claims,
+) *errr.AppError {
+	labelID, err := strconv.ParseInt(labelIDStr, 10, 64)
+	if err != nil {
+		return errr.NewBadRequestError("Invalid label id")
+	}
+
+	if labelReq.Name == "" && labelReq.Color == "" {
+		return errr.NewBadRequestError("Invalid label format")
+	}
+	label := labelReq.ToLabel()
+	if label.Name != "" && !label.IsValidName() {
+		return errr.NewBadRequestError("Invalid label name")
+	}
+	if label.Color != "" && !label.IsValidColor() {
+		return errr.NewBadRequestError("Invalid label color, use #rrggbb")
+	}
+	label.UserID = claims.ID
+
+	return ls.labelRepo.UpdateLabel(labelID, label)
+}
+
+func (ls *labelService) DeleteLabel(idString string, claims models.Claims) *errr.AppError {
+	id, err := strconv.ParseInt(idString, 10, 64)
+	if err != nil {
+		return errr.NewBadRequestError("Invalid label id")
+	}
+
+	return ls.labelRepo.DeleteLabel(id, claims.ID)
+}
+
+func (ls *labelService) GetLabels(claims models.Claims) ([]models.LabelResponseDto, *errr.AppError) {
+	labels, appErr := ls.labelRepo.GetLabels(claims.ID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	labelRes := make([]models.LabelResponseDto, len(labels))
+	for i := range labels {
+		labelRes[i] = labels[i].ToDto()
+	}
+
+	return labelRes, nil
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/test/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+func Test_labelService_CreateLabel(t *testing.T) {
+	tests := []struct {
+		name           string
+		setupLabelRepo func(mlr *mocks.MockLabelRepo)
+		labelReq       models.LabelRequestDto
+		want           models.LabelResponseDto
+		appErr         *errr.AppError
+	}{
+		{
+			name: "successfully created label",
+			setupLabelRepo: func(mlr *mocks.MockLabelRepo) {
+				mlr.EXPECT().SaveLabel(models.Label{
+					UserID: 1234,
+					Name:   "work",
+					Color:  "#1e90ff",
+				}).Return(models.Label{ID: 7, UserID: 1234, Name: "work", Color: "#1e90ff"}, nil)
+			},
+			labelReq: models.LabelRequestDto{Name: "work", Color: "#1e90ff"},
+			want:     models.LabelResponseDto{ID: "7", Name: "work", Color: "#1e90ff"},
+		},
+		{
+			name:           "invalid color",
+			setupLabelRepo: func(mlr *mocks.MockLabelRepo) {},
+			labelReq:       models.LabelRequestDto{Name: "work", Color: "blue"},
+			appErr: &errr.AppError{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid label, name and a #rrggbb color are required",
+			},
+		},
+		{
+			name: "label repo failed to save label",
+			setupLabelRepo: func(mlr *mocks.MockLabelRepo) {
+				mlr.EXPECT().SaveLabel(gomock.Any()).Return(
+					models.Label{},
+					&errr.AppError{Code: http.StatusConflict, Message: "label already exists"},
+				)
+			},
+			labelReq: models.LabelRequestDto{Name: "work", Color: "#1e90ff"},
+			appErr:   &errr.AppError{Code: http.StatusConflict, Message: "label already exists"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mlr := mocks.NewMockLabelRepo(ctrl)
+			tt.setupLabelRepo(mlr)
+			ls := NewLabelService(mlr)
+
+			got, appErr := ls.CreateLabel(tt.labelReq, models.Claims{ID: 1234})
+			if tt.appErr == nil && appErr != nil {
+				t.Errorf("CreateLabel() failed, got err: %v.", appErr)
+				return
+			}
+			if tt.appErr != nil && appErr == nil {
+				t.Errorf("CreateLabel() successed unexpectedly, wanted err: %v.", tt.appErr)
+				return
+			}
+			if tt.appErr != nil && !reflect.DeepEqual(tt.appErr, appErr) {
+				t.Errorf("CreateLabel() = %v, want %v", appErr, tt.appErr)
+			}
+			if got != tt.want {
+				t.Errorf("CreateLabel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_labelService_UpdateLabel(t *testing.T) {
+	tests := []struct {
+		name           string
+		setupLabelRepo func(mlr *mocks.MockLabelRepo)
+		id             string
+		labelReq       models.LabelRequestDto
+		appErr         *errr.AppError
+	}{
+		{
+			name: "successfully renamed label",
+			setupLabelRepo: func(mlr *mocks.MockLabelRepo) {
+				mlr.EXPECT().UpdateLabel(int64(7), models.Label{UserID: 1234, Name: "home"}).
+					Return(nil)
+			},
+			id:       "7",
+			labelReq: models.LabelRequestDto{Name: "home"},
+		},
+		{
+			name:           "invalid id",
+			setupLabelRepo: func(mlr *mocks.MockLabelRepo) {},
+			id:             "seven",
+			labelReq:       models.LabelRequestDto{Name: "home"},
+			appErr:         &errr.AppError{Code: http.StatusBadRequest, Message: "Invalid label id"},
+		},
+		{
+			name:           "nothing to update",
+			setupLabelRepo: func(mlr *mocks.MockLabelRepo) {},
+			id:             "7",
+			appErr:         &errr.AppError{Code: http.StatusBadRequest, Message: "Invalid label format"},
+		},
+		{
+			name:           "invalid color",
+			setupLabelRepo: func(mlr *mocks.MockLabelRepo) {},
+			id:             "7",
+			labelReq:       models.LabelRequestDto{Color: "red"},
+			appErr: &errr.AppError{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid label color, use #rrggbb",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mlr := mocks.NewMockLabelRepo(ctrl)
+			tt.setupLabelRepo(mlr)
+			ls := NewLabelService(mlr)
+
+			got := ls.UpdateLabel(tt.id, tt.labelReq, models.Claims{ID: 1234})
+			if tt.appErr == nil && got != nil {
+				t.Errorf("UpdateLabel() failed, got err: %v.", got)
+				return
+			}
+			if tt.appErr != nil && got == nil {
+				t.Errorf("UpdateLabel() successed unexpectedly, wanted err: %v.", tt.appErr)
+				return
+			}
+			if tt.appErr != nil && !reflect.DeepEqual(tt.appErr, got) {
+				t.Errorf("UpdateLabel() = %v, want %v", got, tt.appErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"strconv"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/internal/ports"
+)
+
+type projectService struct {
+	projectRepo ports.ProjectRepo
+}
+
+func NewProjectService(projectRepo ports.ProjectRepo) *projectService {
+	return &projectService{
+		projectRepo: projectRepo,
+	}
+}
+
+func (ps *projectService) CreateProject(
+	projectReq models.ProjectRequestDto,
+	claims models.Claims,
+) (models.ProjectResponseDto, *errr.AppError) {
+	project := projectReq.ApplyTo(models.Project{})
+	project.UserID = claims.ID
+	if !project.IsValidName() || !project.IsValidColor() {
+		return models.ProjectResponseDto{}, errr.NewBadRequestError(
+			"Invalid project, name and a #rrggbb color are required",
+		)
+	}
+
+	project, appErr := ps.projectRepo.SaveProject(project)
+	if appErr != nil {
+		return models.ProjectResponseDto{}, appErr
+	}
+
+	return project.ToDto(), nil
+}
+
+func (ps *projectService) GetProject(
+	idString string,
+	claims models.Claims,
+) (models.ProjectResponseDto, *errr.AppError) {
+	id, err := strconv.ParseInt(idString, 10, 64)
+	if err != nil {
+		return models.ProjectResponseDto{}, errr.NewBadRequestError("Invalid project id")
+	}
+
+	project, appErr := ps.projectRepo.GetProject(id, claims.ID)
+	if appErr != nil {
+		return models.ProjectResponseDto{}, appErr
+	}
+
+	return project.ToDto(), nil
+}
+
+func (ps *projectService) UpdateProject(
+	projectIDStr string,
+	projectReq models.ProjectRequestDto,
+	claims models.Claims,
+) *errr.AppError {
+	projectID, err := strconv.ParseInt(projectIDStr, 10, 64)
+	if err != nil {
+		return errr.NewBadRequestError("Invalid project id")
+	}
+
+	if projectReq.Name == "" && projectReq.Color == "" && projectReq.Archived == nil &&
+		projectReq.Position == nil {
+		return errr.NewBadRequestError("Invalid project format")
+	}
+
+	project, appErr := ps.projectRepo.GetProject(projectID, claims.ID)
+	if appErr != nil {
+		return appErr
+	}
+	project = projectReq.ApplyTo(project)
+	if !project.IsValidName() {
+		return errr.NewBadRequestError("Invalid project name")
+	}
+	if !project.IsValidColor() {
+		return errr.NewBadRequestError("Invalid project color, use #rrggbb")
+	}
+	if project.Position < 0 {
+		return errr.NewBadRequestError("Invalid project position")
+	}
+
+	return ps.projectRepo.UpdateProject(projectID, project)
+}
+
+func (ps *projectService) DeleteProject(idString string, claims models.Claims) *errr.AppError {
+	id, err := strconv.ParseInt(idString, 10, 64)
+	if err != nil {
+		return errr.NewBadRequestError("Invalid project id")
+	}
+
+	return ps.projectRepo.DeleteProject(id, claims.ID)
+}
+
+func (ps *projectService) GetProjects(
+	claims models.Claims,
+) ([]models.ProjectResponseDto, *errr.AppError) {
+	projects, appErr := ps.projectRepo.GetProjects(claims.ID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	projectRes := make([]models.ProjectResponseDto, len(projects))
+	for i := range projects {
+		projectRes[i] = projects[i].ToDto()
+	}
+
+	return projectRes, nil
+}
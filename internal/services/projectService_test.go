@@ -0,0 +1,107 @@
+package services
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/test/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+func Test_projectService_CreateProject(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mpr := mocks.NewMockProjectRepo(ctrl)
+	mpr.EXPECT().SaveProject(models.Project{UserID: 1234, Name: "work", Color: "#1e90ff"}).
+		Return(models.Project{ID: 7, UserID: 1234, Name: "work", Color: "#1e90ff", Position: 2}, nil)
+	ps := NewProjectService(mpr)
+
+	got, appErr := ps.CreateProject(
+		models.ProjectRequestDto{Name: "work", Color: "#1e90ff"},
+		models.Claims{ID: 1234},
+	)
+	want := models.ProjectResponseDto{ID: "7", Name: "work", Color: "#1e90ff", Position: 2}
+	if appErr != nil || got != want {
+		t.Errorf("CreateProject() = %v, %v, want %v", got, appErr, want)
+	}
+
+	_, appErr = ps.CreateProject(models.ProjectRequestDto{Name: "work"}, models.Claims{ID: 1234})
+	if appErr == nil || appErr.Code != http.StatusBadRequest {
+		t.Errorf("CreateProject() without a colour = %v, want bad request", appErr)
+	}
+}
+
+func Test_projectService_UpdateProject(t *testing.T) {
+	stored := models.Project{ID: 7, UserID: 1234, Name: "work", Color: "#1e90ff", Position: 2}
+	archived := true
+	position := -1
+
+	tests := []struct {
+		name             string
+		setupProjectRepo func(mpr *mocks.MockProjectRepo)
+		projectID        string
+		projectReq       models.ProjectRequestDto
+		appErr           *errr.AppError
+	}{
+		{
+			name: "archives project keeping the other fields",
+			setupProjectRepo: func(mpr *mocks.MockProjectRepo) {
+				mpr.EXPECT().GetProject(int64(7), int64(1234)).Return(stored, nil)
+				want := stored
+				want.Archived = true
+				mpr.EXPECT().UpdateProject(int64(7), want).Return(nil)
+			},
+			projectID:  "7",
+			projectReq: models.ProjectRequestDto{Archived: &archived},
+		},
+		{
+			name:             "empty request",
+			setupProjectRepo: func(mpr *mocks.MockProjectRepo) {},
+			projectID:        "7",
+			appErr:           errr.NewBadRequestError("Invalid project format"),
+		},
+		{
+			name: "negative position",
+			setupProjectRepo: func(mpr *mocks.MockProjectRepo) {
+				mpr.EXPECT().GetProject(int64(7), int64(1234)).Return(stored, nil)
+			},
+			projectID:  "7",
+			projectReq: models.ProjectRequestDto{Position: &position},
+			appErr:     errr.NewBadRequestError("Invalid project position"),
+		},
+		{
+			name: "project of another user",
+			setupProjectRepo: func(mpr *mocks.MockProjectRepo) {
+				mpr.EXPECT().GetProject(int64(7), int64(1234)).Return(
+					models.Project{}, errr.NewUnauthorizedError("Unauthorized to view project"),
+				)
+			},
+			projectID:  "7",
+			projectReq: models.ProjectRequestDto{Name: "home"},
+			appErr:     errr.NewUnauthorizedError("Unauthorized to view project"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mpr := mocks.NewMockProjectRepo(ctrl)
+			tt.setupProjectRepo(mpr)
+			ps := NewProjectService(mpr)
+
+			appErr := ps.UpdateProject(tt.projectID, tt.projectReq, models.Claims{ID: 1234})
+			if tt.appErr == nil && appErr != nil {
+				t.Errorf("UpdateProject() failed, got err: %v.", appErr)
+				return
+			}
+			if tt.appErr != nil && (appErr == nil || !reflect.DeepEqual(tt.appErr, appErr)) {
+				t.Errorf("UpdateProject() = %v, want %v", appErr, tt.appErr)
+			}
+		})
+	}
+}
@@ -1,74 +1,408 @@
 package services
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/jsonpatch"
 	"github.com/Jashanveer-Singh/todo-go/internal/models"
 	"github.com/Jashanveer-Singh/todo-go/internal/ports"
 )
 
+const (
+	defaultTaskPageSize = 50
+	maxTaskPageSize     = 200
+
+	defaultOccurrences = 5
+	maxOccurrences     = 50
+
+	defaultSearchResults = 20
+	maxSearchResults     = 100
+	maxSearchLength      = 200
+	// maxIndexAttempts is how often a search reads the tasks of a user to
+	// index them when they keep changing meanwhile.
+	maxIndexAttempts = 3
+)
+
 type taskService struct {
-	taskRepo ports.TaskRepo
+	taskRepo     ports.TaskRepo
+	labelRepo    ports.LabelRepo
+	projectRepo  ports.ProjectRepo
+	workflowRepo ports.WorkflowRepo
+	index        ports.TaskIndex
+	now          func() time.Time
 }
 
-func NewTaskService(taskRepo ports.TaskRepo) *taskService {
+func NewTaskService(
+	taskRepo ports.TaskRepo,
+	labelRepo ports.LabelRepo,
+	projectRepo ports.ProjectRepo,
+	workflowRepo ports.WorkflowRepo,
+	index ports.TaskIndex,
+) *taskService {
 	return &taskService{
-		taskRepo: taskRepo,
+		taskRepo:     taskRepo,
+		labelRepo:    labelRepo,
+		projectRepo:  projectRepo,
+		workflowRepo: workflowRepo,
+		index:        index,
+		now:          time.Now,
 	}
 }
 
+// RebuildIndex indexes the tasks of every user for search, replacing what the
+// index held. It runs before tasks are served, so none change meanwhile.
+func (ts *taskService) RebuildIndex() *errr.AppError {
+	tasks, appErr := ts.taskRepo.GetAllTasks()
+	if appErr != nil {
+		return appErr
+	}
+
+	byUser := map[int64][]models.Task{}
+	for _, task := range tasks {
+		byUser[task.UserID] = append(byUser[task.UserID], task)
+	}
+	for userID, tasks := range byUser {
+		ts.index.Replace(userID, tasks, ts.index.Generation(userID))
+	}
+
+	return nil
+}
+
 func (ts *taskService) CreateTask(
 	taskReq models.TaskRequestDto,
 	claims models.Claims,
-) *errr.AppError {
-	task := taskReq.ToTask()
-	task.Status = 2
+) (models.TaskResponseDto, *errr.AppError) {
+	if violations := taskReq.Validate(claims.Location()); len(violations) > 0 {
+		return models.TaskResponseDto{}, invalidRequest("Invalid task", violations)
+	}
+	task, err := taskReq.ToTaskIn(claims.Location())
+	if err != nil {
+		return models.TaskResponseDto{}, errr.NewBadRequestError("Invalid task")
+	}
 	task.UserID = claims.ID
-	if !task.IsValidTask() {
-		return &errr.AppError{
-			Message: "Invalid task",
-			Code:    http.StatusBadRequest,
+	if taskReq.ParentID != "" {
+		task.ParentID, err = strconv.ParseInt(taskReq.ParentID, 10, 64)
+		if err != nil {
+			return models.TaskResponseDto{}, errr.NewBadRequestError("Invalid parent task id")
 		}
 	}
+	if task.Recurrence != nil && !task.IsRecurring() {
+		task.Recurrence = nil
+	}
+	if task.IsRecurring() {
+		recurrence := task.Recurrence.AnchoredAt(task.DueAt, claims.Location())
+		task.Recurrence = &recurrence
+	}
+	task.CreatedAt = ts.now()
+	task.UpdatedAt = task.CreatedAt
 
-	appErr := ts.taskRepo.SaveTask(task)
-	if appErr != nil {
+	// The workflow is read and the labels and project are checked in the
+	// transaction saving the task, so a workflow saved meanwhile is checked
+	// against it and a label or project deleted meanwhile is detached from it.
+	var workflow models.Workflow
+	appErr := ts.transaction(func(tx *taskService) *errr.AppError {
+		var appErr *errr.AppError
+		workflow, appErr = tx.workflowRepo.GetWorkflow(claims.ID)
+		if appErr != nil {
+			return appErr
+		}
+		// New tasks always start in the first status of the workflow.
+		task.Status = workflow.Initial().ID
+		task.Done = workflow.Initial().Terminal
+
+		labelIDs, appErr := tx.parseLabelIDs(taskReq.LabelIDs, claims.ID)
+		if appErr != nil {
+			return appErr
+		}
+		task.LabelIDs = labelIDs
+		task.ProjectID, appErr = tx.parseProjectID(taskReq.ProjectID, claims.ID)
+		if appErr != nil {
+			return appErr
+		}
+
+		task, appErr = tx.taskRepo.SaveTask(task)
 		return appErr
+	})
+	if appErr != nil {
+		return models.TaskResponseDto{}, appErr
 	}
+	ts.index.Put(task)
 
-	return nil
+	return task.ToDto(workflow, claims.Location(), ts.now()), nil
 }
 
+// UpdateTask replaces the task with id by taskReq.
 func (ts *taskService) UpdateTask(
 	taskIDStr string,
+	version int64,
 	taskReq models.TaskRequestDto,
 	claims models.Claims,
-) *errr.AppError {
+) (int64, *errr.AppError) {
 	taskID, err := strconv.ParseInt(taskIDStr, 10, 64)
 	if err != nil {
-		return errr.NewBadRequestError("Invalid task id")
+		return 0, errr.NewBadRequestError("Invalid task id")
 	}
 
-	if taskReq.Title == "" && taskReq.Desc == "" && !taskReq.IsValidStatus() {
-		return &errr.AppError{
-			Message: "Invalid task format",
-			Code:    http.StatusBadRequest,
-		}
+	stored, appErr := ts.taskRepo.GetTask(taskID, claims.ID)
+	if appErr != nil {
+		return 0, appErr
+	}
+
+	return ts.replaceTask(stored, version, taskReq, claims)
+}
+
+// PatchTask applies patch to the task with id, as an update of the fields
+// it changes.
+func (ts *taskService) PatchTask(
+	taskIDStr string,
+	version int64,
+	patch models.TaskPatch,
+	claims models.Claims,
+) (int64, *errr.AppError) {
+	taskID, err := strconv.ParseInt(taskIDStr, 10, 64)
+	if err != nil {
+		return 0, errr.NewBadRequestError("Invalid task id")
 	}
-	task := taskReq.ToTask()
-	task.UserID = claims.ID
 
-	appErr := ts.taskRepo.UpdateTask(taskID, task)
+	stored, appErr := ts.taskRepo.GetTask(taskID, claims.ID)
 	if appErr != nil {
+		return 0, appErr
+	}
+	workflow, appErr := ts.workflowRepo.GetWorkflow(claims.ID)
+	if appErr != nil {
+		return 0, appErr
+	}
+
+	doc, err := json.Marshal(stored.ToRequestDto(workflow.StatusName(stored.Status), claims.Location()))
+	if err != nil {
+		return 0, errr.NewUnexpectedError("Unable to patch task due to internal server error")
+	}
+	switch patch.Format {
+	case models.MergePatch:
+		doc, err = jsonpatch.MergePatch(doc, patch.Doc)
+	case models.JSONPatch:
+		doc, err = jsonpatch.Apply(doc, patch.Doc)
+	default:
+		return 0, errr.NewBadRequestError("Unknown patch format")
+	}
+	if errors.Is(err, jsonpatch.ErrTestFailed) {
+		return 0, errr.NewDuplicateError("Task does not pass the test of the patch")
+	}
+	if err != nil {
+		return 0, errr.NewBadRequestError("Invalid patch, " + err.Error())
+	}
+
+	var taskReq models.TaskRequestDto
+	decoder := json.NewDecoder(bytes.NewReader(doc))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&taskReq); err != nil {
+		return 0, errr.NewBadRequestError("Patched task is not a valid task")
+	}
+
+	return ts.replaceTask(stored, version, taskReq, claims)
+}
+
+// replaceTask replaces stored by taskReq, starting the next occurrence of
+// the task when it gets done, and returns the version of the replaced task.
+// A version other than 0 has to be the one of stored.
+func (ts *taskService) replaceTask(
+	stored models.Task,
+	version int64,
+	taskReq models.TaskRequestDto,
+	claims models.Claims,
+) (int64, *errr.AppError) {
+	if version != 0 && version != stored.Version {
+		return 0, errr.NewPreconditionFailedError("Task has changed since it was read")
+	}
+
+	// The status is resolved through the workflow and the labels and project
+	// are checked in the transaction saving the task, so a workflow saved
+	// meanwhile is checked against it and a label or project deleted
+	// meanwhile is detached from it. The next occurrence is saved along with
+	// the update, so a task never gets done without it.
+	var task, next models.Task
+	appErr := ts.transaction(func(tx *taskService) *errr.AppError {
+		var appErr *errr.AppError
+		task, next, appErr = tx.replace(stored, taskReq, claims)
 		return appErr
+	})
+	if appErr != nil {
+		return 0, appErr
 	}
 
-	return nil
+	ts.index.Put(task)
+	if next.ID != 0 {
+		ts.index.Put(next)
+	}
+
+	return task.Version, nil
 }
 
-func (ts *taskService) DeleteTask(idString string, claims models.Claims) *errr.AppError {
+// replace does the work of replaceTask within its transaction, returning the
+// replaced task and the next occurrence it started, if any.
+func (ts *taskService) replace(
+	stored models.Task,
+	taskReq models.TaskRequestDto,
+	claims models.Claims,
+) (models.Task, models.Task, *errr.AppError) {
+	workflow, appErr := ts.workflowRepo.GetWorkflow(claims.ID)
+	if appErr != nil {
+		return models.Task{}, models.Task{}, appErr
+	}
+
+	violations := taskReq.Validate(claims.Location())
+	status, known := workflow.StatusNamed(taskReq.Status)
+	if taskReq.Status == "" {
+		violations.Add("status", models.RuleRequired, "Status is required")
+	} else if !known {
+		violations.Add("status", models.RuleUnknownStatus, "Unknown status")
+	}
+	if len(violations) > 0 {
+		return models.Task{}, models.Task{}, invalidRequest("Invalid task", violations)
+	}
+	task, err := taskReq.ToTaskIn(claims.Location())
+	if err != nil {
+		return models.Task{}, models.Task{}, errr.NewBadRequestError("Invalid task")
+	}
+	task.UserID = claims.ID
+	task.Status = status.ID
+	task.Done = status.Terminal
+	if task.Recurrence != nil && !task.IsRecurring() {
+		task.Recurrence = nil
+	}
+	if task.IsRecurring() {
+		recurrence := task.Recurrence.AnchoredAt(task.DueAt, claims.Location())
+		task.Recurrence = &recurrence
+	}
+	if !workflow.CanMove(stored.Status, task.Status) {
+		return models.Task{}, models.Task{}, errr.NewDuplicateError(fmt.Sprintf(
+			"Status can't change from %s to %s",
+			workflow.StatusName(stored.Status), workflow.StatusName(task.Status),
+		))
+	}
+	labelIDs, appErr := ts.parseLabelIDs(taskReq.LabelIDs, claims.ID)
+	if appErr != nil {
+		return models.Task{}, models.Task{}, appErr
+	}
+	task.LabelIDs = labelIDs
+	task.ProjectID, appErr = ts.parseProjectID(taskReq.ProjectID, claims.ID)
+	if appErr != nil {
+		return models.Task{}, models.Task{}, appErr
+	}
+	task.UpdatedAt = ts.now()
+	task.Version = stored.Version
+
+	appErr = ts.taskRepo.UpdateTask(stored.ID, task)
+	if appErr != nil {
+		return models.Task{}, models.Task{}, appErr
+	}
+	task.ID = stored.ID
+	task.Version = stored.Version + 1
+
+	var next models.Task
+	if task.Done && !stored.Done {
+		next, appErr = ts.spawnNextOccurrence(stored.ID, workflow, claims)
+	}
+	return task, next, appErr
+}
+
+// spawnNextOccurrence creates the task following the just completed task
+// with id when it recurs, due at the next date of its rule and in the first
+// status of workflow. It returns the task it created, if any.
+func (ts *taskService) spawnNextOccurrence(
+	id int64,
+	workflow models.Workflow,
+	claims models.Claims,
+) (models.Task, *errr.AppError) {
+	done, appErr := ts.taskRepo.GetTask(id, claims.ID)
+	if appErr != nil || !done.IsRecurring() {
+		return models.Task{}, appErr
+	}
+
+	next := models.Task{
+		Title:      done.Title,
+		Desc:       done.Desc,
+		Status:     workflow.Initial().ID,
+		Done:       workflow.Initial().Terminal,
+		Priority:   done.Priority,
+		UserID:     done.UserID,
+		ParentID:   done.ParentID,
+		ProjectID:  done.ProjectID,
+		LabelIDs:   done.LabelIDs,
+		Recurrence: done.Recurrence,
+		SeriesID:   done.SeriesID,
+		DueAt:      done.Recurrence.Next(done.DueAt, ts.now(), claims.Location()),
+		CreatedAt:  ts.now(),
+	}
+	next.UpdatedAt = next.CreatedAt
+	if next.SeriesID == 0 {
+		next.SeriesID = done.ID
+	}
+	if !done.StartAt.IsZero() {
+		next.StartAt = next.DueAt.Add(done.StartAt.Sub(done.DueAt))
+	}
+	for _, item := range done.Checklist {
+		next.Checklist = append(next.Checklist, models.ChecklistItem{Text: item.Text})
+	}
+
+	return ts.taskRepo.SaveTask(next)
+}
+
+// GetOccurrences previews the next occurrences of a recurring task, count of
+// them with a default of 5.
+func (ts *taskService) GetOccurrences(
+	idString string,
+	count string,
+	claims models.Claims,
+) ([]models.OccurrenceDto, *errr.AppError) {
+	id, err := strconv.ParseInt(idString, 10, 64)
+	if err != nil {
+		return nil, errr.NewBadRequestError("Invalid task id")
+	}
+	n := defaultOccurrences
+	if count != "" {
+		n, err = strconv.Atoi(count)
+		if err != nil || n < 1 || n > maxOccurrences {
+			return nil, errr.NewBadRequestError(
+				fmt.Sprintf("Invalid count, use 1 to %d", maxOccurrences),
+			)
+		}
+	}
+
+	task, appErr := ts.taskRepo.GetTask(id, claims.ID)
+	if appErr != nil {
+		return nil, appErr
+	}
+	if !task.IsRecurring() {
+		return nil, errr.NewBadRequestError("Task does not recur")
+	}
+
+	loc := claims.Location()
+	occurrences := []models.OccurrenceDto{}
+	for _, due := range task.Recurrence.Occurrences(task.DueAt, n, loc) {
+		occurrence := models.Task{DueAt: due}
+		if !task.StartAt.IsZero() {
+			occurrence.StartAt = due.Add(task.StartAt.Sub(task.DueAt))
+		}
+		occurrences = append(occurrences, models.OccurrenceDto{
+			DueAt:   models.FormatTaskTime(occurrence.DueAt, loc),
+			StartAt: models.FormatTaskTime(occurrence.StartAt, loc),
+		})
+	}
+
+	return occurrences, nil
+}
+
+func (ts *taskService) DeleteTask(idString string, version int64, claims models.Claims) *errr.AppError {
 	id, err := strconv.ParseInt(idString, 10, 64)
 	if err != nil {
 		return &errr.AppError{
@@ -77,25 +411,516 @@ func (ts *taskService) DeleteTask(idString string, claims models.Claims) *errr.A
 		}
 	}
 
-	appErr := ts.taskRepo.DeleteTask(id, claims.ID)
+	appErr := ts.taskRepo.DeleteTask(id, claims.ID, version, ts.now())
 	if appErr != nil {
 		return appErr
 	}
+	ts.index.Remove(claims.ID, id)
 
 	return nil
 }
 
-func (ts *taskService) GetTasks(claims models.Claims) ([]models.TaskResponseDto, *errr.AppError) {
-	tasks, appErr := ts.taskRepo.GetTasks(claims.ID)
+// GetTrash lists the tasks the user deleted, most recently deleted first.
+func (ts *taskService) GetTrash(claims models.Claims) ([]models.TaskResponseDto, *errr.AppError) {
+	workflow, appErr := ts.workflowRepo.GetWorkflow(claims.ID)
+	if appErr != nil {
+		return nil, appErr
+	}
+	tasks, appErr := ts.taskRepo.GetTrash(claims.ID)
 	if appErr != nil {
 		return nil, appErr
 	}
 
-	taskRes := make([]models.TaskResponseDto, len(tasks))
+	loc, now := claims.Location(), ts.now()
+	trash := make([]models.TaskResponseDto, 0, len(tasks))
+	for _, task := range tasks {
+		trash = append(trash, task.ToDto(workflow, loc, now))
+	}
+
+	return trash, nil
+}
+
+// RestoreTask takes the task out of the trash along with the subtasks deleted
+// with it.
+func (ts *taskService) RestoreTask(idString string, claims models.Claims) *errr.AppError {
+	id, err := strconv.ParseInt(idString, 10, 64)
+	if err != nil {
+		return errr.NewBadRequestError("Invalid task id")
+	}
+
+	appErr := ts.taskRepo.RestoreTask(id, claims.ID)
+	if appErr != nil {
+		return appErr
+	}
+	// The task is back either way, a failure to index it only leaves the
+	// tasks of the user to be indexed again on the next search.
+	if appErr := ts.indexSubtree(id, claims.ID); appErr != nil {
+		ts.index.Forget(claims.ID)
+	}
 
-	for i := range tasks {
-		taskRes[i] = tasks[i].ToDto()
+	return nil
+}
+
+// indexSubtree indexes the task with id and its subtasks as they are stored.
+func (ts *taskService) indexSubtree(id int64, userID int64) *errr.AppError {
+	task, appErr := ts.taskRepo.GetTask(id, userID)
+	if appErr != nil {
+		return appErr
+	}
+	ts.index.Put(task)
+
+	query := models.NewTaskQuery(userID)
+	query.ParentIDs = []int64{id}
+	for len(query.ParentIDs) > 0 {
+		subtasks, appErr := ts.taskRepo.GetTasks(query)
+		if appErr != nil {
+			return appErr
+		}
+		query.ParentIDs = []int64{}
+		for _, subtask := range subtasks {
+			ts.index.Put(subtask)
+			query.ParentIDs = append(query.ParentIDs, subtask.ID)
+		}
+	}
+
+	return nil
+}
+
+// PurgeTask deletes the task in the trash for good.
+func (ts *taskService) PurgeTask(idString string, claims models.Claims) *errr.AppError {
+	id, err := strconv.ParseInt(idString, 10, 64)
+	if err != nil {
+		return errr.NewBadRequestError("Invalid task id")
+	}
+
+	return ts.taskRepo.PurgeTask(id, claims.ID)
+}
+
+// PurgeTrash deletes the tasks of every user that have been in the trash for
+// longer than retention, returning how many it deleted.
+func (ts *taskService) PurgeTrash(retention time.Duration) (int, *errr.AppError) {
+	return ts.taskRepo.PurgeTrash(ts.now().Add(-retention))
+}
+
+// Batch runs the operations of batch in one transaction of the task repo. In
+// atomic mode the first failing operation fails the batch and undoes the
+// others, in per-item mode its error becomes its result. An unexpected error
+// fails the batch in either mode.
+func (ts *taskService) Batch(
+	batch models.TaskBatchDto,
+	claims models.Claims,
+) ([]models.TaskOperationResult, *errr.AppError) {
+	if violations := batch.Validate(); len(violations) > 0 {
+		return nil, invalidRequest("Invalid batch", violations)
+	}
+	atomic := batch.Mode != models.BatchPerItem
+
+	var results []models.TaskOperationResult
+	appErr := ts.transaction(func(tx *taskService) *errr.AppError {
+		results = make([]models.TaskOperationResult, 0, len(batch.Operations))
+		for i, op := range batch.Operations {
+			result := tx.runOperation(op, claims)
+			if result.Err != nil && (atomic || result.Err.Code >= http.StatusInternalServerError) {
+				return operationFailed(i, result.Err)
+			}
+			results = append(results, result)
+		}
+		return nil
+	})
+	// The operations indexed their changes before the transaction committed
+	// or rolled back, so the tasks of the user are indexed again on the next
+	// search.
+	ts.index.Forget(claims.ID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	return results, nil
+}
+
+func (ts *taskService) runOperation(
+	op models.TaskOperationDto,
+	claims models.Claims,
+) models.TaskOperationResult {
+	result := models.TaskOperationResult{Op: op.Op}
+	switch op.Op {
+	case models.BatchCreate:
+		task, appErr := ts.CreateTask(*op.Task, claims)
+		if appErr == nil {
+			result.Task = &task
+		}
+		result.Err = appErr
+	case models.BatchUpdate:
+		_, result.Err = ts.UpdateTask(op.ID, op.Version, *op.Task, claims)
+	case models.BatchPatch:
+		patch := models.TaskPatch{Format: models.MergePatch, Doc: op.Patch}
+		_, result.Err = ts.PatchTask(op.ID, op.Version, patch, claims)
+	case models.BatchDelete:
+		result.Err = ts.DeleteTask(op.ID, op.Version, claims)
+	}
+	return result
+}
+
+// transaction runs fn with a copy of the service bound to a transaction of
+// the task repo.
+func (ts *taskService) transaction(fn func(tx *taskService) *errr.AppError) *errr.AppError {
+	return ts.taskRepo.Transaction(func(taskRepo ports.TaskRepo) *errr.AppError {
+		tx := *ts
+		tx.taskRepo = taskRepo
+		return fn(&tx)
+	})
+}
+
+// operationFailed is the error failing a batch at the operation with index
+// i, naming the operation in its message and field paths.
+func operationFailed(i int, appErr *errr.AppError) *errr.AppError {
+	failed := *appErr
+	failed.Message = fmt.Sprintf("Operation %d: %s", i, appErr.Message)
+	failed.Fields = nil
+	for _, field := range appErr.Fields {
+		field.Field = fmt.Sprintf("operations[%d].task.%s", i, field.Field)
+		failed.Fields = append(failed.Fields, field)
+	}
+	return &failed
+}
+
+// GetTask returns the task with id, which the user must own, along with the
+// progress of its subtasks.
+func (ts *taskService) GetTask(
+	idString string,
+	claims models.Claims,
+) (models.TaskResponseDto, *errr.AppError) {
+	id, err := strconv.ParseInt(idString, 10, 64)
+	if err != nil {
+		return models.TaskResponseDto{}, errr.NewBadRequestError("Invalid task id")
+	}
+
+	task, appErr := ts.taskRepo.GetTask(id, claims.ID)
+	if appErr != nil {
+		return models.TaskResponseDto{}, appErr
+	}
+	workflow, appErr := ts.workflowRepo.GetWorkflow(claims.ID)
+	if appErr != nil {
+		return models.TaskResponseDto{}, appErr
+	}
+
+	subtaskQuery := models.NewTaskQuery(claims.ID)
+	subtaskQuery.ParentIDs = []int64{task.ID}
+	subtasks, appErr := ts.taskRepo.GetTasks(subtaskQuery)
+	if appErr != nil {
+		return models.TaskResponseDto{}, appErr
+	}
+	task.Subtasks = models.SubtaskProgress(subtasks)[task.ID]
+
+	return task.ToDto(workflow, claims.Location(), ts.now()), nil
+}
+
+// GetTasks lists a page of the tasks of the user that pass filter, with the
+// cursor of the next page when there is one.
+func (ts *taskService) GetTasks(
+	claims models.Claims,
+	filter models.TaskFilterDto,
+) (models.TaskPageDto, *errr.AppError) {
+	workflow, appErr := ts.workflowRepo.GetWorkflow(claims.ID)
+	if appErr != nil {
+		return models.TaskPageDto{}, appErr
+	}
+	query, appErr := parseTaskQuery(filter, workflow, claims)
+	if appErr != nil {
+		return models.TaskPageDto{}, appErr
+	}
+
+	// One task more than the page holds tells whether another page follows.
+	limit := query.Limit
+	query.Limit++
+	tasks, appErr := ts.taskRepo.GetTasks(query)
+	if appErr != nil {
+		return models.TaskPageDto{}, appErr
+	}
+
+	page := models.TaskPageDto{Tasks: make([]models.TaskResponseDto, 0, len(tasks))}
+	if len(tasks) > limit {
+		tasks = tasks[:limit]
+		page.NextCursor = query.CursorAt(tasks[limit-1]).String()
+	}
+	if len(tasks) == 0 {
+		return page, nil
+	}
+
+	subtaskQuery := models.NewTaskQuery(claims.ID)
+	for _, task := range tasks {
+		subtaskQuery.ParentIDs = append(subtaskQuery.ParentIDs, task.ID)
+	}
+	subtasks, appErr := ts.taskRepo.GetTasks(subtaskQuery)
+	if appErr != nil {
+		return models.TaskPageDto{}, appErr
+	}
+	progress := models.SubtaskProgress(subtasks)
+
+	loc, now := claims.Location(), ts.now()
+	for _, task := range tasks {
+		task.Subtasks = progress[task.ID]
+		page.Tasks = append(page.Tasks, task.ToDto(workflow, loc, now))
+	}
+
+	return page, nil
+}
+
+// parseTaskQuery turns the filter of a listing into a query for the tasks of
+// the user, with statuses named as in workflow.
+func parseTaskQuery(
+	filter models.TaskFilterDto,
+	workflow models.Workflow,
+	claims models.Claims,
+) (models.TaskQuery, *errr.AppError) {
+	query := models.NewTaskQuery(claims.ID)
+	query.Limit = defaultTaskPageSize
+
+	var err error
+	loc := claims.Location()
+	query.DueBefore, err = models.ParseTaskTime(filter.DueBefore, loc)
+	if err != nil {
+		return models.TaskQuery{}, errr.NewBadRequestError("Invalid due_before, use RFC 3339")
+	}
+	query.DueAfter, err = models.ParseTaskTime(filter.DueAfter, loc)
+	if err != nil {
+		return models.TaskQuery{}, errr.NewBadRequestError("Invalid due_after, use RFC 3339")
+	}
+	if filter.Status != "" {
+		status, ok := workflow.StatusNamed(filter.Status)
+		if !ok {
+			return models.TaskQuery{}, errr.NewBadRequestError("Unknown status")
+		}
+		query.Status = status.ID
+	}
+	if filter.Label != "" {
+		query.LabelID, err = strconv.ParseInt(filter.Label, 10, 64)
+		if err != nil {
+			return models.TaskQuery{}, errr.NewBadRequestError("Invalid label")
+		}
+	}
+	if filter.Project != "" {
+		query.ProjectID, err = strconv.ParseInt(filter.Project, 10, 64)
+		if err != nil {
+			return models.TaskQuery{}, errr.NewBadRequestError("Invalid project")
+		}
+	}
+
+	switch filter.Sort {
+	case "":
+	case models.TaskSortCreated, models.TaskSortUpdated, models.TaskSortTitle,
+		models.TaskSortDue, models.TaskSortPriority:
+		query.Sort = filter.Sort
+	default:
+		return models.TaskQuery{}, errr.NewBadRequestError(
+			"Invalid sort, use created, updated, title, due or priority",
+		)
+	}
+	// Priority lists the most urgent tasks first, so it runs descending
+	// unless asked otherwise while the other orders run ascending.
+	switch filter.Order {
+	case "":
+	case "asc":
+		query.Reverse = query.Sort == models.TaskSortPriority
+	case "desc":
+		query.Reverse = query.Sort != models.TaskSortPriority
+	default:
+		return models.TaskQuery{}, errr.NewBadRequestError("Invalid order, use asc or desc")
+	}
+
+	if filter.Limit != "" {
+		query.Limit, err = strconv.Atoi(filter.Limit)
+		if err != nil || query.Limit < 1 || query.Limit > maxTaskPageSize {
+			return models.TaskQuery{}, errr.NewBadRequestError(
+				fmt.Sprintf("Invalid limit, use 1 to %d", maxTaskPageSize),
+			)
+		}
+	}
+	if filter.After != "" {
+		cursor, err := models.ParseTaskCursor(filter.After)
+		if err != nil || cursor.Sort != query.Sort || cursor.Reverse != query.Reverse {
+			return models.TaskQuery{}, errr.NewBadRequestError(
+				"Invalid after, pass the next_cursor of a listing with the same sort and order",
+			)
+		}
+		query.After = &cursor
+	}
+
+	return query, nil
+}
+
+// SearchTasks ranks the tasks of the user by how well their title and
+// description match query. The first search of a user indexes their tasks,
+// every change to a task updates the index after that.
+func (ts *taskService) SearchTasks(
+	query string,
+	limit string,
+	claims models.Claims,
+) ([]models.SearchResultDto, *errr.AppError) {
+	query = strings.TrimSpace(query)
+	if query == "" || len(query) > maxSearchLength {
+		return nil, errr.NewBadRequestError(
+			fmt.Sprintf("Invalid q, search for 1 to %d characters", maxSearchLength),
+		)
+	}
+	n := defaultSearchResults
+	if limit != "" {
+		var err error
+		n, err = strconv.Atoi(limit)
+		if err != nil || n < 1 || n > maxSearchResults {
+			return nil, errr.NewBadRequestError(
+				fmt.Sprintf("Invalid limit, use 1 to %d", maxSearchResults),
+			)
+		}
+	}
+
+	workflow, appErr := ts.workflowRepo.GetWorkflow(claims.ID)
+	if appErr != nil {
+		return nil, appErr
+	}
+	appErr = ts.indexTasks(claims.ID)
+	if appErr != nil {
+		return nil, appErr
+	}
+	hits := ts.index.Search(claims.ID, query, n)
+	if len(hits) == 0 {
+		return []models.SearchResultDto{}, nil
+	}
+
+	taskQuery := models.NewTaskQuery(claims.ID)
+	for _, hit := range hits {
+		taskQuery.IDs = append(taskQuery.IDs, hit.TaskID)
+	}
+	tasks, appErr := ts.taskRepo.GetTasks(taskQuery)
+	if appErr != nil {
+		return nil, appErr
+	}
+	subtaskQuery := models.NewTaskQuery(claims.ID)
+	subtaskQuery.ParentIDs = taskQuery.IDs
+	subtasks, appErr := ts.taskRepo.GetTasks(subtaskQuery)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	byID := make(map[int64]models.Task, len(tasks))
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+	progress := models.SubtaskProgress(subtasks)
+
+	loc, now := claims.Location(), ts.now()
+	results := make([]models.SearchResultDto, 0, len(hits))
+	for _, hit := range hits {
+		task, ok := byID[hit.TaskID]
+		if !ok {
+			continue
+		}
+		task.Subtasks = progress[task.ID]
+		results = append(results, hit.ToDto(task.ToDto(workflow, loc, now)))
+	}
+
+	return results, nil
+}
+
+// indexTasks indexes the tasks of the user unless they are. Tasks that
+// change while they are read are read again.
+func (ts *taskService) indexTasks(userID int64) *errr.AppError {
+	if ts.index.Indexed(userID) {
+		return nil
+	}
+
+	for range maxIndexAttempts {
+		gen := ts.index.Generation(userID)
+		tasks, appErr := ts.taskRepo.GetTasks(models.NewTaskQuery(userID))
+		if appErr != nil {
+			return appErr
+		}
+		if ts.index.Replace(userID, tasks, gen) {
+			return nil
+		}
+	}
+	return errr.NewUnexpectedError("Unable to search tasks due to internal server error")
+}
+
+// GetProjectTasks lists the tasks in the project with id, which the user must
+// own.
+func (ts *taskService) GetProjectTasks(
+	idString string,
+	claims models.Claims,
+	filter models.TaskFilterDto,
+) (models.TaskPageDto, *errr.AppError) {
+	id, err := strconv.ParseInt(idString, 10, 64)
+	if err != nil {
+		return models.TaskPageDto{}, errr.NewBadRequestError("Invalid project id")
+	}
+
+	_, appErr := ts.projectRepo.GetProject(id, claims.ID)
+	if appErr != nil {
+		return models.TaskPageDto{}, appErr
+	}
+
+	filter.Project = idString
+	return ts.GetTasks(claims, filter)
+}
+
+// parseProjectID turns the project id of a request into the id of an
+// unarchived project owned by the user. Nil and an empty id give 0.
+func (ts *taskService) parseProjectID(idStr *string, userID int64) (int64, *errr.AppError) {
+	if idStr == nil || *idStr == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseInt(*idStr, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, errr.NewBadRequestError("Invalid project id")
+	}
+
+	project, appErr := ts.projectRepo.GetProject(id, userID)
+	if appErr != nil && appErr.Code == http.StatusNotFound {
+		return 0, errr.NewBadRequestError("Unknown project id")
+	}
+	if appErr != nil && appErr.Code == http.StatusForbidden {
+		return 0, errr.NewUnauthorizedError("Unauthorized to move task into project")
+	}
+	if appErr != nil {
+		return 0, appErr
+	}
+	if project.Archived {
+		return 0, errr.NewBadRequestError("Project is archived")
+	}
+
+	return id, nil
+}
+
+// parseLabelIDs turns the label ids of a request into the ids of labels owned
+// by the user, sorted and without duplicates. Nil stays nil.
+func (ts *taskService) parseLabelIDs(ids []string, userID int64) ([]int64, *errr.AppError) {
+	if ids == nil {
+		return nil, nil
+	}
+
+	labelIDs := make([]int64, 0, len(ids))
+	for _, idStr := range ids {
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			return nil, errr.NewBadRequestError("Invalid label id")
+		}
+		labelIDs = append(labelIDs, id)
+	}
+	if len(labelIDs) == 0 {
+		return labelIDs, nil
+	}
+
+	labels, appErr := ts.labelRepo.GetLabels(userID)
+	if appErr != nil {
+		return nil, appErr
+	}
+	for _, id := range labelIDs {
+		if !slices.ContainsFunc(labels, func(l models.Label) bool { return l.ID == id }) {
+			return nil, errr.NewBadRequestError("Unknown label id")
+		}
 	}
 
-	return taskRes, nil
+	slices.Sort(labelIDs)
+	return slices.Compact(labelIDs), nil
 }
@@ -2,38 +2,96 @@ package services
 
 import (
 	"net/http"
-	"slices"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/Jashanveer-Singh/todo-go/internal/errr"
 	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/internal/ports"
 	"github.com/Jashanveer-Singh/todo-go/test/mocks"
 	"github.com/golang/mock/gomock"
 )
 
+// testNow is the time the task service reads from its clock in tests.
+var testNow = time.Date(2025, time.February, 1, 12, 0, 0, 0, time.UTC)
+
+// defaultWorkflowRepo serves the default workflow to any user.
+func defaultWorkflowRepo(ctrl *gomock.Controller) *mocks.MockWorkflowRepo {
+	mwr := mocks.NewMockWorkflowRepo(ctrl)
+	mwr.EXPECT().GetWorkflow(gomock.Any()).DoAndReturn(func(userID int64) (models.Workflow, *errr.AppError) {
+		return models.DefaultWorkflow(userID), nil
+	}).AnyTimes()
+	return mwr
+}
+
+// changingIndex expects the tasks of any user to change.
+func changingIndex(ctrl *gomock.Controller) *mocks.MockTaskIndex {
+	mti := mocks.NewMockTaskIndex(ctrl)
+	mti.EXPECT().Put(gomock.Any()).AnyTimes()
+	mti.EXPECT().Remove(gomock.Any(), gomock.Any()).AnyTimes()
+	mti.EXPECT().Forget(gomock.Any()).AnyTimes()
+	return mti
+}
+
+// inTransaction runs the next transaction on the repo itself.
+func inTransaction(mtr *mocks.MockTaskRepo) {
+	mtr.EXPECT().Transaction(gomock.Any()).DoAndReturn(func(fn func(ports.TaskRepo) *errr.AppError) *errr.AppError {
+		return fn(mtr)
+	})
+}
+
+// violation is the error for a request breaking a single rule.
+func violation(field, rule, message string) *errr.AppError {
+	return errr.NewValidationError(message, []errr.FieldError{
+		{Field: field, Reason: rule, Message: message},
+	})
+}
+
+// storesTask saves tasks under id, as a TaskRepo would.
+func storesTask(id int64) func(models.Task) (models.Task, *errr.AppError) {
+	return func(task models.Task) (models.Task, *errr.AppError) {
+		task.ID = id
+		return task, nil
+	}
+}
+
 func Test_taskService_CreateTask(t *testing.T) {
 	tests := []struct {
 		name          string
 		setupTaskRepo func(mtr *mocks.MockTaskRepo)
 		taskReq       models.TaskRequestDto
+		want          models.TaskResponseDto
 		appErr        *errr.AppError
 		claims        models.Claims
 	}{
 		{
 			name: "successfully created task",
 			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {
+				inTransaction(mtr)
 				mtr.EXPECT().SaveTask(models.Task{
-					Title:  "title",
-					Desc:   "desc",
-					Status: 2,
-					UserID: 1234,
-				}).Return(nil)
+					Title:     "title",
+					Desc:      "desc",
+					Status:    2,
+					UserID:    1234,
+					CreatedAt: testNow,
+					UpdatedAt: testNow,
+				}).DoAndReturn(storesTask(99))
 			},
 			taskReq: models.TaskRequestDto{
 				Title:  "title",
 				Desc:   "desc",
 				Status: "Pending",
 			},
+			want: models.TaskResponseDto{
+				ID:        "99",
+				Title:     "title",
+				Desc:      "desc",
+				Status:    "Waiting",
+				Priority:  "None",
+				CreatedAt: "2025-02-01T12:00:00Z",
+				UpdatedAt: "2025-02-01T12:00:00Z",
+			},
 			appErr: nil,
 			claims: models.Claims{
 				ID:   1234,
@@ -43,18 +101,30 @@ func Test_taskService_CreateTask(t *testing.T) {
 		{
 			name: "successfully created task with invalid status input",
 			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {
+				inTransaction(mtr)
 				mtr.EXPECT().SaveTask(models.Task{
-					Title:  "title",
-					Desc:   "desc",
-					Status: 2,
-					UserID: 1234,
-				}).Return(nil)
+					Title:     "title",
+					Desc:      "desc",
+					Status:    2,
+					UserID:    1234,
+					CreatedAt: testNow,
+					UpdatedAt: testNow,
+				}).DoAndReturn(storesTask(99))
 			},
 			taskReq: models.TaskRequestDto{
 				Title:  "title",
 				Desc:   "desc",
 				Status: "sdaf",
 			},
+			want: models.TaskResponseDto{
+				ID:        "99",
+				Title:     "title",
+				Desc:      "desc",
+				Status:    "Waiting",
+				Priority:  "None",
+				CreatedAt: "2025-02-01T12:00:00Z",
+				UpdatedAt: "2025-02-01T12:00:00Z",
+			},
 			appErr: nil,
 			claims: models.Claims{
 				ID:   1234,
@@ -69,10 +139,7 @@ func Test_taskService_CreateTask(t *testing.T) {
 				Desc:   "desc",
 				Status: "Pending",
 			},
-			appErr: &errr.AppError{
-				Message: "Invalid task",
-				Code:    http.StatusBadRequest,
-			},
+			appErr: violation("title", models.RuleRequired, "Title is required"),
 			claims: models.Claims{
 				ID:   1234,
 				Role: "",
@@ -86,24 +153,168 @@ func Test_taskService_CreateTask(t *testing.T) {
 				Desc:   "",
 				Status: "Pending",
 			},
-			appErr: &errr.AppError{
-				Message: "Invalid task",
-				Code:    http.StatusBadRequest,
-			},
+			appErr: violation("desc", models.RuleRequired, "Description is required"),
 			claims: models.Claims{
 				ID:   1234,
 				Role: "",
 			},
 		},
+		{
+			name: "successfully created task with due date in user's time zone",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {
+				inTransaction(mtr)
+				mtr.EXPECT().SaveTask(models.Task{
+					Title:     "title",
+					Desc:      "desc",
+					Status:    2,
+					UserID:    1234,
+					DueAt:     time.Date(2020, time.January, 2, 9, 0, 0, 0, time.UTC),
+					CreatedAt: testNow,
+					UpdatedAt: testNow,
+				}).DoAndReturn(storesTask(99))
+			},
+			taskReq: models.TaskRequestDto{
+				Title: "title",
+				Desc:  "desc",
+				DueAt: "2020-01-02T10:00:00",
+			},
+			want: models.TaskResponseDto{
+				ID:        "99",
+				Title:     "title",
+				Desc:      "desc",
+				Status:    "Waiting",
+				Priority:  "None",
+				DueAt:     "2020-01-02T10:00:00+01:00",
+				Overdue:   true,
+				CreatedAt: "2025-02-01T13:00:00+01:00",
+				UpdatedAt: "2025-02-01T13:00:00+01:00",
+			},
+			appErr: nil,
+			claims: models.Claims{
+				ID:       1234,
+				TimeZone: "Europe/Berlin",
+			},
+		},
+		{
+			name: "task due after the service's clock is not overdue",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {
+				inTransaction(mtr)
+				mtr.EXPECT().SaveTask(models.Task{
+					Title:     "title",
+					Desc:      "desc",
+					Status:    2,
+					UserID:    1234,
+					DueAt:     time.Date(2025, time.March, 1, 9, 0, 0, 0, time.UTC),
+					CreatedAt: testNow,
+					UpdatedAt: testNow,
+				}).DoAndReturn(storesTask(99))
+			},
+			taskReq: models.TaskRequestDto{
+				Title: "title",
+				Desc:  "desc",
+				DueAt: "2025-03-01T10:00:00",
+			},
+			want: models.TaskResponseDto{
+				ID:        "99",
+				Title:     "title",
+				Desc:      "desc",
+				Status:    "Waiting",
+				Priority:  "None",
+				DueAt:     "2025-03-01T10:00:00+01:00",
+				CreatedAt: "2025-02-01T13:00:00+01:00",
+				UpdatedAt: "2025-02-01T13:00:00+01:00",
+			},
+			appErr: nil,
+			claims: models.Claims{
+				ID:       1234,
+				TimeZone: "Europe/Berlin",
+			},
+		},
+		{
+			name: "successfully created task with priority",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {
+				inTransaction(mtr)
+				mtr.EXPECT().SaveTask(models.Task{
+					Title:     "title",
+					Desc:      "desc",
+					Status:    2,
+					Priority:  4,
+					UserID:    1234,
+					CreatedAt: testNow,
+					UpdatedAt: testNow,
+				}).DoAndReturn(storesTask(99))
+			},
+			taskReq: models.TaskRequestDto{
+				Title:    "title",
+				Desc:     "desc",
+				Priority: "Urgent",
+			},
+			want: models.TaskResponseDto{
+				ID:        "99",
+				Title:     "title",
+				Desc:      "desc",
+				Status:    "Waiting",
+				Priority:  "Urgent",
+				CreatedAt: "2025-02-01T12:00:00Z",
+				UpdatedAt: "2025-02-01T12:00:00Z",
+			},
+			appErr: nil,
+			claims: models.Claims{
+				ID: 1234,
+			},
+		},
+		{
+			name:          "failed to create task because of invalid priority",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {},
+			taskReq: models.TaskRequestDto{
+				Title:    "title",
+				Desc:     "desc",
+				Priority: "Critical",
+			},
+			appErr: violation("priority", models.RuleUnknownValue, "Invalid priority"),
+			claims: models.Claims{
+				ID: 1234,
+			},
+		},
+		{
+			name:          "failed to create task because of invalid due date",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {},
+			taskReq: models.TaskRequestDto{
+				Title: "title",
+				Desc:  "desc",
+				DueAt: "02/01/2020",
+			},
+			appErr: violation("due_at", models.RuleInvalidFormat, "Invalid due date, use RFC 3339"),
+			claims: models.Claims{
+				ID: 1234,
+			},
+		},
+		{
+			name:          "failed to create task because it starts after it is due",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {},
+			taskReq: models.TaskRequestDto{
+				Title:   "title",
+				Desc:    "desc",
+				DueAt:   "2020-01-02T10:00:00Z",
+				StartAt: "2020-01-03T10:00:00Z",
+			},
+			appErr: violation("start_at", models.RuleDateOrder, "Start date must not be after due date"),
+			claims: models.Claims{
+				ID: 1234,
+			},
+		},
 		{
 			name: "task repo failed to save task",
 			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {
+				inTransaction(mtr)
 				mtr.EXPECT().SaveTask(models.Task{
-					Title:  "title",
-					Desc:   "desc",
-					Status: 2,
-					UserID: 1234,
-				}).Return(&errr.AppError{
+					Title:     "title",
+					Desc:      "desc",
+					Status:    2,
+					UserID:    1234,
+					CreatedAt: testNow,
+					UpdatedAt: testNow,
+				}).Return(models.Task{}, &errr.AppError{
 					Code:    0,
 					Message: "error message from task repo",
 				})
@@ -130,10 +341,160 @@ func Test_taskService_CreateTask(t *testing.T) {
 
 			mtr := mocks.NewMockTaskRepo(ctrl)
 			tt.setupTaskRepo(mtr)
-			ts := NewTaskService(mtr)
+			ts := NewTaskService(mtr, mocks.NewMockLabelRepo(ctrl), mocks.NewMockProjectRepo(ctrl), defaultWorkflowRepo(ctrl), changingIndex(ctrl))
+			ts.now = func() time.Time { return testNow }
 
-			got := ts.CreateTask(tt.taskReq, tt.claims)
-			if tt.appErr == nil && tt.appErr != got {
+			got, appErr := ts.CreateTask(tt.taskReq, tt.claims)
+			if tt.appErr == nil && tt.appErr != appErr {
+				t.Errorf("CreateTask() failed, got err: %v.", appErr)
+				return
+			}
+			if tt.appErr != nil && appErr == nil {
+				t.Errorf("CreateTask() successed unexpectedly, wanted err: %v.", tt.appErr)
+				return
+			}
+			if tt.appErr != nil && !reflect.DeepEqual(tt.appErr, appErr) {
+				t.Errorf("CreateTask() = %v, want %v", appErr, tt.appErr)
+			}
+			if tt.appErr == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CreateTask() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_taskService_CreateTask_subtask(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mtr := mocks.NewMockTaskRepo(ctrl)
+	inTransaction(mtr)
+	mtr.EXPECT().SaveTask(models.Task{
+		Title:     "title",
+		Desc:      "desc",
+		Status:    2,
+		UserID:    1234,
+		ParentID:  7,
+		CreatedAt: testNow,
+		UpdatedAt: testNow,
+	}).DoAndReturn(storesTask(99))
+	ts := NewTaskService(mtr, mocks.NewMockLabelRepo(ctrl), mocks.NewMockProjectRepo(ctrl), defaultWorkflowRepo(ctrl), changingIndex(ctrl))
+	ts.now = func() time.Time { return testNow }
+
+	taskReq := models.TaskRequestDto{Title: "title", Desc: "desc", ParentID: "7"}
+	got, appErr := ts.CreateTask(taskReq, models.Claims{ID: 1234})
+	if appErr != nil || got.ParentID != "7" {
+		t.Errorf("CreateTask() = %v, %v, want a subtask of 7", got, appErr)
+	}
+
+	taskReq.ParentID = "seven"
+	_, appErr = ts.CreateTask(taskReq, models.Claims{ID: 1234})
+	want := violation("parent_id", models.RuleInvalidFormat, "Invalid parent task id")
+	if !reflect.DeepEqual(appErr, want) {
+		t.Errorf("CreateTask() = %v, want %v", appErr, want)
+	}
+}
+
+func Test_taskService_UpdateTask_violations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mtr := mocks.NewMockTaskRepo(ctrl)
+	mtr.EXPECT().GetTask(int64(7), int64(1234)).Return(models.Task{ID: 7, UserID: 1234}, nil)
+	inTransaction(mtr)
+	ts := NewTaskService(
+		mtr, mocks.NewMockLabelRepo(ctrl), mocks.NewMockProjectRepo(ctrl),
+		defaultWorkflowRepo(ctrl), mocks.NewMockTaskIndex(ctrl),
+	)
+
+	taskReq := models.TaskRequestDto{
+		Status:    "Someday",
+		Priority:  "Whenever",
+		Checklist: []models.ChecklistItem{{Text: "step"}, {}},
+	}
+	_, got := ts.UpdateTask("7", 0, taskReq, models.Claims{ID: 1234})
+	want := errr.NewValidationError("Invalid task", []errr.FieldError{
+		{Field: "title", Reason: models.RuleRequired, Message: "Title is required"},
+		{Field: "desc", Reason: models.RuleRequired, Message: "Description is required"},
+		{Field: "priority", Reason: models.RuleUnknownValue, Message: "Invalid priority"},
+		{Field: "checklist[1].text", Reason: models.RuleRequired, Message: "Checklist item text is required"},
+		{Field: "status", Reason: models.RuleUnknownStatus, Message: "Unknown status"},
+	})
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("UpdateTask() = %v, want %v", got, want)
+	}
+}
+
+func Test_taskService_CreateTask_labels(t *testing.T) {
+	tests := []struct {
+		name           string
+		setupTaskRepo  func(mtr *mocks.MockTaskRepo)
+		setupLabelRepo func(mlr *mocks.MockLabelRepo)
+		labelIDs       []string
+		appErr         *errr.AppError
+	}{
+		{
+			name: "labels are deduplicated and sorted",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {
+				mtr.EXPECT().SaveTask(models.Task{
+					Title:     "title",
+					Desc:      "desc",
+					Status:    2,
+					UserID:    1234,
+					LabelIDs:  []int64{7, 9},
+					CreatedAt: testNow,
+					UpdatedAt: testNow,
+				}).DoAndReturn(storesTask(99))
+			},
+			setupLabelRepo: func(mlr *mocks.MockLabelRepo) {
+				mlr.EXPECT().GetLabels(int64(1234)).Return([]models.Label{
+					{ID: 7, UserID: 1234},
+					{ID: 9, UserID: 1234},
+				}, nil)
+			},
+			labelIDs: []string{"9", "7", "9"},
+			appErr:   nil,
+		},
+		{
+			name:          "label of another user",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {},
+			setupLabelRepo: func(mlr *mocks.MockLabelRepo) {
+				mlr.EXPECT().GetLabels(int64(1234)).Return([]models.Label{
+					{ID: 7, UserID: 1234},
+				}, nil)
+			},
+			labelIDs: []string{"8"},
+			appErr: &errr.AppError{
+				Code:    http.StatusBadRequest,
+				Message: "Unknown label id",
+			},
+		},
+		{
+			name:           "label id is not a number",
+			setupTaskRepo:  func(mtr *mocks.MockTaskRepo) {},
+			setupLabelRepo: func(mlr *mocks.MockLabelRepo) {},
+			labelIDs:       []string{"work"},
+			appErr: &errr.AppError{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid label id",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mtr := mocks.NewMockTaskRepo(ctrl)
+			inTransaction(mtr)
+			tt.setupTaskRepo(mtr)
+			mlr := mocks.NewMockLabelRepo(ctrl)
+			tt.setupLabelRepo(mlr)
+			ts := NewTaskService(mtr, mlr, mocks.NewMockProjectRepo(ctrl), defaultWorkflowRepo(ctrl), changingIndex(ctrl))
+			ts.now = func() time.Time { return testNow }
+
+			taskReq := models.TaskRequestDto{Title: "title", Desc: "desc", LabelIDs: tt.labelIDs}
+			_, got := ts.CreateTask(taskReq, models.Claims{ID: 1234})
+			if tt.appErr == nil && got != nil {
 				t.Errorf("CreateTask() failed, got err: %v.", got)
 				return
 			}
@@ -141,30 +502,184 @@ func Test_taskService_CreateTask(t *testing.T) {
 				t.Errorf("CreateTask() successed unexpectedly, wanted err: %v.", tt.appErr)
 				return
 			}
-			if tt.appErr != nil && *tt.appErr != *got {
+			if tt.appErr != nil && !reflect.DeepEqual(tt.appErr, got) {
 				t.Errorf("CreateTask() = %v, want %v", got, tt.appErr)
 			}
 		})
 	}
 }
 
+func Test_taskService_UpdateTask_project(t *testing.T) {
+	ptr := func(s string) *string { return &s }
+	replaced := func(projectID int64) models.Task {
+		return models.Task{
+			Title: "title", Desc: "desc", UserID: 1234, ProjectID: projectID,
+			UpdatedAt: testNow, Version: 1,
+		}
+	}
+
+	tests := []struct {
+		name             string
+		setupTaskRepo    func(mtr *mocks.MockTaskRepo)
+		setupProjectRepo func(mpr *mocks.MockProjectRepo)
+		projectID        *string
+		appErr           *errr.AppError
+	}{
+		{
+			name: "moves task into own project",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {
+				mtr.EXPECT().UpdateTask(int64(3), replaced(7)).Return(nil)
+			},
+			setupProjectRepo: func(mpr *mocks.MockProjectRepo) {
+				mpr.EXPECT().GetProject(int64(7), int64(1234)).
+					Return(models.Project{ID: 7, UserID: 1234}, nil)
+			},
+			projectID: ptr("7"),
+		},
+		{
+			name: "empty id moves task out of its project",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {
+				mtr.EXPECT().UpdateTask(int64(3), replaced(0)).Return(nil)
+			},
+			setupProjectRepo: func(mpr *mocks.MockProjectRepo) {},
+			projectID:        ptr(""),
+		},
+		{
+			name: "leaving the id out moves task out of its project",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {
+				mtr.EXPECT().UpdateTask(int64(3), replaced(0)).Return(nil)
+			},
+			setupProjectRepo: func(mpr *mocks.MockProjectRepo) {},
+		},
+		{
+			name:          "project of another user",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {},
+			setupProjectRepo: func(mpr *mocks.MockProjectRepo) {
+				mpr.EXPECT().GetProject(int64(7), int64(1234)).Return(
+					models.Project{}, errr.NewUnauthorizedError("Unauthorized to view project"),
+				)
+			},
+			projectID: ptr("7"),
+			appErr:    errr.NewUnauthorizedError("Unauthorized to move task into project"),
+		},
+		{
+			name:          "unknown project",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {},
+			setupProjectRepo: func(mpr *mocks.MockProjectRepo) {
+				mpr.EXPECT().GetProject(int64(7), int64(1234)).Return(
+					models.Project{}, errr.NewNotFoundError("no project found with id"),
+				)
+			},
+			projectID: ptr("7"),
+			appErr:    errr.NewBadRequestError("Unknown project id"),
+		},
+		{
+			name:          "archived project",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {},
+			setupProjectRepo: func(mpr *mocks.MockProjectRepo) {
+				mpr.EXPECT().GetProject(int64(7), int64(1234)).
+					Return(models.Project{ID: 7, UserID: 1234, Archived: true}, nil)
+			},
+			projectID: ptr("7"),
+			appErr:    errr.NewBadRequestError("Project is archived"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mtr := mocks.NewMockTaskRepo(ctrl)
+			mtr.EXPECT().GetTask(int64(3), int64(1234)).
+				Return(models.Task{ID: 3, UserID: 1234, ProjectID: 5, Version: 1}, nil)
+			inTransaction(mtr)
+			tt.setupTaskRepo(mtr)
+			mpr := mocks.NewMockProjectRepo(ctrl)
+			tt.setupProjectRepo(mpr)
+			ts := NewTaskService(mtr, mocks.NewMockLabelRepo(ctrl), mpr, defaultWorkflowRepo(ctrl), changingIndex(ctrl))
+			ts.now = func() time.Time { return testNow }
+
+			taskReq := models.TaskRequestDto{Title: "title", Desc: "desc", Status: "Pending", ProjectID: tt.projectID}
+			_, got := ts.UpdateTask("3", 0, taskReq, models.Claims{ID: 1234})
+			if tt.appErr == nil && got != nil {
+				t.Errorf("UpdateTask() failed, got err: %v.", got)
+				return
+			}
+			if tt.appErr != nil && (got == nil || !reflect.DeepEqual(tt.appErr, got)) {
+				t.Errorf("UpdateTask() = %v, want %v", got, tt.appErr)
+			}
+		})
+	}
+}
+
+func Test_taskService_GetProjectTasks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	query := models.NewTaskQuery(1234)
+	query.ProjectID = 7
+	query.Limit = defaultTaskPageSize + 1
+	subtaskQuery := models.NewTaskQuery(1234)
+	subtaskQuery.ParentIDs = []int64{1}
+	mtr := mocks.NewMockTaskRepo(ctrl)
+	mtr.EXPECT().GetTasks(query).Return([]models.Task{
+		{ID: 1, Title: "a", Desc: "d", Status: 2, UserID: 1234, ProjectID: 7},
+	}, nil)
+	mtr.EXPECT().GetTasks(subtaskQuery).Return([]models.Task{}, nil)
+	mpr := mocks.NewMockProjectRepo(ctrl)
+	mpr.EXPECT().GetProject(int64(7), int64(1234)).Return(models.Project{ID: 7, UserID: 1234}, nil)
+	mpr.EXPECT().GetProject(int64(8), int64(1234)).Return(
+		models.Project{}, errr.NewUnauthorizedError("Unauthorized to view project"),
+	)
+	ts := NewTaskService(mtr, mocks.NewMockLabelRepo(ctrl), mpr, defaultWorkflowRepo(ctrl), changingIndex(ctrl))
+	ts.now = func() time.Time { return testNow }
+
+	got, appErr := ts.GetProjectTasks("7", models.Claims{ID: 1234}, models.TaskFilterDto{})
+	if appErr != nil {
+		t.Fatalf("GetProjectTasks() failed, got err: %v.", appErr)
+	}
+	if len(got.Tasks) != 1 || got.Tasks[0].ID != "1" || got.Tasks[0].ProjectID != "7" {
+		t.Errorf("GetProjectTasks() = %v, want only task 1", got)
+	}
+
+	_, appErr = ts.GetProjectTasks("8", models.Claims{ID: 1234}, models.TaskFilterDto{})
+	if appErr == nil || appErr.Code != http.StatusForbidden {
+		t.Errorf("GetProjectTasks() of another user = %v, want forbidden", appErr)
+	}
+}
+
 func Test_taskService_UpdateTask(t *testing.T) {
+	stored := models.Task{
+		ID:       1234,
+		Title:    "old title",
+		Desc:     "old desc",
+		Status:   2,
+		Priority: 3,
+		UserID:   1234,
+		DueAt:    time.Date(2020, time.January, 1, 10, 0, 0, 0, time.UTC),
+		Version:  3,
+	}
 	tests := []struct {
 		name          string
 		setupTaskRepo func(mtr *mocks.MockTaskRepo)
 		id            string
+		version       int64
 		taskReq       models.TaskRequestDto
 		appErr        *errr.AppError
 		claims        models.Claims
 	}{
 		{
-			name: "successfully updated task",
+			name: "successfully replaced task",
 			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {
+				mtr.EXPECT().GetTask(int64(1234), int64(1234)).Return(stored, nil)
+				inTransaction(mtr)
 				mtr.EXPECT().UpdateTask(int64(1234), models.Task{
-					Title:  "title",
-					Desc:   "desc",
-					Status: 0,
-					UserID: 1234,
+					Title:     "title",
+					Desc:      "desc",
+					Status:    0,
+					UserID:    1234,
+					UpdatedAt: testNow,
+					Version:   3,
 				}).Return(nil)
 			},
 			id: "1234",
@@ -180,22 +695,102 @@ func Test_taskService_UpdateTask(t *testing.T) {
 			},
 		},
 		{
-			name: "successfully created task with only title changed",
+			name: "successfully replaced task at its version",
 			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {
+				mtr.EXPECT().GetTask(int64(1234), int64(1234)).Return(stored, nil)
+				inTransaction(mtr)
 				mtr.EXPECT().UpdateTask(int64(1234), models.Task{
-					Title:  "title",
-					Status: -1,
-					UserID: 1234,
+					Title:     "title",
+					Desc:      "desc",
+					Status:    2,
+					UserID:    1234,
+					Priority:  4,
+					DueAt:     time.Date(2020, time.January, 2, 10, 0, 0, 0, time.UTC),
+					UpdatedAt: testNow,
+					Version:   3,
 				}).Return(nil)
 			},
+			id:      "1234",
+			version: 3,
+			taskReq: models.TaskRequestDto{
+				Title:    "title",
+				Desc:     "desc",
+				Status:   "Waiting",
+				Priority: "Urgent",
+				DueAt:    "2020-01-02T10:00:00Z",
+			},
+			appErr: nil,
+			claims: models.Claims{
+				ID: 1234,
+			},
+		},
+		{
+			name: "failed to update task changed since it was read",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {
+				mtr.EXPECT().GetTask(int64(1234), int64(1234)).Return(stored, nil)
+			},
+			id:      "1234",
+			version: 2,
+			taskReq: models.TaskRequestDto{
+				Title:  "title",
+				Desc:   "desc",
+				Status: "Pending",
+			},
+			appErr: errr.NewPreconditionFailedError("Task has changed since it was read"),
+			claims: models.Claims{
+				ID: 1234,
+			},
+		},
+		{
+			name: "failed to update task because of invalid priority",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {
+				mtr.EXPECT().GetTask(int64(1234), int64(1234)).Return(stored, nil)
+				inTransaction(mtr)
+			},
+			id: "1234",
+			taskReq: models.TaskRequestDto{
+				Title:    "title",
+				Desc:     "desc",
+				Status:   "Pending",
+				Priority: "Whenever",
+			},
+			appErr: violation("priority", models.RuleUnknownValue, "Invalid priority"),
+			claims: models.Claims{
+				ID: 1234,
+			},
+		},
+		{
+			name: "failed to update task because of invalid start date",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {
+				mtr.EXPECT().GetTask(int64(1234), int64(1234)).Return(stored, nil)
+				inTransaction(mtr)
+			},
+			id: "1234",
+			taskReq: models.TaskRequestDto{
+				Title:   "title",
+				Desc:    "desc",
+				Status:  "Pending",
+				StartAt: "yesterday",
+			},
+			appErr: violation("start_at", models.RuleInvalidFormat, "Invalid start date, use RFC 3339"),
+			claims: models.Claims{
+				ID: 1234,
+			},
+		},
+		{
+			name: "failed to update task without status",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {
+				mtr.EXPECT().GetTask(int64(1234), int64(1234)).Return(stored, nil)
+				inTransaction(mtr)
+			},
 			id: "1234",
 			taskReq: models.TaskRequestDto{
 				Title: "title",
+				Desc:  "desc",
 			},
-			appErr: nil,
+			appErr: violation("status", models.RuleRequired, "Status is required"),
 			claims: models.Claims{
-				ID:   1234,
-				Role: "",
+				ID: 1234,
 			},
 		},
 		{
@@ -213,27 +808,34 @@ func Test_taskService_UpdateTask(t *testing.T) {
 			},
 		},
 		{
-			name:          "failed to update task beacause to empty task fields",
-			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {},
-			id:            "1234",
-			taskReq:       models.TaskRequestDto{},
-			appErr: &errr.AppError{
-				Message: "Invalid task format",
-				Code:    http.StatusBadRequest,
+			name: "failed to update task of another user",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {
+				mtr.EXPECT().GetTask(int64(1234), int64(1234)).
+					Return(models.Task{}, errr.NewUnauthorizedError("Unauthorized to view task"))
+			},
+			id: "1234",
+			taskReq: models.TaskRequestDto{
+				Title:  "title",
+				Desc:   "desc",
+				Status: "Pending",
 			},
+			appErr: errr.NewUnauthorizedError("Unauthorized to view task"),
 			claims: models.Claims{
-				ID:   1234,
-				Role: "",
+				ID: 1234,
 			},
 		},
 		{
 			name: "task repo failed to update task",
 			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {
+				mtr.EXPECT().GetTask(int64(1234), int64(1234)).Return(stored, nil)
+				inTransaction(mtr)
 				mtr.EXPECT().UpdateTask(int64(1234), models.Task{
-					Title:  "title",
-					Desc:   "desc",
-					Status: 0,
-					UserID: 1234,
+					Title:     "title",
+					Desc:      "desc",
+					Status:    0,
+					UserID:    1234,
+					UpdatedAt: testNow,
+					Version:   3,
 				}).Return(&errr.AppError{
 					Code:    0,
 					Message: "error message from task repo",
@@ -263,8 +865,9 @@ func Test_taskService_UpdateTask(t *testing.T) {
 			mtr := mocks.NewMockTaskRepo(ctrl)
 			tt.setupTaskRepo(mtr)
 
-			ts := NewTaskService(mtr)
-			got := ts.UpdateTask(tt.id, tt.taskReq, tt.claims)
+			ts := NewTaskService(mtr, mocks.NewMockLabelRepo(ctrl), mocks.NewMockProjectRepo(ctrl), defaultWorkflowRepo(ctrl), changingIndex(ctrl))
+			ts.now = func() time.Time { return testNow }
+			_, got := ts.UpdateTask(tt.id, tt.version, tt.taskReq, tt.claims)
 
 			if tt.appErr == nil && tt.appErr != got {
 				t.Errorf("UpdateTask() failed, got err: %v.", got)
@@ -274,25 +877,160 @@ func Test_taskService_UpdateTask(t *testing.T) {
 				t.Errorf("UpdateTask() successed unexpectedly, wanted err: %v.", tt.appErr)
 				return
 			}
-			if tt.appErr != nil && *tt.appErr != *got {
+			if tt.appErr != nil && !reflect.DeepEqual(tt.appErr, got) {
 				t.Errorf("UpdateTask() = %v, want %v", got, tt.appErr)
 			}
 		})
 	}
 }
 
-func Test_taskService_DeleteTask(t *testing.T) {
+func Test_taskService_PatchTask(t *testing.T) {
+	stored := models.Task{
+		ID:        7,
+		Title:     "title",
+		Desc:      "desc",
+		Status:    0,
+		Priority:  3,
+		UserID:    1234,
+		DueAt:     time.Date(2020, time.January, 2, 10, 0, 0, 0, time.UTC),
+		LabelIDs:  []int64{2},
+		ProjectID: 5,
+		Version:   4,
+	}
 	tests := []struct {
 		name          string
 		setupTaskRepo func(mtr *mocks.MockTaskRepo)
-		id            string
+		version       int64
+		patch         models.TaskPatch
 		appErr        *errr.AppError
-		claims        models.Claims
 	}{
 		{
-			name: "successfully deleted task",
+			name: "merge patch null clears a field",
 			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {
-				mtr.EXPECT().DeleteTask(int64(1234), int64(4321)).Return(nil)
+				inTransaction(mtr)
+				mtr.EXPECT().UpdateTask(int64(7), models.Task{
+					Title:     "new title",
+					Desc:      "desc",
+					Status:    0,
+					Priority:  3,
+					UserID:    1234,
+					LabelIDs:  []int64{2},
+					ProjectID: 5,
+					UpdatedAt: testNow,
+					Version:   4,
+				}).Return(nil)
+			},
+			version: 4,
+			patch: models.TaskPatch{
+				Format: models.MergePatch,
+				Doc:    []byte(`{"title":"new title","due_at":null}`),
+			},
+		},
+		{
+			name: "json patch removes labels and project",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {
+				inTransaction(mtr)
+				mtr.EXPECT().UpdateTask(int64(7), models.Task{
+					Title:     "title",
+					Desc:      "desc",
+					Status:    0,
+					UserID:    1234,
+					DueAt:     time.Date(2020, time.January, 2, 10, 0, 0, 0, time.UTC),
+					UpdatedAt: testNow,
+					Version:   4,
+				}).Return(nil)
+			},
+			patch: models.TaskPatch{
+				Format: models.JSONPatch,
+				Doc: []byte(`[
+					{"op":"remove","path":"/label_ids"},
+					{"op":"remove","path":"/project_id"},
+					{"op":"replace","path":"/priority","value":null}
+				]`),
+			},
+		},
+		{
+			name:          "json patch test fails",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {},
+			patch: models.TaskPatch{
+				Format: models.JSONPatch,
+				Doc:    []byte(`[{"op":"test","path":"/title","value":"other title"}]`),
+			},
+			appErr: errr.NewDuplicateError("Task does not pass the test of the patch"),
+		},
+		{
+			name:          "json patch of a missing field",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {},
+			patch: models.TaskPatch{
+				Format: models.JSONPatch,
+				Doc:    []byte(`[{"op":"replace","path":"/start_at","value":"2020-01-01T10:00:00Z"}]`),
+			},
+			appErr: errr.NewBadRequestError("Invalid patch, operation 0: path /start_at does not exist"),
+		},
+		{
+			name:          "merge patch clears a required field",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) { inTransaction(mtr) },
+			patch: models.TaskPatch{
+				Format: models.MergePatch,
+				Doc:    []byte(`{"title":null}`),
+			},
+			appErr: violation("title", models.RuleRequired, "Title is required"),
+		},
+		{
+			name:          "merge patch adds an unknown field",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {},
+			patch: models.TaskPatch{
+				Format: models.MergePatch,
+				Doc:    []byte(`{"colour":"red"}`),
+			},
+			appErr: errr.NewBadRequestError("Patched task is not a valid task"),
+		},
+		{
+			name:          "task changed since it was read",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {},
+			version:       3,
+			patch: models.TaskPatch{
+				Format: models.MergePatch,
+				Doc:    []byte(`{"title":"new title"}`),
+			},
+			appErr: errr.NewPreconditionFailedError("Task has changed since it was read"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mtr := mocks.NewMockTaskRepo(ctrl)
+			mtr.EXPECT().GetTask(int64(7), int64(1234)).Return(stored, nil)
+			tt.setupTaskRepo(mtr)
+			mlr := mocks.NewMockLabelRepo(ctrl)
+			mlr.EXPECT().GetLabels(int64(1234)).Return([]models.Label{{ID: 2, UserID: 1234}}, nil).AnyTimes()
+			mpr := mocks.NewMockProjectRepo(ctrl)
+			mpr.EXPECT().GetProject(int64(5), int64(1234)).Return(models.Project{ID: 5, UserID: 1234}, nil).AnyTimes()
+			ts := NewTaskService(mtr, mlr, mpr, defaultWorkflowRepo(ctrl), changingIndex(ctrl))
+			ts.now = func() time.Time { return testNow }
+
+			_, got := ts.PatchTask("7", tt.version, tt.patch, models.Claims{ID: 1234})
+			if !reflect.DeepEqual(got, tt.appErr) {
+				t.Errorf("PatchTask() = %v, want %v", got, tt.appErr)
+			}
+		})
+	}
+}
+
+func Test_taskService_DeleteTask(t *testing.T) {
+	tests := []struct {
+		name          string
+		setupTaskRepo func(mtr *mocks.MockTaskRepo)
+		id            string
+		appErr        *errr.AppError
+		claims        models.Claims
+	}{
+		{
+			name: "successfully deleted task",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {
+				mtr.EXPECT().DeleteTask(int64(1234), int64(4321), int64(0), testNow).Return(nil)
 			},
 			id:     "1234",
 			appErr: nil,
@@ -317,7 +1055,7 @@ func Test_taskService_DeleteTask(t *testing.T) {
 		{
 			name: "task repo failed to delete task",
 			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {
-				mtr.EXPECT().DeleteTask(int64(1234), int64(1234)).Return(&errr.AppError{
+				mtr.EXPECT().DeleteTask(int64(1234), int64(1234), int64(0), gomock.Any()).Return(&errr.AppError{
 					Code:    0,
 					Message: "error message from task repo",
 				})
@@ -340,9 +1078,10 @@ func Test_taskService_DeleteTask(t *testing.T) {
 
 			mtr := mocks.NewMockTaskRepo(ctrl)
 			tt.setupTaskRepo(mtr)
-			ts := NewTaskService(mtr)
+			ts := NewTaskService(mtr, mocks.NewMockLabelRepo(ctrl), mocks.NewMockProjectRepo(ctrl), defaultWorkflowRepo(ctrl), changingIndex(ctrl))
+			ts.now = func() time.Time { return testNow }
 
-			got := ts.DeleteTask(tt.id, tt.claims)
+			got := ts.DeleteTask(tt.id, 0, tt.claims)
 			if tt.appErr == nil && tt.appErr != got {
 				t.Errorf("DeleteTask() failed, got err: %v.", got)
 				return
@@ -351,25 +1090,220 @@ func Test_taskService_DeleteTask(t *testing.T) {
 				t.Errorf("DeleteTask() successed unexpectedly, wanted err: %v.", tt.appErr)
 				return
 			}
-			if tt.appErr != nil && *tt.appErr != *got {
+			if tt.appErr != nil && !reflect.DeepEqual(tt.appErr, got) {
 				t.Errorf("DeleteTask() = %v, want %v", got, tt.appErr)
 			}
 		})
 	}
 }
 
+func Test_taskService_trash(t *testing.T) {
+	claims := models.Claims{ID: 1234}
+	ctrl := gomock.NewController(t)
+	mtr := mocks.NewMockTaskRepo(ctrl)
+	ts := NewTaskService(mtr, mocks.NewMockLabelRepo(ctrl), mocks.NewMockProjectRepo(ctrl), defaultWorkflowRepo(ctrl), changingIndex(ctrl))
+	ts.now = func() time.Time { return testNow }
+
+	mtr.EXPECT().GetTrash(int64(1234)).Return([]models.Task{
+		{ID: 7, Title: "title", Status: 2, UserID: 1234, DeletedAt: testNow},
+	}, nil)
+	trash, appErr := ts.GetTrash(claims)
+	want := []models.TaskResponseDto{{
+		ID: "7", Title: "title", Status: "Waiting", Priority: "None", DeletedAt: "2025-02-01T12:00:00Z",
+	}}
+	if appErr != nil || !reflect.DeepEqual(trash, want) {
+		t.Errorf("GetTrash() = %v, %v, want %v", trash, appErr, want)
+	}
+
+	mtr.EXPECT().RestoreTask(int64(7), int64(1234)).Return(nil)
+	mtr.EXPECT().GetTask(int64(7), int64(1234)).Return(models.Task{ID: 7, UserID: 1234}, nil)
+	subtasks := models.NewTaskQuery(1234)
+	subtasks.ParentIDs = []int64{7}
+	mtr.EXPECT().GetTasks(subtasks).Return([]models.Task{{ID: 8, UserID: 1234, ParentID: 7}}, nil)
+	subtasks.ParentIDs = []int64{8}
+	mtr.EXPECT().GetTasks(subtasks).Return([]models.Task{}, nil)
+	if appErr := ts.RestoreTask("7", claims); appErr != nil {
+		t.Errorf("RestoreTask() failed: %v", appErr)
+	}
+	if appErr := ts.RestoreTask("seven", claims); appErr == nil || appErr.Code != http.StatusBadRequest {
+		t.Errorf("RestoreTask() of an invalid id = %v, want bad request", appErr)
+	}
+
+	mtr.EXPECT().PurgeTask(int64(7), int64(1234)).Return(errr.NewNotFoundError("no task found in the trash with id"))
+	if appErr := ts.PurgeTask("7", claims); appErr == nil || appErr.Code != http.StatusNotFound {
+		t.Errorf("PurgeTask() = %v, want not found", appErr)
+	}
+
+	mtr.EXPECT().PurgeTrash(testNow.Add(-24*time.Hour)).Return(3, nil)
+	if purged, appErr := ts.PurgeTrash(24 * time.Hour); appErr != nil || purged != 3 {
+		t.Errorf("PurgeTrash() = %v, %v, want 3", purged, appErr)
+	}
+}
+
+func Test_taskService_updates_the_index(t *testing.T) {
+	claims := models.Claims{ID: 1234}
+	initial := models.DefaultWorkflow(1234).Initial()
+	ctrl := gomock.NewController(t)
+	mtr := mocks.NewMockTaskRepo(ctrl)
+	mti := mocks.NewMockTaskIndex(ctrl)
+	ts := NewTaskService(mtr, mocks.NewMockLabelRepo(ctrl), mocks.NewMockProjectRepo(ctrl), defaultWorkflowRepo(ctrl), mti)
+	ts.now = func() time.Time { return testNow }
+
+	inTransaction(mtr)
+	mtr.EXPECT().SaveTask(gomock.Any()).DoAndReturn(storesTask(7))
+	mti.EXPECT().Put(models.Task{
+		ID: 7, Title: "title", Desc: "desc", Status: initial.ID, UserID: 1234, CreatedAt: testNow, UpdatedAt: testNow,
+	})
+	if _, appErr := ts.CreateTask(models.TaskRequestDto{Title: "title", Desc: "desc"}, claims); appErr != nil {
+		t.Fatalf("CreateTask() failed: %v", appErr)
+	}
+
+	stored := models.Task{ID: 7, Title: "title", Desc: "desc", Status: initial.ID, UserID: 1234, Version: 1}
+	mtr.EXPECT().GetTask(int64(7), int64(1234)).Return(stored, nil)
+	inTransaction(mtr)
+	mtr.EXPECT().UpdateTask(int64(7), gomock.Any()).Return(nil)
+	mti.EXPECT().Put(models.Task{
+		ID: 7, Title: "new title", Desc: "desc", Status: initial.ID, UserID: 1234,
+		UpdatedAt: testNow, Version: 2,
+	})
+	taskReq := models.TaskRequestDto{Title: "new title", Desc: "desc", Status: initial.Name}
+	if version, appErr := ts.UpdateTask("7", 1, taskReq, claims); appErr != nil || version != 2 {
+		t.Fatalf("UpdateTask() = %d, %v, want version 2", version, appErr)
+	}
+
+	mtr.EXPECT().DeleteTask(int64(7), int64(1234), int64(0), testNow).Return(nil)
+	mti.EXPECT().Remove(int64(1234), int64(7))
+	if appErr := ts.DeleteTask("7", 0, claims); appErr != nil {
+		t.Fatalf("DeleteTask() failed: %v", appErr)
+	}
+
+	// the task is restored even when it can't be indexed right away.
+	mtr.EXPECT().RestoreTask(int64(7), int64(1234)).Return(nil)
+	mtr.EXPECT().GetTask(int64(7), int64(1234)).Return(models.Task{}, errr.NewUnexpectedError("unavailable"))
+	mti.EXPECT().Forget(int64(1234))
+	if appErr := ts.RestoreTask("7", claims); appErr != nil {
+		t.Fatalf("RestoreTask() failed: %v", appErr)
+	}
+}
+
+func Test_taskService_GetTask(t *testing.T) {
+	subtasks := models.NewTaskQuery(4321)
+	subtasks.ParentIDs = []int64{1234}
+	tests := []struct {
+		name          string
+		setupTaskRepo func(mtr *mocks.MockTaskRepo)
+		id            string
+		want          models.TaskResponseDto
+		appErr        *errr.AppError
+	}{
+		{
+			name: "task with subtask progress",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {
+				mtr.EXPECT().GetTask(int64(1234), int64(4321)).Return(models.Task{
+					ID:     1234,
+					UserID: 4321,
+					Title:  "title",
+					Status: models.StatusPending,
+				}, nil)
+				mtr.EXPECT().GetTasks(subtasks).Return([]models.Task{
+					{ID: 1, UserID: 4321, ParentID: 1234, Done: true},
+					{ID: 2, UserID: 4321, ParentID: 1234},
+				}, nil)
+			},
+			id: "1234",
+			want: models.TaskResponseDto{
+				ID:       "1234",
+				Title:    "title",
+				Status:   "Pending",
+				Priority: "None",
+				Progress: "1/2 done",
+			},
+		},
+		{
+			name:          "invalid id",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {},
+			id:            "12x",
+			appErr:        &errr.AppError{Code: http.StatusBadRequest, Message: "Invalid task id"},
+		},
+		{
+			name: "task not found",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {
+				mtr.EXPECT().GetTask(int64(1234), int64(4321)).Return(
+					models.Task{},
+					errr.NewNotFoundError("no task found with id"),
+				)
+			},
+			id:     "1234",
+			appErr: &errr.AppError{Code: http.StatusNotFound, Message: "no task found with id"},
+		},
+		{
+			name: "task of another user",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {
+				mtr.EXPECT().GetTask(int64(1234), int64(4321)).Return(
+					models.Task{},
+					errr.NewUnauthorizedError("Unauthorized to get task"),
+				)
+			},
+			id:     "1234",
+			appErr: &errr.AppError{Code: http.StatusForbidden, Message: "Unauthorized to get task"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mtr := mocks.NewMockTaskRepo(ctrl)
+			tt.setupTaskRepo(mtr)
+			ts := NewTaskService(mtr, mocks.NewMockLabelRepo(ctrl), mocks.NewMockProjectRepo(ctrl), defaultWorkflowRepo(ctrl), changingIndex(ctrl))
+
+			got, appErr := ts.GetTask(tt.id, models.Claims{ID: 4321})
+			if tt.appErr != nil {
+				if appErr == nil || !reflect.DeepEqual(appErr, tt.appErr) {
+					t.Errorf("GetTask() err = %v, want %v", appErr, tt.appErr)
+				}
+				return
+			}
+			if appErr != nil {
+				t.Fatalf("GetTask() failed, got err: %v.", appErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetTask() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_taskService_GetTasks(t *testing.T) {
+	// pageQuery is the query of a listing of user 1234 with the default page
+	// size, changed by edit.
+	pageQuery := func(edit func(q *models.TaskQuery)) models.TaskQuery {
+		q := models.NewTaskQuery(1234)
+		q.Limit = defaultTaskPageSize + 1
+		if edit != nil {
+			edit(&q)
+		}
+		return q
+	}
+	subtaskQuery := func(parentIDs ...int64) models.TaskQuery {
+		q := models.NewTaskQuery(1234)
+		q.ParentIDs = parentIDs
+		return q
+	}
+	cursor := models.TaskCursor{Sort: models.TaskSortTitle, Reverse: true, ID: 3, Title: "c"}
+
 	tests := []struct {
 		name          string
 		setupTaskRepo func(mtr *mocks.MockTaskRepo)
 		appErr        *errr.AppError
-		want          []models.TaskResponseDto
+		want          models.TaskPageDto
 		claims        models.Claims
+		filter        models.TaskFilterDto
 	}{
 		{
 			name: "successfully got task",
 			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {
-				mtr.EXPECT().GetTasks(int64(1234)).Return([]models.Task{
+				mtr.EXPECT().GetTasks(pageQuery(nil)).Return([]models.Task{
 					{
 						ID:     1234,
 						Title:  "my title",
@@ -381,33 +1315,259 @@ func Test_taskService_GetTasks(t *testing.T) {
 						Title:  "my title",
 						Desc:   "my string",
 						Status: 1,
+						Done:   true,
 					},
 				}, nil)
+				mtr.EXPECT().GetTasks(subtaskQuery(1234, 1235)).Return([]models.Task{}, nil)
 			},
 			appErr: nil,
-			want: []models.TaskResponseDto{
+			want: models.TaskPageDto{Tasks: []models.TaskResponseDto{
 				{
-					ID:     "1234",
-					Title:  "my title",
-					Desc:   "my string",
-					Status: "Pending",
+					ID:       "1234",
+					Title:    "my title",
+					Desc:     "my string",
+					Status:   "Pending",
+					Priority: "None",
 				},
 				{
-					ID:     "1235",
-					Title:  "my title",
-					Desc:   "my string",
-					Status: "Done",
+					ID:       "1235",
+					Title:    "my title",
+					Desc:     "my string",
+					Status:   "Done",
+					Priority: "None",
 				},
-			},
+			}},
 			claims: models.Claims{
 				ID:   1234,
 				Role: "",
 			},
 		},
+		{
+			name: "no tasks",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {
+				mtr.EXPECT().GetTasks(pageQuery(nil)).Return([]models.Task{}, nil)
+			},
+			appErr: nil,
+			want:   models.TaskPageDto{Tasks: []models.TaskResponseDto{}},
+			claims: models.Claims{
+				ID: 1234,
+			},
+		},
+		{
+			name: "filters are passed to the repo",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {
+				mtr.EXPECT().GetTasks(pageQuery(func(q *models.TaskQuery) {
+					q.Status = models.StatusDone
+					q.DueAfter = time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+					q.DueBefore = time.Date(2020, time.January, 3, 0, 0, 0, 0, time.UTC)
+					q.LabelID = 9
+					q.ProjectID = 7
+					q.Sort = models.TaskSortDue
+				})).Return([]models.Task{}, nil)
+			},
+			appErr: nil,
+			want:   models.TaskPageDto{Tasks: []models.TaskResponseDto{}},
+			claims: models.Claims{
+				ID: 1234,
+			},
+			filter: models.TaskFilterDto{
+				DueAfter:  "2020-01-01T00:00:00",
+				DueBefore: "2020-01-03T00:00:00Z",
+				Status:    "done",
+				Label:     "9",
+				Project:   "7",
+				Sort:      "due",
+				Order:     "asc",
+			},
+		},
+		{
+			name: "priority runs most urgent first unless ascending",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {
+				mtr.EXPECT().GetTasks(pageQuery(func(q *models.TaskQuery) {
+					q.Sort = models.TaskSortPriority
+					q.Reverse = true
+				})).Return([]models.Task{}, nil)
+			},
+			appErr: nil,
+			want:   models.TaskPageDto{Tasks: []models.TaskResponseDto{}},
+			claims: models.Claims{
+				ID: 1234,
+			},
+			filter: models.TaskFilterDto{
+				Sort:  "priority",
+				Order: "asc",
+			},
+		},
+		{
+			name: "full page links to the next one",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {
+				mtr.EXPECT().GetTasks(pageQuery(func(q *models.TaskQuery) {
+					q.Sort = models.TaskSortTitle
+					q.Reverse = true
+					q.Limit = 3
+					q.After = &cursor
+				})).Return([]models.Task{
+					{ID: 5, Title: "b"},
+					{ID: 4, Title: "a"},
+					{ID: 6, Title: "a"},
+				}, nil)
+				mtr.EXPECT().GetTasks(subtaskQuery(5, 4)).Return([]models.Task{}, nil)
+			},
+			appErr: nil,
+			want: models.TaskPageDto{
+				Tasks: []models.TaskResponseDto{
+					{ID: "5", Title: "b", Status: "Pending", Priority: "None"},
+					{ID: "4", Title: "a", Status: "Pending", Priority: "None"},
+				},
+				NextCursor: models.TaskCursor{
+					Sort:    models.TaskSortTitle,
+					Reverse: true,
+					ID:      4,
+					Title:   "a",
+				}.String(),
+			},
+			claims: models.Claims{
+				ID: 1234,
+			},
+			filter: models.TaskFilterDto{
+				Sort:  "title",
+				Order: "desc",
+				Limit: "2",
+				After: cursor.String(),
+			},
+		},
+		{
+			name: "progress counts checklist items and subtasks",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {
+				mtr.EXPECT().GetTasks(pageQuery(nil)).Return([]models.Task{
+					{
+						ID:        1,
+						Title:     "project",
+						Checklist: []models.ChecklistItem{{Text: "plan", Done: true}},
+					},
+				}, nil)
+				mtr.EXPECT().GetTasks(subtaskQuery(1)).Return([]models.Task{
+					{ID: 2, Title: "step", ParentID: 1, Status: 1, Done: true},
+					{ID: 3, Title: "other step", ParentID: 1},
+				}, nil)
+			},
+			appErr: nil,
+			want: models.TaskPageDto{Tasks: []models.TaskResponseDto{
+				{
+					ID:        "1",
+					Title:     "project",
+					Status:    "Pending",
+					Priority:  "None",
+					Progress:  "2/3 done",
+					Checklist: []models.ChecklistItem{{Text: "plan", Done: true}},
+				},
+			}},
+			claims: models.Claims{
+				ID: 1234,
+			},
+		},
+		{
+			name:          "invalid label filter",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {},
+			appErr: &errr.AppError{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid label",
+			},
+			claims: models.Claims{
+				ID: 1234,
+			},
+			filter: models.TaskFilterDto{
+				Label: "work",
+			},
+		},
+		{
+			name:          "unknown status filter",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {},
+			appErr: &errr.AppError{
+				Code:    http.StatusBadRequest,
+				Message: "Unknown status",
+			},
+			claims: models.Claims{
+				ID: 1234,
+			},
+			filter: models.TaskFilterDto{
+				Status: "Blocked",
+			},
+		},
+		{
+			name:          "invalid sort",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {},
+			appErr: &errr.AppError{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid sort, use created, updated, title, due or priority",
+			},
+			claims: models.Claims{
+				ID: 1234,
+			},
+			filter: models.TaskFilterDto{
+				Sort: "color",
+			},
+		},
+		{
+			name:          "invalid order",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {},
+			appErr: &errr.AppError{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid order, use asc or desc",
+			},
+			claims: models.Claims{
+				ID: 1234,
+			},
+			filter: models.TaskFilterDto{
+				Order: "up",
+			},
+		},
+		{
+			name:          "limit out of range",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {},
+			appErr: &errr.AppError{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid limit, use 1 to 200",
+			},
+			claims: models.Claims{
+				ID: 1234,
+			},
+			filter: models.TaskFilterDto{
+				Limit: "0",
+			},
+		},
+		{
+			name:          "cursor of another sort",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {},
+			appErr: &errr.AppError{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid after, pass the next_cursor of a listing with the same sort and order",
+			},
+			claims: models.Claims{
+				ID: 1234,
+			},
+			filter: models.TaskFilterDto{
+				After: cursor.String(),
+			},
+		},
+		{
+			name:          "invalid due filter",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {},
+			appErr: &errr.AppError{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid due_before, use RFC 3339",
+			},
+			claims: models.Claims{
+				ID: 1234,
+			},
+			filter: models.TaskFilterDto{
+				DueBefore: "next week",
+			},
+		},
 		{
 			name: "task repo failed to get task",
 			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {
-				mtr.EXPECT().GetTasks(int64(1234)).Return(nil, &errr.AppError{
+				mtr.EXPECT().GetTasks(pageQuery(nil)).Return(nil, &errr.AppError{
 					Code:    0,
 					Message: "error message from task repo",
 				})
@@ -429,24 +1589,525 @@ func Test_taskService_GetTasks(t *testing.T) {
 
 			mtr := mocks.NewMockTaskRepo(ctrl)
 			tt.setupTaskRepo(mtr)
-			ts := NewTaskService(mtr)
+			ts := NewTaskService(mtr, mocks.NewMockLabelRepo(ctrl), mocks.NewMockProjectRepo(ctrl), defaultWorkflowRepo(ctrl), changingIndex(ctrl))
+			ts.now = func() time.Time { return testNow }
 
-			got, err := ts.GetTasks(tt.claims)
+			got, err := ts.GetTasks(tt.claims, tt.filter)
 
 			if tt.appErr == nil && tt.appErr != err {
-				t.Errorf("DeleteTask() failed, got err: %v.", got)
+				t.Errorf("GetTasks() failed, got err: %v.", err)
 				return
 			}
-			if tt.appErr == nil && !slices.Equal(got, tt.want) {
+			if tt.appErr == nil && !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("wanted output: %v, got: %v", tt.want, got)
 			}
 			if tt.appErr != nil && err == nil {
-				t.Errorf("DeleteTask() successed unexpectedly, wanted err: %v.", tt.appErr)
+				t.Errorf("GetTasks() successed unexpectedly, wanted err: %v.", tt.appErr)
 				return
 			}
-			if tt.appErr != nil && *tt.appErr != *err {
-				t.Errorf("DeleteTask() = %v, want %v", got, tt.appErr)
+			if tt.appErr != nil && !reflect.DeepEqual(tt.appErr, err) {
+				t.Errorf("GetTasks() = %v, want %v", got, tt.appErr)
+			}
+		})
+	}
+}
+
+func Test_taskService_UpdateTask_recurring(t *testing.T) {
+	weekly, _ := models.ParseRecurrence("FREQ=WEEKLY;BYDAY=MO")
+	stored := models.Task{
+		ID:         7,
+		Title:      "report",
+		Desc:       "weekly report",
+		Status:     2,
+		UserID:     1234,
+		DueAt:      time.Date(2025, time.March, 3, 9, 0, 0, 0, time.UTC),
+		StartAt:    time.Date(2025, time.March, 3, 8, 0, 0, 0, time.UTC),
+		Recurrence: &weekly,
+		Checklist:  []models.ChecklistItem{{Text: "draft", Done: true}},
+	}
+	done := stored
+	done.Status = 1
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mtr := mocks.NewMockTaskRepo(ctrl)
+	inTransaction(mtr)
+	gomock.InOrder(
+		mtr.EXPECT().GetTask(int64(7), int64(1234)).Return(stored, nil),
+		mtr.EXPECT().UpdateTask(int64(7), gomock.Any()).Return(nil),
+		mtr.EXPECT().GetTask(int64(7), int64(1234)).Return(done, nil),
+		mtr.EXPECT().SaveTask(models.Task{
+			Title:      "report",
+			Desc:       "weekly report",
+			Status:     2,
+			UserID:     1234,
+			DueAt:      time.Date(2025, time.March, 10, 9, 0, 0, 0, time.UTC),
+			StartAt:    time.Date(2025, time.March, 10, 8, 0, 0, 0, time.UTC),
+			Recurrence: &weekly,
+			SeriesID:   7,
+			Checklist:  []models.ChecklistItem{{Text: "draft"}},
+			CreatedAt:  testNow,
+			UpdatedAt:  testNow,
+		}).DoAndReturn(storesTask(99)),
+	)
+	ts := NewTaskService(mtr, mocks.NewMockLabelRepo(ctrl), mocks.NewMockProjectRepo(ctrl), defaultWorkflowRepo(ctrl), changingIndex(ctrl))
+	ts.now = func() time.Time { return testNow }
+
+	_, appErr := ts.UpdateTask("7", 0, stored.ToRequestDto("Done", time.UTC), models.Claims{ID: 1234})
+	if appErr != nil {
+		t.Errorf("UpdateTask() failed, got err: %v.", appErr)
+	}
+}
+
+func Test_taskService_UpdateTask_workflow(t *testing.T) {
+	workflow := models.Workflow{
+		UserID: 1234,
+		Statuses: []models.WorkflowStatus{
+			{ID: 3, Name: "Backlog", Next: []int{4}},
+			{ID: 4, Name: "Review", Next: []int{3, 5}},
+			{ID: 5, Name: "Shipped", Terminal: true},
+		},
+	}
+	tests := []struct {
+		name          string
+		setupTaskRepo func(mtr *mocks.MockTaskRepo)
+		status        string
+		appErr        *errr.AppError
+	}{
+		{
+			name: "allowed transition",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {
+				mtr.EXPECT().GetTask(int64(7), int64(1234)).Return(models.Task{ID: 7, UserID: 1234, Status: 4}, nil)
+				inTransaction(mtr)
+				mtr.EXPECT().UpdateTask(int64(7), models.Task{
+					Title: "title", Desc: "desc", Status: 5, Done: true, UserID: 1234,
+					UpdatedAt: testNow,
+				}).Return(nil)
+				mtr.EXPECT().GetTask(int64(7), int64(1234)).Return(models.Task{ID: 7, Status: 5}, nil)
+			},
+			status: "shipped",
+		},
+		{
+			name: "transition not in the workflow",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {
+				mtr.EXPECT().GetTask(int64(7), int64(1234)).Return(models.Task{ID: 7, UserID: 1234, Status: 3}, nil)
+				inTransaction(mtr)
+			},
+			status: "Shipped",
+			appErr: errr.NewDuplicateError("Status can't change from Backlog to Shipped"),
+		},
+		{
+			name: "status of the default workflow",
+			setupTaskRepo: func(mtr *mocks.MockTaskRepo) {
+				mtr.EXPECT().GetTask(int64(7), int64(1234)).Return(models.Task{ID: 7, UserID: 1234, Status: 3}, nil)
+				inTransaction(mtr)
+			},
+			status: "Done",
+			appErr: violation("status", models.RuleUnknownStatus, "Unknown status"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mtr := mocks.NewMockTaskRepo(ctrl)
+			tt.setupTaskRepo(mtr)
+			mwr := mocks.NewMockWorkflowRepo(ctrl)
+			mwr.EXPECT().GetWorkflow(int64(1234)).Return(workflow, nil)
+			ts := NewTaskService(mtr, mocks.NewMockLabelRepo(ctrl), mocks.NewMockProjectRepo(ctrl), mwr, changingIndex(ctrl))
+			ts.now = func() time.Time { return testNow }
+
+			taskReq := models.TaskRequestDto{Title: "title", Desc: "desc", Status: tt.status}
+			_, got := ts.UpdateTask("7", 0, taskReq, models.Claims{ID: 1234})
+			if tt.appErr == nil && got != nil {
+				t.Errorf("UpdateTask() failed, got err: %v.", got)
+				return
+			}
+			if tt.appErr != nil && (got == nil || !reflect.DeepEqual(tt.appErr, got)) {
+				t.Errorf("UpdateTask() = %v, want %v", got, tt.appErr)
 			}
 		})
 	}
 }
+
+func Test_taskService_UpdateTask_alreadyDone(t *testing.T) {
+	weekly, _ := models.ParseRecurrence("FREQ=WEEKLY;BYDAY=MO")
+	stored := models.Task{
+		ID:         7,
+		Title:      "report",
+		Desc:       "weekly report",
+		Status:     1,
+		Done:       true,
+		UserID:     1234,
+		DueAt:      time.Date(2025, time.March, 3, 9, 0, 0, 0, time.UTC),
+		Recurrence: &weekly,
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mtr := mocks.NewMockTaskRepo(ctrl)
+	mtr.EXPECT().GetTask(int64(7), int64(1234)).Return(stored, nil)
+	inTransaction(mtr)
+	mtr.EXPECT().UpdateTask(int64(7), gomock.Any()).Return(nil)
+	ts := NewTaskService(mtr, mocks.NewMockLabelRepo(ctrl), mocks.NewMockProjectRepo(ctrl), defaultWorkflowRepo(ctrl), changingIndex(ctrl))
+	ts.now = func() time.Time { return testNow }
+
+	_, appErr := ts.UpdateTask("7", 0, stored.ToRequestDto("Done", time.UTC), models.Claims{ID: 1234})
+	if appErr != nil {
+		t.Errorf("UpdateTask() failed, got err: %v.", appErr)
+	}
+}
+
+func Test_taskService_GetOccurrences(t *testing.T) {
+	daily, _ := models.ParseRecurrence("FREQ=DAILY")
+	tasks := []models.Task{
+		{
+			ID:         7,
+			UserID:     1234,
+			DueAt:      time.Date(2025, time.March, 3, 9, 0, 0, 0, time.UTC),
+			Recurrence: &daily,
+		},
+		{ID: 8, UserID: 1234},
+	}
+	tests := []struct {
+		name   string
+		id     string
+		count  string
+		want   []models.OccurrenceDto
+		appErr *errr.AppError
+	}{
+		{
+			name:  "upcoming occurrences",
+			id:    "7",
+			count: "2",
+			want: []models.OccurrenceDto{
+				{DueAt: "2025-03-04T10:00:00+01:00"},
+				{DueAt: "2025-03-05T10:00:00+01:00"},
+			},
+		},
+		{
+			name:   "task does not recur",
+			id:     "8",
+			appErr: &errr.AppError{Code: http.StatusBadRequest, Message: "Task does not recur"},
+		},
+		{
+			name:   "task not found",
+			id:     "9",
+			appErr: &errr.AppError{Code: http.StatusNotFound, Message: "no task found with id"},
+		},
+		{
+			name:   "count too large",
+			id:     "7",
+			count:  "500",
+			appErr: &errr.AppError{Code: http.StatusBadRequest, Message: "Invalid count, use 1 to 50"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mtr := mocks.NewMockTaskRepo(ctrl)
+			mtr.EXPECT().GetTask(gomock.Any(), int64(1234)).DoAndReturn(func(id, userID int64) (models.Task, *errr.AppError) {
+				for _, task := range tasks {
+					if task.ID == id {
+						return task, nil
+					}
+				}
+				return models.Task{}, errr.NewNotFoundError("no task found with id")
+			}).AnyTimes()
+			ts := NewTaskService(mtr, mocks.NewMockLabelRepo(ctrl), mocks.NewMockProjectRepo(ctrl), defaultWorkflowRepo(ctrl), changingIndex(ctrl))
+			ts.now = func() time.Time { return testNow }
+
+			got, appErr := ts.GetOccurrences(
+				tt.id,
+				tt.count,
+				models.Claims{ID: 1234, TimeZone: "Europe/Paris"},
+			)
+			if tt.appErr != nil {
+				if appErr == nil || !reflect.DeepEqual(appErr, tt.appErr) {
+					t.Errorf("GetOccurrences() err = %v, want %v", appErr, tt.appErr)
+				}
+				return
+			}
+			if appErr != nil {
+				t.Fatalf("GetOccurrences() failed, got err: %v.", appErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetOccurrences() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_taskService_SearchTasks(t *testing.T) {
+	tasks := []models.Task{
+		{ID: 1, UserID: 1234, Title: "Buy milk", Desc: "and bread"},
+		{ID: 2, UserID: 1234, Title: "Bake bread", Status: models.StatusDone, Done: true, ParentID: 1},
+		{ID: 3, UserID: 1234, Title: "Call mum"},
+	}
+	breadHits := []models.SearchHit{
+		{TaskID: 2, Score: 2.5, Title: "Bake <mark>bread</mark>"},
+		{TaskID: 1, Score: 1.5, Title: "Buy milk", Snippet: "and <mark>bread</mark>"},
+	}
+	byIDs := func(ids ...int64) models.TaskQuery {
+		query := models.NewTaskQuery(1234)
+		query.IDs = ids
+		return query
+	}
+	byParentIDs := func(ids ...int64) models.TaskQuery {
+		query := models.NewTaskQuery(1234)
+		query.ParentIDs = ids
+		return query
+	}
+	// returnsHits serves the hits of the bread search along with their tasks.
+	returnsHits := func(mtr *mocks.MockTaskRepo, mti *mocks.MockTaskIndex) {
+		mti.EXPECT().Search(int64(1234), "bread", 20).Return(breadHits)
+		mtr.EXPECT().GetTasks(byIDs(2, 1)).Return(tasks[:2], nil)
+		mtr.EXPECT().GetTasks(byParentIDs(2, 1)).Return(tasks[1:2], nil)
+	}
+	breadResults := []models.SearchResultDto{
+		{
+			Task:       models.TaskResponseDto{ID: "2", Title: "Bake bread", Status: "Done", Priority: "None", ParentID: "1"},
+			Score:      2.5,
+			Highlights: models.SearchHighlightsDto{Title: "Bake <mark>bread</mark>"},
+		},
+		{
+			Task: models.TaskResponseDto{
+				ID:       "1",
+				Title:    "Buy milk",
+				Desc:     "and bread",
+				Status:   "Pending",
+				Priority: "None",
+				Progress: "1/1 done",
+			},
+			Score:      1.5,
+			Highlights: models.SearchHighlightsDto{Title: "Buy milk", Desc: "and <mark>bread</mark>"},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		query  string
+		limit  string
+		setup  func(mtr *mocks.MockTaskRepo, mti *mocks.MockTaskIndex)
+		want   []models.SearchResultDto
+		appErr *errr.AppError
+	}{
+		{
+			name:  "indexes the user on the first search",
+			query: " bread ",
+			setup: func(mtr *mocks.MockTaskRepo, mti *mocks.MockTaskIndex) {
+				mti.EXPECT().Indexed(int64(1234)).Return(false)
+				mti.EXPECT().Generation(int64(1234)).Return(uint64(4))
+				mtr.EXPECT().GetTasks(models.NewTaskQuery(1234)).Return(tasks, nil)
+				mti.EXPECT().Replace(int64(1234), tasks, uint64(4)).Return(true)
+				returnsHits(mtr, mti)
+			},
+			want: breadResults,
+		},
+		{
+			name:  "reads the tasks again when they changed while indexing",
+			query: "bread",
+			setup: func(mtr *mocks.MockTaskRepo, mti *mocks.MockTaskIndex) {
+				mti.EXPECT().Indexed(int64(1234)).Return(false)
+				gomock.InOrder(
+					mti.EXPECT().Generation(int64(1234)).Return(uint64(4)),
+					mti.EXPECT().Replace(int64(1234), tasks, uint64(4)).Return(false),
+					mti.EXPECT().Generation(int64(1234)).Return(uint64(5)),
+					mti.EXPECT().Replace(int64(1234), tasks, uint64(5)).Return(true),
+				)
+				mtr.EXPECT().GetTasks(models.NewTaskQuery(1234)).Return(tasks, nil).Times(2)
+				returnsHits(mtr, mti)
+			},
+			want: breadResults,
+		},
+		{
+			name:  "tasks keep changing while indexing",
+			query: "bread",
+			setup: func(mtr *mocks.MockTaskRepo, mti *mocks.MockTaskIndex) {
+				mti.EXPECT().Indexed(int64(1234)).Return(false)
+				mti.EXPECT().Generation(int64(1234)).Return(uint64(4)).Times(maxIndexAttempts)
+				mtr.EXPECT().GetTasks(models.NewTaskQuery(1234)).Return(tasks, nil).Times(maxIndexAttempts)
+				mti.EXPECT().Replace(int64(1234), tasks, uint64(4)).Return(false).Times(maxIndexAttempts)
+			},
+			appErr: errr.NewUnexpectedError("Unable to search tasks due to internal server error"),
+		},
+		{
+			name:  "reads only the tasks of the hits",
+			query: "bread",
+			setup: func(mtr *mocks.MockTaskRepo, mti *mocks.MockTaskIndex) {
+				mti.EXPECT().Indexed(int64(1234)).Return(true)
+				returnsHits(mtr, mti)
+			},
+			want: breadResults,
+		},
+		{
+			name:  "skips hits on tasks that are gone",
+			query: "milk",
+			limit: "5",
+			setup: func(mtr *mocks.MockTaskRepo, mti *mocks.MockTaskIndex) {
+				mti.EXPECT().Indexed(int64(1234)).Return(true)
+				mti.EXPECT().Search(int64(1234), "milk", 5).Return([]models.SearchHit{
+					{TaskID: 9, Score: 1, Title: "<mark>milk</mark>"},
+				})
+				mtr.EXPECT().GetTasks(byIDs(9)).Return([]models.Task{}, nil)
+				mtr.EXPECT().GetTasks(byParentIDs(9)).Return([]models.Task{}, nil)
+			},
+			want: []models.SearchResultDto{},
+		},
+		{
+			name:  "no hits",
+			query: "tea",
+			setup: func(mtr *mocks.MockTaskRepo, mti *mocks.MockTaskIndex) {
+				mti.EXPECT().Indexed(int64(1234)).Return(true)
+				mti.EXPECT().Search(int64(1234), "tea", 20).Return([]models.SearchHit{})
+			},
+			want: []models.SearchResultDto{},
+		},
+		{
+			name:   "empty query",
+			query:  "   ",
+			setup:  func(mtr *mocks.MockTaskRepo, mti *mocks.MockTaskIndex) {},
+			appErr: &errr.AppError{Code: http.StatusBadRequest, Message: "Invalid q, search for 1 to 200 characters"},
+		},
+		{
+			name:   "limit too large",
+			query:  "milk",
+			limit:  "101",
+			setup:  func(mtr *mocks.MockTaskRepo, mti *mocks.MockTaskIndex) {},
+			appErr: &errr.AppError{Code: http.StatusBadRequest, Message: "Invalid limit, use 1 to 100"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mtr := mocks.NewMockTaskRepo(ctrl)
+			mti := mocks.NewMockTaskIndex(ctrl)
+			tt.setup(mtr, mti)
+			ts := NewTaskService(mtr, mocks.NewMockLabelRepo(ctrl), mocks.NewMockProjectRepo(ctrl), defaultWorkflowRepo(ctrl), mti)
+
+			got, appErr := ts.SearchTasks(tt.query, tt.limit, models.Claims{ID: 1234})
+			if tt.appErr != nil {
+				if appErr == nil || !reflect.DeepEqual(appErr, tt.appErr) {
+					t.Errorf("SearchTasks() err = %v, want %v", appErr, tt.appErr)
+				}
+				return
+			}
+			if appErr != nil {
+				t.Fatalf("SearchTasks() failed, got err: %v.", appErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SearchTasks() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_taskService_RebuildIndex(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tasks := []models.Task{
+		{ID: 1, UserID: 1, Title: "one"},
+		{ID: 2, UserID: 2, Title: "two"},
+		{ID: 3, UserID: 1, Title: "three"},
+	}
+	mtr := mocks.NewMockTaskRepo(ctrl)
+	mtr.EXPECT().GetAllTasks().Return(tasks, nil)
+	mti := mocks.NewMockTaskIndex(ctrl)
+	mti.EXPECT().Generation(gomock.Any()).Return(uint64(0)).Times(2)
+	mti.EXPECT().Replace(int64(1), []models.Task{tasks[0], tasks[2]}, uint64(0)).Return(true)
+	mti.EXPECT().Replace(int64(2), []models.Task{tasks[1]}, uint64(0)).Return(true)
+	ts := NewTaskService(mtr, mocks.NewMockLabelRepo(ctrl), mocks.NewMockProjectRepo(ctrl), defaultWorkflowRepo(ctrl), mti)
+
+	if appErr := ts.RebuildIndex(); appErr != nil {
+		t.Errorf("RebuildIndex() failed, got err: %v.", appErr)
+	}
+}
+
+func Test_taskService_Batch(t *testing.T) {
+	claims := models.Claims{ID: 1234}
+	operations := []models.TaskOperationDto{
+		{Op: models.BatchCreate, Task: &models.TaskRequestDto{Title: "title", Desc: "desc", Status: "Pending"}},
+		{Op: models.BatchCreate, Task: &models.TaskRequestDto{Desc: "desc", Status: "Pending"}},
+		{Op: models.BatchDelete, ID: "7"},
+	}
+
+	t.Run("atomic batch fails at the first failing operation", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mtr := mocks.NewMockTaskRepo(ctrl)
+		// The create runs in a transaction of its own within the batch.
+		inTransaction(mtr)
+		inTransaction(mtr)
+		mtr.EXPECT().SaveTask(gomock.Any()).DoAndReturn(storesTask(99))
+		ts := NewTaskService(mtr, mocks.NewMockLabelRepo(ctrl), mocks.NewMockProjectRepo(ctrl), defaultWorkflowRepo(ctrl), changingIndex(ctrl))
+		ts.now = func() time.Time { return testNow }
+
+		results, appErr := ts.Batch(models.TaskBatchDto{Operations: operations}, claims)
+		want := errr.NewValidationError("Operation 1: Title is required", []errr.FieldError{
+			{Field: "operations[1].task.title", Reason: models.RuleRequired, Message: "Title is required"},
+		})
+		if results != nil || !reflect.DeepEqual(appErr, want) {
+			t.Errorf("Batch() = %v, %v, want %v", results, appErr, want)
+		}
+	})
+
+	t.Run("per item batch reports every operation", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mtr := mocks.NewMockTaskRepo(ctrl)
+		// The create runs in a transaction of its own within the batch.
+		inTransaction(mtr)
+		inTransaction(mtr)
+		mtr.EXPECT().SaveTask(gomock.Any()).DoAndReturn(storesTask(99))
+		mtr.EXPECT().DeleteTask(int64(7), int64(1234), int64(0), gomock.Any()).Return(errr.NewNotFoundError("Task not found"))
+		ts := NewTaskService(mtr, mocks.NewMockLabelRepo(ctrl), mocks.NewMockProjectRepo(ctrl), defaultWorkflowRepo(ctrl), changingIndex(ctrl))
+		ts.now = func() time.Time { return testNow }
+
+		results, appErr := ts.Batch(models.TaskBatchDto{Mode: models.BatchPerItem, Operations: operations}, claims)
+		if appErr != nil || len(results) != 3 {
+			t.Fatalf("Batch() = %v, %v, want 3 results", results, appErr)
+		}
+		if results[0].Err != nil || results[0].Task == nil || results[0].Task.ID != "99" {
+			t.Errorf("result 0 = %+v, want task 99", results[0])
+		}
+		if results[1].Err == nil || results[1].Err.Code != http.StatusBadRequest {
+			t.Errorf("result 1 = %+v, want a validation error", results[1])
+		}
+		if results[2].Err == nil || results[2].Err.Code != http.StatusNotFound {
+			t.Errorf("result 2 = %+v, want not found", results[2])
+		}
+	})
+
+	t.Run("unexpected error fails a per item batch", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mtr := mocks.NewMockTaskRepo(ctrl)
+		inTransaction(mtr)
+		mtr.EXPECT().DeleteTask(int64(7), int64(1234), int64(0), gomock.Any()).Return(errr.NewUnexpectedError("Unable to delete task"))
+		ts := NewTaskService(mtr, mocks.NewMockLabelRepo(ctrl), mocks.NewMockProjectRepo(ctrl), defaultWorkflowRepo(ctrl), changingIndex(ctrl))
+
+		batch := models.TaskBatchDto{Mode: models.BatchPerItem, Operations: operations[2:]}
+		_, appErr := ts.Batch(batch, claims)
+		if appErr == nil || appErr.Code != http.StatusInternalServerError || appErr.Message != "Operation 0: Unable to delete task" {
+			t.Errorf("Batch() err = %v, want the unexpected error of operation 0", appErr)
+		}
+	})
+
+	t.Run("invalid batch runs nothing", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ts := NewTaskService(mocks.NewMockTaskRepo(ctrl), mocks.NewMockLabelRepo(ctrl), mocks.NewMockProjectRepo(ctrl), defaultWorkflowRepo(ctrl), changingIndex(ctrl))
+
+		_, appErr := ts.Batch(models.TaskBatchDto{Operations: []models.TaskOperationDto{{Op: models.BatchDelete}}}, claims)
+		want := errr.NewValidationError("Task id is required", []errr.FieldError{
+			{Field: "operations[0].id", Reason: models.RuleRequired, Message: "Task id is required"},
+		})
+		if !reflect.DeepEqual(appErr, want) {
+			t.Errorf("Batch() err = %v, want %v", appErr, want)
+		}
+	})
+}
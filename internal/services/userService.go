@@ -18,22 +18,24 @@ type userService struct {
 	passwordHasher ports.PasswordHasher
 }
 
-func (as *userService) CreateUser(userReq models.UserRequestDto) *errr.AppError {
+func (as *userService) CreateUser(
+	userReq models.UserRequestDto,
+) (models.UserResponseDto, *errr.AppError) {
 	user := userReq.ToUser()
 
-	if !user.IsValidUser() {
-		return errr.NewBadRequestError("Invalid user data")
+	if violations := user.Validate(); len(violations) > 0 {
+		return models.UserResponseDto{}, invalidRequest("Invalid user data", violations)
 	}
 	hash, err := as.passwordHasher.Hash(user.Password)
 	if err != nil {
-		return errr.NewUnexpectedError(err.Error())
+		return models.UserResponseDto{}, errr.NewUnexpectedError(err.Error())
 	}
 	user.Password = hash
 
-	appErr := as.userRepo.CreateUser(user)
+	user, appErr := as.userRepo.CreateUser(user)
 	if appErr != nil {
-		return appErr
+		return models.UserResponseDto{}, appErr
 	}
 
-	return nil
+	return user.ToDto(), nil
 }
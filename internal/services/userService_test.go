@@ -3,6 +3,7 @@ package services
 import (
 	"errors"
 	"net/http"
+	"reflect"
 	"testing"
 
 	"github.com/Jashanveer-Singh/todo-go/internal/errr"
@@ -19,6 +20,7 @@ func Test_userService_CreateUser(t *testing.T) {
 		setupUserRepo       func(mur *mocks.MockUserRepo)
 		setupPasswordHasher func(mph *mocks.MockPasswordHasher)
 		// Named input parameters for target function.
+		want       models.UserResponseDto
 		wantAppErr *errr.AppError
 	}{
 		{
@@ -29,10 +31,10 @@ func Test_userService_CreateUser(t *testing.T) {
 			},
 			setupUserRepo:       func(mur *mocks.MockUserRepo) {},
 			setupPasswordHasher: func(mph *mocks.MockPasswordHasher) {},
-			wantAppErr: &errr.AppError{
-				Code:    http.StatusBadRequest,
-				Message: "Invalid user data",
-			},
+			wantAppErr: errr.NewValidationError("Invalid user data", []errr.FieldError{
+				{Field: "username", Reason: models.RuleRequired, Message: "Username is required"},
+				{Field: "password", Reason: models.RuleRequired, Message: "Password is required"},
+			}),
 		},
 		{
 			name: "password too short",
@@ -42,10 +44,13 @@ func Test_userService_CreateUser(t *testing.T) {
 			},
 			setupUserRepo:       func(mur *mocks.MockUserRepo) {},
 			setupPasswordHasher: func(mph *mocks.MockPasswordHasher) {},
-			wantAppErr: &errr.AppError{
-				Code:    http.StatusBadRequest,
-				Message: "Invalid user data",
-			},
+			wantAppErr: errr.NewValidationError("Password must be at least 8 characters", []errr.FieldError{
+				{
+					Field:   "password",
+					Reason:  models.RulePasswordPolicy,
+					Message: "Password must be at least 8 characters",
+				},
+			}),
 		},
 		{
 			name: "password hasher failed to create hash",
@@ -75,7 +80,8 @@ func Test_userService_CreateUser(t *testing.T) {
 					ID:       0,
 					Username: "user",
 					Password: "hashed password",
-				}).Return(&errr.AppError{
+					Role:     models.RoleUser,
+				}).Return(models.User{}, &errr.AppError{
 					Code:    0,
 					Message: "error message from user repo",
 				})
@@ -99,11 +105,18 @@ func Test_userService_CreateUser(t *testing.T) {
 					ID:       0,
 					Username: "user",
 					Password: "hashed password",
-				}).Return(nil)
+					Role:     models.RoleUser,
+				}).Return(models.User{
+					ID:       77,
+					Username: "user",
+					Password: "hashed password",
+					Role:     models.RoleUser,
+				}, nil)
 			},
 			setupPasswordHasher: func(mph *mocks.MockPasswordHasher) {
 				mph.EXPECT().Hash("password").Return("hashed password", nil)
 			},
+			want:       models.UserResponseDto{ID: "77", Username: "user", Role: models.RoleUser},
 			wantAppErr: nil,
 		},
 	}
@@ -120,8 +133,7 @@ func Test_userService_CreateUser(t *testing.T) {
 			tt.setupPasswordHasher(passwordHasher)
 
 			as := NewUserService(userRepo, passwordHasher)
-			gotAppErr := as.CreateUser(tt.userReq)
-			// TODO: update the condition below to compare got with tt.want.
+			got, gotAppErr := as.CreateUser(tt.userReq)
 			if tt.wantAppErr == nil && gotAppErr != nil {
 				t.Errorf("CreateUser() failed. got appErr: %v", gotAppErr)
 				return
@@ -131,9 +143,12 @@ func Test_userService_CreateUser(t *testing.T) {
 				return
 			}
 
-			if tt.wantAppErr != nil && *gotAppErr != *tt.wantAppErr {
+			if tt.wantAppErr != nil && !reflect.DeepEqual(gotAppErr, tt.wantAppErr) {
 				t.Errorf("wanted appErr: %v, got: %v", tt.wantAppErr, gotAppErr)
 			}
+			if got != tt.want {
+				t.Errorf("CreateUser() = %v, want %v", got, tt.want)
+			}
 		})
 	}
 }
@@ -0,0 +1,20 @@
+package services
+
+import (
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+)
+
+// invalidRequest reports every violation of a request, with the message of
+// the only one as the detail or summary when there are several.
+func invalidRequest(summary string, violations models.Violations) *errr.AppError {
+	fields := make([]errr.FieldError, 0, len(violations))
+	for _, v := range violations {
+		fields = append(fields, errr.FieldError{
+			Field:   v.Field,
+			Reason:  v.Rule,
+			Message: v.Message,
+		})
+	}
+	return errr.NewValidationError(violations.Message(summary), fields)
+}
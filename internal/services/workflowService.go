@@ -0,0 +1,55 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/internal/ports"
+)
+
+type workflowService struct {
+	workflowRepo ports.WorkflowRepo
+}
+
+func NewWorkflowService(workflowRepo ports.WorkflowRepo) *workflowService {
+	return &workflowService{
+		workflowRepo: workflowRepo,
+	}
+}
+
+func (ws *workflowService) GetWorkflow(claims models.Claims) (models.WorkflowDto, *errr.AppError) {
+	workflow, appErr := ws.workflowRepo.GetWorkflow(claims.ID)
+	if appErr != nil {
+		return models.WorkflowDto{}, appErr
+	}
+
+	return workflow.ToDto(), nil
+}
+
+// UpdateWorkflow replaces the workflow of the user. Statuses still used by
+// tasks can't be removed or change whether they are terminal, as that would
+// strand the tasks or leave their done flag stale.
+func (ws *workflowService) UpdateWorkflow(
+	workflowReq models.WorkflowDto,
+	claims models.Claims,
+) *errr.AppError {
+	stored, appErr := ws.workflowRepo.GetWorkflow(claims.ID)
+	if appErr != nil {
+		return appErr
+	}
+
+	workflow, err := workflowReq.ToWorkflow(stored)
+	if errors.Is(err, models.ErrUnknownStatus) {
+		return errr.NewBadRequestError("Unknown status in next")
+	}
+	if err != nil || !workflow.IsValid() {
+		return errr.NewBadRequestError(
+			"Invalid workflow, statuses need unique names of up to 30 characters and one must be terminal",
+		)
+	}
+
+	// The repo refuses to remove statuses still used by tasks, those in the
+	// trash too as they would be restored to a status the workflow lacks.
+	return ws.workflowRepo.SaveWorkflow(workflow)
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/Jashanveer-Singh/todo-go/internal/errr"
+	"github.com/Jashanveer-Singh/todo-go/internal/models"
+	"github.com/Jashanveer-Singh/todo-go/test/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+func Test_workflowService_UpdateWorkflow(t *testing.T) {
+	review := models.WorkflowDto{Statuses: []models.WorkflowStatusDto{
+		{Name: "Waiting", Next: []string{"Review"}},
+		{Name: "Review"},
+		{Name: "Done", Terminal: true},
+	}}
+
+	tests := []struct {
+		name              string
+		setupWorkflowRepo func(mwr *mocks.MockWorkflowRepo)
+		workflowReq       models.WorkflowDto
+		appErr            *errr.AppError
+	}{
+		{
+			name: "replaces workflow keeping ids of unchanged statuses",
+			setupWorkflowRepo: func(mwr *mocks.MockWorkflowRepo) {
+				mwr.EXPECT().SaveWorkflow(models.Workflow{
+					UserID: 1234,
+					Statuses: []models.WorkflowStatus{
+						{ID: models.StatusWaiting, Name: "Waiting", Next: []int{3}},
+						{ID: 3, Name: "Review"},
+						{ID: models.StatusDone, Name: "Done", Terminal: true},
+					},
+				}).Return(nil)
+			},
+			workflowReq: review,
+		},
+		{
+			name: "status still used by tasks",
+			setupWorkflowRepo: func(mwr *mocks.MockWorkflowRepo) {
+				mwr.EXPECT().SaveWorkflow(gomock.Any()).
+					Return(errr.NewDuplicateError("Status Pending is still used by tasks"))
+			},
+			workflowReq: review,
+			appErr:      errr.NewDuplicateError("Status Pending is still used by tasks"),
+		},
+		{
+			name:              "transition to an unknown status",
+			setupWorkflowRepo: func(mwr *mocks.MockWorkflowRepo) {},
+			workflowReq: models.WorkflowDto{Statuses: []models.WorkflowStatusDto{
+				{Name: "Open", Next: []string{"Closed"}},
+				{Name: "Done", Terminal: true},
+			}},
+			appErr: errr.NewBadRequestError("Unknown status in next"),
+		},
+		{
+			name:              "no terminal status",
+			setupWorkflowRepo: func(mwr *mocks.MockWorkflowRepo) {},
+			workflowReq: models.WorkflowDto{Statuses: []models.WorkflowStatusDto{
+				{Name: "Open"},
+			}},
+			appErr: &errr.AppError{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid workflow, statuses need unique names of up to 30 characters and one must be terminal",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mwr := mocks.NewMockWorkflowRepo(ctrl)
+			mwr.EXPECT().GetWorkflow(int64(1234)).Return(models.DefaultWorkflow(1234), nil)
+			tt.setupWorkflowRepo(mwr)
+			ws := NewWorkflowService(mwr)
+
+			appErr := ws.UpdateWorkflow(tt.workflowReq, models.Claims{ID: 1234})
+			if tt.appErr == nil && appErr != nil {
+				t.Errorf("UpdateWorkflow() failed, got err: %v.", appErr)
+				return
+			}
+			if tt.appErr != nil && (appErr == nil || !reflect.DeepEqual(tt.appErr, appErr)) {
+				t.Errorf("UpdateWorkflow() = %v, want %v", appErr, tt.appErr)
+			}
+		})
+	}
+}
@@ -6,9 +6,11 @@ package mocks
 
 import (
 	reflect "reflect"
+	time "time"
 
 	errr "github.com/Jashanveer-Singh/todo-go/internal/errr"
 	models "github.com/Jashanveer-Singh/todo-go/internal/models"
+	ports "github.com/Jashanveer-Singh/todo-go/internal/ports"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -36,48 +38,151 @@ func (m *MockTaskRepo) EXPECT() *MockTaskRepoMockRecorder {
 }
 
 // DeleteTask mocks base method.
-func (m *MockTaskRepo) DeleteTask(id, userID int64) *errr.AppError {
+func (m *MockTaskRepo) DeleteTask(id, userID, version int64, deletedAt time.Time) *errr.AppError {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteTask", id, userID)
+	ret := m.ctrl.Call(m, "DeleteTask", id, userID, version, deletedAt)
 	ret0, _ := ret[0].(*errr.AppError)
 	return ret0
 }
 
 // DeleteTask indicates an expected call of DeleteTask.
-func (mr *MockTaskRepoMockRecorder) DeleteTask(id, userID interface{}) *gomock.Call {
+func (mr *MockTaskRepoMockRecorder) DeleteTask(id, userID, version, deletedAt interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTask", reflect.TypeOf((*MockTaskRepo)(nil).DeleteTask), id, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTask", reflect.TypeOf((*MockTaskRepo)(nil).DeleteTask), id, userID, version, deletedAt)
+}
+
+// GetAllTasks mocks base method.
+func (m *MockTaskRepo) GetAllTasks() ([]models.Task, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllTasks")
+	ret0, _ := ret[0].([]models.Task)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// GetAllTasks indicates an expected call of GetAllTasks.
+func (mr *MockTaskRepoMockRecorder) GetAllTasks() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllTasks", reflect.TypeOf((*MockTaskRepo)(nil).GetAllTasks))
+}
+
+// GetTask mocks base method.
+func (m *MockTaskRepo) GetTask(id, userID int64) (models.Task, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTask", id, userID)
+	ret0, _ := ret[0].(models.Task)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// GetTask indicates an expected call of GetTask.
+func (mr *MockTaskRepoMockRecorder) GetTask(id, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTask", reflect.TypeOf((*MockTaskRepo)(nil).GetTask), id, userID)
 }
 
 // GetTasks mocks base method.
-func (m *MockTaskRepo) GetTasks(userId int64) ([]models.Task, *errr.AppError) {
+func (m *MockTaskRepo) GetTasks(query models.TaskQuery) ([]models.Task, *errr.AppError) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTasks", userId)
+	ret := m.ctrl.Call(m, "GetTasks", query)
 	ret0, _ := ret[0].([]models.Task)
 	ret1, _ := ret[1].(*errr.AppError)
 	return ret0, ret1
 }
 
 // GetTasks indicates an expected call of GetTasks.
-func (mr *MockTaskRepoMockRecorder) GetTasks(userId interface{}) *gomock.Call {
+func (mr *MockTaskRepoMockRecorder) GetTasks(query interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTasks", reflect.TypeOf((*MockTaskRepo)(nil).GetTasks), userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTasks", reflect.TypeOf((*MockTaskRepo)(nil).GetTasks), query)
 }
 
-// SaveTask mocks base method.
-func (m *MockTaskRepo) SaveTask(task models.Task) *errr.AppError {
+// GetTrash mocks base method.
+func (m *MockTaskRepo) GetTrash(userID int64) ([]models.Task, *errr.AppError) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveTask", task)
+	ret := m.ctrl.Call(m, "GetTrash", userID)
+	ret0, _ := ret[0].([]models.Task)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// GetTrash indicates an expected call of GetTrash.
+func (mr *MockTaskRepoMockRecorder) GetTrash(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrash", reflect.TypeOf((*MockTaskRepo)(nil).GetTrash), userID)
+}
+
+// PurgeTask mocks base method.
+func (m *MockTaskRepo) PurgeTask(id, userID int64) *errr.AppError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTask", id, userID)
+	ret0, _ := ret[0].(*errr.AppError)
+	return ret0
+}
+
+// PurgeTask indicates an expected call of PurgeTask.
+func (mr *MockTaskRepoMockRecorder) PurgeTask(id, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTask", reflect.TypeOf((*MockTaskRepo)(nil).PurgeTask), id, userID)
+}
+
+// PurgeTrash mocks base method.
+func (m *MockTaskRepo) PurgeTrash(deletedBefore time.Time) (int, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTrash", deletedBefore)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// PurgeTrash indicates an expected call of PurgeTrash.
+func (mr *MockTaskRepoMockRecorder) PurgeTrash(deletedBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTrash", reflect.TypeOf((*MockTaskRepo)(nil).PurgeTrash), deletedBefore)
+}
+
+// RestoreTask mocks base method.
+func (m *MockTaskRepo) RestoreTask(id, userID int64) *errr.AppError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreTask", id, userID)
 	ret0, _ := ret[0].(*errr.AppError)
 	return ret0
 }
 
+// RestoreTask indicates an expected call of RestoreTask.
+func (mr *MockTaskRepoMockRecorder) RestoreTask(id, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreTask", reflect.TypeOf((*MockTaskRepo)(nil).RestoreTask), id, userID)
+}
+
+// SaveTask mocks base method.
+func (m *MockTaskRepo) SaveTask(task models.Task) (models.Task, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveTask", task)
+	ret0, _ := ret[0].(models.Task)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
 // SaveTask indicates an expected call of SaveTask.
 func (mr *MockTaskRepoMockRecorder) SaveTask(task interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTask", reflect.TypeOf((*MockTaskRepo)(nil).SaveTask), task)
 }
 
+// Transaction mocks base method.
+func (m *MockTaskRepo) Transaction(fn func(ports.TaskRepo) *errr.AppError) *errr.AppError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transaction", fn)
+	ret0, _ := ret[0].(*errr.AppError)
+	return ret0
+}
+
+// Transaction indicates an expected call of Transaction.
+func (mr *MockTaskRepoMockRecorder) Transaction(fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transaction", reflect.TypeOf((*MockTaskRepo)(nil).Transaction), fn)
+}
+
 // UpdateTask mocks base method.
 func (m *MockTaskRepo) UpdateTask(id int64, task models.Task) *errr.AppError {
 	m.ctrl.T.Helper()
@@ -92,6 +197,235 @@ func (mr *MockTaskRepoMockRecorder) UpdateTask(id, task interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTask", reflect.TypeOf((*MockTaskRepo)(nil).UpdateTask), id, task)
 }
 
+// MockLabelRepo is a mock of LabelRepo interface.
+type MockLabelRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockLabelRepoMockRecorder
+}
+
+// MockLabelRepoMockRecorder is the mock recorder for MockLabelRepo.
+type MockLabelRepoMockRecorder struct {
+	mock *MockLabelRepo
+}
+
+// NewMockLabelRepo creates a new mock instance.
+func NewMockLabelRepo(ctrl *gomock.Controller) *MockLabelRepo {
+	mock := &MockLabelRepo{ctrl: ctrl}
+	mock.recorder = &MockLabelRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLabelRepo) EXPECT() *MockLabelRepoMockRecorder {
+	return m.recorder
+}
+
+// DeleteLabel mocks base method.
+func (m *MockLabelRepo) DeleteLabel(id, userID int64) *errr.AppError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLabel", id, userID)
+	ret0, _ := ret[0].(*errr.AppError)
+	return ret0
+}
+
+// DeleteLabel indicates an expected call of DeleteLabel.
+func (mr *MockLabelRepoMockRecorder) DeleteLabel(id, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLabel", reflect.TypeOf((*MockLabelRepo)(nil).DeleteLabel), id, userID)
+}
+
+// GetLabels mocks base method.
+func (m *MockLabelRepo) GetLabels(userID int64) ([]models.Label, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLabels", userID)
+	ret0, _ := ret[0].([]models.Label)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// GetLabels indicates an expected call of GetLabels.
+func (mr *MockLabelRepoMockRecorder) GetLabels(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLabels", reflect.TypeOf((*MockLabelRepo)(nil).GetLabels), userID)
+}
+
+// SaveLabel mocks base method.
+func (m *MockLabelRepo) SaveLabel(label models.Label) (models.Label, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveLabel", label)
+	ret0, _ := ret[0].(models.Label)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// SaveLabel indicates an expected call of SaveLabel.
+func (mr *MockLabelRepoMockRecorder) SaveLabel(label interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveLabel", reflect.TypeOf((*MockLabelRepo)(nil).SaveLabel), label)
+}
+
+// UpdateLabel mocks base method.
+func (m *MockLabelRepo) UpdateLabel(id int64, label models.Label) *errr.AppError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLabel", id, label)
+	ret0, _ := ret[0].(*errr.AppError)
+	return ret0
+}
+
+// UpdateLabel indicates an expected call of UpdateLabel.
+func (mr *MockLabelRepoMockRecorder) UpdateLabel(id, label interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLabel", reflect.TypeOf((*MockLabelRepo)(nil).UpdateLabel), id, label)
+}
+
+// MockProjectRepo is a mock of ProjectRepo interface.
+type MockProjectRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockProjectRepoMockRecorder
+}
+
+// MockProjectRepoMockRecorder is the mock recorder for MockProjectRepo.
+type MockProjectRepoMockRecorder struct {
+	mock *MockProjectRepo
+}
+
+// NewMockProjectRepo creates a new mock instance.
+func NewMockProjectRepo(ctrl *gomock.Controller) *MockProjectRepo {
+	mock := &MockProjectRepo{ctrl: ctrl}
+	mock.recorder = &MockProjectRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProjectRepo) EXPECT() *MockProjectRepoMockRecorder {
+	return m.recorder
+}
+
+// DeleteProject mocks base method.
+func (m *MockProjectRepo) DeleteProject(id, userID int64) *errr.AppError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteProject", id, userID)
+	ret0, _ := ret[0].(*errr.AppError)
+	return ret0
+}
+
+// DeleteProject indicates an expected call of DeleteProject.
+func (mr *MockProjectRepoMockRecorder) DeleteProject(id, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProject", reflect.TypeOf((*MockProjectRepo)(nil).DeleteProject), id, userID)
+}
+
+// GetProject mocks base method.
+func (m *MockProjectRepo) GetProject(id, userID int64) (models.Project, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProject", id, userID)
+	ret0, _ := ret[0].(models.Project)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// GetProject indicates an expected call of GetProject.
+func (mr *MockProjectRepoMockRecorder) GetProject(id, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProject", reflect.TypeOf((*MockProjectRepo)(nil).GetProject), id, userID)
+}
+
+// GetProjects mocks base method.
+func (m *MockProjectRepo) GetProjects(userID int64) ([]models.Project, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProjects", userID)
+	ret0, _ := ret[0].([]models.Project)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// GetProjects indicates an expected call of GetProjects.
+func (mr *MockProjectRepoMockRecorder) GetProjects(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjects", reflect.TypeOf((*MockProjectRepo)(nil).GetProjects), userID)
+}
+
+// SaveProject mocks base method.
+func (m *MockProjectRepo) SaveProject(project models.Project) (models.Project, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveProject", project)
+	ret0, _ := ret[0].(models.Project)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// SaveProject indicates an expected call of SaveProject.
+func (mr *MockProjectRepoMockRecorder) SaveProject(project interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveProject", reflect.TypeOf((*MockProjectRepo)(nil).SaveProject), project)
+}
+
+// UpdateProject mocks base method.
+func (m *MockProjectRepo) UpdateProject(id int64, project models.Project) *errr.AppError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProject", id, project)
+	ret0, _ := ret[0].(*errr.AppError)
+	return ret0
+}
+
+// UpdateProject indicates an expected call of UpdateProject.
+func (mr *MockProjectRepoMockRecorder) UpdateProject(id, project interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProject", reflect.TypeOf((*MockProjectRepo)(nil).UpdateProject), id, project)
+}
+
+// MockWorkflowRepo is a mock of WorkflowRepo interface.
+type MockWorkflowRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockWorkflowRepoMockRecorder
+}
+
+// MockWorkflowRepoMockRecorder is the mock recorder for MockWorkflowRepo.
+type MockWorkflowRepoMockRecorder struct {
+	mock *MockWorkflowRepo
+}
+
+// NewMockWorkflowRepo creates a new mock instance.
+func NewMockWorkflowRepo(ctrl *gomock.Controller) *MockWorkflowRepo {
+	mock := &MockWorkflowRepo{ctrl: ctrl}
+	mock.recorder = &MockWorkflowRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWorkflowRepo) EXPECT() *MockWorkflowRepoMockRecorder {
+	return m.recorder
+}
+
+// GetWorkflow mocks base method.
+func (m *MockWorkflowRepo) GetWorkflow(userID int64) (models.Workflow, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkflow", userID)
+	ret0, _ := ret[0].(models.Workflow)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// GetWorkflow indicates an expected call of GetWorkflow.
+func (mr *MockWorkflowRepoMockRecorder) GetWorkflow(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkflow", reflect.TypeOf((*MockWorkflowRepo)(nil).GetWorkflow), userID)
+}
+
+// SaveWorkflow mocks base method.
+func (m *MockWorkflowRepo) SaveWorkflow(workflow models.Workflow) *errr.AppError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveWorkflow", workflow)
+	ret0, _ := ret[0].(*errr.AppError)
+	return ret0
+}
+
+// SaveWorkflow indicates an expected call of SaveWorkflow.
+func (mr *MockWorkflowRepoMockRecorder) SaveWorkflow(workflow interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveWorkflow", reflect.TypeOf((*MockWorkflowRepo)(nil).SaveWorkflow), workflow)
+}
+
 // MockUserRepo is a mock of UserRepo interface.
 type MockUserRepo struct {
 	ctrl     *gomock.Controller
@@ -116,11 +450,12 @@ func (m *MockUserRepo) EXPECT() *MockUserRepoMockRecorder {
 }
 
 // CreateUser mocks base method.
-func (m *MockUserRepo) CreateUser(user models.User) *errr.AppError {
+func (m *MockUserRepo) CreateUser(user models.User) (models.User, *errr.AppError) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateUser", user)
-	ret0, _ := ret[0].(*errr.AppError)
-	return ret0
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
 }
 
 // CreateUser indicates an expected call of CreateUser.
@@ -129,6 +464,21 @@ func (mr *MockUserRepoMockRecorder) CreateUser(user interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockUserRepo)(nil).CreateUser), user)
 }
 
+// GetUser mocks base method.
+func (m *MockUserRepo) GetUser(id int64) (models.User, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", id)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockUserRepoMockRecorder) GetUser(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockUserRepo)(nil).GetUser), id)
+}
+
 // GetUserByUsername mocks base method.
 func (m *MockUserRepo) GetUserByUsername(username string) (models.User, *errr.AppError) {
 	m.ctrl.T.Helper()
@@ -143,3 +493,355 @@ func (mr *MockUserRepoMockRecorder) GetUserByUsername(username interface{}) *gom
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByUsername", reflect.TypeOf((*MockUserRepo)(nil).GetUserByUsername), username)
 }
+
+// GetUsers mocks base method.
+func (m *MockUserRepo) GetUsers() ([]models.User, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsers")
+	ret0, _ := ret[0].([]models.User)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// GetUsers indicates an expected call of GetUsers.
+func (mr *MockUserRepoMockRecorder) GetUsers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsers", reflect.TypeOf((*MockUserRepo)(nil).GetUsers))
+}
+
+// UpdateUser mocks base method.
+func (m *MockUserRepo) UpdateUser(user models.User) *errr.AppError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUser", user)
+	ret0, _ := ret[0].(*errr.AppError)
+	return ret0
+}
+
+// UpdateUser indicates an expected call of UpdateUser.
+func (mr *MockUserRepoMockRecorder) UpdateUser(user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockUserRepo)(nil).UpdateUser), user)
+}
+
+// MockAuditRepo is a mock of AuditRepo interface.
+type MockAuditRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditRepoMockRecorder
+}
+
+// MockAuditRepoMockRecorder is the mock recorder for MockAuditRepo.
+type MockAuditRepoMockRecorder struct {
+	mock *MockAuditRepo
+}
+
+// NewMockAuditRepo creates a new mock instance.
+func NewMockAuditRepo(ctrl *gomock.Controller) *MockAuditRepo {
+	mock := &MockAuditRepo{ctrl: ctrl}
+	mock.recorder = &MockAuditRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditRepo) EXPECT() *MockAuditRepoMockRecorder {
+	return m.recorder
+}
+
+// GetAuditEntries mocks base method.
+func (m *MockAuditRepo) GetAuditEntries() ([]models.AuditEntry, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditEntries")
+	ret0, _ := ret[0].([]models.AuditEntry)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// GetAuditEntries indicates an expected call of GetAuditEntries.
+func (mr *MockAuditRepoMockRecorder) GetAuditEntries() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditEntries", reflect.TypeOf((*MockAuditRepo)(nil).GetAuditEntries))
+}
+
+// SaveAuditEntry mocks base method.
+func (m *MockAuditRepo) SaveAuditEntry(entry models.AuditEntry) *errr.AppError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveAuditEntry", entry)
+	ret0, _ := ret[0].(*errr.AppError)
+	return ret0
+}
+
+// SaveAuditEntry indicates an expected call of SaveAuditEntry.
+func (mr *MockAuditRepoMockRecorder) SaveAuditEntry(entry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAuditEntry", reflect.TypeOf((*MockAuditRepo)(nil).SaveAuditEntry), entry)
+}
+
+// MockRefreshTokenRepo is a mock of RefreshTokenRepo interface.
+type MockRefreshTokenRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockRefreshTokenRepoMockRecorder
+}
+
+// MockRefreshTokenRepoMockRecorder is the mock recorder for MockRefreshTokenRepo.
+type MockRefreshTokenRepoMockRecorder struct {
+	mock *MockRefreshTokenRepo
+}
+
+// NewMockRefreshTokenRepo creates a new mock instance.
+func NewMockRefreshTokenRepo(ctrl *gomock.Controller) *MockRefreshTokenRepo {
+	mock := &MockRefreshTokenRepo{ctrl: ctrl}
+	mock.recorder = &MockRefreshTokenRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRefreshTokenRepo) EXPECT() *MockRefreshTokenRepoMockRecorder {
+	return m.recorder
+}
+
+// GetRefreshToken mocks base method.
+func (m *MockRefreshTokenRepo) GetRefreshToken(hash string) (models.RefreshToken, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefreshToken", hash)
+	ret0, _ := ret[0].(models.RefreshToken)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// GetRefreshToken indicates an expected call of GetRefreshToken.
+func (mr *MockRefreshTokenRepoMockRecorder) GetRefreshToken(hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefreshToken", reflect.TypeOf((*MockRefreshTokenRepo)(nil).GetRefreshToken), hash)
+}
+
+// RevokeTokenFamily mocks base method.
+func (m *MockRefreshTokenRepo) RevokeTokenFamily(familyID string) *errr.AppError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeTokenFamily", familyID)
+	ret0, _ := ret[0].(*errr.AppError)
+	return ret0
+}
+
+// RevokeTokenFamily indicates an expected call of RevokeTokenFamily.
+func (mr *MockRefreshTokenRepoMockRecorder) RevokeTokenFamily(familyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeTokenFamily", reflect.TypeOf((*MockRefreshTokenRepo)(nil).RevokeTokenFamily), familyID)
+}
+
+// RevokeUserTokens mocks base method.
+func (m *MockRefreshTokenRepo) RevokeUserTokens(userID int64) *errr.AppError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUserTokens", userID)
+	ret0, _ := ret[0].(*errr.AppError)
+	return ret0
+}
+
+// RevokeUserTokens indicates an expected call of RevokeUserTokens.
+func (mr *MockRefreshTokenRepoMockRecorder) RevokeUserTokens(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserTokens", reflect.TypeOf((*MockRefreshTokenRepo)(nil).RevokeUserTokens), userID)
+}
+
+// SaveRefreshToken mocks base method.
+func (m *MockRefreshTokenRepo) SaveRefreshToken(token models.RefreshToken) *errr.AppError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveRefreshToken", token)
+	ret0, _ := ret[0].(*errr.AppError)
+	return ret0
+}
+
+// SaveRefreshToken indicates an expected call of SaveRefreshToken.
+func (mr *MockRefreshTokenRepoMockRecorder) SaveRefreshToken(token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRefreshToken", reflect.TypeOf((*MockRefreshTokenRepo)(nil).SaveRefreshToken), token)
+}
+
+// UseRefreshToken mocks base method.
+func (m *MockRefreshTokenRepo) UseRefreshToken(hash string, usedAt time.Time) *errr.AppError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRefreshToken", hash, usedAt)
+	ret0, _ := ret[0].(*errr.AppError)
+	return ret0
+}
+
+// UseRefreshToken indicates an expected call of UseRefreshToken.
+func (mr *MockRefreshTokenRepoMockRecorder) UseRefreshToken(hash, usedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRefreshToken", reflect.TypeOf((*MockRefreshTokenRepo)(nil).UseRefreshToken), hash, usedAt)
+}
+
+// MockRevocationRepo is a mock of RevocationRepo interface.
+type MockRevocationRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockRevocationRepoMockRecorder
+}
+
+// MockRevocationRepoMockRecorder is the mock recorder for MockRevocationRepo.
+type MockRevocationRepoMockRecorder struct {
+	mock *MockRevocationRepo
+}
+
+// NewMockRevocationRepo creates a new mock instance.
+func NewMockRevocationRepo(ctrl *gomock.Controller) *MockRevocationRepo {
+	mock := &MockRevocationRepo{ctrl: ctrl}
+	mock.recorder = &MockRevocationRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRevocationRepo) EXPECT() *MockRevocationRepoMockRecorder {
+	return m.recorder
+}
+
+// IsRevoked mocks base method.
+func (m *MockRevocationRepo) IsRevoked(claims models.Claims) (bool, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsRevoked", claims)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// IsRevoked indicates an expected call of IsRevoked.
+func (mr *MockRevocationRepoMockRecorder) IsRevoked(claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRevoked", reflect.TypeOf((*MockRevocationRepo)(nil).IsRevoked), claims)
+}
+
+// PruneRevocations mocks base method.
+func (m *MockRevocationRepo) PruneRevocations(now time.Time) (int, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneRevocations", now)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// PruneRevocations indicates an expected call of PruneRevocations.
+func (mr *MockRevocationRepoMockRecorder) PruneRevocations(now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneRevocations", reflect.TypeOf((*MockRevocationRepo)(nil).PruneRevocations), now)
+}
+
+// SaveRevocation mocks base method.
+func (m *MockRevocationRepo) SaveRevocation(revocation models.Revocation) *errr.AppError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveRevocation", revocation)
+	ret0, _ := ret[0].(*errr.AppError)
+	return ret0
+}
+
+// SaveRevocation indicates an expected call of SaveRevocation.
+func (mr *MockRevocationRepoMockRecorder) SaveRevocation(revocation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRevocation", reflect.TypeOf((*MockRevocationRepo)(nil).SaveRevocation), revocation)
+}
+
+// MockAccessTokenRepo is a mock of AccessTokenRepo interface.
+type MockAccessTokenRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccessTokenRepoMockRecorder
+}
+
+// MockAccessTokenRepoMockRecorder is the mock recorder for MockAccessTokenRepo.
+type MockAccessTokenRepoMockRecorder struct {
+	mock *MockAccessTokenRepo
+}
+
+// NewMockAccessTokenRepo creates a new mock instance.
+func NewMockAccessTokenRepo(ctrl *gomock.Controller) *MockAccessTokenRepo {
+	mock := &MockAccessTokenRepo{ctrl: ctrl}
+	mock.recorder = &MockAccessTokenRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccessTokenRepo) EXPECT() *MockAccessTokenRepoMockRecorder {
+	return m.recorder
+}
+
+// DeleteAccessToken mocks base method.
+func (m *MockAccessTokenRepo) DeleteAccessToken(id, userID int64) *errr.AppError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccessToken", id, userID)
+	ret0, _ := ret[0].(*errr.AppError)
+	return ret0
+}
+
+// DeleteAccessToken indicates an expected call of DeleteAccessToken.
+func (mr *MockAccessTokenRepoMockRecorder) DeleteAccessToken(id, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccessToken", reflect.TypeOf((*MockAccessTokenRepo)(nil).DeleteAccessToken), id, userID)
+}
+
+// GetAccessTokenByHash mocks base method.
+func (m *MockAccessTokenRepo) GetAccessTokenByHash(hash string) (models.AccessToken, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccessTokenByHash", hash)
+	ret0, _ := ret[0].(models.AccessToken)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// GetAccessTokenByHash indicates an expected call of GetAccessTokenByHash.
+func (mr *MockAccessTokenRepoMockRecorder) GetAccessTokenByHash(hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccessTokenByHash", reflect.TypeOf((*MockAccessTokenRepo)(nil).GetAccessTokenByHash), hash)
+}
+
+// GetAccessTokens mocks base method.
+func (m *MockAccessTokenRepo) GetAccessTokens(userID int64) ([]models.AccessToken, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccessTokens", userID)
+	ret0, _ := ret[0].([]models.AccessToken)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// GetAccessTokens indicates an expected call of GetAccessTokens.
+func (mr *MockAccessTokenRepoMockRecorder) GetAccessTokens(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccessTokens", reflect.TypeOf((*MockAccessTokenRepo)(nil).GetAccessTokens), userID)
+}
+
+// RevokeUserAccessTokens mocks base method.
+func (m *MockAccessTokenRepo) RevokeUserAccessTokens(userID int64) *errr.AppError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUserAccessTokens", userID)
+	ret0, _ := ret[0].(*errr.AppError)
+	return ret0
+}
+
+// RevokeUserAccessTokens indicates an expected call of RevokeUserAccessTokens.
+func (mr *MockAccessTokenRepoMockRecorder) RevokeUserAccessTokens(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserAccessTokens", reflect.TypeOf((*MockAccessTokenRepo)(nil).RevokeUserAccessTokens), userID)
+}
+
+// SaveAccessToken mocks base method.
+func (m *MockAccessTokenRepo) SaveAccessToken(token models.AccessToken) (models.AccessToken, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveAccessToken", token)
+	ret0, _ := ret[0].(models.AccessToken)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// SaveAccessToken indicates an expected call of SaveAccessToken.
+func (mr *MockAccessTokenRepoMockRecorder) SaveAccessToken(token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAccessToken", reflect.TypeOf((*MockAccessTokenRepo)(nil).SaveAccessToken), token)
+}
+
+// TouchAccessToken mocks base method.
+func (m *MockAccessTokenRepo) TouchAccessToken(id int64, usedAt time.Time) *errr.AppError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchAccessToken", id, usedAt)
+	ret0, _ := ret[0].(*errr.AppError)
+	return ret0
+}
+
+// TouchAccessToken indicates an expected call of TouchAccessToken.
+func (mr *MockAccessTokenRepoMockRecorder) TouchAccessToken(id, usedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchAccessToken", reflect.TypeOf((*MockAccessTokenRepo)(nil).TouchAccessToken), id, usedAt)
+}
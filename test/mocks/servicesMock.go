@@ -6,6 +6,7 @@ package mocks
 
 import (
 	reflect "reflect"
+	time "time"
 
 	errr "github.com/Jashanveer-Singh/todo-go/internal/errr"
 	models "github.com/Jashanveer-Singh/todo-go/internal/models"
@@ -35,12 +36,28 @@ func (m *MockTaskService) EXPECT() *MockTaskServiceMockRecorder {
 	return m.recorder
 }
 
+// Batch mocks base method.
+func (m *MockTaskService) Batch(batch models.TaskBatchDto, claims models.Claims) ([]models.TaskOperationResult, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Batch", batch, claims)
+	ret0, _ := ret[0].([]models.TaskOperationResult)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// Batch indicates an expected call of Batch.
+func (mr *MockTaskServiceMockRecorder) Batch(batch, claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Batch", reflect.TypeOf((*MockTaskService)(nil).Batch), batch, claims)
+}
+
 // CreateTask mocks base method.
-func (m *MockTaskService) CreateTask(taskReq models.TaskRequestDto, claims models.Claims) *errr.AppError {
+func (m *MockTaskService) CreateTask(taskReq models.TaskRequestDto, claims models.Claims) (models.TaskResponseDto, *errr.AppError) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateTask", taskReq, claims)
-	ret0, _ := ret[0].(*errr.AppError)
-	return ret0
+	ret0, _ := ret[0].(models.TaskResponseDto)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
 }
 
 // CreateTask indicates an expected call of CreateTask.
@@ -50,46 +67,409 @@ func (mr *MockTaskServiceMockRecorder) CreateTask(taskReq, claims interface{}) *
 }
 
 // DeleteTask mocks base method.
-func (m *MockTaskService) DeleteTask(id string, claims models.Claims) *errr.AppError {
+func (m *MockTaskService) DeleteTask(id string, version int64, claims models.Claims) *errr.AppError {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteTask", id, claims)
+	ret := m.ctrl.Call(m, "DeleteTask", id, version, claims)
 	ret0, _ := ret[0].(*errr.AppError)
 	return ret0
 }
 
 // DeleteTask indicates an expected call of DeleteTask.
-func (mr *MockTaskServiceMockRecorder) DeleteTask(id, claims interface{}) *gomock.Call {
+func (mr *MockTaskServiceMockRecorder) DeleteTask(id, version, claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTask", reflect.TypeOf((*MockTaskService)(nil).DeleteTask), id, version, claims)
+}
+
+// GetOccurrences mocks base method.
+func (m *MockTaskService) GetOccurrences(id, count string, claims models.Claims) ([]models.OccurrenceDto, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOccurrences", id, count, claims)
+	ret0, _ := ret[0].([]models.OccurrenceDto)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// GetOccurrences indicates an expected call of GetOccurrences.
+func (mr *MockTaskServiceMockRecorder) GetOccurrences(id, count, claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOccurrences", reflect.TypeOf((*MockTaskService)(nil).GetOccurrences), id, count, claims)
+}
+
+// GetProjectTasks mocks base method.
+func (m *MockTaskService) GetProjectTasks(id string, claims models.Claims, filter models.TaskFilterDto) (models.TaskPageDto, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProjectTasks", id, claims, filter)
+	ret0, _ := ret[0].(models.TaskPageDto)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// GetProjectTasks indicates an expected call of GetProjectTasks.
+func (mr *MockTaskServiceMockRecorder) GetProjectTasks(id, claims, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjectTasks", reflect.TypeOf((*MockTaskService)(nil).GetProjectTasks), id, claims, filter)
+}
+
+// GetTask mocks base method.
+func (m *MockTaskService) GetTask(id string, claims models.Claims) (models.TaskResponseDto, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTask", id, claims)
+	ret0, _ := ret[0].(models.TaskResponseDto)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// GetTask indicates an expected call of GetTask.
+func (mr *MockTaskServiceMockRecorder) GetTask(id, claims interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTask", reflect.TypeOf((*MockTaskService)(nil).DeleteTask), id, claims)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTask", reflect.TypeOf((*MockTaskService)(nil).GetTask), id, claims)
 }
 
 // GetTasks mocks base method.
-func (m *MockTaskService) GetTasks(claims models.Claims) ([]models.TaskResponseDto, *errr.AppError) {
+func (m *MockTaskService) GetTasks(claims models.Claims, filter models.TaskFilterDto) (models.TaskPageDto, *errr.AppError) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTasks", claims)
-	ret0, _ := ret[0].([]models.TaskResponseDto)
+	ret := m.ctrl.Call(m, "GetTasks", claims, filter)
+	ret0, _ := ret[0].(models.TaskPageDto)
 	ret1, _ := ret[1].(*errr.AppError)
 	return ret0, ret1
 }
 
 // GetTasks indicates an expected call of GetTasks.
-func (mr *MockTaskServiceMockRecorder) GetTasks(claims interface{}) *gomock.Call {
+func (mr *MockTaskServiceMockRecorder) GetTasks(claims, filter interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTasks", reflect.TypeOf((*MockTaskService)(nil).GetTasks), claims)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTasks", reflect.TypeOf((*MockTaskService)(nil).GetTasks), claims, filter)
 }
 
-// UpdateTask mocks base method.
-func (m *MockTaskService) UpdateTask(id string, task models.TaskRequestDto, claims models.Claims) *errr.AppError {
+// GetTrash mocks base method.
+func (m *MockTaskService) GetTrash(claims models.Claims) ([]models.TaskResponseDto, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrash", claims)
+	ret0, _ := ret[0].([]models.TaskResponseDto)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// GetTrash indicates an expected call of GetTrash.
+func (mr *MockTaskServiceMockRecorder) GetTrash(claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrash", reflect.TypeOf((*MockTaskService)(nil).GetTrash), claims)
+}
+
+// PatchTask mocks base method.
+func (m *MockTaskService) PatchTask(id string, version int64, patch models.TaskPatch, claims models.Claims) (int64, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchTask", id, version, patch, claims)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// PatchTask indicates an expected call of PatchTask.
+func (mr *MockTaskServiceMockRecorder) PatchTask(id, version, patch, claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchTask", reflect.TypeOf((*MockTaskService)(nil).PatchTask), id, version, patch, claims)
+}
+
+// PurgeTask mocks base method.
+func (m *MockTaskService) PurgeTask(id string, claims models.Claims) *errr.AppError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTask", id, claims)
+	ret0, _ := ret[0].(*errr.AppError)
+	return ret0
+}
+
+// PurgeTask indicates an expected call of PurgeTask.
+func (mr *MockTaskServiceMockRecorder) PurgeTask(id, claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTask", reflect.TypeOf((*MockTaskService)(nil).PurgeTask), id, claims)
+}
+
+// PurgeTrash mocks base method.
+func (m *MockTaskService) PurgeTrash(retention time.Duration) (int, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTrash", retention)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// PurgeTrash indicates an expected call of PurgeTrash.
+func (mr *MockTaskServiceMockRecorder) PurgeTrash(retention interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTrash", reflect.TypeOf((*MockTaskService)(nil).PurgeTrash), retention)
+}
+
+// RestoreTask mocks base method.
+func (m *MockTaskService) RestoreTask(id string, claims models.Claims) *errr.AppError {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateTask", id, task, claims)
+	ret := m.ctrl.Call(m, "RestoreTask", id, claims)
 	ret0, _ := ret[0].(*errr.AppError)
 	return ret0
 }
 
+// RestoreTask indicates an expected call of RestoreTask.
+func (mr *MockTaskServiceMockRecorder) RestoreTask(id, claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreTask", reflect.TypeOf((*MockTaskService)(nil).RestoreTask), id, claims)
+}
+
+// SearchTasks mocks base method.
+func (m *MockTaskService) SearchTasks(query, limit string, claims models.Claims) ([]models.SearchResultDto, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchTasks", query, limit, claims)
+	ret0, _ := ret[0].([]models.SearchResultDto)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// SearchTasks indicates an expected call of SearchTasks.
+func (mr *MockTaskServiceMockRecorder) SearchTasks(query, limit, claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTasks", reflect.TypeOf((*MockTaskService)(nil).SearchTasks), query, limit, claims)
+}
+
+// UpdateTask mocks base method.
+func (m *MockTaskService) UpdateTask(id string, version int64, task models.TaskRequestDto, claims models.Claims) (int64, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTask", id, version, task, claims)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
 // UpdateTask indicates an expected call of UpdateTask.
-func (mr *MockTaskServiceMockRecorder) UpdateTask(id, task, claims interface{}) *gomock.Call {
+func (mr *MockTaskServiceMockRecorder) UpdateTask(id, version, task, claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTask", reflect.TypeOf((*MockTaskService)(nil).UpdateTask), id, version, task, claims)
+}
+
+// MockLabelService is a mock of LabelService interface.
+type MockLabelService struct {
+	ctrl     *gomock.Controller
+	recorder *MockLabelServiceMockRecorder
+}
+
+// MockLabelServiceMockRecorder is the mock recorder for MockLabelService.
+type MockLabelServiceMockRecorder struct {
+	mock *MockLabelService
+}
+
+// NewMockLabelService creates a new mock instance.
+func NewMockLabelService(ctrl *gomock.Controller) *MockLabelService {
+	mock := &MockLabelService{ctrl: ctrl}
+	mock.recorder = &MockLabelServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLabelService) EXPECT() *MockLabelServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateLabel mocks base method.
+func (m *MockLabelService) CreateLabel(labelReq models.LabelRequestDto, claims models.Claims) (models.LabelResponseDto, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLabel", labelReq, claims)
+	ret0, _ := ret[0].(models.LabelResponseDto)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// CreateLabel indicates an expected call of CreateLabel.
+func (mr *MockLabelServiceMockRecorder) CreateLabel(labelReq, claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLabel", reflect.TypeOf((*MockLabelService)(nil).CreateLabel), labelReq, claims)
+}
+
+// DeleteLabel mocks base method.
+func (m *MockLabelService) DeleteLabel(id string, claims models.Claims) *errr.AppError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLabel", id, claims)
+	ret0, _ := ret[0].(*errr.AppError)
+	return ret0
+}
+
+// DeleteLabel indicates an expected call of DeleteLabel.
+func (mr *MockLabelServiceMockRecorder) DeleteLabel(id, claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLabel", reflect.TypeOf((*MockLabelService)(nil).DeleteLabel), id, claims)
+}
+
+// GetLabels mocks base method.
+func (m *MockLabelService) GetLabels(claims models.Claims) ([]models.LabelResponseDto, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLabels", claims)
+	ret0, _ := ret[0].([]models.LabelResponseDto)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// GetLabels indicates an expected call of GetLabels.
+func (mr *MockLabelServiceMockRecorder) GetLabels(claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLabels", reflect.TypeOf((*MockLabelService)(nil).GetLabels), claims)
+}
+
+// UpdateLabel mocks base method.
+func (m *MockLabelService) UpdateLabel(id string, label models.LabelRequestDto, claims models.Claims) *errr.AppError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLabel", id, label, claims)
+	ret0, _ := ret[0].(*errr.AppError)
+	return ret0
+}
+
+// UpdateLabel indicates an expected call of UpdateLabel.
+func (mr *MockLabelServiceMockRecorder) UpdateLabel(id, label, claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLabel", reflect.TypeOf((*MockLabelService)(nil).UpdateLabel), id, label, claims)
+}
+
+// MockProjectService is a mock of ProjectService interface.
+type MockProjectService struct {
+	ctrl     *gomock.Controller
+	recorder *MockProjectServiceMockRecorder
+}
+
+// MockProjectServiceMockRecorder is the mock recorder for MockProjectService.
+type MockProjectServiceMockRecorder struct {
+	mock *MockProjectService
+}
+
+// NewMockProjectService creates a new mock instance.
+func NewMockProjectService(ctrl *gomock.Controller) *MockProjectService {
+	mock := &MockProjectService{ctrl: ctrl}
+	mock.recorder = &MockProjectServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProjectService) EXPECT() *MockProjectServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateProject mocks base method.
+func (m *MockProjectService) CreateProject(projectReq models.ProjectRequestDto, claims models.Claims) (models.ProjectResponseDto, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateProject", projectReq, claims)
+	ret0, _ := ret[0].(models.ProjectResponseDto)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// CreateProject indicates an expected call of CreateProject.
+func (mr *MockProjectServiceMockRecorder) CreateProject(projectReq, claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProject", reflect.TypeOf((*MockProjectService)(nil).CreateProject), projectReq, claims)
+}
+
+// DeleteProject mocks base method.
+func (m *MockProjectService) DeleteProject(id string, claims models.Claims) *errr.AppError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteProject", id, claims)
+	ret0, _ := ret[0].(*errr.AppError)
+	return ret0
+}
+
+// DeleteProject indicates an expected call of DeleteProject.
+func (mr *MockProjectServiceMockRecorder) DeleteProject(id, claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProject", reflect.TypeOf((*MockProjectService)(nil).DeleteProject), id, claims)
+}
+
+// GetProject mocks base method.
+func (m *MockProjectService) GetProject(id string, claims models.Claims) (models.ProjectResponseDto, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProject", id, claims)
+	ret0, _ := ret[0].(models.ProjectResponseDto)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// GetProject indicates an expected call of GetProject.
+func (mr *MockProjectServiceMockRecorder) GetProject(id, claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProject", reflect.TypeOf((*MockProjectService)(nil).GetProject), id, claims)
+}
+
+// GetProjects mocks base method.
+func (m *MockProjectService) GetProjects(claims models.Claims) ([]models.ProjectResponseDto, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProjects", claims)
+	ret0, _ := ret[0].([]models.ProjectResponseDto)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// GetProjects indicates an expected call of GetProjects.
+func (mr *MockProjectServiceMockRecorder) GetProjects(claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjects", reflect.TypeOf((*MockProjectService)(nil).GetProjects), claims)
+}
+
+// UpdateProject mocks base method.
+func (m *MockProjectService) UpdateProject(id string, project models.ProjectRequestDto, claims models.Claims) *errr.AppError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProject", id, project, claims)
+	ret0, _ := ret[0].(*errr.AppError)
+	return ret0
+}
+
+// UpdateProject indicates an expected call of UpdateProject.
+func (mr *MockProjectServiceMockRecorder) UpdateProject(id, project, claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProject", reflect.TypeOf((*MockProjectService)(nil).UpdateProject), id, project, claims)
+}
+
+// MockWorkflowService is a mock of WorkflowService interface.
+type MockWorkflowService struct {
+	ctrl     *gomock.Controller
+	recorder *MockWorkflowServiceMockRecorder
+}
+
+// MockWorkflowServiceMockRecorder is the mock recorder for MockWorkflowService.
+type MockWorkflowServiceMockRecorder struct {
+	mock *MockWorkflowService
+}
+
+// NewMockWorkflowService creates a new mock instance.
+func NewMockWorkflowService(ctrl *gomock.Controller) *MockWorkflowService {
+	mock := &MockWorkflowService{ctrl: ctrl}
+	mock.recorder = &MockWorkflowServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWorkflowService) EXPECT() *MockWorkflowServiceMockRecorder {
+	return m.recorder
+}
+
+// GetWorkflow mocks base method.
+func (m *MockWorkflowService) GetWorkflow(claims models.Claims) (models.WorkflowDto, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkflow", claims)
+	ret0, _ := ret[0].(models.WorkflowDto)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// GetWorkflow indicates an expected call of GetWorkflow.
+func (mr *MockWorkflowServiceMockRecorder) GetWorkflow(claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkflow", reflect.TypeOf((*MockWorkflowService)(nil).GetWorkflow), claims)
+}
+
+// UpdateWorkflow mocks base method.
+func (m *MockWorkflowService) UpdateWorkflow(workflow models.WorkflowDto, claims models.Claims) *errr.AppError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkflow", workflow, claims)
+	ret0, _ := ret[0].(*errr.AppError)
+	return ret0
+}
+
+// UpdateWorkflow indicates an expected call of UpdateWorkflow.
+func (mr *MockWorkflowServiceMockRecorder) UpdateWorkflow(workflow, claims interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTask", reflect.TypeOf((*MockTaskService)(nil).UpdateTask), id, task, claims)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkflow", reflect.TypeOf((*MockWorkflowService)(nil).UpdateWorkflow), workflow, claims)
 }
 
 // MockUserService is a mock of UserService interface.
@@ -116,11 +496,12 @@ func (m *MockUserService) EXPECT() *MockUserServiceMockRecorder {
 }
 
 // CreateUser mocks base method.
-func (m *MockUserService) CreateUser(arg0 models.UserRequestDto) *errr.AppError {
+func (m *MockUserService) CreateUser(arg0 models.UserRequestDto) (models.UserResponseDto, *errr.AppError) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateUser", arg0)
-	ret0, _ := ret[0].(*errr.AppError)
-	return ret0
+	ret0, _ := ret[0].(models.UserResponseDto)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
 }
 
 // CreateUser indicates an expected call of CreateUser.
@@ -129,6 +510,196 @@ func (mr *MockUserServiceMockRecorder) CreateUser(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockUserService)(nil).CreateUser), arg0)
 }
 
+// MockAccessTokenService is a mock of AccessTokenService interface.
+type MockAccessTokenService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccessTokenServiceMockRecorder
+}
+
+// MockAccessTokenServiceMockRecorder is the mock recorder for MockAccessTokenService.
+type MockAccessTokenServiceMockRecorder struct {
+	mock *MockAccessTokenService
+}
+
+// NewMockAccessTokenService creates a new mock instance.
+func NewMockAccessTokenService(ctrl *gomock.Controller) *MockAccessTokenService {
+	mock := &MockAccessTokenService{ctrl: ctrl}
+	mock.recorder = &MockAccessTokenServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccessTokenService) EXPECT() *MockAccessTokenServiceMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockAccessTokenService) Authenticate(token string) (models.Claims, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", token)
+	ret0, _ := ret[0].(models.Claims)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockAccessTokenServiceMockRecorder) Authenticate(token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockAccessTokenService)(nil).Authenticate), token)
+}
+
+// CreateAccessToken mocks base method.
+func (m *MockAccessTokenService) CreateAccessToken(token models.AccessTokenRequestDto, claims models.Claims) (models.AccessTokenResponseDto, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccessToken", token, claims)
+	ret0, _ := ret[0].(models.AccessTokenResponseDto)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// CreateAccessToken indicates an expected call of CreateAccessToken.
+func (mr *MockAccessTokenServiceMockRecorder) CreateAccessToken(token, claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccessToken", reflect.TypeOf((*MockAccessTokenService)(nil).CreateAccessToken), token, claims)
+}
+
+// DeleteAccessToken mocks base method.
+func (m *MockAccessTokenService) DeleteAccessToken(id string, claims models.Claims) *errr.AppError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccessToken", id, claims)
+	ret0, _ := ret[0].(*errr.AppError)
+	return ret0
+}
+
+// DeleteAccessToken indicates an expected call of DeleteAccessToken.
+func (mr *MockAccessTokenServiceMockRecorder) DeleteAccessToken(id, claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccessToken", reflect.TypeOf((*MockAccessTokenService)(nil).DeleteAccessToken), id, claims)
+}
+
+// GetAccessTokens mocks base method.
+func (m *MockAccessTokenService) GetAccessTokens(claims models.Claims) ([]models.AccessTokenResponseDto, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccessTokens", claims)
+	ret0, _ := ret[0].([]models.AccessTokenResponseDto)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// GetAccessTokens indicates an expected call of GetAccessTokens.
+func (mr *MockAccessTokenServiceMockRecorder) GetAccessTokens(claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccessTokens", reflect.TypeOf((*MockAccessTokenService)(nil).GetAccessTokens), claims)
+}
+
+// MockAdminService is a mock of AdminService interface.
+type MockAdminService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdminServiceMockRecorder
+}
+
+// MockAdminServiceMockRecorder is the mock recorder for MockAdminService.
+type MockAdminServiceMockRecorder struct {
+	mock *MockAdminService
+}
+
+// NewMockAdminService creates a new mock instance.
+func NewMockAdminService(ctrl *gomock.Controller) *MockAdminService {
+	mock := &MockAdminService{ctrl: ctrl}
+	mock.recorder = &MockAdminServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdminService) EXPECT() *MockAdminServiceMockRecorder {
+	return m.recorder
+}
+
+// AuditDenied mocks base method.
+func (m *MockAdminService) AuditDenied(action, id string, claims models.Claims) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AuditDenied", action, id, claims)
+}
+
+// AuditDenied indicates an expected call of AuditDenied.
+func (mr *MockAdminServiceMockRecorder) AuditDenied(action, id, claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuditDenied", reflect.TypeOf((*MockAdminService)(nil).AuditDenied), action, id, claims)
+}
+
+// GetAuditLog mocks base method.
+func (m *MockAdminService) GetAuditLog(claims models.Claims) ([]models.AuditEntryDto, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditLog", claims)
+	ret0, _ := ret[0].([]models.AuditEntryDto)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// GetAuditLog indicates an expected call of GetAuditLog.
+func (mr *MockAdminServiceMockRecorder) GetAuditLog(claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLog", reflect.TypeOf((*MockAdminService)(nil).GetAuditLog), claims)
+}
+
+// GetUserTasks mocks base method.
+func (m *MockAdminService) GetUserTasks(id string, filter models.TaskFilterDto, claims models.Claims) (models.TaskPageDto, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserTasks", id, filter, claims)
+	ret0, _ := ret[0].(models.TaskPageDto)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// GetUserTasks indicates an expected call of GetUserTasks.
+func (mr *MockAdminServiceMockRecorder) GetUserTasks(id, filter, claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserTasks", reflect.TypeOf((*MockAdminService)(nil).GetUserTasks), id, filter, claims)
+}
+
+// GetUsers mocks base method.
+func (m *MockAdminService) GetUsers(claims models.Claims) ([]models.UserResponseDto, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsers", claims)
+	ret0, _ := ret[0].([]models.UserResponseDto)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// GetUsers indicates an expected call of GetUsers.
+func (mr *MockAdminServiceMockRecorder) GetUsers(claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsers", reflect.TypeOf((*MockAdminService)(nil).GetUsers), claims)
+}
+
+// ResetPassword mocks base method.
+func (m *MockAdminService) ResetPassword(id string, reset models.PasswordResetDto, claims models.Claims) *errr.AppError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", id, reset, claims)
+	ret0, _ := ret[0].(*errr.AppError)
+	return ret0
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockAdminServiceMockRecorder) ResetPassword(id, reset, claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockAdminService)(nil).ResetPassword), id, reset, claims)
+}
+
+// SetUserDisabled mocks base method.
+func (m *MockAdminService) SetUserDisabled(id string, disabled bool, claims models.Claims) *errr.AppError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserDisabled", id, disabled, claims)
+	ret0, _ := ret[0].(*errr.AppError)
+	return ret0
+}
+
+// SetUserDisabled indicates an expected call of SetUserDisabled.
+func (mr *MockAdminServiceMockRecorder) SetUserDisabled(id, disabled, claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserDisabled", reflect.TypeOf((*MockAdminService)(nil).SetUserDisabled), id, disabled, claims)
+}
+
 // MockAuthService is a mock of AuthService interface.
 type MockAuthService struct {
 	ctrl     *gomock.Controller
@@ -153,10 +724,10 @@ func (m *MockAuthService) EXPECT() *MockAuthServiceMockRecorder {
 }
 
 // Login mocks base method.
-func (m *MockAuthService) Login(username, password string) (string, *errr.AppError) {
+func (m *MockAuthService) Login(username, password string) (models.TokenPairDto, *errr.AppError) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Login", username, password)
-	ret0, _ := ret[0].(string)
+	ret0, _ := ret[0].(models.TokenPairDto)
 	ret1, _ := ret[1].(*errr.AppError)
 	return ret0, ret1
 }
@@ -166,3 +737,61 @@ func (mr *MockAuthServiceMockRecorder) Login(username, password interface{}) *go
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockAuthService)(nil).Login), username, password)
 }
+
+// Logout mocks base method.
+func (m *MockAuthService) Logout(claims models.Claims, refreshToken string) *errr.AppError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", claims, refreshToken)
+	ret0, _ := ret[0].(*errr.AppError)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockAuthServiceMockRecorder) Logout(claims, refreshToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockAuthService)(nil).Logout), claims, refreshToken)
+}
+
+// LogoutAll mocks base method.
+func (m *MockAuthService) LogoutAll(claims models.Claims) *errr.AppError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LogoutAll", claims)
+	ret0, _ := ret[0].(*errr.AppError)
+	return ret0
+}
+
+// LogoutAll indicates an expected call of LogoutAll.
+func (mr *MockAuthServiceMockRecorder) LogoutAll(claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogoutAll", reflect.TypeOf((*MockAuthService)(nil).LogoutAll), claims)
+}
+
+// PruneRevocations mocks base method.
+func (m *MockAuthService) PruneRevocations() (int, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneRevocations")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// PruneRevocations indicates an expected call of PruneRevocations.
+func (mr *MockAuthServiceMockRecorder) PruneRevocations() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneRevocations", reflect.TypeOf((*MockAuthService)(nil).PruneRevocations))
+}
+
+// Refresh mocks base method.
+func (m *MockAuthService) Refresh(refreshToken string) (models.TokenPairDto, *errr.AppError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", refreshToken)
+	ret0, _ := ret[0].(models.TokenPairDto)
+	ret1, _ := ret[1].(*errr.AppError)
+	return ret0, ret1
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockAuthServiceMockRecorder) Refresh(refreshToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockAuthService)(nil).Refresh), refreshToken)
+}
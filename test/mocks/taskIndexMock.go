@@ -0,0 +1,127 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/ports/taskIndex.go
+
+// Package mock_ports is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	models "github.com/Jashanveer-Singh/todo-go/internal/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockTaskIndex is a mock of TaskIndex interface.
+type MockTaskIndex struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaskIndexMockRecorder
+}
+
+// MockTaskIndexMockRecorder is the mock recorder for MockTaskIndex.
+type MockTaskIndexMockRecorder struct {
+	mock *MockTaskIndex
+}
+
+// NewMockTaskIndex creates a new mock instance.
+func NewMockTaskIndex(ctrl *gomock.Controller) *MockTaskIndex {
+	mock := &MockTaskIndex{ctrl: ctrl}
+	mock.recorder = &MockTaskIndexMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTaskIndex) EXPECT() *MockTaskIndexMockRecorder {
+	return m.recorder
+}
+
+// Forget mocks base method.
+func (m *MockTaskIndex) Forget(userID int64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Forget", userID)
+}
+
+// Forget indicates an expected call of Forget.
+func (mr *MockTaskIndexMockRecorder) Forget(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Forget", reflect.TypeOf((*MockTaskIndex)(nil).Forget), userID)
+}
+
+// Generation mocks base method.
+func (m *MockTaskIndex) Generation(userID int64) uint64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Generation", userID)
+	ret0, _ := ret[0].(uint64)
+	return ret0
+}
+
+// Generation indicates an expected call of Generation.
+func (mr *MockTaskIndexMockRecorder) Generation(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Generation", reflect.TypeOf((*MockTaskIndex)(nil).Generation), userID)
+}
+
+// Indexed mocks base method.
+func (m *MockTaskIndex) Indexed(userID int64) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Indexed", userID)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Indexed indicates an expected call of Indexed.
+func (mr *MockTaskIndexMockRecorder) Indexed(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Indexed", reflect.TypeOf((*MockTaskIndex)(nil).Indexed), userID)
+}
+
+// Put mocks base method.
+func (m *MockTaskIndex) Put(task models.Task) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Put", task)
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockTaskIndexMockRecorder) Put(task interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockTaskIndex)(nil).Put), task)
+}
+
+// Remove mocks base method.
+func (m *MockTaskIndex) Remove(userID, id int64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Remove", userID, id)
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockTaskIndexMockRecorder) Remove(userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockTaskIndex)(nil).Remove), userID, id)
+}
+
+// Replace mocks base method.
+func (m *MockTaskIndex) Replace(userID int64, tasks []models.Task, gen uint64) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replace", userID, tasks, gen)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Replace indicates an expected call of Replace.
+func (mr *MockTaskIndexMockRecorder) Replace(userID, tasks, gen interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockTaskIndex)(nil).Replace), userID, tasks, gen)
+}
+
+// Search mocks base method.
+func (m *MockTaskIndex) Search(userID int64, query string, limit int) []models.SearchHit {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", userID, query, limit)
+	ret0, _ := ret[0].([]models.SearchHit)
+	return ret0
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockTaskIndexMockRecorder) Search(userID, query, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockTaskIndex)(nil).Search), userID, query, limit)
+}
@@ -49,6 +49,20 @@ func (mr *MockTokenProviderMockRecorder) GenerateToken(claims interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateToken", reflect.TypeOf((*MockTokenProvider)(nil).GenerateToken), claims)
 }
 
+// PublicKeys mocks base method.
+func (m *MockTokenProvider) PublicKeys() models.JSONWebKeySet {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublicKeys")
+	ret0, _ := ret[0].(models.JSONWebKeySet)
+	return ret0
+}
+
+// PublicKeys indicates an expected call of PublicKeys.
+func (mr *MockTokenProviderMockRecorder) PublicKeys() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublicKeys", reflect.TypeOf((*MockTokenProvider)(nil).PublicKeys))
+}
+
 // ValidateToken mocks base method.
 func (m *MockTokenProvider) ValidateToken(token string) (models.Claims, error) {
 	m.ctrl.T.Helper()
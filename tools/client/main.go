@@ -2,6 +2,7 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -18,6 +19,7 @@ var (
 	titleWidth              = 0
 	descWidth               = 0
 	statusWidth             = 20
+	priorityWidth           = 10
 	tasks           []task
 	username        string
 	password        string
@@ -30,10 +32,28 @@ func init() {
 }
 
 type task struct {
-	ID     string `json:"id"`
-	Title  string `json:"title"`
-	Desc   string `json:"Desc"`
-	Status string `json:"status"`
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Desc     string `json:"Desc"`
+	Status   string `json:"status"`
+	Priority string `json:"priority"`
+}
+
+// priorityColors are the ansi colours of the priority column.
+var priorityColors = map[string]string{
+	"Low":    "\033[32m",
+	"Medium": "\033[34m",
+	"High":   "\033[33m",
+	"Urgent": "\033[31m",
+}
+
+func colorPriority(priority string) string {
+	padded := fmt.Sprintf("%-*s", priorityWidth, priority)
+	color, ok := priorityColors[priority]
+	if !ok {
+		return padded
+	}
+	return color + padded + "\033[0m"
 }
 
 func pressEnterToContinue() {
@@ -109,7 +129,7 @@ func handleCreateUser() {
 			printErrf("Failed to create user and failed to get error message")
 			return
 		}
-		printErrf("Failed to create user. err: %s", string(data))
+		printErrf("Failed to create user. err: %s", problemDetail(data))
 		return
 	}
 	fmt.Println("User created successfully")
@@ -138,15 +158,18 @@ func handleLogin() {
 			printErrf("Failed to get user token and error message")
 			return
 		}
-		printErrf("Failed to get user token. err: %s", data)
+		printErrf("Failed to get user token. err: %s", problemDetail(data))
 		return
 	}
-	data, err := io.ReadAll(response.Body)
+	tokens := struct {
+		AccessToken string `json:"access_token"`
+	}{}
+	err = json.NewDecoder(response.Body).Decode(&tokens)
 	if err != nil {
 		printErrf("Failed to get user token. error in reading response body")
 		return
 	}
-	token = "Bearer " + string(data)
+	token = "Bearer " + tokens.AccessToken
 	pressEnterToContinue()
 	handlePostLogin()
 }
@@ -187,7 +210,15 @@ func handleCreateTask() {
 	fmt.Print("Enter task description: ")
 	scanner.Scan()
 	description := scanner.Text()
-	jsonbody := fmt.Sprintf(`{"title": "%s", "desc": "%s"}`, title, description)
+	fmt.Print("Enter task priority (None/Low/Medium/High/Urgent, leave empty for None): ")
+	scanner.Scan()
+	priority := scanner.Text()
+	jsonbody := fmt.Sprintf(
+		`{"title": "%s", "desc": "%s", "priority": "%s"}`,
+		title,
+		description,
+		priority,
+	)
 
 	request, err := http.NewRequest(
 		http.MethodPost,
@@ -207,13 +238,19 @@ func handleCreateTask() {
 	defer response.Body.Close()
 
 	if response.StatusCode == http.StatusCreated {
-		fmt.Println("Task created successfully")
+		var created task
+		err := json.NewDecoder(response.Body).Decode(&created)
+		if err != nil {
+			printErrf("Task created but failed to read it\n%s", err.Error())
+			return
+		}
+		fmt.Printf("Task %s created successfully\n", created.ID)
 	} else {
 		message, err := io.ReadAll(response.Body)
 		if err != nil {
 			fmt.Println("Failed to create task and failed to get error message")
 		}
-		fmt.Println("Failed to create task. err: ", string(message))
+		fmt.Println("Failed to create task. err: ", problemDetail(message))
 	}
 }
 
@@ -237,7 +274,11 @@ func handleShowTask() {
 	}
 
 	if response.StatusCode == http.StatusOK {
-		err := json.Unmarshal(data, &tasks)
+		var page struct {
+			Tasks []task `json:"tasks"`
+		}
+		err := json.Unmarshal(data, &page)
+		tasks = page.Tasks
 		if err != nil {
 			printErrf("Failed to json tasks\n%s", err.Error())
 			os.Exit(1)
@@ -282,14 +323,25 @@ func handleUpdateTask() {
 		status = ""
 	}
 
-	body := strings.NewReader(
-		fmt.Sprintf(`{"title":"%s", "desc": "%s", "status": "%s"}`, title, desc, status),
-	)
-	request, err := http.NewRequest(http.MethodPut, "http://localhost:8080/tasks/"+id, body)
+	// Only the fields given are sent, as a merge patch leaves the others
+	// alone.
+	changes := map[string]string{}
+	if title != "" {
+		changes["title"] = title
+	}
+	if desc != "" {
+		changes["desc"] = desc
+	}
+	if status != "" {
+		changes["status"] = status
+	}
+	patch, _ := json.Marshal(changes)
+	request, err := http.NewRequest(http.MethodPatch, "http://localhost:8080/tasks/"+id, bytes.NewReader(patch))
 	if err != nil {
 		printErrf("Failed to create request for updating task")
 	}
 	request.Header.Set("Authorization", token)
+	request.Header.Set("Content-Type", "application/merge-patch+json")
 	response, err := http.DefaultClient.Do(request)
 	if err != nil {
 		printErrf("unexpected error while http request.\n%s\n", err.Error())
@@ -306,7 +358,7 @@ func handleUpdateTask() {
 	if err != nil {
 		printErrf("Failed to get tasks and error message")
 	}
-	printErrf("Failed to update task. err: %s", data)
+	printErrf("Failed to update task. err: %s", problemDetail(data))
 }
 
 func handleDeleteTask() {
@@ -343,13 +395,25 @@ func handleDeleteTask() {
 	if err != nil {
 		printErrf("Failed to get tasks and error message")
 	}
-	printErrf("Failed to update task. err: %s", data)
+	printErrf("Failed to update task. err: %s", problemDetail(data))
 }
 
 func printErrf(s string, a ...any) {
 	fmt.Fprintf(os.Stderr, s, a...)
 }
 
+// problemDetail returns the detail of a problem+json error body, or the body
+// itself when it isn't one.
+func problemDetail(data []byte) string {
+	var problem struct {
+		Detail string `json:"detail"`
+	}
+	if json.Unmarshal(data, &problem) != nil || problem.Detail == "" {
+		return string(data)
+	}
+	return problem.Detail
+}
+
 func printTasks() {
 	if len(tasks) == 0 {
 		fmt.Println("no tasks till now")
@@ -372,7 +436,7 @@ func printTasks() {
 		" ",
 		descWidth-descLeftPadding-4,
 	)
-	fmt.Printf("SNo|%s|%s|      Status\n", title, desc)
+	fmt.Printf("SNo|%s|%s| Priority |      Status\n", title, desc)
 	// fmt.Println("")
 	horizontalLine := "---+" + strings.Repeat(
 		"-",
@@ -380,6 +444,9 @@ func printTasks() {
 	) + "+" + strings.Repeat(
 		"-",
 		descWidth,
+	) + "+" + strings.Repeat(
+		"-",
+		priorityWidth,
 	) + "+" + strings.Repeat(
 		"-",
 		statusWidth,
@@ -387,7 +454,14 @@ func printTasks() {
 	fmt.Println(horizontalLine)
 
 	for i, task := range tasks {
-		fmt.Printf("%-3d|%-40s|%-60s|%-20s\n", i+1, task.Title, task.Desc, task.Status)
+		fmt.Printf(
+			"%-3d|%-40s|%-60s|%s|%-20s\n",
+			i+1,
+			task.Title,
+			task.Desc,
+			colorPriority(task.Priority),
+			task.Status,
+		)
 	}
 	fmt.Printf("\n\n")
 }